@@ -38,6 +38,8 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	"AWSCODECOMMIT":   {CodeHost: true, JSONSchema: schema.AWSCodeCommitSchemaJSON},
 	"BITBUCKETCLOUD":  {CodeHost: true, JSONSchema: schema.BitbucketCloudSchemaJSON},
 	"BITBUCKETSERVER": {CodeHost: true, JSONSchema: schema.BitbucketServerSchemaJSON},
+	"GERRIT":          {CodeHost: true, JSONSchema: schema.GerritSchemaJSON},
+	"GITEA":           {CodeHost: true, JSONSchema: schema.GiteaSchemaJSON},
 	"GITHUB":          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	"GITLAB":          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	"GITOLITE":        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
@@ -403,6 +405,28 @@ func (c *ExternalServicesStore) ListBitbucketServerConnections(ctx context.Conte
 	return connections, nil
 }
 
+// ListGerritConnections returns a list of GerritConnection configs.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (c *ExternalServicesStore) ListGerritConnections(ctx context.Context) ([]*schema.GerritConnection, error) {
+	var connections []*schema.GerritConnection
+	if err := c.listConfigs(ctx, "GERRIT", &connections); err != nil {
+		return nil, err
+	}
+	return connections, nil
+}
+
+// ListGiteaConnections returns a list of GiteaConnection configs.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (c *ExternalServicesStore) ListGiteaConnections(ctx context.Context) ([]*schema.GiteaConnection, error) {
+	var connections []*schema.GiteaConnection
+	if err := c.listConfigs(ctx, "GITEA", &connections); err != nil {
+		return nil, err
+	}
+	return connections, nil
+}
+
 // ListGitHubConnections returns a list of GitHubConnection configs.
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
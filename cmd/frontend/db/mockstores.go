@@ -10,13 +10,14 @@ type MockStores struct {
 	DiscussionComments        MockDiscussionComments
 	DiscussionMailReplyTokens MockDiscussionMailReplyTokens
 
-	Repos         MockRepos
-	Orgs          MockOrgs
-	OrgMembers    MockOrgMembers
-	SavedSearches MockSavedSearches
-	Settings      MockSettings
-	Users         MockUsers
-	UserEmails    MockUserEmails
+	Repos           MockRepos
+	Orgs            MockOrgs
+	OrgMembers      MockOrgMembers
+	SavedSearches   MockSavedSearches
+	SavedSearchRuns MockSavedSearchRuns
+	Settings        MockSettings
+	Users           MockUsers
+	UserEmails      MockUserEmails
 
 	Phabricator MockPhabricator
 
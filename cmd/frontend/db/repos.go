@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db/query"
@@ -253,6 +254,40 @@ type ReposListOptions struct {
 	// OnlyArchived excludes non-archived repositories from the list.
 	OnlyArchived bool
 
+	// NoPrivate excludes private repositories from the list.
+	NoPrivate bool
+
+	// OnlyPrivate excludes public repositories from the list.
+	OnlyPrivate bool
+
+	// Topics excludes repositories that don't have all of the given topics
+	// from the list. Topics are matched case-insensitively.
+	Topics []string
+
+	// ExcludeTopics excludes repositories that have any of the given topics
+	// from the list. Topics are matched case-insensitively.
+	ExcludeTopics []string
+
+	// Languages excludes repositories whose primary language isn't one of the
+	// given languages from the list. Languages are matched case-insensitively.
+	Languages []string
+
+	// ExcludeLanguages excludes repositories whose primary language is one of
+	// the given languages from the list. Languages are matched
+	// case-insensitively.
+	ExcludeLanguages []string
+
+	// MinStars, if non-nil, excludes repositories with fewer stars from the list.
+	MinStars *int
+
+	// MaxStars, if non-nil, excludes repositories with more stars from the list.
+	MaxStars *int
+
+	// OnlyPendingDeletion excludes repositories that aren't pending deletion
+	// from the list. These are repositories that repo-updater would have
+	// deleted if the deletion safeguard hadn't quarantined them.
+	OnlyPendingDeletion bool
+
 	// OnlyRepoIDs skips fetching of RepoFields in each Repo.
 	OnlyRepoIDs bool
 
@@ -440,6 +475,33 @@ func (*repos) listSQL(opt ReposListOptions) (conds []*sqlf.Query, err error) {
 	if opt.OnlyArchived {
 		conds = append(conds, sqlf.Sprintf("archived"))
 	}
+	if opt.NoPrivate {
+		conds = append(conds, sqlf.Sprintf("NOT private"))
+	}
+	if opt.OnlyPrivate {
+		conds = append(conds, sqlf.Sprintf("private"))
+	}
+	if len(opt.Topics) > 0 {
+		conds = append(conds, sqlf.Sprintf("topics @> %s", pq.Array(lowerAll(opt.Topics))))
+	}
+	if len(opt.ExcludeTopics) > 0 {
+		conds = append(conds, sqlf.Sprintf("NOT topics && %s", pq.Array(lowerAll(opt.ExcludeTopics))))
+	}
+	if len(opt.Languages) > 0 {
+		conds = append(conds, sqlf.Sprintf("lower(language) = ANY(%s)", pq.Array(lowerAll(opt.Languages))))
+	}
+	if len(opt.ExcludeLanguages) > 0 {
+		conds = append(conds, sqlf.Sprintf("NOT COALESCE(lower(language), '') = ANY(%s)", pq.Array(lowerAll(opt.ExcludeLanguages))))
+	}
+	if opt.MinStars != nil {
+		conds = append(conds, sqlf.Sprintf("stars >= %d", *opt.MinStars))
+	}
+	if opt.MaxStars != nil {
+		conds = append(conds, sqlf.Sprintf("stars <= %d", *opt.MaxStars))
+	}
+	if opt.OnlyPendingDeletion {
+		conds = append(conds, sqlf.Sprintf("pending_deletion_at IS NOT NULL"))
+	}
 
 	if opt.Index != nil {
 		// We don't currently have an index column, but when we want the
@@ -454,6 +516,17 @@ func (*repos) listSQL(opt ReposListOptions) (conds []*sqlf.Query, err error) {
 	return conds, nil
 }
 
+// lowerAll returns the lower-cased strings, matching how repo-updater
+// normalizes topics. Languages are lower-cased too, since they aren't
+// normalized when they are synced.
+func lowerAll(ss []string) []string {
+	lowered := make([]string, len(ss))
+	for i, s := range ss {
+		lowered[i] = strings.ToLower(s)
+	}
+	return lowered
+}
+
 // parseIncludePattern either (1) parses the pattern into a list of exact possible
 // string values and LIKE patterns if such a list can be determined from the pattern,
 // and (2) returns the original regexp if those patterns are not equivalent to the
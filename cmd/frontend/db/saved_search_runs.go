@@ -0,0 +1,207 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// maxSavedSearchRuns is the number of most recent runs that are kept for each
+// saved search. Older runs are deleted when a new run is recorded.
+const maxSavedSearchRuns = 100
+
+type savedSearchRuns struct{}
+
+// SavedSearchRun is a single execution of a saved search by query-runner.
+type SavedSearchRun struct {
+	ID            int64
+	SavedSearchID int32
+	Query         string
+	ExecutedAt    time.Time
+	ExecDuration  time.Duration
+	ResultCount   int
+
+	// LimitHit is whether the search hit its result limit, so that Results
+	// may be incomplete.
+	LimitHit bool
+
+	// Results are the fingerprints of all results of the run. It is only
+	// populated by GetLatest.
+	Results []api.SavedQueryResult
+
+	// Added and Removed are the results which appeared and disappeared since
+	// the previous run.
+	Added, Removed []api.SavedQueryResult
+}
+
+// Create records a new run of a saved search, and deletes all but the most
+// recent runs of that saved search.
+func (s *savedSearchRuns) Create(ctx context.Context, run *SavedSearchRun) error {
+	if Mocks.SavedSearchRuns.Create != nil {
+		return Mocks.SavedSearchRuns.Create(ctx, run)
+	}
+
+	results, err := marshalSavedQueryResults(run.Results)
+	if err != nil {
+		return err
+	}
+	added, err := marshalSavedQueryResults(run.Added)
+	if err != nil {
+		return err
+	}
+	removed, err := marshalSavedQueryResults(run.Removed)
+	if err != nil {
+		return err
+	}
+
+	err = dbconn.Global.QueryRowContext(ctx, `INSERT INTO saved_search_runs(
+			saved_search_id,
+			query,
+			executed_at,
+			exec_duration_ns,
+			result_count,
+			fingerprints,
+			added,
+			removed,
+			limit_hit
+		) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+		run.SavedSearchID,
+		run.Query,
+		run.ExecutedAt,
+		int64(run.ExecDuration),
+		run.ResultCount,
+		results,
+		added,
+		removed,
+		run.LimitHit,
+	).Scan(&run.ID)
+	if err != nil {
+		return errors.Wrap(err, "INSERT")
+	}
+
+	_, err = dbconn.Global.ExecContext(ctx, `DELETE FROM saved_search_runs WHERE saved_search_id=$1 AND id NOT IN (
+			SELECT id FROM saved_search_runs WHERE saved_search_id=$1 ORDER BY executed_at DESC, id DESC LIMIT $2
+		)`,
+		run.SavedSearchID,
+		maxSavedSearchRuns,
+	)
+	if err != nil {
+		return errors.Wrap(err, "DELETE")
+	}
+	return nil
+}
+
+// GetLatest returns the most recent run of the given saved search, including
+// the fingerprints of all of its results. nil is returned if the saved search
+// has never been run.
+func (s *savedSearchRuns) GetLatest(ctx context.Context, savedSearchID int32) (*SavedSearchRun, error) {
+	if Mocks.SavedSearchRuns.GetLatest != nil {
+		return Mocks.SavedSearchRuns.GetLatest(ctx, savedSearchID)
+	}
+
+	var (
+		run                     SavedSearchRun
+		execDurationNs          int64
+		results, added, removed []byte
+	)
+	err := dbconn.Global.QueryRowContext(ctx, `SELECT
+			id,
+			saved_search_id,
+			query,
+			executed_at,
+			exec_duration_ns,
+			result_count,
+			fingerprints,
+			added,
+			removed,
+			limit_hit
+		FROM saved_search_runs WHERE saved_search_id=$1 ORDER BY executed_at DESC, id DESC LIMIT 1`,
+		savedSearchID,
+	).Scan(&run.ID, &run.SavedSearchID, &run.Query, &run.ExecutedAt, &execDurationNs, &run.ResultCount, &results, &added, &removed, &run.LimitHit)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "QueryRow")
+	}
+	run.ExecDuration = time.Duration(execDurationNs)
+	for _, v := range []struct {
+		data []byte
+		dst  *[]api.SavedQueryResult
+	}{{results, &run.Results}, {added, &run.Added}, {removed, &run.Removed}} {
+		if err := json.Unmarshal(v.data, v.dst); err != nil {
+			return nil, errors.Wrap(err, "Unmarshal")
+		}
+	}
+	return &run, nil
+}
+
+// List returns the most recent runs of the given saved search, newest first.
+// The Results field of the returned runs is not populated.
+//
+// 🚨 SECURITY: This method does NOT verify the user's identity or that the
+// user is an admin. It is the callers responsibility to ensure that only users
+// with access to the saved search can access its runs.
+func (s *savedSearchRuns) List(ctx context.Context, savedSearchID int32, limit int) ([]*SavedSearchRun, error) {
+	if Mocks.SavedSearchRuns.List != nil {
+		return Mocks.SavedSearchRuns.List(ctx, savedSearchID, limit)
+	}
+
+	q := sqlf.Sprintf(`SELECT
+			id,
+			saved_search_id,
+			query,
+			executed_at,
+			exec_duration_ns,
+			result_count,
+			added,
+			removed,
+			limit_hit
+		FROM saved_search_runs WHERE saved_search_id=%d ORDER BY executed_at DESC, id DESC LIMIT %d`,
+		savedSearchID,
+		limit,
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, errors.Wrap(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	var runs []*SavedSearchRun
+	for rows.Next() {
+		var (
+			run            SavedSearchRun
+			execDurationNs int64
+			added, removed []byte
+		)
+		if err := rows.Scan(&run.ID, &run.SavedSearchID, &run.Query, &run.ExecutedAt, &execDurationNs, &run.ResultCount, &added, &removed, &run.LimitHit); err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		run.ExecDuration = time.Duration(execDurationNs)
+		if err := json.Unmarshal(added, &run.Added); err != nil {
+			return nil, errors.Wrap(err, "Unmarshal")
+		}
+		if err := json.Unmarshal(removed, &run.Removed); err != nil {
+			return nil, errors.Wrap(err, "Unmarshal")
+		}
+		runs = append(runs, &run)
+	}
+	return runs, rows.Err()
+}
+
+func marshalSavedQueryResults(results []api.SavedQueryResult) ([]byte, error) {
+	if results == nil {
+		results = []api.SavedQueryResult{}
+	}
+	b, err := json.Marshal(results)
+	if err != nil {
+		return nil, errors.Wrap(err, "Marshal")
+	}
+	return b, nil
+}
@@ -0,0 +1,9 @@
+package db
+
+import "context"
+
+type MockSavedSearchRuns struct {
+	Create    func(ctx context.Context, run *SavedSearchRun) error
+	GetLatest func(ctx context.Context, savedSearchID int32) (*SavedSearchRun, error)
+	List      func(ctx context.Context, savedSearchID int32, limit int) ([]*SavedSearchRun, error)
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestSavedSearchRuns(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+	_, err := Users.Create(ctx, NewUser{DisplayName: "test", Email: "test@test.com", Username: "test", Password: "test", EmailVerificationCode: "c2"})
+	if err != nil {
+		t.Fatal("can't create user", err)
+	}
+	userID := int32(1)
+	ss, err := SavedSearches.Create(ctx, &types.SavedSearch{
+		Query:       "test",
+		Description: "test",
+		Notify:      true,
+		UserID:      &userID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := SavedSearchRuns.GetLatest(ctx, ss.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != nil {
+		t.Fatalf("got latest run %+v, want nil", latest)
+	}
+
+	a := api.SavedQueryResult{Repo: "r", Path: "a.go", LineHash: "aaaa"}
+	b := api.SavedQueryResult{Repo: "r", Commit: "deadbeef"}
+	executedAt := time.Now().UTC().Truncate(time.Microsecond)
+	runs := []*SavedSearchRun{
+		{
+			SavedSearchID: ss.ID,
+			Query:         "test",
+			ExecutedAt:    executedAt,
+			ExecDuration:  time.Second,
+			ResultCount:   1,
+			Results:       []api.SavedQueryResult{a},
+			Added:         []api.SavedQueryResult{},
+			Removed:       []api.SavedQueryResult{},
+		},
+		{
+			SavedSearchID: ss.ID,
+			Query:         "test",
+			ExecutedAt:    executedAt.Add(time.Minute),
+			ExecDuration:  2 * time.Second,
+			ResultCount:   1,
+			LimitHit:      true,
+			Results:       []api.SavedQueryResult{b},
+			Added:         []api.SavedQueryResult{b},
+			Removed:       []api.SavedQueryResult{a},
+		},
+	}
+	for _, run := range runs {
+		if err := SavedSearchRuns.Create(ctx, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	latest, err = SavedSearchRuns.GetLatest(ctx, ss.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest.ExecutedAt = latest.ExecutedAt.UTC()
+	if !reflect.DeepEqual(latest, runs[1]) {
+		t.Errorf("got latest run %+v, want %+v", latest, runs[1])
+	}
+
+	listed, err := SavedSearchRuns.List(ctx, ss.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 || listed[0].ID != runs[1].ID || listed[1].ID != runs[0].ID {
+		t.Fatalf("got runs %+v, want newest first", listed)
+	}
+	if listed[0].Results != nil {
+		t.Errorf("got results %+v from List, want none", listed[0].Results)
+	}
+}
@@ -12,6 +12,7 @@ type MockSavedSearches struct {
 	ListSavedSearchesByUserID func(ctx context.Context, userID int32) ([]*types.SavedSearch, error)
 	Create                    func(ctx context.Context, newSavedSearch *types.SavedSearch) (*types.SavedSearch, error)
 	Update                    func(ctx context.Context, savedSearch *types.SavedSearch) (*types.SavedSearch, error)
+	UpdateWebhook             func(ctx context.Context, id int32, notifyWebhook bool, webhookURL, webhookSecret *string) error
 	Delete                    func(ctx context.Context, id int32) error
 	GetByID                   func(ctx context.Context, id int32) (*api.SavedQuerySpecAndConfig, error)
 }
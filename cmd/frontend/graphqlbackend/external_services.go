@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 )
 
 var extsvcConfigAllowEdits, _ = strconv.ParseBool(env.Get("EXTSVC_CONFIG_ALLOW_EDITS", "false", "When EXTSVC_CONFIG_FILE is in use, allow edits in the application to be made which will be overwritten on next process restart"))
@@ -159,6 +160,106 @@ func (*schemaResolver) DeleteExternalService(ctx context.Context, args *struct {
 	return &EmptyResponse{}, nil
 }
 
+func (*schemaResolver) DryRunExternalService(ctx context.Context, args *struct {
+	Input *struct {
+		ID     *graphql.ID
+		Kind   *string
+		Config string
+	}
+}) (*externalServiceDryRunResolver, error) {
+	// 🚨 SECURITY: Only site admins may preview external service changes (they have secrets).
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(args.Input.Config) == "" {
+		return nil, fmt.Errorf("blank external service configuration is invalid (must be valid JSONC)")
+	}
+
+	svc := api.ExternalService{Config: args.Input.Config}
+	if args.Input.ID != nil {
+		id, err := unmarshalExternalServiceID(*args.Input.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		externalService, err := db.ExternalServices.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		svc.ID = externalService.ID
+		svc.Kind = externalService.Kind
+		svc.DisplayName = externalService.DisplayName
+	} else if args.Input.Kind != nil {
+		svc.Kind = *args.Input.Kind
+	} else {
+		return nil, errors.New("either the id or the kind of the external service is required")
+	}
+
+	if err := db.ExternalServices.ValidateConfig(svc.Kind, svc.Config, conf.Get().AuthProviders); err != nil {
+		return nil, err
+	}
+
+	res, err := repoupdater.DefaultClient.ExternalServiceDryRun(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &externalServiceDryRunResolver{res: res}, nil
+}
+
+type externalServiceDryRunResolver struct {
+	res *protocol.ExternalServiceDryRunResult
+}
+
+func (r *externalServiceDryRunResolver) Added() []string { return repoNameStrings(r.res.Added) }
+
+func (r *externalServiceDryRunResolver) Deleted() []string { return repoNameStrings(r.res.Deleted) }
+
+func (r *externalServiceDryRunResolver) Quarantined() []string {
+	return repoNameStrings(r.res.Quarantined)
+}
+
+func (r *externalServiceDryRunResolver) Renamed() []*repositoryRenameResolver {
+	renamed := make([]*repositoryRenameResolver, 0, len(r.res.Renamed))
+	for _, rename := range r.res.Renamed {
+		renamed = append(renamed, &repositoryRenameResolver{rename: rename})
+	}
+	return renamed
+}
+
+func (r *externalServiceDryRunResolver) Modified() []string { return repoNameStrings(r.res.Modified) }
+
+func repoNameStrings(names []api.RepoName) []string {
+	strs := make([]string, 0, len(names))
+	for _, name := range names {
+		strs = append(strs, string(name))
+	}
+	return strs
+}
+
+type repositoryRenameResolver struct {
+	rename protocol.RepoRename
+}
+
+func (r *repositoryRenameResolver) From() string { return string(r.rename.From) }
+
+func (r *repositoryRenameResolver) To() string { return string(r.rename.To) }
+
+func (*schemaResolver) ConfirmPendingRepositoryDeletions(ctx context.Context) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can delete repositories.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := repoupdater.DefaultClient.DeletePendingRepos(ctx); err != nil {
+		return nil, err
+	}
+
+	return &EmptyResponse{}, nil
+}
+
 func (r *schemaResolver) ExternalServices(ctx context.Context, args *struct {
 	graphqlutil.ConnectionArgs
 }) (*externalServiceConnectionResolver, error) {
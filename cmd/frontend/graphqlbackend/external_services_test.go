@@ -0,0 +1,172 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+)
+
+func TestDryRunExternalService(t *testing.T) {
+	const config = `{"url": "https://github.com", "token": "abc", "repositoryQuery": ["none"]}`
+
+	resetMocks()
+	t.Run("authenticated as non-site-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: false}, nil
+		}
+		defer func() { db.Mocks.Users.GetByCurrentAuthUser = nil }()
+
+		kind := "GITHUB"
+		result, err := (&schemaResolver{}).DryRunExternalService(context.Background(), &struct {
+			Input *struct {
+				ID     *graphql.ID
+				Kind   *string
+				Config string
+			}
+		}{Input: &struct {
+			ID     *graphql.ID
+			Kind   *string
+			Config string
+		}{Kind: &kind, Config: config}})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("got err %v, want %v", err, want)
+		}
+		if result != nil {
+			t.Errorf("got result %v, want nil", result)
+		}
+	})
+
+	t.Run("existing external service", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		defer func() { db.Mocks.Users.GetByCurrentAuthUser = nil }()
+
+		db.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
+			return &types.ExternalService{ID: id, Kind: "GITHUB", DisplayName: "GitHub.com testing"}, nil
+		}
+		defer func() { db.Mocks.ExternalServices.GetByID = nil }()
+
+		var got api.ExternalService
+		repoupdater.MockExternalServiceDryRun = func(_ context.Context, svc api.ExternalService) (*protocol.ExternalServiceDryRunResult, error) {
+			got = svc
+			return &protocol.ExternalServiceDryRunResult{
+				Added:    []api.RepoName{"github.com/foo/new"},
+				Deleted:  []api.RepoName{"github.com/foo/old", "github.com/foo/older"},
+				Renamed:  []protocol.RepoRename{{From: "github.com/foo/before", To: "github.com/foo/after"}},
+				Modified: []api.RepoName{},
+			}, nil
+		}
+		defer func() { repoupdater.MockExternalServiceDryRun = nil }()
+
+		gqltesting.RunTests(t, []*gqltesting.Test{
+			{
+				Schema: mustParseGraphQLSchema(t),
+				Query: `
+					mutation($config: String!) {
+						dryRunExternalService(input: {id: "RXh0ZXJuYWxTZXJ2aWNlOjE=", config: $config}) {
+							added
+							deleted
+							renamed {
+								from
+								to
+							}
+							modified
+						}
+					}
+				`,
+				Variables: map[string]interface{}{"config": config},
+				ExpectedResult: `
+					{
+						"dryRunExternalService": {
+							"added": ["github.com/foo/new"],
+							"deleted": ["github.com/foo/old", "github.com/foo/older"],
+							"renamed": [
+								{
+									"from": "github.com/foo/before",
+									"to": "github.com/foo/after"
+								}
+							],
+							"modified": []
+						}
+					}
+				`,
+			},
+		})
+
+		want := api.ExternalService{ID: 1, Kind: "GITHUB", DisplayName: "GitHub.com testing", Config: config}
+		if got != want {
+			t.Errorf("got external service %+v, want %+v", got, want)
+		}
+	})
+}
+
+func TestConfirmPendingRepositoryDeletions(t *testing.T) {
+	resetMocks()
+	t.Run("authenticated as non-site-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: false}, nil
+		}
+		defer func() { db.Mocks.Users.GetByCurrentAuthUser = nil }()
+
+		repoupdater.MockDeletePendingRepos = func(_ context.Context) (*protocol.DeletePendingReposResponse, error) {
+			t.Fatal("deleted pending repos")
+			return nil, nil
+		}
+		defer func() { repoupdater.MockDeletePendingRepos = nil }()
+
+		result, err := (&schemaResolver{}).ConfirmPendingRepositoryDeletions(context.Background())
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("got err %v, want %v", err, want)
+		}
+		if result != nil {
+			t.Errorf("got result %v, want nil", result)
+		}
+	})
+
+	t.Run("authenticated as site-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		defer func() { db.Mocks.Users.GetByCurrentAuthUser = nil }()
+
+		called := false
+		repoupdater.MockDeletePendingRepos = func(_ context.Context) (*protocol.DeletePendingReposResponse, error) {
+			called = true
+			return &protocol.DeletePendingReposResponse{Deleted: []api.RepoName{"github.com/foo/old"}}, nil
+		}
+		defer func() { repoupdater.MockDeletePendingRepos = nil }()
+
+		gqltesting.RunTests(t, []*gqltesting.Test{
+			{
+				Schema: mustParseGraphQLSchema(t),
+				Query: `
+					mutation {
+						confirmPendingRepositoryDeletions {
+							alwaysNil
+						}
+					}
+				`,
+				ExpectedResult: `
+					{
+						"confirmPendingRepositoryDeletions": {
+							"alwaysNil": null
+						}
+					}
+				`,
+			},
+		})
+
+		if !called {
+			t.Error("pending repos weren't deleted")
+		}
+	})
+}
@@ -187,6 +187,22 @@ func reposourceCloneURLToRepoName(ctx context.Context, cloneURL string) (repoNam
 		repoSources = append(repoSources, reposource.Gitolite{GitoliteConnection: c})
 	}
 
+	giteas, err := db.ExternalServices.ListGiteaConnections(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range giteas {
+		repoSources = append(repoSources, reposource.Gitea{GiteaConnection: c})
+	}
+
+	gerrits, err := db.ExternalServices.ListGerritConnections(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range gerrits {
+		repoSources = append(repoSources, reposource.Gerrit{GerritConnection: c})
+	}
+
 	// Fallback for github.com
 	repoSources = append(repoSources, reposource.GitHub{
 		GitHubConnection: &schema.GitHubConnection{Url: "https://github.com"},
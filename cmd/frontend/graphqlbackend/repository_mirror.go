@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
@@ -172,6 +173,93 @@ func (r *updateQueueResolver) Total() int32 {
 	return int32(r.queue.Total)
 }
 
+func (r *repositoryMirrorInfoResolver) Stats(ctx context.Context) (*gitserverRepositoryStatsResolver, error) {
+	// 🚨 SECURITY: Fetch errors may contain details about the code host, so
+	// only site admins may see them.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	stats, err := gitserver.DefaultClient.RepoStats(ctx, r.repository.repo.Name)
+	if err != nil || stats == nil {
+		return nil, err
+	}
+	return &gitserverRepositoryStatsResolver{stats: stats}, nil
+}
+
+type gitserverRepositoryStatsResolver struct {
+	stats *protocol.RepoStats
+}
+
+func (r *gitserverRepositoryStatsResolver) RepositoryName() string {
+	return string(r.stats.Repo)
+}
+
+func (r *gitserverRepositoryStatsResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := backend.Repos.GetByName(ctx, r.stats.Repo)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return NewRepositoryResolver(repo), nil
+}
+
+func (r *gitserverRepositoryStatsResolver) Gitserver() *string {
+	if r.stats.Gitserver == "" {
+		return nil
+	}
+	return &r.stats.Gitserver
+}
+
+func (r *gitserverRepositoryStatsResolver) SizeBytes() float64 {
+	return float64(r.stats.SizeBytes)
+}
+
+func (r *gitserverRepositoryStatsResolver) PackfileCount() int32 {
+	return int32(r.stats.PackfileCount)
+}
+
+func (r *gitserverRepositoryStatsResolver) PackedObjects() float64 {
+	return float64(r.stats.PackedObjects)
+}
+
+func (r *gitserverRepositoryStatsResolver) LooseObjects() float64 {
+	return float64(r.stats.LooseObjects)
+}
+
+func (r *gitserverRepositoryStatsResolver) LooseSizeBytes() float64 {
+	return float64(r.stats.LooseSizeBytes)
+}
+
+func (r *gitserverRepositoryStatsResolver) LastFetchedAt() *DateTime {
+	return DateTimeOrNil(r.stats.LastFetched)
+}
+
+func (r *gitserverRepositoryStatsResolver) LastFetchDurationSeconds() *float64 {
+	if r.stats.LastFetchDuration == 0 {
+		return nil
+	}
+	seconds := r.stats.LastFetchDuration.Seconds()
+	return &seconds
+}
+
+func (r *gitserverRepositoryStatsResolver) LastFetchError() *string {
+	if r.stats.LastFetchError == "" {
+		return nil
+	}
+	return &r.stats.LastFetchError
+}
+
+func (r *gitserverRepositoryStatsResolver) LastGCAt() *DateTime {
+	return DateTimeOrNil(r.stats.LastGC)
+}
+
+func (r *gitserverRepositoryStatsResolver) ClonedAt() *DateTime {
+	return DateTimeOrNil(r.stats.CloneTime)
+}
+
 func (r *schemaResolver) CheckMirrorRepositoryConnection(ctx context.Context, args *struct {
 	Repository *graphql.ID
 	Name       *string
@@ -3,6 +3,10 @@ package graphqlbackend
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
@@ -10,6 +14,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/cmd/query-runner/queryrunnerapi"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 )
 
@@ -59,6 +65,8 @@ func savedSearchByID(ctx context.Context, id graphql.ID) (*savedSearchResolver,
 			UserID:          ss.Config.UserID,
 			OrgID:           ss.Config.OrgID,
 			SlackWebhookURL: ss.Config.SlackWebhookURL,
+			NotifyWebhook:   ss.Config.NotifyWebhook,
+			WebhookURL:      ss.Config.WebhookURL,
 		},
 	}
 	return savedSearch, nil
@@ -97,6 +105,77 @@ func (r savedSearchResolver) OrgID() *graphql.ID {
 }
 func (r savedSearchResolver) SlackWebhookURL() *string { return r.s.SlackWebhookURL }
 
+func (r savedSearchResolver) NotifyWebhook() bool { return r.s.NotifyWebhook }
+
+func (r savedSearchResolver) WebhookURL() *string { return r.s.WebhookURL }
+
+func (r savedSearchResolver) Runs(ctx context.Context, args *struct{ First int32 }) ([]*savedSearchRunResolver, error) {
+	if args.First < 0 {
+		return nil, errors.New("first must be non-negative")
+	}
+	runs, err := db.SavedSearchRuns.List(ctx, r.s.ID, int(args.First))
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*savedSearchRunResolver, 0, len(runs))
+	for _, run := range runs {
+		resolvers = append(resolvers, &savedSearchRunResolver{run: run})
+	}
+	return resolvers, nil
+}
+
+type savedSearchRunResolver struct {
+	run *db.SavedSearchRun
+}
+
+func (r *savedSearchRunResolver) Query() string { return r.run.Query }
+
+func (r *savedSearchRunResolver) ExecutedAt() DateTime { return DateTime{Time: r.run.ExecutedAt} }
+
+func (r *savedSearchRunResolver) DurationMilliseconds() int32 {
+	return int32(r.run.ExecDuration.Milliseconds())
+}
+
+func (r *savedSearchRunResolver) ResultCount() int32 { return int32(r.run.ResultCount) }
+
+func (r *savedSearchRunResolver) LimitHit() bool { return r.run.LimitHit }
+
+func (r *savedSearchRunResolver) Added() []*savedSearchRunResultResolver {
+	return toSavedSearchRunResultResolvers(r.run.Added)
+}
+
+func (r *savedSearchRunResolver) Removed() []*savedSearchRunResultResolver {
+	return toSavedSearchRunResultResolvers(r.run.Removed)
+}
+
+type savedSearchRunResultResolver struct {
+	result api.SavedQueryResult
+}
+
+func toSavedSearchRunResultResolvers(results []api.SavedQueryResult) []*savedSearchRunResultResolver {
+	resolvers := make([]*savedSearchRunResultResolver, 0, len(results))
+	for _, result := range results {
+		resolvers = append(resolvers, &savedSearchRunResultResolver{result: result})
+	}
+	return resolvers
+}
+
+func (r *savedSearchRunResultResolver) Repository() string { return r.result.Repo }
+
+func (r *savedSearchRunResultResolver) Path() *string {
+	if r.result.Path == "" {
+		return nil
+	}
+	return strptr(r.result.Path)
+}
+
+func (r *savedSearchRunResultResolver) Commit() *string {
+	if r.result.Commit == "" {
+		return nil
+	}
+	return strptr(r.result.Commit)
+}
+
 func toSavedSearchResolver(entry types.SavedSearch) *savedSearchResolver {
 	return &savedSearchResolver{entry}
 }
@@ -142,12 +221,15 @@ func (r *schemaResolver) SendSavedSearchTestNotification(ctx context.Context, ar
 }
 
 func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
-	Description string
-	Query       string
-	NotifyOwner bool
-	NotifySlack bool
-	OrgID       *graphql.ID
-	UserID      *graphql.ID
+	Description   string
+	Query         string
+	NotifyOwner   bool
+	NotifySlack   bool
+	OrgID         *graphql.ID
+	UserID        *graphql.ID
+	NotifyWebhook bool
+	WebhookURL    *string
+	WebhookSecret *string
 }) (*savedSearchResolver, error) {
 	var userID, orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to create a saved search for the specified user or org.
@@ -176,14 +258,20 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 	if !queryHasPatternType(args.Query) {
 		return nil, errMissingPatternType
 	}
+	if err := validateSavedSearchWebhookURL(args.NotifyWebhook, args.WebhookURL); err != nil {
+		return nil, err
+	}
 
 	ss, err := db.SavedSearches.Create(ctx, &types.SavedSearch{
-		Description: args.Description,
-		Query:       args.Query,
-		Notify:      args.NotifyOwner,
-		NotifySlack: args.NotifySlack,
-		UserID:      userID,
-		OrgID:       orgID,
+		Description:   args.Description,
+		Query:         args.Query,
+		Notify:        args.NotifyOwner,
+		NotifySlack:   args.NotifySlack,
+		UserID:        userID,
+		OrgID:         orgID,
+		NotifyWebhook: args.NotifyWebhook,
+		WebhookURL:    args.WebhookURL,
+		WebhookSecret: args.WebhookSecret,
 	})
 	if err != nil {
 		return nil, err
@@ -193,13 +281,16 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 }
 
 func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
-	ID          graphql.ID
-	Description string
-	Query       string
-	NotifyOwner bool
-	NotifySlack bool
-	OrgID       *graphql.ID
-	UserID      *graphql.ID
+	ID            graphql.ID
+	Description   string
+	Query         string
+	NotifyOwner   bool
+	NotifySlack   bool
+	OrgID         *graphql.ID
+	UserID        *graphql.ID
+	NotifyWebhook *bool
+	WebhookURL    *string
+	WebhookSecret *string
 }) (*savedSearchResolver, error) {
 	var userID, orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to update a saved search for the specified user or org.
@@ -233,6 +324,14 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 	if !queryHasPatternType(args.Query) {
 		return nil, errMissingPatternType
 	}
+	if args.NotifyWebhook != nil {
+		if err := validateSavedSearchWebhookURL(*args.NotifyWebhook, args.WebhookURL); err != nil {
+			return nil, err
+		}
+		if err := db.SavedSearches.UpdateWebhook(ctx, id, *args.NotifyWebhook, args.WebhookURL, args.WebhookSecret); err != nil {
+			return nil, err
+		}
+	}
 
 	ss, err := db.SavedSearches.Update(ctx, &types.SavedSearch{
 		ID:          id,
@@ -287,3 +386,25 @@ func queryHasPatternType(query string) bool {
 }
 
 var errMissingPatternType error = errors.New("a `patternType:` filter is required in the query for all saved searches. `patternType` can be \"literal\" or \"regexp\"")
+
+// validateSavedSearchWebhookURL returns an error if notifyWebhook is set and
+// webhookURL is not an absolute http or https URL, or if webhookURL points at
+// an internal address. query-runner checks the resolved address of host names
+// again when it posts to the webhook.
+func validateSavedSearchWebhookURL(notifyWebhook bool, webhookURL *string) error {
+	if webhookURL == nil || *webhookURL == "" {
+		if notifyWebhook {
+			return errors.New("a webhook URL is required to enable webhook notifications")
+		}
+		return nil
+	}
+	u, err := url.Parse(*webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q (must be an absolute http or https URL)", *webhookURL)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if ip := net.ParseIP(host); (ip != nil && !httpcli.IsPublicIP(ip)) || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("invalid webhook URL %q (must not point at an internal address)", *webhookURL)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
@@ -61,12 +62,15 @@ func TestCreateSavedSearch(t *testing.T) {
 	}
 	userID := MarshalUserID(key)
 	savedSearches, err := (&schemaResolver{}).CreateSavedSearch(ctx, &struct {
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		Description   string
+		Query         string
+		NotifyOwner   bool
+		NotifySlack   bool
+		OrgID         *graphql.ID
+		UserID        *graphql.ID
+		NotifyWebhook bool
+		WebhookURL    *string
+		WebhookSecret *string
 	}{Description: "test query", Query: "test type:diff patternType:regexp", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err != nil {
 		t.Fatal(err)
@@ -91,12 +95,15 @@ func TestCreateSavedSearch(t *testing.T) {
 
 	// Ensure create saved search errors when patternType is not provided in the query.
 	_, err = (&schemaResolver{}).CreateSavedSearch(ctx, &struct {
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		Description   string
+		Query         string
+		NotifyOwner   bool
+		NotifySlack   bool
+		OrgID         *graphql.ID
+		UserID        *graphql.ID
+		NotifyWebhook bool
+		WebhookURL    *string
+		WebhookSecret *string
 	}{Description: "test query", Query: "test type:diff", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err == nil {
 		t.Error("Expected error for createSavedSearch when query does not provide a patternType: field.")
@@ -119,13 +126,16 @@ func TestUpdateSavedSearch(t *testing.T) {
 	}
 	userID := MarshalUserID(key)
 	savedSearches, err := (&schemaResolver{}).UpdateSavedSearch(ctx, &struct {
-		ID          graphql.ID
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		ID            graphql.ID
+		Description   string
+		Query         string
+		NotifyOwner   bool
+		NotifySlack   bool
+		OrgID         *graphql.ID
+		UserID        *graphql.ID
+		NotifyWebhook *bool
+		WebhookURL    *string
+		WebhookSecret *string
 	}{ID: marshalSavedSearchID(key), Description: "updated query description", Query: "test type:diff patternType:regexp", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err != nil {
 		t.Fatal(err)
@@ -151,13 +161,16 @@ func TestUpdateSavedSearch(t *testing.T) {
 
 	// Ensure update saved search errors when patternType is not provided in the query.
 	_, err = (&schemaResolver{}).UpdateSavedSearch(ctx, &struct {
-		ID          graphql.ID
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		ID            graphql.ID
+		Description   string
+		Query         string
+		NotifyOwner   bool
+		NotifySlack   bool
+		OrgID         *graphql.ID
+		UserID        *graphql.ID
+		NotifyWebhook *bool
+		WebhookURL    *string
+		WebhookSecret *string
 	}{ID: marshalSavedSearchID(key), Description: "updated query description", Query: "test type:diff", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err == nil {
 		t.Error("Expected error for updateSavedSearch when query does not provide a patternType: field.")
@@ -195,3 +208,71 @@ func TestDeleteSavedSearch(t *testing.T) {
 		t.Errorf("Database method db.SavedSearches.Delete not called")
 	}
 }
+
+func TestSavedSearchRuns(t *testing.T) {
+	ctx := context.Background()
+	defer resetMocks()
+
+	executedAt := time.Now()
+	db.Mocks.SavedSearchRuns.List = func(ctx context.Context, savedSearchID int32, limit int) ([]*db.SavedSearchRun, error) {
+		if savedSearchID != 1 || limit != 10 {
+			t.Errorf("got savedSearchID %d limit %d, want 1 and 10", savedSearchID, limit)
+		}
+		return []*db.SavedSearchRun{{
+			SavedSearchID: savedSearchID,
+			Query:         "test type:diff",
+			ExecutedAt:    executedAt,
+			ExecDuration:  1500 * time.Millisecond,
+			ResultCount:   2,
+			Added:         []api.SavedQueryResult{{Repo: "r", Commit: "deadbeef"}},
+			Removed:       []api.SavedQueryResult{{Repo: "r", Path: "a.go", LineHash: "abc"}},
+		}}, nil
+	}
+
+	runs, err := savedSearchResolver{types.SavedSearch{ID: 1}}.Runs(ctx, &struct{ First int32 }{First: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("got %d runs, want 1", len(runs))
+	}
+	run := runs[0]
+	if run.ExecutedAt().Time != executedAt || run.DurationMilliseconds() != 1500 || run.ResultCount() != 2 {
+		t.Errorf("unexpected run %+v", run.run)
+	}
+	added, removed := run.Added(), run.Removed()
+	if len(added) != 1 || added[0].Repository() != "r" || added[0].Path() != nil || *added[0].Commit() != "deadbeef" {
+		t.Errorf("unexpected added results %+v", added)
+	}
+	if len(removed) != 1 || *removed[0].Path() != "a.go" || removed[0].Commit() != nil {
+		t.Errorf("unexpected removed results %+v", removed)
+	}
+}
+
+func TestValidateSavedSearchWebhookURL(t *testing.T) {
+	tests := []struct {
+		notifyWebhook bool
+		webhookURL    *string
+		wantErr       bool
+	}{
+		{notifyWebhook: false, webhookURL: nil},
+		{notifyWebhook: true, webhookURL: nil, wantErr: true},
+		{notifyWebhook: true, webhookURL: strptr(""), wantErr: true},
+		{notifyWebhook: true, webhookURL: strptr("https://example.com/hook")},
+		{notifyWebhook: false, webhookURL: strptr("http://example.com/hook")},
+		{notifyWebhook: true, webhookURL: strptr("ftp://example.com/hook"), wantErr: true},
+		{notifyWebhook: true, webhookURL: strptr("/hook"), wantErr: true},
+		{notifyWebhook: true, webhookURL: strptr("http://localhost:3080/hook"), wantErr: true},
+		{notifyWebhook: true, webhookURL: strptr("http://127.0.0.1/hook"), wantErr: true},
+		{notifyWebhook: true, webhookURL: strptr("http://[::1]/hook"), wantErr: true},
+		{notifyWebhook: true, webhookURL: strptr("http://10.0.0.1/hook"), wantErr: true},
+		{notifyWebhook: true, webhookURL: strptr("http://169.254.169.254/latest/meta-data"), wantErr: true},
+		{notifyWebhook: true, webhookURL: strptr("https://93.184.216.34/hook")},
+	}
+	for _, test := range tests {
+		err := validateSavedSearchWebhookURL(test.notifyWebhook, test.webhookURL)
+		if (err != nil) != test.wantErr {
+			t.Errorf("notifyWebhook=%v webhookURL=%v: got error %v, want error: %v", test.notifyWebhook, test.webhookURL, err, test.wantErr)
+		}
+	}
+}
@@ -105,6 +105,16 @@ type Mutation {
     updateExternalService(input: UpdateExternalServiceInput!): ExternalService!
     # Delete an external service. Only site admins may perform this mutation.
     deleteExternalService(externalService: ID!): EmptyResponse!
+    # Lists the repositories of a new or changed external service configuration
+    # and returns the changes syncing it would make to the synced repositories,
+    # without saving the configuration. Use it to review the effects of a
+    # configuration change before saving it. Only site admins may perform this
+    # mutation.
+    dryRunExternalService(input: DryRunExternalServiceInput!): ExternalServiceDryRun!
+    # Deletes the repositories that are pending deletion because a sync would
+    # have deleted more repositories than the repoDeletionSafeguard site
+    # configuration allows. Only site admins may perform this mutation.
+    confirmPendingRepositoryDeletions: EmptyResponse!
     # DEPRECATED: All repositories are accessible or deleted. To prevent a
     # repository from being accessed on Sourcegraph add it to the external
     # service exclude configuration. This mutation will be removed in 3.6.
@@ -374,6 +384,13 @@ type Mutation {
         notifySlack: Boolean!
         orgID: ID
         userID: ID
+        # Whether to post notifications to webhookURL.
+        notifyWebhook: Boolean = false
+        # The http or https URL that webhook notifications are posted to.
+        webhookURL: String
+        # If set, webhook payloads are signed with an HMAC-SHA256 of this secret. The signature is sent in
+        # the X-Sourcegraph-Signature header as "sha256=<hex digest>".
+        webhookSecret: String
     ): SavedSearch!
     # Updates a saved search
     updateSavedSearch(
@@ -384,6 +401,14 @@ type Mutation {
         notifySlack: Boolean!
         orgID: ID
         userID: ID
+        # Whether to post notifications to webhookURL. If null, the webhook settings of the saved search
+        # are left unchanged.
+        notifyWebhook: Boolean
+        # The http or https URL that webhook notifications are posted to.
+        webhookURL: String
+        # If set, webhook payloads are signed with an HMAC-SHA256 of this secret. If null, the existing
+        # secret is left unchanged. An empty string removes the secret.
+        webhookSecret: String
     ): SavedSearch!
     # Deletes a saved search
     deleteSavedSearch(id: ID!): EmptyResponse
@@ -845,6 +870,17 @@ input UpdateExternalServiceInput {
     config: String
 }
 
+# A proposed configuration of a new or existing external service.
+input DryRunExternalServiceInput {
+    # The id of the existing external service whose configuration would
+    # change. Omit it for a new external service.
+    id: ID
+    # The kind of the new external service. Ignored if id is given.
+    kind: ExternalServiceKind
+    # The proposed JSON configuration of the external service.
+    config: String!
+}
+
 # A selection within a file.
 input DiscussionThreadTargetRepoSelectionInput {
     # The line that the selection started on (zero-based, inclusive).
@@ -1616,6 +1652,46 @@ type SavedSearch implements Node {
     orgID: ID
     # The Slack webhook URL associated with this saved search, if any.
     slackWebhookURL: String
+    # Whether or not to post notifications to the webhook URL.
+    notifyWebhook: Boolean!
+    # The URL that webhook notifications are posted to, if any.
+    webhookURL: String
+    # The most recent runs of this saved search by the query runner, newest first.
+    runs(
+        # Returns the first n runs from the list.
+        first: Int = 10
+    ): [SavedSearchRun!]!
+}
+
+# A single run of a saved search by the query runner. Notifications for a run only report the results
+# that appeared or disappeared since the previous run.
+type SavedSearchRun {
+    # The query that was run.
+    query: String!
+    # The time at which the query was run.
+    executedAt: DateTime!
+    # The time it took to run the query, in milliseconds.
+    durationMilliseconds: Int!
+    # The number of results found.
+    resultCount: Int!
+    # Whether the search hit its result limit, so that the run's results are incomplete.
+    limitHit: Boolean!
+    # The results that appeared since the previous run. This is empty if this run or the previous run
+    # hit its result limit, since results that were beyond the limit can't be told apart from new results.
+    added: [SavedSearchRunResult!]!
+    # The results that disappeared since the previous run. This is empty if the search hit its result
+    # limit, since results beyond the limit can't be told apart from removed results.
+    removed: [SavedSearchRunResult!]!
+}
+
+# A search result found by a saved search run.
+type SavedSearchRunResult {
+    # The name of the repository the result was found in.
+    repository: String!
+    # The path of the matched file, if the result is a file or line match.
+    path: String
+    # The OID of the matched commit, if the result is a commit or diff match.
+    commit: String
 }
 
 # A search query description.
@@ -1727,6 +1803,8 @@ enum ExternalServiceKind {
     AWSCODECOMMIT
     BITBUCKETCLOUD
     BITBUCKETSERVER
+    GERRIT
+    GITEA
     GITHUB
     GITLAB
     GITOLITE
@@ -1756,6 +1834,31 @@ type ExternalService implements Node {
     warning: String
 }
 
+# The changes syncing an external service with a proposed configuration would
+# make to the synced repositories.
+type ExternalServiceDryRun {
+    # The names of the repositories that would be added.
+    added: [String!]!
+    # The names of the repositories that would be deleted.
+    deleted: [String!]!
+    # The names of the repositories that would be marked as pending deletion
+    # instead of being deleted, because the sync would delete more repositories
+    # than the repoDeletionSafeguard site configuration allows.
+    quarantined: [String!]!
+    # The repositories that would be renamed.
+    renamed: [RepositoryRename!]!
+    # The names of the other repositories whose metadata would change.
+    modified: [String!]!
+}
+
+# A repository that would be renamed by a sync.
+type RepositoryRename {
+    # The current name of the repository.
+    from: String!
+    # The new name of the repository.
+    to: String!
+}
+
 # A list of repositories.
 type RepositoryConnection {
     # A list of repositories.
@@ -1981,6 +2084,39 @@ type MirrorRepositoryInfo {
     updateSchedule: UpdateSchedule
     # The state of this repository in the update queue.
     updateQueue: UpdateQueue
+    # Statistics about the clone of this repository on gitserver, or null if it is not cloned. Only site
+    # admins may access this field.
+    stats: GitserverRepositoryStats
+}
+
+# Statistics about the clone of a repository on gitserver.
+type GitserverRepositoryStats {
+    # The name of the repository.
+    repositoryName: String!
+    # The repository, or null if it no longer exists or the viewer can't access it.
+    repository: Repository
+    # The address of the gitserver holding the clone. Only set in site.gitserverRepositoryStats.
+    gitserver: String
+    # The disk space used by the clone, in bytes.
+    sizeBytes: Float!
+    # The number of packfiles.
+    packfileCount: Int!
+    # The number of objects in packfiles.
+    packedObjects: Float!
+    # The number of loose objects, which aren't in packfiles.
+    looseObjects: Float!
+    # The disk space used by loose objects, in bytes.
+    looseSizeBytes: Float!
+    # When the repository was last fetched from the remote source repository.
+    lastFetchedAt: DateTime
+    # How long the last fetch from the remote source repository took, in seconds.
+    lastFetchDurationSeconds: Float
+    # The error of the last fetch from the remote source repository, or null if it succeeded.
+    lastFetchError: String
+    # When git gc last repacked the objects of the clone, or null if it never did.
+    lastGCAt: DateTime
+    # When the repository was cloned. Repositories are periodically recloned.
+    clonedAt: DateTime
 }
 
 # The state of a repository in the update schedule.
@@ -2884,6 +3020,19 @@ type FileMatch {
     symbols: [Symbol!]!
     # The line matches.
     lineMatches: [LineMatch!]!
+    # The matches of a pattern that can match text spanning several lines (such as a regular
+    # expression containing \n or (?s)). Each of these matches is also reported per line in
+    # lineMatches. Empty for other patterns.
+    multilineMatches: [MultilineMatch!]!
+    # The line matches together with the surrounding lines requested by the "context:",
+    # "contextbefore:" and "contextafter:" query fields. Overlapping ranges are merged. Empty
+    # unless context lines were requested.
+    contextRanges: [ContextRange!]!
+    # The searched revisions of the repository that contain this file with the same content, when the
+    # search covers several revisions of the repository (such as repo:foo@*refs/heads/release/*). The
+    # match is only reported once, for the first of these revisions. Empty when a single revision of
+    # the repository was searched.
+    revisions: [String!]!
     # Whether or not the limit was hit.
     limitHit: Boolean!
 }
@@ -2900,6 +3049,22 @@ type LineMatch {
     limitHit: Boolean!
 }
 
+# A match that may span several lines.
+type MultilineMatch {
+    # The preview, which contains all lines spanned by the match.
+    preview: String!
+    # The range of the match in the file. Character offsets are measured in characters (not bytes).
+    range: Range!
+}
+
+# A run of consecutive lines of a file around one or more line matches.
+type ContextRange {
+    # The 0-based line number of the first line of the range.
+    startLine: Int!
+    # The content of the lines in the range.
+    preview: String!
+}
+
 # A hunk.
 type Hunk {
     # The startLine.
@@ -3650,6 +3815,38 @@ type Site implements SettingsSubject {
         # Months of history (based on current UTC time).
         months: Int
     ): CodeIntelUsageStatistics!
+    # Repositories which gitservers recently removed to free up disk space, most recent first. Each
+    # gitserver keeps its 100 most recent evictions. Only visible to site admins.
+    gitserverEvictions: [GitserverEviction!]!
+    # The repositories with the largest clones on gitservers, largest first, with statistics about their
+    # clones. A replicated repository is listed once per gitserver holding a clone of it. Only visible to
+    # site admins.
+    gitserverRepositoryStats(
+        # Returns the first n repositories.
+        first: Int = 20
+    ): [GitserverRepositoryStats!]!
+}
+
+# A repository which a gitserver removed to free up disk space. Gitservers remove the least valuable
+# repositories first, based on how frequently and recently they were used and their size. Repositories
+# in the gitServerPinnedRepos site configuration are never removed.
+type GitserverEviction {
+    # The name of the repository.
+    repositoryName: String!
+    # The repository, or null if it no longer exists or the viewer can't access it.
+    repository: Repository
+    # The address of the gitserver which removed the repository.
+    gitserver: String!
+    # When the repository was removed.
+    evictedAt: DateTime!
+    # Why the repository was removed.
+    reason: String!
+    # The disk space freed, in bytes.
+    sizeBytes: Float!
+    # When the repository was last fetched or used.
+    lastUsedAt: DateTime!
+    # The number of recent accesses of the repository, where older accesses count less.
+    accessScore: Float!
 }
 
 # The configuration for a site.
@@ -78,6 +78,16 @@ func NewSearchImplementer(args *SearchArgs) (SearchImplementer, error) {
 		return nil, errors.New("Structural search is disabled in the site configuration.")
 	}
 
+	if conf.AndOrQueryEnabled() && query.ContainsAndOrKeyword(args.Query) {
+		andOrQuery, err := query.ProcessAndOr(args.Query)
+		if err != nil {
+			return alertForQuery(args.Query, err), nil
+		}
+		if query.ContainsAndOrOperator(andOrQuery) {
+			return newAndOrSearchResolver(args, andOrQuery, searchType)
+		}
+	}
+
 	var queryString string
 	if searchType == query.SearchTypeLiteral {
 		queryString = query.ConvertToLiteral(args.Query)
@@ -176,6 +186,7 @@ func detectSearchType(version string, patternType *string, input string) (query.
 type searchResolver struct {
 	query         *query.Query          // the validated search query
 	parseTree     syntax.ParseTree      // the parsed search query
+	andOrQuery    query.Node            // the boolean query, or nil if the query does not use boolean operators
 	originalQuery string                // the raw string of the original search query
 	pagination    *searchPaginationInfo // pagination information, or nil if the request is not paginated.
 	patternType   query.SearchType
@@ -297,6 +308,11 @@ func (r *searchResolver) resolveRepositories(ctx context.Context, effectiveRepoF
 
 	commitAfter, _ := r.query.StringValue(query.FieldRepoHasCommitAfter)
 
+	topics, minusTopics := r.query.StringValues(query.FieldRepoTopic)
+	languages, minusLanguages := r.query.StringValues(query.FieldRepoLang)
+	visibility := r.query.Visibility()
+	minStars, maxStars := r.query.Stars()
+
 	tr.LazyPrintf("resolveRepositories - start")
 	repoRevs, missingRepoRevs, overLimit, err = resolveRepositories(ctx, resolveRepoOp{
 		repoFilters:      repoFilters,
@@ -306,6 +322,14 @@ func (r *searchResolver) resolveRepositories(ctx context.Context, effectiveRepoF
 		noForks:          fork == No || fork == False,
 		onlyArchived:     archived == Only || archived == True,
 		noArchived:       archived == No || archived == False,
+		onlyPrivate:      visibility == query.VisibilityPrivate,
+		noPrivate:        visibility == query.VisibilityPublic,
+		topics:           topics,
+		minusTopics:      minusTopics,
+		languages:        languages,
+		minusLanguages:   minusLanguages,
+		minStars:         minStars,
+		maxStars:         maxStars,
 		commitAfter:      commitAfter,
 	})
 	tr.LazyPrintf("resolveRepositories - done")
@@ -419,6 +443,14 @@ type resolveRepoOp struct {
 	onlyForks        bool
 	noArchived       bool
 	onlyArchived     bool
+	noPrivate        bool
+	onlyPrivate      bool
+	topics           []string
+	minusTopics      []string
+	languages        []string
+	minusLanguages   []string
+	minStars         *int
+	maxStars         *int
 	commitAfter      string
 }
 
@@ -492,11 +524,19 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 			IncludePatterns: includePatterns,
 			ExcludePattern:  unionRegExps(excludePatterns),
 			// List N+1 repos so we can see if there are repos omitted due to our repo limit.
-			LimitOffset:  &db.LimitOffset{Limit: maxRepoListSize + 1},
-			NoForks:      op.noForks,
-			OnlyForks:    op.onlyForks,
-			NoArchived:   op.noArchived,
-			OnlyArchived: op.onlyArchived,
+			LimitOffset:      &db.LimitOffset{Limit: maxRepoListSize + 1},
+			NoForks:          op.noForks,
+			OnlyForks:        op.onlyForks,
+			NoArchived:       op.noArchived,
+			OnlyArchived:     op.onlyArchived,
+			NoPrivate:        op.noPrivate,
+			OnlyPrivate:      op.onlyPrivate,
+			Topics:           op.topics,
+			ExcludeTopics:    op.minusTopics,
+			Languages:        op.languages,
+			ExcludeLanguages: op.minusLanguages,
+			MinStars:         op.minStars,
+			MaxStars:         op.maxStars,
 		})
 		tr.LazyPrintf("Repos.List - done")
 		if err != nil {
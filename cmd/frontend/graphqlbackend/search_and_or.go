@@ -0,0 +1,593 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// This file contains the evaluation of queries in the boolean query language
+// (see query.ParseAndOr). Subtrees of the query that do not use a boolean
+// operator are evaluated like a regular flat query by doResults. The results
+// of the subtrees are then combined per result: "and" intersects them, "or"
+// takes their union and "not" subtracts them. Results are identified by their
+// file, commit or repository, so for text search the set operations apply
+// per file.
+
+// evaluateAndOr returns the results for the boolean query of r.
+func (r *searchResolver) evaluateAndOr(ctx context.Context, forceOnlyResultType string) (res *SearchResultsResolver, err error) {
+	tr, ctx := trace.New(ctx, "graphql.SearchResults.evaluateAndOr", r.rawQuery())
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// The results of an operand are not results of the whole query, so
+	// don't stream them.
+	ctx = withSearchStream(ctx, nil)
+
+	start := time.Now()
+	res, err = r.evaluate(ctx, nil, r.andOrQuery, forceOnlyResultType)
+	if res != nil {
+		res.start = start
+		if isSelectQuery(r.query) {
+			res.SearchResults = selectResults(ctx, r.query, res.SearchResults)
+			res.resultCount = countResults(res.SearchResults)
+		}
+		sortResults(res.SearchResults)
+	}
+	return res, err
+}
+
+// evaluate returns the results for n, where the parameters in scope apply to
+// all patterns in n.
+func (r *searchResolver) evaluate(ctx context.Context, scope []*query.Parameter, n query.Node, forceOnlyResultType string) (*SearchResultsResolver, error) {
+	if !query.ContainsAndOrOperator(n) {
+		return r.evaluateLeaf(ctx, scope, n, forceOnlyResultType)
+	}
+	if o, ok := n.(*query.Operator); ok {
+		switch o.Kind {
+		case query.Or:
+			return r.evaluateOr(ctx, scope, o.Operands, forceOnlyResultType)
+		case query.And, query.Concat:
+			return r.evaluateAnd(ctx, scope, o, forceOnlyResultType)
+		}
+	}
+	return nil, &badRequestError{fmt.Errorf("the negated term %s must be combined with a positive pattern using \"and\"", n)}
+}
+
+// evaluateLeaf evaluates a query without boolean operators using the regular
+// search code paths.
+func (r *searchResolver) evaluateLeaf(ctx context.Context, scope []*query.Parameter, n query.Node, forceOnlyResultType string) (*SearchResultsResolver, error) {
+	return r.evaluateFlatQuery(ctx, query.FlatQueryString(scope, n), r.patternType, forceOnlyResultType)
+}
+
+func (r *searchResolver) evaluateFlatQuery(ctx context.Context, queryString string, patternType query.SearchType, forceOnlyResultType string) (*SearchResultsResolver, error) {
+	if patternType == query.SearchTypeLiteral {
+		queryString = query.ConvertToLiteral(queryString)
+	}
+	q, p, err := query.Process(queryString, patternType)
+	if err != nil {
+		return &SearchResultsResolver{alert: alertForQuery(queryString, err)}, nil
+	}
+	sub := &searchResolver{
+		query:         q,
+		parseTree:     p,
+		originalQuery: queryString,
+		patternType:   patternType,
+		zoekt:         r.zoekt,
+		searcherURLs:  r.searcherURLs,
+	}
+	return sub.doResults(ctx, forceOnlyResultType)
+}
+
+// evaluateOr returns the union of the results of operands.
+func (r *searchResolver) evaluateOr(ctx context.Context, scope []*query.Parameter, operands []query.Node, forceOnlyResultType string) (*SearchResultsResolver, error) {
+	// A disjunction of plain patterns can be pushed down to the backends
+	// (including Zoekt) as a single regular expression.
+	if pattern, ok := r.unionPatterns(scope, operands); ok {
+		queryString := query.FlatQueryString(scope, nil) + " content:" + strconv.Quote(pattern)
+		return r.evaluateFlatQuery(ctx, strings.TrimSpace(queryString), query.SearchTypeRegex, forceOnlyResultType)
+	}
+
+	results, err := r.evaluateAll(ctx, scope, operands, forceOnlyResultType)
+	if err != nil {
+		return nil, err
+	}
+	union := results[0]
+	for _, other := range results[1:] {
+		union = unionSearchResults(union, other)
+	}
+	return union, nil
+}
+
+// unionPatterns returns a regular expression matching any of operands, if
+// all of them are plain patterns.
+func (r *searchResolver) unionPatterns(scope []*query.Parameter, operands []query.Node) (string, bool) {
+	if r.patternType == query.SearchTypeStructural {
+		return "", false
+	}
+	for _, p := range scope {
+		if p.Field == query.FieldContent {
+			return "", false
+		}
+	}
+	pieces := make([]string, 0, len(operands))
+	for _, operand := range operands {
+		p, ok := operand.(*query.Pattern)
+		if !ok || p.Negated || p.Value == "" || strings.ContainsAny(p.Value[:1], `"'/`) {
+			return "", false
+		}
+		piece := p.Value
+		if r.patternType == query.SearchTypeLiteral {
+			piece = regexp.QuoteMeta(piece)
+		} else if _, err := regexp.Compile(piece); err != nil {
+			// Let the regular evaluation report the error.
+			return "", false
+		}
+		pieces = append(pieces, "(?:"+piece+")")
+	}
+	return strings.Join(pieces, "|"), true
+}
+
+// evaluateAnd returns the intersection of the results of the positive
+// operands of o, minus the results of its negated operands.
+func (r *searchResolver) evaluateAnd(ctx context.Context, scope []*query.Parameter, o *query.Operator, forceOnlyResultType string) (*SearchResultsResolver, error) {
+	scope = scope[:len(scope):len(scope)] // copy on append
+	operands := o.Operands
+	var positive, negative []query.Node
+	for i := 0; i < len(operands); i++ {
+		operand := operands[i]
+		if !query.ContainsPattern(operand) {
+			// Operands without patterns restrict the scope of the patterns.
+			switch p := pushDownNegation(operand).(type) {
+			case *query.Parameter:
+				scope = append(scope, p)
+			case *query.Operator:
+				if p.Kind == query.Or {
+					// (a or b) c is evaluated as (a c) or (b c), where
+					// each alternative keeps the kind of o.
+					rest := make([]query.Node, 0, len(operands)-1)
+					rest = append(rest, operands[:i]...)
+					rest = append(rest, operands[i+1:]...)
+					alternatives := make([]query.Node, len(p.Operands))
+					for j, alternative := range p.Operands {
+						alternatives[j] = &query.Operator{Kind: o.Kind, Operands: append([]query.Node{alternative}, rest...)}
+					}
+					return r.evaluateOr(ctx, scope, alternatives, forceOnlyResultType)
+				}
+				operands = append(operands[:len(operands):len(operands)], p.Operands...)
+			}
+			continue
+		}
+		switch n := operand.(type) {
+		case *query.Pattern:
+			if n.Negated {
+				cpy := *n
+				cpy.Negated = false
+				negative = append(negative, &cpy)
+				continue
+			}
+		case *query.Operator:
+			if n.Kind == query.Not {
+				negative = append(negative, n.Operands[0])
+				continue
+			}
+		}
+		positive = append(positive, operand)
+	}
+
+	if o.Kind == query.Concat {
+		// Keep the flat query semantics for the patterns of a concatenation
+		// which do not use boolean operators.
+		var flat, other []query.Node
+		for _, operand := range positive {
+			if query.ContainsAndOrOperator(operand) {
+				other = append(other, operand)
+			} else {
+				flat = append(flat, operand)
+			}
+		}
+		if len(flat) > 1 {
+			positive = append([]query.Node{&query.Operator{Kind: query.Concat, Operands: flat}}, other...)
+		}
+	}
+
+	if len(positive) == 0 {
+		if len(negative) > 0 {
+			return nil, &badRequestError{fmt.Errorf("the negated term -%s must be combined with a positive pattern using \"and\"", negative[0])}
+		}
+		return r.evaluateLeaf(ctx, scope, nil, forceOnlyResultType)
+	}
+
+	results, err := r.evaluateAll(ctx, scope, append(positive, negative...), forceOnlyResultType)
+	if err != nil {
+		return nil, err
+	}
+	intersection := results[0]
+	for _, other := range results[1:len(positive)] {
+		intersection = intersectSearchResults(intersection, other)
+	}
+	for _, other := range results[len(positive):] {
+		intersection = subtractSearchResults(intersection, other)
+	}
+	return intersection, nil
+}
+
+// evaluateAll evaluates operands concurrently and returns their results in
+// the same order.
+func (r *searchResolver) evaluateAll(ctx context.Context, scope []*query.Parameter, operands []query.Node, forceOnlyResultType string) ([]*SearchResultsResolver, error) {
+	var (
+		wg       sync.WaitGroup
+		results  = make([]*SearchResultsResolver, len(operands))
+		multiErr *multierror.Error
+		mu       sync.Mutex
+	)
+	for i, operand := range operands {
+		i, operand := i, operand
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := r.evaluate(ctx, scope, operand, forceOnlyResultType)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				multiErr = multierror.Append(multiErr, err)
+				return
+			}
+			results[i] = res
+		}()
+	}
+	wg.Wait()
+	if err := multiErr.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// pushDownNegation applies De Morgan's laws to n, which must not contain a
+// pattern, so that only parameters are negated.
+func pushDownNegation(n query.Node) query.Node {
+	o, ok := n.(*query.Operator)
+	if !ok {
+		return n
+	}
+	if o.Kind != query.Not {
+		operands := make([]query.Node, len(o.Operands))
+		for i, operand := range o.Operands {
+			operands[i] = pushDownNegation(operand)
+		}
+		return &query.Operator{Kind: o.Kind, Operands: operands}
+	}
+	switch negated := o.Operands[0].(type) {
+	case *query.Parameter:
+		cpy := *negated
+		cpy.Negated = !cpy.Negated
+		return &cpy
+	case *query.Operator:
+		if negated.Kind == query.Not {
+			return pushDownNegation(negated.Operands[0])
+		}
+		kind := query.Or
+		if negated.Kind == query.Or {
+			kind = query.And
+		}
+		operands := make([]query.Node, len(negated.Operands))
+		for i, operand := range negated.Operands {
+			operands[i] = pushDownNegation(&query.Operator{Kind: query.Not, Operands: []query.Node{operand}})
+		}
+		return &query.Operator{Kind: kind, Operands: operands}
+	}
+	return n
+}
+
+// searchResultKey identifies a search result across the results of different
+// queries.
+func searchResultKey(r SearchResultResolver) string {
+	switch r := r.(type) {
+	case *FileMatchResolver:
+		return "file:" + r.uri
+	case *RepositoryResolver:
+		return "repo:" + string(r.repo.Name)
+	case *commitSearchResultResolver:
+		return "commit:" + r.url
+	case *codemodResultResolver:
+		repo, file := r.searchResultURIs()
+		return "codemod:" + repo + "/" + file
+	}
+	panic(fmt.Sprintf("unexpected search result type %T", r))
+}
+
+// unionSearchResults returns the results that are in a or b. File matches in
+// both have their line matches and symbols merged.
+func unionSearchResults(a, b *SearchResultsResolver) *SearchResultsResolver {
+	if a.alert != nil && len(a.SearchResults) == 0 {
+		return combineSearchResults(b, a, b.SearchResults)
+	}
+	results := append([]SearchResultResolver{}, a.SearchResults...)
+	byKey := make(map[string]SearchResultResolver, len(results))
+	for _, r := range results {
+		byKey[searchResultKey(r)] = r
+	}
+	for _, r := range b.SearchResults {
+		if existing, ok := byKey[searchResultKey(r)]; ok {
+			mergeSearchResult(existing, r)
+			continue
+		}
+		results = append(results, r)
+	}
+	return combineSearchResults(a, b, results)
+}
+
+// intersectSearchResults returns the results that are in both a and b.
+func intersectSearchResults(a, b *SearchResultsResolver) *SearchResultsResolver {
+	byKey := make(map[string]SearchResultResolver, len(b.SearchResults))
+	for _, r := range b.SearchResults {
+		byKey[searchResultKey(r)] = r
+	}
+	var results []SearchResultResolver
+	for _, r := range a.SearchResults {
+		if other, ok := byKey[searchResultKey(r)]; ok {
+			mergeSearchResult(r, other)
+			results = append(results, r)
+		}
+	}
+	return combineSearchResults(a, b, results)
+}
+
+// subtractSearchResults returns the results that are in a but not in b.
+func subtractSearchResults(a, b *SearchResultsResolver) *SearchResultsResolver {
+	exclude := make(map[string]struct{}, len(b.SearchResults))
+	for _, r := range b.SearchResults {
+		exclude[searchResultKey(r)] = struct{}{}
+	}
+	var results []SearchResultResolver
+	for _, r := range a.SearchResults {
+		if _, ok := exclude[searchResultKey(r)]; !ok {
+			results = append(results, r)
+		}
+	}
+	res := combineSearchResults(a, b, results)
+	// Only the results of a are returned, so the match count of b does not
+	// contribute to the limit.
+	res.searchResultsCommon.limitHit = a.searchResultsCommon.limitHit || b.searchResultsCommon.LimitHit()
+	return res
+}
+
+// combineSearchResults returns a resolver for results, with the statistics
+// and alerts of a and b.
+func combineSearchResults(a, b *SearchResultsResolver, results []SearchResultResolver) *SearchResultsResolver {
+	common := searchResultsCommon{maxResultsCount: a.maxResultsCount}
+	common.update(a.searchResultsCommon)
+	common.update(b.searchResultsCommon)
+	common.limitHit = a.LimitHit() || b.LimitHit()
+	common.resultCount = 0
+	for _, r := range results {
+		common.resultCount += r.resultCount()
+	}
+	if common.maxResultsCount < b.maxResultsCount {
+		common.maxResultsCount = b.maxResultsCount
+	}
+
+	alert := a.alert
+	if alert == nil {
+		alert = b.alert
+	}
+	return &SearchResultsResolver{
+		SearchResults:       results,
+		searchResultsCommon: common,
+		alert:               alert,
+	}
+}
+
+// mergeSearchResult merges the matches of src into dst, which must identify
+// the same result.
+func mergeSearchResult(dst, src SearchResultResolver) {
+	dstFile, ok := dst.(*FileMatchResolver)
+	if !ok {
+		return
+	}
+	srcFile, ok := src.(*FileMatchResolver)
+	if !ok {
+		return
+	}
+	dstFile.JLimitHit = dstFile.JLimitHit || srcFile.JLimitHit
+
+	lines := make(map[int32]*lineMatch, len(dstFile.JLineMatches))
+	for _, lm := range dstFile.JLineMatches {
+		lines[lm.JLineNumber] = lm
+	}
+	for _, lm := range srcFile.JLineMatches {
+		existing, ok := lines[lm.JLineNumber]
+		if !ok {
+			dstFile.JLineMatches = append(dstFile.JLineMatches, lm)
+			lines[lm.JLineNumber] = lm
+			continue
+		}
+		existing.JLimitHit = existing.JLimitHit || lm.JLimitHit
+		existing.JOffsetAndLengths = mergeOffsetAndLengths(existing.JOffsetAndLengths, lm.JOffsetAndLengths)
+	}
+	sortLineMatches(dstFile.JLineMatches)
+
+	multilineMatches := make(map[multilineMatch]struct{}, len(dstFile.JMultilineMatches))
+	for _, mm := range dstFile.JMultilineMatches {
+		multilineMatches[*mm] = struct{}{}
+	}
+	for _, mm := range srcFile.JMultilineMatches {
+		if _, ok := multilineMatches[*mm]; !ok {
+			dstFile.JMultilineMatches = append(dstFile.JMultilineMatches, mm)
+			multilineMatches[*mm] = struct{}{}
+		}
+	}
+	dstFile.JContextRanges = mergeContextRanges(dstFile.JContextRanges, srcFile.JContextRanges)
+
+	for _, rev := range srcFile.revisions {
+		found := false
+		for _, existing := range dstFile.revisions {
+			if existing == rev {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dstFile.revisions = append(dstFile.revisions, rev)
+		}
+	}
+	sort.Strings(dstFile.revisions)
+
+	type symbolKey struct {
+		name, kind string
+		line       int
+	}
+	symbols := make(map[symbolKey]struct{}, len(dstFile.symbols))
+	for _, s := range dstFile.symbols {
+		symbols[symbolKey{s.symbol.Name, s.symbol.Kind, s.symbol.Line}] = struct{}{}
+	}
+	for _, s := range srcFile.symbols {
+		if _, ok := symbols[symbolKey{s.symbol.Name, s.symbol.Kind, s.symbol.Line}]; !ok {
+			dstFile.symbols = append(dstFile.symbols, s)
+		}
+	}
+}
+
+// mergeOffsetAndLengths returns the sorted union of the highlighted ranges a
+// and b.
+func mergeOffsetAndLengths(a, b [][2]int32) [][2]int32 {
+	seen := make(map[[2]int32]struct{}, len(a))
+	merged := append([][2]int32{}, a...)
+	for _, ol := range a {
+		seen[ol] = struct{}{}
+	}
+	for _, ol := range b {
+		if _, ok := seen[ol]; !ok {
+			merged = append(merged, ol)
+			seen[ol] = struct{}{}
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i][0] < merged[j][0]
+	})
+	return merged
+}
+
+// mergeContextRanges returns the union of the context ranges a and b of the
+// same file, merging ranges that overlap or touch.
+func mergeContextRanges(a, b []*contextRange) []*contextRange {
+	if len(b) == 0 {
+		return a
+	}
+	lines := map[int32]string{}
+	for _, cr := range append(append([]*contextRange{}, a...), b...) {
+		for i, line := range strings.Split(cr.JPreview, "\n") {
+			lines[cr.JStartLine+int32(i)] = line
+		}
+	}
+	numbers := make([]int32, 0, len(lines))
+	for n := range lines {
+		numbers = append(numbers, n)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	var merged []*contextRange
+	for i, n := range numbers {
+		if i > 0 && numbers[i-1] == n-1 {
+			last := merged[len(merged)-1]
+			last.JPreview += "\n" + lines[n]
+			continue
+		}
+		merged = append(merged, &contextRange{JStartLine: n, JPreview: lines[n]})
+	}
+	return merged
+}
+
+func sortLineMatches(lineMatches []*lineMatch) {
+	sort.Slice(lineMatches, func(i, j int) bool {
+		return lineMatches[i].JLineNumber < lineMatches[j].JLineNumber
+	})
+}
+
+// newAndOrSearchResolver returns a resolver for a query in the boolean query
+// language.
+func newAndOrSearchResolver(args *SearchArgs, andOrQuery query.Node, searchType query.SearchType) (SearchImplementer, error) {
+	if args.First != nil || args.After != nil {
+		return nil, errors.New(`Search: paginated requests are not supported for queries using "and", "or" or "not"`)
+	}
+
+	// "select:" applies to the results of the whole query, so the operands
+	// are evaluated without it.
+	andOrQuery, selectParam := withoutSelect(andOrQuery)
+
+	// The parameters that apply to the whole query are the typechecked query
+	// of the resolver, e.g. for suggestions.
+	var scope []*query.Parameter
+	if o, ok := andOrQuery.(*query.Operator); ok && (o.Kind == query.And || o.Kind == query.Concat) {
+		for _, operand := range o.Operands {
+			if p, ok := operand.(*query.Parameter); ok {
+				scope = append(scope, p)
+			}
+		}
+	}
+	if selectParam != nil {
+		scope = append(scope, selectParam)
+	}
+	q, err := query.ParseAndCheck(query.FlatQueryString(scope, nil))
+	if err != nil {
+		return alertForQuery(args.Query, err), nil
+	}
+
+	return &searchResolver{
+		query:         q,
+		andOrQuery:    andOrQuery,
+		originalQuery: args.Query,
+		patternType:   searchType,
+		zoekt:         search.Indexed(),
+		searcherURLs:  search.SearcherURLs(),
+	}, nil
+}
+
+// withoutSelect returns n without its "select:" parameter, and the
+// parameter. The parameter may only be combined with the rest of the query
+// using "and" (see query.CheckAndOr).
+func withoutSelect(n query.Node) (query.Node, *query.Parameter) {
+	switch n := n.(type) {
+	case *query.Parameter:
+		if n.Field == query.FieldSelect {
+			return nil, n
+		}
+	case *query.Operator:
+		if n.Kind != query.And && n.Kind != query.Concat {
+			return n, nil
+		}
+		var selectParam *query.Parameter
+		operands := make([]query.Node, 0, len(n.Operands))
+		for _, operand := range n.Operands {
+			operand, p := withoutSelect(operand)
+			if p != nil {
+				selectParam = p
+			}
+			if operand != nil {
+				operands = append(operands, operand)
+			}
+		}
+		switch len(operands) {
+		case 0:
+			return nil, selectParam
+		case 1:
+			return operands[0], selectParam
+		}
+		return &query.Operator{Kind: n.Kind, Operands: operands}, selectParam
+	}
+	return n, nil
+}
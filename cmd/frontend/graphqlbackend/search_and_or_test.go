@@ -0,0 +1,206 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSearchResults_AndOr(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExperimentalFeatures: &schema.ExperimentalFeatures{AndOrQuery: "enabled"},
+	}})
+	defer conf.Mock(nil)
+
+	db.Mocks.Repos.List = func(_ context.Context, op db.ReposListOptions) ([]*types.Repo, error) {
+		return []*types.Repo{{ID: 1, Name: "repo"}}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+	db.Mocks.Repos.MockGetByName(t, "repo", 1)
+	db.Mocks.Repos.MockGet(t, 1)
+
+	mockSearchRepositories = func(args *search.TextParameters) ([]SearchResultResolver, *searchResultsCommon, error) {
+		return nil, &searchResultsCommon{}, nil
+	}
+	defer func() { mockSearchRepositories = nil }()
+
+	// The files that contain each pattern, with the line number of the match.
+	files := map[string]map[string]int32{
+		"foo":     {"a.go": 1, "b.go": 2, "c.rs": 3},
+		"bar":     {"b.go": 4, "c.rs": 5},
+		"baz":     {"c.rs": 6},
+		"foo bar": {"d.go": 7, "e.rs": 8},
+	}
+	var (
+		mu       sync.Mutex
+		patterns []string
+	)
+	mockSearchFilesInRepos = func(args *search.TextParameters) ([]*FileMatchResolver, *searchResultsCommon, error) {
+		mu.Lock()
+		patterns = append(patterns, args.PatternInfo.Pattern)
+		mu.Unlock()
+
+		var includePatterns []*regexp.Regexp
+		for _, p := range args.PatternInfo.IncludePatterns {
+			includePatterns = append(includePatterns, regexp.MustCompile(p))
+		}
+		lines := map[string][]*lineMatch{}
+		for pattern, matches := range files {
+			if ok, _ := regexp.MatchString("^(?:"+args.PatternInfo.Pattern+")$", pattern); !ok {
+				continue
+			}
+		nextFile:
+			for path, line := range matches {
+				for _, p := range includePatterns {
+					if !p.MatchString(path) {
+						continue nextFile
+					}
+				}
+				lines[path] = append(lines[path], &lineMatch{JLineNumber: line})
+			}
+		}
+		var matches []*FileMatchResolver
+		for path, lms := range lines {
+			sortLineMatches(lms)
+			matches = append(matches, &FileMatchResolver{
+				uri:          "git://repo#" + path,
+				JPath:        path,
+				JLineMatches: lms,
+				Repo:         &types.Repo{ID: 1, Name: "repo"},
+			})
+		}
+		return matches, &searchResultsCommon{repos: []*types.Repo{{ID: 1, Name: "repo"}}}, nil
+	}
+	defer func() { mockSearchFilesInRepos = nil }()
+
+	tests := []struct {
+		query        string
+		want         []string
+		wantPatterns []string
+	}{
+		{
+			query:        "foo and bar",
+			want:         []string{"b.go:2,4", "c.rs:3,5"},
+			wantPatterns: []string{"bar", "foo"},
+		},
+		{
+			query:        "foo and not bar",
+			want:         []string{"a.go:1"},
+			wantPatterns: []string{"bar", "foo"},
+		},
+		{
+			query:        "bar or baz",
+			want:         []string{"b.go:4", "c.rs:5,6"},
+			wantPatterns: []string{"(?:bar)|(?:baz)"},
+		},
+		{
+			query:        "(bar or baz) and not foo",
+			want:         nil,
+			wantPatterns: []string{"(?:bar)|(?:baz)", "foo"},
+		},
+		{
+			query:        "(lang:go or lang:rust) bar",
+			want:         []string{"b.go:4", "c.rs:5"},
+			wantPatterns: []string{"bar", "bar"},
+		},
+		{
+			// The alternatives keep the concatenation of foo and bar.
+			query:        "(lang:go or lang:rust) foo bar",
+			want:         []string{"d.go:7", "e.rs:8"},
+			wantPatterns: []string{"foo bar", "foo bar"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			patterns = nil
+			r, err := (&schemaResolver{}).Search(&SearchArgs{Query: tt.query, Version: "V2"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			results, err := r.Results(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if results.alert != nil {
+				t.Fatalf("unexpected alert: %s: %s", results.alert.title, results.alert.description)
+			}
+			var got []string
+			for _, result := range results.SearchResults {
+				fm, ok := result.ToFileMatch()
+				if !ok {
+					t.Fatalf("unexpected result %T", result)
+				}
+				var lines []string
+				for _, lm := range fm.JLineMatches {
+					lines = append(lines, fmt.Sprint(lm.JLineNumber))
+				}
+				got = append(got, fm.JPath+":"+strings.Join(lines, ","))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got results %v, want %v", got, tt.want)
+			}
+			sort.Strings(patterns)
+			if !reflect.DeepEqual(patterns, tt.wantPatterns) {
+				t.Errorf("got patterns %q, want %q", patterns, tt.wantPatterns)
+			}
+		})
+	}
+
+	t.Run("negated term without positive term", func(t *testing.T) {
+		r, err := (&schemaResolver{}).Search(&SearchArgs{Query: "not foo", Version: "V2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		alert, ok := r.(*searchAlert)
+		if !ok {
+			t.Fatalf("got %T, want alert", r)
+		}
+		if want := `The negated term -foo must be combined with a positive term using "and"`; *alert.Description() != want {
+			t.Errorf("got %q, want %q", *alert.Description(), want)
+		}
+	})
+}
+
+func TestPushDownNegation(t *testing.T) {
+	tests := map[string]string{
+		"not repo:a":                   "-repo:a",
+		"not (repo:a or repo:b)":       "(-repo:a and -repo:b)",
+		"not (repo:a and -file:b)":     "(-repo:a or file:b)",
+		"not (not (repo:a or lang:b))": "(repo:a or lang:b)",
+	}
+	for input, want := range tests {
+		n, err := query.ParseAndOr(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := pushDownNegation(n).String(); got != want {
+			t.Errorf("%q: got %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestMergeContextRanges(t *testing.T) {
+	a := []*contextRange{{JStartLine: 0, JPreview: "a\nb"}, {JStartLine: 6, JPreview: "g"}}
+	b := []*contextRange{{JStartLine: 1, JPreview: "b\nc"}, {JStartLine: 4, JPreview: "e"}}
+	got := mergeContextRanges(a, b)
+	want := []*contextRange{
+		{JStartLine: 0, JPreview: "a\nb\nc"},
+		{JStartLine: 4, JPreview: "e"},
+		{JStartLine: 6, JPreview: "g"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
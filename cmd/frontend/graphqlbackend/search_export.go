@@ -0,0 +1,70 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// exportPageSize is the number of results requested per page when exporting
+// the results of a search. It is the largest page size that paginated search
+// allows.
+const exportPageSize = 5000
+
+// ExportSearch runs the search described by args to completion, without a
+// limit on the number of results, and calls fn with each page of results.
+// Searching stops at the first error returned by fn.
+//
+// Like paginated search, it supports text, path and symbol matches, and
+// exports text and path matches if the query has no type: field. The
+// repositories are filtered by the permissions of the actor in ctx.
+func ExportSearch(ctx context.Context, args *SearchArgs, fn func([]StreamMatch) error) error {
+	if args.First != nil || args.After != nil {
+		return errors.New("search export does not support pagination arguments")
+	}
+
+	first := int32(exportPageSize)
+	var after *string
+	for {
+		impl, err := NewSearchImplementer(&SearchArgs{
+			Version:     args.Version,
+			PatternType: args.PatternType,
+			Query:       args.Query,
+			First:       &first,
+			After:       after,
+		})
+		if err != nil {
+			return err
+		}
+		results, err := impl.Results(ctx)
+		if err != nil {
+			return err
+		}
+		if results.cursor == nil {
+			// The search did not run, e.g. because the query is invalid
+			// or matches no repositories. The alert explains why.
+			return alertError(results.Alert())
+		}
+		if len(results.SearchResults) > 0 {
+			if err := fn(StreamMatches(results.SearchResults)); err != nil {
+				return err
+			}
+		}
+
+		pageInfo := results.PageInfo()
+		if !pageInfo.HasNextPage() {
+			return nil
+		}
+		after = pageInfo.EndCursor()
+	}
+}
+
+func alertError(alert *searchAlert) error {
+	if alert == nil {
+		return errors.New("search did not run")
+	}
+	if d := alert.Description(); d != nil {
+		return fmt.Errorf("%s: %s", alert.Title(), *d)
+	}
+	return errors.New(alert.Title())
+}
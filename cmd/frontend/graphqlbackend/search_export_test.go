@@ -0,0 +1,92 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+)
+
+func TestExportSearch(t *testing.T) {
+	repos := []*types.Repo{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+	db.Mocks.Repos.List = func(_ context.Context, op db.ReposListOptions) ([]*types.Repo, error) {
+		return repos, nil
+	}
+	db.Mocks.Repos.Count = func(context.Context, db.ReposListOptions) (int, error) {
+		return len(repos), nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	mockSearchFilesInRepos = func(args *search.TextParameters) ([]*FileMatchResolver, *searchResultsCommon, error) {
+		var matches []*FileMatchResolver
+		for _, r := range args.Repos {
+			matches = append(matches, &FileMatchResolver{
+				uri:          "git://" + string(r.Repo.Name) + "#main.go",
+				JPath:        "main.go",
+				JLineMatches: []*lineMatch{{JPreview: "foo", JLineNumber: 2}},
+				Repo:         r.Repo,
+			})
+		}
+		return matches, &searchResultsCommon{}, nil
+	}
+	defer func() { mockSearchFilesInRepos = nil }()
+
+	mockSearchSymbols = func(_ context.Context, args *search.TextParameters, _ int) ([]*FileMatchResolver, *searchResultsCommon, error) {
+		var matches []*FileMatchResolver
+		for _, r := range args.Repos {
+			matches = append(matches, &FileMatchResolver{
+				uri:     "git://" + string(r.Repo.Name) + "#main.go",
+				JPath:   "main.go",
+				symbols: []*searchSymbolResult{{symbol: protocol.Symbol{Name: "foo", Kind: "func", Line: 3}}},
+				Repo:    r.Repo,
+			})
+		}
+		return matches, &searchResultsCommon{}, nil
+	}
+	defer func() { mockSearchSymbols = nil }()
+
+	export := func(t *testing.T, q string) []StreamMatch {
+		var got []StreamMatch
+		err := ExportSearch(context.Background(), &SearchArgs{Query: q, Version: "V2"}, func(matches []StreamMatch) error {
+			got = append(got, matches...)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Slice(got, func(i, j int) bool { return got[i].Repository < got[j].Repository })
+		return got
+	}
+
+	t.Run("text", func(t *testing.T) {
+		want := []StreamMatch{
+			{Type: "file", Repository: "a", Path: "main.go", LineMatches: []StreamLineMatch{{LineNumber: 2, Preview: "foo"}}},
+			{Type: "file", Repository: "b", Path: "main.go", LineMatches: []StreamLineMatch{{LineNumber: 2, Preview: "foo"}}},
+		}
+		if got := export(t, "foo"); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("symbol", func(t *testing.T) {
+		want := []StreamMatch{
+			{Type: "file", Repository: "a", Path: "main.go", Symbols: []StreamSymbol{{Name: "foo", Kind: "func", Line: 3}}},
+			{Type: "file", Repository: "b", Path: "main.go", Symbols: []StreamSymbol{{Name: "foo", Kind: "func", Line: 3}}},
+		}
+		if got := export(t, "type:symbol foo"); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("unsupported result type", func(t *testing.T) {
+		err := ExportSearch(context.Background(), &SearchArgs{Query: "type:commit foo", Version: "V2"}, func([]StreamMatch) error { return nil })
+		if err == nil {
+			t.Fatal("got nil error, want error")
+		}
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
@@ -132,6 +133,12 @@ func (r *searchResolver) paginatedResults(ctx context.Context) (result *SearchRe
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 
+	if isSelectQuery(r.query) {
+		// Projected results can't be paginated because distinct results may
+		// span pages.
+		return nil, &badRequestError{fmt.Errorf(`Search: paginated requests are not supported for queries using "select:"`)}
+	}
+
 	repos, missingRepoRevs, alertResult, err := r.determineRepos(ctx, tr, start)
 	if err != nil {
 		return nil, err
@@ -162,10 +169,23 @@ func (r *searchResolver) paginatedResults(ctx context.Context) (result *SearchRe
 	}
 
 	resultTypes := r.determineResultTypes(args, "")
+	if types, _ := r.query.StringValues(query.FieldType); len(types) == 0 {
+		// Repository results can't be paginated, so queries without a type:
+		// field only return text and path results.
+		resultTypes = []string{"file", "path"}
+	}
 	tr.LazyPrintf("resultTypes: %v", resultTypes)
 
-	if len(resultTypes) != 1 || resultTypes[0] != "file" {
-		return nil, fmt.Errorf("experimental paginated search currently only supports 'file' (text match) result types. Found %q", resultTypes)
+	var searchBatch func(ctx context.Context, args *search.TextParameters) ([]*FileMatchResolver, *searchResultsCommon, error)
+	switch {
+	case isTextResultTypes(resultTypes):
+		searchBatch = searchFilesInRepos
+	case len(resultTypes) == 1 && resultTypes[0] == "symbol":
+		searchBatch = func(ctx context.Context, args *search.TextParameters) ([]*FileMatchResolver, *searchResultsCommon, error) {
+			return searchSymbols(ctx, args, int(r.maxResults()))
+		}
+	default:
+		return nil, fmt.Errorf("experimental paginated search currently only supports 'file' (text match), 'path' and 'symbol' result types, but not both text and symbol results. Found %q", resultTypes)
 	}
 
 	// Since we're searching a subset of the repositories this query would
@@ -180,7 +200,7 @@ func (r *searchResolver) paginatedResults(ctx context.Context) (result *SearchRe
 	})
 
 	common := searchResultsCommon{maxResultsCount: r.maxResults()}
-	cursor, results, fileCommon, err := paginatedSearchFilesInRepos(ctx, &args, r.pagination)
+	cursor, results, fileCommon, err := paginatedSearchFilesInRepos(ctx, &args, r.pagination, searchBatch)
 	if err != nil {
 		return nil, err
 	}
@@ -220,9 +240,21 @@ func repoIsLess(i, j *types.Repo) bool {
 	return i.ID < j.ID
 }
 
+// isTextResultTypes reports whether all result types are searched by
+// searchFilesInRepos.
+func isTextResultTypes(resultTypes []string) bool {
+	for _, t := range resultTypes {
+		if t != "file" && t != "path" {
+			return false
+		}
+	}
+	return len(resultTypes) > 0
+}
+
 // paginatedSearchFilesInRepos implements result-level pagination by calling
-// searchFilesInRepos to search over subsets (batches) of the total list of
-// repositories that may have results for this request (args.Repos). It does
+// searchBatch (searchFilesInRepos or searchSymbols) to search over subsets
+// (batches) of the total list of repositories that may have results for this
+// request (args.Repos). It does
 // this by picking some tradeoffs to balance some conflicting facts:
 //
 // 1. Paginated text searches must currently ask Zoekt AND non-indexed search
@@ -243,7 +275,7 @@ func repoIsLess(i, j *types.Repo) bool {
 //    top of the penalty we incur from the larger `count:` mentioned in point
 //    2 above (in the worst case scenario).
 //
-func paginatedSearchFilesInRepos(ctx context.Context, args *search.TextParameters, pagination *searchPaginationInfo) (*searchCursor, []SearchResultResolver, *searchResultsCommon, error) {
+func paginatedSearchFilesInRepos(ctx context.Context, args *search.TextParameters, pagination *searchPaginationInfo, searchBatch func(context.Context, *search.TextParameters) ([]*FileMatchResolver, *searchResultsCommon, error)) (*searchCursor, []SearchResultResolver, *searchResultsCommon, error) {
 	plan := &repoPaginationPlan{
 		pagination:          pagination,
 		repositories:        args.Repos,
@@ -254,13 +286,13 @@ func paginatedSearchFilesInRepos(ctx context.Context, args *search.TextParameter
 	return plan.execute(ctx, func(batch []*search.RepositoryRevisions) ([]SearchResultResolver, *searchResultsCommon, error) {
 		batchArgs := *args
 		batchArgs.Repos = batch
-		fileResults, fileCommon, err := searchFilesInRepos(ctx, &batchArgs)
+		fileResults, fileCommon, err := searchBatch(ctx, &batchArgs)
 		// Timeouts are reported through searchResultsCommon so don't report an error for them
 		if err != nil && !(err == context.DeadlineExceeded || err == context.Canceled) {
 			return nil, nil, err
 		}
 		if fileCommon == nil {
-			// searchFilesInRepos can return a nil structure, but the executor
+			// searchBatch can return a nil structure, but the executor
 			// requires a non-nil one always (which is more sane).
 			fileCommon = &searchResultsCommon{
 				partial: map[api.RepoName]struct{}{},
@@ -38,6 +38,14 @@ func searchRepositories(ctx context.Context, args *search.TextParameters, limit
 		query.FieldCase:               {},
 		query.FieldRepoHasFile:        {},
 		query.FieldRepoHasCommitAfter: {},
+		query.FieldRepoTopic:          {},
+		query.FieldRepoLang:           {},
+		query.FieldVisibility:         {},
+		query.FieldStars:              {},
+		query.FieldSelect:             {},
+		query.FieldContext:            {},
+		query.FieldContextBefore:      {},
+		query.FieldContextAfter:       {},
 	}
 	// Don't return repo results if the search contains fields that aren't on the whitelist.
 	// Matching repositories based whether they contain files at a certain path (etc.) is not yet implemented.
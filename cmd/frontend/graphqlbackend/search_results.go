@@ -784,6 +784,7 @@ func getPatternInfo(q *query.Query, opts *getPatternInfoOptions) (*search.TextPa
 	excludePatterns = append(excludePatterns, langExcludePatterns...)
 
 	languages, _ := q.StringValues(query.FieldLang)
+	contextBefore, contextAfter := q.ContextLines()
 
 	patternInfo := &search.TextPatternInfo{
 		IsRegExp:                     isRegExp,
@@ -798,6 +799,8 @@ func getPatternInfo(q *query.Query, opts *getPatternInfoOptions) (*search.TextPa
 		Languages:                    languages,
 		PathPatternsAreCaseSensitive: q.IsCaseSensitive(),
 		CombyRule:                    strings.Join(combyRule, ""),
+		ContextBefore:                contextBefore,
+		ContextAfter:                 contextAfter,
 	}
 	if len(excludePatterns) > 0 {
 		patternInfo.ExcludePattern = unionRegExps(excludePatterns)
@@ -877,7 +880,12 @@ func (r *searchResolver) determineResultTypes(args search.TextParameters, forceO
 	} else {
 		resultTypes, _ = r.query.StringValues(query.FieldType)
 		if len(resultTypes) == 0 {
-			resultTypes = []string{"file", "path", "repo"}
+			if value, _ := r.query.StringValue(query.FieldSelect); value == query.SelectAuthor {
+				// Only commits have authors.
+				resultTypes = []string{"commit"}
+			} else {
+				resultTypes = []string{"file", "path", "repo"}
+			}
 		}
 	}
 	for _, resultType := range resultTypes {
@@ -951,6 +959,10 @@ func alertOnSearchLimit(resultTypes []string, args *search.TextParameters) ([]st
 //
 // Partial results AND an error may be returned.
 func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType string) (res *SearchResultsResolver, err error) {
+	if r.andOrQuery != nil {
+		return r.evaluateAndOr(ctx, forceOnlyResultType)
+	}
+
 	tr, ctx := trace.New(ctx, "graphql.SearchResults", r.rawQuery())
 	defer func() {
 		tr.SetError(err)
@@ -959,6 +971,12 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 
 	start := time.Now()
 
+	if isSelectQuery(r.query) {
+		// The results of the backends are projected before they are
+		// returned, so don't stream them.
+		ctx = withSearchStream(ctx, nil)
+	}
+
 	ctx, cancel, err := r.withTimeout(ctx)
 	if err != nil {
 		return nil, err
@@ -1074,11 +1092,14 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					resultsMu.Lock()
 					results = append(results, repoResults...)
 					resultsMu.Unlock()
+					sendSearchResults(ctx, repoResults)
 				}
 				if repoCommon != nil {
 					commonMu.Lock()
 					common.update(*repoCommon)
+					progress := common.progress(ctx)
 					commonMu.Unlock()
+					sendSearchProgress(ctx, progress)
 				}
 			})
 		case "symbol":
@@ -1094,6 +1115,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					multiErr = multierror.Append(multiErr, errors.Wrap(err, "symbol search failed"))
 					multiErrMu.Unlock()
 				}
+				sendSearchResults(ctx, fileMatchesToSearchResults(symbolFileMatches))
 				for _, symbolFileMatch := range symbolFileMatches {
 					key := symbolFileMatch.uri
 					fileMatchesMu.Lock()
@@ -1110,7 +1132,9 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 				if symbolsCommon != nil {
 					commonMu.Lock()
 					common.update(*symbolsCommon)
+					progress := common.progress(ctx)
 					commonMu.Unlock()
+					sendSearchProgress(ctx, progress)
 				}
 			})
 		case "file", "path":
@@ -1139,6 +1163,9 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 						// merge line match results with an existing symbol result
 						m.JLimitHit = m.JLimitHit || r.JLimitHit
 						m.JLineMatches = r.JLineMatches
+						m.JMultilineMatches = r.JMultilineMatches
+						m.JContextRanges = r.JContextRanges
+						m.revisions = r.revisions
 					} else {
 						fileMatches[key] = r
 						resultsMu.Lock()
@@ -1150,7 +1177,9 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 				if fileCommon != nil {
 					commonMu.Lock()
 					common.update(*fileCommon)
+					progress := common.progress(ctx)
 					commonMu.Unlock()
+					sendSearchProgress(ctx, progress)
 				}
 			})
 		case "diff":
@@ -1185,11 +1214,14 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					resultsMu.Lock()
 					results = append(results, diffResults...)
 					resultsMu.Unlock()
+					sendSearchResults(ctx, diffResults)
 				}
 				if diffCommon != nil {
 					commonMu.Lock()
 					common.update(*diffCommon)
+					progress := common.progress(ctx)
 					commonMu.Unlock()
+					sendSearchProgress(ctx, progress)
 				}
 			})
 		case "commit":
@@ -1225,11 +1257,14 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					resultsMu.Lock()
 					results = append(results, commitResults...)
 					resultsMu.Unlock()
+					sendSearchResults(ctx, commitResults)
 				}
 				if commitCommon != nil {
 					commonMu.Lock()
 					common.update(*commitCommon)
+					progress := common.progress(ctx)
 					commonMu.Unlock()
+					sendSearchProgress(ctx, progress)
 				}
 			})
 		case "codemod":
@@ -1249,11 +1284,14 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					resultsMu.Lock()
 					results = append(results, codemodResults...)
 					resultsMu.Unlock()
+					sendSearchResults(ctx, codemodResults)
 				}
 				if codemodCommon != nil {
 					commonMu.Lock()
 					common.update(*codemodCommon)
+					progress := common.progress(ctx)
 					commonMu.Unlock()
+					sendSearchProgress(ctx, progress)
 				}
 			})
 		}
@@ -1295,6 +1333,11 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 		multiErr = nil
 	}
 
+	if isSelectQuery(r.query) {
+		results = selectResults(ctx, r.query, results)
+		common.resultCount = countResults(results)
+	}
+
 	sortResults(results)
 
 	resultsResolver := SearchResultsResolver{
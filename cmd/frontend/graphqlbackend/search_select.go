@@ -0,0 +1,300 @@
+package graphqlbackend
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/neelance/parallel"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// This file implements the "select:" field, which projects the results of a
+// search onto distinct repositories, files, enclosing symbols or commit
+// authors. The projection is applied once all backends have returned, so
+// results from Zoekt and searcher for the same repository or file are
+// deduplicated.
+
+// selectResults returns the projection of results requested by the "select:"
+// field of q, or results unchanged if q has no "select:" field.
+func selectResults(ctx context.Context, q *query.Query, results []SearchResultResolver) []SearchResultResolver {
+	value, _ := q.StringValue(query.FieldSelect)
+	switch value {
+	case query.SelectRepo:
+		return selectRepos(results)
+	case query.SelectFile:
+		return selectFiles(results)
+	case query.SelectSymbol:
+		return selectSymbols(ctx, results)
+	case query.SelectAuthor:
+		return selectAuthors(results)
+	}
+	return results
+}
+
+// isSelectQuery reports whether q projects its results with "select:".
+func isSelectQuery(q *query.Query) bool {
+	value, _ := q.StringValue(query.FieldSelect)
+	return value != ""
+}
+
+// countResults returns the number of matches in results.
+func countResults(results []SearchResultResolver) int32 {
+	var count int32
+	for _, result := range results {
+		count += result.resultCount()
+	}
+	return count
+}
+
+// selectRepos returns the distinct repositories of results.
+func selectRepos(results []SearchResultResolver) []SearchResultResolver {
+	var selected []SearchResultResolver
+	seen := map[api.RepoID]struct{}{}
+	add := func(repo *types.Repo) {
+		if repo == nil {
+			return
+		}
+		if _, ok := seen[repo.ID]; ok {
+			return
+		}
+		seen[repo.ID] = struct{}{}
+		selected = append(selected, &RepositoryResolver{repo: repo})
+	}
+	for _, result := range results {
+		switch r := result.(type) {
+		case *RepositoryResolver:
+			add(r.repo)
+		case *FileMatchResolver:
+			add(r.Repo)
+		case *commitSearchResultResolver:
+			add(r.commit.repo.repo)
+		case *codemodResultResolver:
+			add(r.commit.repo.repo)
+		}
+	}
+	return selected
+}
+
+// selectFiles returns the distinct files of results, without their line
+// matches and symbols.
+func selectFiles(results []SearchResultResolver) []SearchResultResolver {
+	type fileKey struct {
+		repo     api.RepoID
+		inputRev string
+		path     string
+	}
+	var selected []SearchResultResolver
+	seen := map[fileKey]struct{}{}
+	for _, result := range results {
+		fm, ok := result.(*FileMatchResolver)
+		if !ok {
+			continue
+		}
+		key := fileKey{repo: fm.Repo.ID, path: fm.JPath}
+		if fm.InputRev != nil {
+			key.inputRev = *fm.InputRev
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		selected = append(selected, &FileMatchResolver{
+			JPath:    fm.JPath,
+			uri:      fm.uri,
+			Repo:     fm.Repo,
+			CommitID: fm.CommitID,
+			InputRev: fm.InputRev,
+		})
+	}
+	return selected
+}
+
+// mockListFileSymbols mocks listFileSymbols in tests.
+var mockListFileSymbols func(ctx context.Context, fm *FileMatchResolver) ([]protocol.Symbol, error)
+
+// listFileSymbols returns the symbols defined in the file of fm and the commit
+// they were listed at.
+func listFileSymbols(ctx context.Context, fm *FileMatchResolver) ([]protocol.Symbol, api.CommitID, error) {
+	commitID := fm.CommitID
+	if mockListFileSymbols != nil {
+		symbols, err := mockListFileSymbols(ctx, fm)
+		return symbols, commitID, err
+	}
+	if commitID == "" {
+		// Results from searcher don't know their commit.
+		var err error
+		commitID, err = git.ResolveRevision(ctx, gitserver.Repo{Name: fm.Repo.Name}, nil, inputRevOf(fm), nil)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	symbols, err := backend.Symbols.ListTags(ctx, search.SymbolsParameters{
+		Repo:            fm.Repo.Name,
+		CommitID:        commitID,
+		IncludePatterns: []string{"^" + regexp.QuoteMeta(fm.JPath) + "$"},
+		IsCaseSensitive: true,
+		First:           maxSymbolsPerFile,
+	})
+	return symbols, commitID, err
+}
+
+// maxSymbolsPerFile is the maximum number of symbols listed for a file when
+// selecting the symbols that enclose matches.
+const maxSymbolsPerFile = 1000
+
+func inputRevOf(fm *FileMatchResolver) string {
+	if fm.InputRev != nil {
+		return *fm.InputRev
+	}
+	return ""
+}
+
+// selectSymbols returns the symbols that enclose the line matches of the file
+// matches in results, along with any symbols that matched the query. A line
+// match is enclosed by the closest symbol defined on or before its line.
+func selectSymbols(ctx context.Context, results []SearchResultResolver) []SearchResultResolver {
+	var fileMatches []*FileMatchResolver
+	for _, result := range results {
+		if fm, ok := result.(*FileMatchResolver); ok {
+			fileMatches = append(fileMatches, fm)
+		}
+	}
+
+	selected := make([]*FileMatchResolver, len(fileMatches))
+	run := parallel.NewRun(10)
+	for i, fm := range fileMatches {
+		i, fm := i, fm
+		run.Acquire()
+		go func() {
+			defer run.Release()
+			symbols, err := enclosingSymbols(ctx, fm)
+			if err != nil {
+				log15.Warn("select:symbol: failed to list symbols", "repo", fm.Repo.Name, "path", fm.JPath, "error", err)
+			}
+			if len(symbols) == 0 {
+				return
+			}
+			selected[i] = &FileMatchResolver{
+				JPath:    fm.JPath,
+				symbols:  symbols,
+				uri:      fm.uri,
+				Repo:     fm.Repo,
+				CommitID: fm.CommitID,
+				InputRev: fm.InputRev,
+			}
+		}()
+	}
+	_ = run.Wait()
+
+	var res []SearchResultResolver
+	for _, fm := range selected {
+		if fm != nil {
+			res = append(res, fm)
+		}
+	}
+	return res
+}
+
+// enclosingSymbols returns the distinct symbols of fm and the symbols that
+// enclose its line matches.
+func enclosingSymbols(ctx context.Context, fm *FileMatchResolver) ([]*searchSymbolResult, error) {
+	type symbolKey struct {
+		name, kind string
+		line       int
+	}
+	seen := map[symbolKey]struct{}{}
+	var symbols []*searchSymbolResult
+	for _, s := range fm.symbols {
+		key := symbolKey{s.symbol.Name, s.symbol.Kind, s.symbol.Line}
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			symbols = append(symbols, s)
+		}
+	}
+	if len(fm.JLineMatches) == 0 {
+		return symbols, nil
+	}
+
+	fileSymbols, commitID, err := listFileSymbols(ctx, fm)
+	if err != nil {
+		return symbols, err
+	}
+	sort.Slice(fileSymbols, func(i, j int) bool { return fileSymbols[i].Line < fileSymbols[j].Line })
+
+	inputRev := inputRevOf(fm)
+	baseURI, err := gituri.Parse("git://" + string(fm.Repo.Name) + "?" + url.QueryEscape(inputRev))
+	if err != nil {
+		return symbols, err
+	}
+	commit := &GitCommitResolver{
+		repo:     &RepositoryResolver{repo: fm.Repo},
+		oid:      GitObjectID(commitID),
+		inputRev: &inputRev,
+		// NOTE: Not all fields are set, for performance.
+	}
+	for _, lm := range fm.JLineMatches {
+		// Symbol lines are 1-based, line matches are 0-based.
+		line := int(lm.JLineNumber) + 1
+		i := sort.Search(len(fileSymbols), func(i int) bool { return fileSymbols[i].Line > line })
+		if i == 0 {
+			continue // the match is before the first symbol
+		}
+		symbol := fileSymbols[i-1]
+		key := symbolKey{symbol.Name, symbol.Kind, symbol.Line}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		symbols = append(symbols, &searchSymbolResult{
+			symbol:  symbol,
+			baseURI: baseURI,
+			lang:    strings.ToLower(symbol.Language),
+			commit:  commit,
+		})
+	}
+	return symbols, nil
+}
+
+// selectAuthors returns the most recent commit result of each distinct
+// commit author in results.
+func selectAuthors(results []SearchResultResolver) []SearchResultResolver {
+	var (
+		selected []SearchResultResolver
+		index    = map[string]int{}
+	)
+	for _, result := range results {
+		r, ok := result.(*commitSearchResultResolver)
+		if !ok || r.commit == nil {
+			continue
+		}
+		author := r.commit.author
+		if author.person == nil {
+			continue
+		}
+		key := strings.ToLower(author.person.email)
+		if key == "" {
+			key = author.person.name
+		}
+		if i, ok := index[key]; ok {
+			if author.date.After(selected[i].(*commitSearchResultResolver).commit.author.date) {
+				selected[i] = r
+			}
+			continue
+		}
+		index[key] = len(selected)
+		selected = append(selected, r)
+	}
+	return selected
+}
@@ -0,0 +1,161 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+)
+
+func TestSelectResults(t *testing.T) {
+	repoA := &types.Repo{ID: 1, Name: "a"}
+	repoB := &types.Repo{ID: 2, Name: "b"}
+	fileMatch := func(repo *types.Repo, path string, lines ...int32) *FileMatchResolver {
+		fm := &FileMatchResolver{uri: "git://" + string(repo.Name) + "#" + path, JPath: path, Repo: repo, CommitID: "c"}
+		for _, line := range lines {
+			fm.JLineMatches = append(fm.JLineMatches, &lineMatch{JLineNumber: line})
+		}
+		return fm
+	}
+	commit := func(repo *types.Repo, email string, date time.Time) *commitSearchResultResolver {
+		return &commitSearchResultResolver{
+			url: "/" + string(repo.Name) + "/-/commit/" + email + date.String(),
+			commit: &GitCommitResolver{
+				repo:   &RepositoryResolver{repo: repo},
+				author: signatureResolver{person: &personResolver{email: email}, date: date},
+			},
+		}
+	}
+
+	mockListFileSymbols = func(ctx context.Context, fm *FileMatchResolver) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "Bar", Kind: "function", Path: fm.JPath, Line: 10},
+			{Name: "Foo", Kind: "function", Path: fm.JPath, Line: 2},
+		}, nil
+	}
+	defer func() { mockListFileSymbols = nil }()
+
+	parse := func(t *testing.T, q string) *query.Query {
+		t.Helper()
+		parsed, err := query.ParseAndCheck(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	t.Run("repo", func(t *testing.T) {
+		results := []SearchResultResolver{
+			fileMatch(repoA, "x.go", 1),
+			fileMatch(repoA, "y.go", 1),
+			&RepositoryResolver{repo: repoB},
+			commit(repoB, "alice@example.com", time.Time{}),
+		}
+		got := selectResults(context.Background(), parse(t, "select:repo"), results)
+		want := []SearchResultResolver{&RepositoryResolver{repo: repoA}, &RepositoryResolver{repo: repoB}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		results := []SearchResultResolver{
+			// The same file from Zoekt and searcher.
+			fileMatch(repoA, "x.go", 1, 2),
+			fileMatch(repoA, "x.go", 3),
+			fileMatch(repoB, "x.go"),
+			&RepositoryResolver{repo: repoB},
+		}
+		got := selectResults(context.Background(), parse(t, "select:file"), results)
+		want := []SearchResultResolver{
+			&FileMatchResolver{uri: "git://a#x.go", JPath: "x.go", Repo: repoA, CommitID: "c"},
+			&FileMatchResolver{uri: "git://b#x.go", JPath: "x.go", Repo: repoB, CommitID: "c"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if count := countResults(got); count != 2 {
+			t.Errorf("got count %d, want 2", count)
+		}
+	})
+
+	t.Run("symbol", func(t *testing.T) {
+		results := []SearchResultResolver{
+			// Line matches are 0-based: lines 1 (before any symbol), 3 and
+			// 5 (in Foo) and 12 (in Bar).
+			fileMatch(repoA, "x.go", 0, 2, 4, 11),
+			fileMatch(repoB, "y.go"),
+		}
+		got := selectResults(context.Background(), parse(t, "select:symbol"), results)
+		if len(got) != 1 {
+			t.Fatalf("got %d results, want 1", len(got))
+		}
+		fm := got[0].(*FileMatchResolver)
+		var names []string
+		for _, s := range fm.symbols {
+			names = append(names, s.symbol.Name)
+		}
+		if want := []string{"Foo", "Bar"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("got symbols %v, want %v", names, want)
+		}
+		if len(fm.JLineMatches) != 0 {
+			t.Errorf("got %d line matches, want none", len(fm.JLineMatches))
+		}
+	})
+
+	t.Run("author", func(t *testing.T) {
+		older := commit(repoA, "alice@example.com", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		newer := commit(repoB, "Alice@example.com", time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC))
+		bob := commit(repoA, "bob@example.com", time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC))
+		results := []SearchResultResolver{older, bob, newer, fileMatch(repoA, "x.go", 1)}
+		got := selectResults(context.Background(), parse(t, "select:author"), results)
+		want := []SearchResultResolver{newer, bob}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		results := []SearchResultResolver{fileMatch(repoA, "x.go", 1), fileMatch(repoA, "x.go", 2)}
+		if got := selectResults(context.Background(), parse(t, "foo"), results); !reflect.DeepEqual(got, results) {
+			t.Errorf("got %v, want %v", got, results)
+		}
+	})
+}
+
+func TestWithoutSelect(t *testing.T) {
+	tests := []struct {
+		input      string
+		want       string
+		wantSelect string
+	}{
+		{input: "foo or bar", want: "(foo or bar)"},
+		{input: "(foo or bar) select:repo", want: "(foo or bar)", wantSelect: "select:repo"},
+		{input: "foo and bar select:file lang:go", want: "(foo and bar lang:go)", wantSelect: "select:file"},
+		{input: "select:file (foo or bar) lang:go", want: "(foo or bar) lang:go", wantSelect: "select:file"},
+		{input: "select:repo and (foo or bar)", want: "(foo or bar)", wantSelect: "select:repo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			n, err := query.ParseAndOr(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, p := withoutSelect(n)
+			if got.String() != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			gotSelect := ""
+			if p != nil {
+				gotSelect = p.String()
+			}
+			if gotSelect != tt.wantSelect {
+				t.Errorf("got select %q, want %q", gotSelect, tt.wantSelect)
+			}
+		})
+	}
+}
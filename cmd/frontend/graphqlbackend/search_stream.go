@@ -0,0 +1,240 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// SearchEvent is sent to a SearchStream while a search is running. It
+// contains either a batch of new results or updated progress counters.
+type SearchEvent struct {
+	Results  []SearchResultResolver
+	Progress *SearchProgress
+}
+
+// SearchProgress describes how far along a running search is.
+type SearchProgress struct {
+	Repositories int  `json:"repositories"` // repos matched by the repo-related filters
+	Searched     int  `json:"searched"`     // repos that were searched
+	Indexed      int  `json:"indexed"`      // repos that were searched using an index
+	Cloning      int  `json:"cloning"`      // repos that could not be searched because they are being cloned
+	Missing      int  `json:"missing"`      // repos that could not be searched because they do not exist
+	Timedout     int  `json:"timedout"`     // repos that could not be searched in time
+	LimitHit     bool `json:"limitHit"`     // whether the limit on results was hit
+}
+
+// SearchStream receives the events of a search as each backend yields them.
+type SearchStream func(SearchEvent)
+
+type searchStreamKey struct{}
+
+// withSearchStream returns a context that makes the search backends send
+// their results and progress to stream. A nil stream disables streaming.
+func withSearchStream(ctx context.Context, stream SearchStream) context.Context {
+	return context.WithValue(ctx, searchStreamKey{}, stream)
+}
+
+func searchStreamFromContext(ctx context.Context) SearchStream {
+	stream, _ := ctx.Value(searchStreamKey{}).(SearchStream)
+	return stream
+}
+
+// sendSearchResults sends results to the stream in ctx, if any.
+func sendSearchResults(ctx context.Context, results []SearchResultResolver) {
+	if stream := searchStreamFromContext(ctx); stream != nil && len(results) > 0 {
+		stream(SearchEvent{Results: results})
+	}
+}
+
+// sendSearchProgress sends progress to the stream in ctx, if any.
+func sendSearchProgress(ctx context.Context, progress *SearchProgress) {
+	if stream := searchStreamFromContext(ctx); stream != nil && progress != nil {
+		stream(SearchEvent{Progress: progress})
+	}
+}
+
+// progress returns the progress counters for c. It returns nil when ctx has
+// no stream, so that callers holding a lock on c don't do needless work.
+func (c *searchResultsCommon) progress(ctx context.Context) *SearchProgress {
+	if searchStreamFromContext(ctx) == nil {
+		return nil
+	}
+	return &SearchProgress{
+		Repositories: countRepos(c.repos),
+		Searched:     countRepos(c.searched),
+		Indexed:      countRepos(c.indexed),
+		Cloning:      countRepos(c.cloning),
+		Missing:      countRepos(c.missing),
+		Timedout:     countRepos(c.timedout),
+		LimitHit:     c.LimitHit(),
+	}
+}
+
+// countRepos returns the number of distinct repos.
+func countRepos(repos []*types.Repo) int {
+	seen := make(map[api.RepoID]struct{}, len(repos))
+	for _, repo := range repos {
+		seen[repo.ID] = struct{}{}
+	}
+	return len(seen)
+}
+
+// StreamSearch runs the search described by args and sends its results and
+// progress to stream as each search backend yields them. stream is never
+// called concurrently, nor after StreamSearch returns.
+//
+// The returned resolver holds the final results, which are ordered and
+// limited the same way as for a GraphQL search, along with any alert. Results
+// that were already streamed may have been dropped from it, e.g. when the
+// search timed out.
+func StreamSearch(ctx context.Context, args *SearchArgs, stream SearchStream) (*SearchResultsResolver, error) {
+	if args.First != nil || args.After != nil {
+		return nil, errors.New("streaming search does not support pagination")
+	}
+	impl, err := NewSearchImplementer(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu   sync.Mutex
+		done bool
+		sent bool
+	)
+	defer func() {
+		mu.Lock()
+		done = true
+		mu.Unlock()
+	}()
+	ctx = withSearchStream(ctx, func(event SearchEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		if done {
+			return
+		}
+		sent = sent || len(event.Results) > 0
+		stream(event)
+	})
+
+	results, err := impl.Results(ctx)
+	if err != nil || results == nil {
+		return results, err
+	}
+
+	// Boolean queries only know their results once all operands have been
+	// evaluated, and "select:" projects the results once all backends have
+	// returned, so they don't stream and we send the results in one batch.
+	mu.Lock()
+	if !sent && len(results.SearchResults) > 0 {
+		stream(SearchEvent{Results: results.SearchResults})
+	}
+	mu.Unlock()
+	return results, nil
+}
+
+func fileMatchesToSearchResults(matches []*FileMatchResolver) []SearchResultResolver {
+	results := make([]SearchResultResolver, 0, len(matches))
+	for _, fm := range matches {
+		results = append(results, fm)
+	}
+	return results
+}
+
+// StreamMatch is the JSON representation of a search result sent by the
+// streaming search API. Which fields are set depends on Type.
+type StreamMatch struct {
+	Type        string            `json:"type"` // "repo", "file", "commit" or "codemod"
+	Repository  string            `json:"repository"`
+	Revision    string            `json:"revision,omitempty"`
+	Commit      string            `json:"commit,omitempty"`
+	Path        string            `json:"path,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Preview     string            `json:"preview,omitempty"` // the commit message, diff or codemod diff
+	LineMatches []StreamLineMatch `json:"lineMatches,omitempty"`
+	Symbols     []StreamSymbol    `json:"symbols,omitempty"`
+	LimitHit    bool              `json:"limitHit,omitempty"`
+}
+
+// StreamLineMatch is the JSON representation of a line match in a file.
+type StreamLineMatch struct {
+	LineNumber       int32      `json:"lineNumber"`
+	Preview          string     `json:"preview"`
+	OffsetAndLengths [][2]int32 `json:"offsetAndLengths"`
+}
+
+// StreamSymbol is the JSON representation of a symbol match in a file.
+type StreamSymbol struct {
+	Name          string `json:"name"`
+	ContainerName string `json:"containerName,omitempty"`
+	Kind          string `json:"kind"`
+	Line          int    `json:"line"`
+}
+
+// StreamMatches converts results to their JSON representation.
+func StreamMatches(results []SearchResultResolver) []StreamMatch {
+	matches := make([]StreamMatch, 0, len(results))
+	for _, result := range results {
+		switch r := result.(type) {
+		case *RepositoryResolver:
+			matches = append(matches, StreamMatch{
+				Type:       "repo",
+				Repository: r.Name(),
+				URL:        r.URL(),
+			})
+		case *FileMatchResolver:
+			m := StreamMatch{
+				Type:       "file",
+				Repository: string(r.Repo.Name),
+				Commit:     string(r.CommitID),
+				Path:       r.JPath,
+				LimitHit:   r.JLimitHit,
+			}
+			if r.InputRev != nil {
+				m.Revision = *r.InputRev
+			}
+			for _, lm := range r.JLineMatches {
+				m.LineMatches = append(m.LineMatches, StreamLineMatch{
+					LineNumber:       lm.JLineNumber,
+					Preview:          lm.JPreview,
+					OffsetAndLengths: lm.JOffsetAndLengths,
+				})
+			}
+			for _, s := range r.symbols {
+				m.Symbols = append(m.Symbols, StreamSymbol{
+					Name:          s.symbol.Name,
+					ContainerName: s.symbol.Parent,
+					Kind:          s.symbol.Kind,
+					Line:          s.symbol.Line,
+				})
+			}
+			matches = append(matches, m)
+		case *commitSearchResultResolver:
+			m := StreamMatch{
+				Type:       "commit",
+				Repository: r.commit.repo.Name(),
+				Commit:     string(r.commit.OID()),
+				URL:        r.url,
+			}
+			if r.diffPreview != nil {
+				m.Preview = r.diffPreview.value
+			} else if r.messagePreview != nil {
+				m.Preview = r.messagePreview.value
+			}
+			matches = append(matches, m)
+		case *codemodResultResolver:
+			matches = append(matches, StreamMatch{
+				Type:       "codemod",
+				Repository: r.commit.repo.Name(),
+				Commit:     string(r.commit.OID()),
+				Path:       r.path,
+				URL:        r.fileURL,
+				Preview:    r.diff,
+			})
+		}
+	}
+	return matches
+}
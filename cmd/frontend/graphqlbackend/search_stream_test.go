@@ -0,0 +1,107 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/zoekt"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/endpoint"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+func TestStreamSearch(t *testing.T) {
+	db.Mocks.Repos.List = func(_ context.Context, op db.ReposListOptions) ([]*types.Repo, error) {
+		return []*types.Repo{{ID: 1, Name: "repo"}}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+	db.Mocks.Repos.MockGetByName(t, "repo", 1)
+	db.Mocks.Repos.MockGet(t, 1)
+
+	mockSearchRepositories = func(args *search.TextParameters) ([]SearchResultResolver, *searchResultsCommon, error) {
+		repo := &types.Repo{ID: 1, Name: "repo"}
+		return []SearchResultResolver{&RepositoryResolver{repo: repo}}, &searchResultsCommon{repos: []*types.Repo{repo}}, nil
+	}
+	defer func() { mockSearchRepositories = nil }()
+
+	var events []SearchEvent
+	results, err := StreamSearch(context.Background(), &SearchArgs{Query: "type:repo repo", Version: "V2"}, func(event SearchEvent) {
+		events = append(events, event)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.SearchResults) != 1 {
+		t.Fatalf("got %d results, want 1", len(results.SearchResults))
+	}
+
+	var (
+		gotMatches  []StreamMatch
+		gotProgress *SearchProgress
+	)
+	for _, event := range events {
+		gotMatches = append(gotMatches, StreamMatches(event.Results)...)
+		if event.Progress != nil {
+			gotProgress = event.Progress
+		}
+	}
+	wantMatches := []StreamMatch{{Type: "repo", Repository: "repo", URL: "/repo"}}
+	if !reflect.DeepEqual(gotMatches, wantMatches) {
+		t.Errorf("got matches %+v, want %+v", gotMatches, wantMatches)
+	}
+	wantProgress := &SearchProgress{Repositories: 1}
+	if !reflect.DeepEqual(gotProgress, wantProgress) {
+		t.Errorf("got progress %+v, want %+v", gotProgress, wantProgress)
+	}
+
+	t.Run("pagination", func(t *testing.T) {
+		first := int32(10)
+		if _, err := StreamSearch(context.Background(), &SearchArgs{Query: "repo", Version: "V2", First: &first}, func(SearchEvent) {}); err == nil {
+			t.Fatal("got nil error, want error")
+		}
+	})
+}
+
+func TestSearchFilesInRepos_stream(t *testing.T) {
+	mockSearchFilesInRepo = func(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*FileMatchResolver, limitHit bool, err error) {
+		return []*FileMatchResolver{
+			{uri: "git://" + string(repo.Name) + "#a.go", JPath: "a.go", Repo: repo},
+			{uri: "git://" + string(repo.Name) + "#b.go", JPath: "b.go", Repo: repo},
+		}, false, nil
+	}
+	defer func() { mockSearchFilesInRepo = nil }()
+
+	q, err := query.ParseAndCheck("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &search.TextParameters{
+		PatternInfo: &search.TextPatternInfo{
+			FileMatchLimit: 3,
+			Pattern:        "foo",
+		},
+		Repos:        makeRepositoryRevisions("foo/one", "foo/two"),
+		Query:        q,
+		Zoekt:        &searchbackend.Zoekt{Client: &fakeSearcher{repos: &zoekt.RepoList{}}},
+		SearcherURLs: endpoint.Static("test"),
+	}
+
+	var streamed int
+	ctx := withSearchStream(context.Background(), func(event SearchEvent) {
+		streamed += len(event.Results)
+	})
+	results, _, err := searchFilesInRepos(ctx, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the matches within the limit are streamed.
+	if streamed != 3 || len(results) != 3 {
+		t.Errorf("got %d streamed and %d final results, want 3 and 3", streamed, len(results))
+	}
+}
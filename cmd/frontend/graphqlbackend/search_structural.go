@@ -3,8 +3,6 @@ package graphqlbackend
 import (
 	"context"
 	"errors"
-	"regexp"
-	"regexp/syntax"
 	"strings"
 	"time"
 
@@ -33,43 +31,36 @@ func splitOnHolesPattern() string {
 
 var matchHoleRegexp = lazyregexp.New(splitOnHolesPattern())
 
-// StructuralPatToRegexpQuery converts a comby pattern to a Zoekt regular
-// expression query. It converts whitespace in the pattern so that content
-// across newlines can be matched in the index. As an incomplete approximation,
-// we use the regex pattern .*? to scan ahead. A shortcircuit option returns a
-// regexp query that may find true matches faster, but may miss all possible
-// matches.
+// StructuralPatToSubstringsQuery converts a comby pattern to a Zoekt query that
+// finds the files that may contain matches of the pattern. Comby matches
+// non-whitespace text outside of holes literally, and whitespace in the
+// pattern matches any whitespace, so every match contains each whitespace
+// separated word of the pattern outside of holes. The query requires all of
+// these words, which Zoekt can evaluate quickly using its trigram index.
 //
 // Example:
-// "ParseInt(:[args]) if err != nil" -> "ParseInt(.*)\s+if\s+err!=\s+nil"
-func StructuralPatToRegexpQuery(pattern string, shortcircuit bool) (zoektquery.Q, error) {
-	substrings := matchHoleRegexp.Split(pattern, -1)
+// "ParseInt(:[args]) if err != nil" -> (and "ParseInt(" ")" "if" "err" "!=" "nil")
+func StructuralPatToSubstringsQuery(pattern string) zoektquery.Q {
 	var children []zoektquery.Q
-	var pieces []string
-	for _, s := range substrings {
-		piece := regexp.QuoteMeta(s)
-		onMatchWhitespace := lazyregexp.New(`[\s]+`)
-		piece = onMatchWhitespace.ReplaceAllLiteralString(piece, `[\s]+`)
-		pieces = append(pieces, piece)
-	}
-
-	if len(pieces) == 0 {
-		return &zoektquery.Const{Value: true}, nil
-	}
-	var rs string
-	if shortcircuit {
-		// As a shortcircuit, do not match across newlines of structural search pieces.
-		rs = "(" + strings.Join(pieces, ").*?(") + ")"
-	} else {
-		rs = "(" + strings.Join(pieces, ")(.|\\s)*?(") + ")"
-	}
-	re, _ := syntax.Parse(rs, syntax.ClassNL|syntax.PerlX|syntax.UnicodeGroups)
-	children = append(children, &zoektquery.Regexp{
-		Regexp:        re,
-		CaseSensitive: true,
-		Content:       true,
-	})
-	return &zoektquery.And{Children: children}, nil
+	seen := map[string]struct{}{}
+	for _, segment := range matchHoleRegexp.Split(pattern, -1) {
+		for _, word := range strings.Fields(segment) {
+			if _, ok := seen[word]; ok {
+				continue
+			}
+			seen[word] = struct{}{}
+			children = append(children, &zoektquery.Substring{
+				Pattern:       word,
+				CaseSensitive: true,
+				Content:       true,
+			})
+		}
+	}
+	if len(children) == 0 {
+		// The pattern only consists of holes, so any file may match.
+		return &zoektquery.Const{Value: true}
+	}
+	return zoektquery.NewAnd(children...)
 }
 
 func HandleFilePathPatterns(query *search.TextPatternInfo) (zoektquery.Q, error) {
@@ -98,16 +89,6 @@ func HandleFilePathPatterns(query *search.TextPatternInfo) (zoektquery.Q, error)
 	return zoektquery.NewAnd(and...), nil
 }
 
-func buildQuery(args *search.TextParameters, newRepoSet *zoektquery.RepoSet, filePathPatterns zoektquery.Q, shortcircuit bool) (zoektquery.Q, error) {
-	q, err := StructuralPatToRegexpQuery(args.PatternInfo.Pattern, shortcircuit)
-	if err != nil {
-		return nil, err
-	}
-	q = zoektquery.NewAnd(newRepoSet, filePathPatterns, q)
-	q = zoektquery.Simplify(q)
-	return q, nil
-}
-
 // zoektSearchHEADOnlyFiles searches repositories using zoekt, returning only the file paths containing
 // content matching the given pattern.
 //
@@ -163,35 +144,20 @@ func zoektSearchHEADOnlyFiles(ctx context.Context, args *search.TextParameters,
 		return nil, false, nil, err
 	}
 
+	// Zoekt only finds the candidate files that contain all the literal
+	// parts of the pattern. Searcher runs comby over these files to find the
+	// actual matches.
+	q := zoektquery.Simplify(zoektquery.NewAnd(newRepoSet, filePathPatterns, StructuralPatToSubstringsQuery(args.PatternInfo.Pattern)))
+
 	t0 := time.Now()
-	q, err := buildQuery(args, newRepoSet, filePathPatterns, true)
-	if err != nil {
-		return nil, false, nil, err
-	}
 	resp, err := args.Zoekt.Client.Search(ctx, q, &searchOpts)
 	if err != nil {
 		return nil, false, nil, err
 	}
-	if since(t0) >= searchOpts.MaxWallTime {
+	if resp.FileCount == 0 && resp.MatchCount == 0 && since(t0) >= searchOpts.MaxWallTime {
 		return nil, false, nil, errNoResultsInTimeout
 	}
-
-	// We always return approximate results (limitHit true) unless we run the branch to perform a more complete search.
-	limitHit = true
-	// If the previous indexed search did not return a substantial number of matching file candidates or count was
-	// manually specified, run a more complete and expensive search.
-	if resp.FileCount < 10 || args.PatternInfo.FileMatchLimit != defaultMaxSearchResults {
-		q, err = buildQuery(args, newRepoSet, filePathPatterns, false)
-		resp, err = args.Zoekt.Client.Search(ctx, q, &searchOpts)
-		if err != nil {
-			return nil, false, nil, err
-		}
-		if since(t0) >= searchOpts.MaxWallTime {
-			return nil, false, nil, errNoResultsInTimeout
-		}
-		// This is the only place limitHit can be set false, meaning we covered everything.
-		limitHit = resp.FilesSkipped+resp.ShardsSkipped > 0
-	}
+	limitHit = resp.FilesSkipped+resp.ShardsSkipped > 0
 
 	if len(resp.Files) == 0 {
 		return nil, false, nil, nil
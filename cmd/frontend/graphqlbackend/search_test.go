@@ -307,6 +307,69 @@ func Test_defaultRepositories(t *testing.T) {
 	}
 }
 
+func TestSearchResolver_resolveRepositories_metadataFilters(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+
+	tcs := []struct {
+		query string
+		want  db.ReposListOptions
+	}{
+		{
+			query: "repotopic:go -repotopic:deprecated",
+			want:  db.ReposListOptions{Topics: []string{"go"}, ExcludeTopics: []string{"deprecated"}},
+		},
+		{
+			query: "repolang:go repolang:Rust -repolang:c",
+			want:  db.ReposListOptions{Languages: []string{"go", "Rust"}, ExcludeLanguages: []string{"c"}},
+		},
+		{
+			query: "visibility:private",
+			want:  db.ReposListOptions{OnlyPrivate: true},
+		},
+		{
+			query: "visibility:Public",
+			want:  db.ReposListOptions{NoPrivate: true},
+		},
+		{
+			query: "visibility:any stars:>100",
+			want:  db.ReposListOptions{MinStars: intPtr(101)},
+		},
+		{
+			query: "stars:10..20",
+			want:  db.ReposListOptions{MinStars: intPtr(10), MaxStars: intPtr(20)},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := query.ParseAndCheck(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var have db.ReposListOptions
+			db.Mocks.Repos.List = func(_ context.Context, op db.ReposListOptions) ([]*types.Repo, error) {
+				have = op
+				return nil, nil
+			}
+			defer func() { db.Mocks = db.MockStores{} }()
+
+			r := &searchResolver{query: q}
+			if _, _, _, err := r.resolveRepositories(context.Background(), nil); err != nil {
+				t.Fatal(err)
+			}
+
+			want := tc.want
+			want.OnlyRepoIDs = true
+			want.LimitOffset = have.LimitOffset
+			want.NoForks = true
+			want.NoArchived = true
+			if !reflect.DeepEqual(have, want) {
+				t.Errorf("got %+v, want %+v", have, want)
+			}
+		})
+	}
+}
+
 func Test_detectSearchType(t *testing.T) {
 	typeRegexp := "regexp"
 	typeLiteral := "literal"
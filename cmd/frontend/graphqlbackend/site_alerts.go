@@ -2,9 +2,11 @@ package graphqlbackend
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -134,4 +136,29 @@ func init() {
 		}
 		return alerts
 	})
+
+	// Warn about repositories that a sync would have deleted.
+	AlertFuncs = append(AlertFuncs, func(args AlertFuncArgs) []*Alert {
+		// Only site admins can act on this alert, so only show it to site admins.
+		if !args.IsSiteAdmin {
+			return nil
+		}
+
+		count, err := db.Repos.Count(context.Background(), db.ReposListOptions{OnlyPendingDeletion: true})
+		if err != nil {
+			return []*Alert{{
+				TypeValue:    AlertTypeError,
+				MessageValue: fmt.Sprintf("Unable to count the repositories pending deletion: %s", err),
+			}}
+		}
+		if count == 0 {
+			return nil
+		}
+
+		return []*Alert{{
+			TypeValue: AlertTypeWarning,
+			MessageValue: fmt.Sprintf("**%d repositories are pending deletion** because syncing the external services would have deleted more repositories than the `repoDeletionSafeguard` [**site configuration**](/site-admin/configuration) allows. They're still searchable. ", count) +
+				"If a code host is missing repositories because of an outage, they're kept once it lists them again. Otherwise [**confirm their deletion**](/help/admin/repo/deletion_safeguard).",
+		}}
+	})
 }
@@ -0,0 +1,55 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func (r *siteResolver) GitserverEvictions(ctx context.Context) ([]*gitserverEvictionResolver, error) {
+	// 🚨 SECURITY: Only site admins can see which repositories were removed.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	evictions, err := gitserver.DefaultClient.Evictions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*gitserverEvictionResolver, len(evictions))
+	for i := range evictions {
+		resolvers[i] = &gitserverEvictionResolver{eviction: evictions[i]}
+	}
+	return resolvers, nil
+}
+
+type gitserverEvictionResolver struct {
+	eviction protocol.Eviction
+}
+
+func (r *gitserverEvictionResolver) RepositoryName() string { return string(r.eviction.Repo) }
+
+func (r *gitserverEvictionResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := backend.Repos.GetByName(ctx, r.eviction.Repo)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return NewRepositoryResolver(repo), nil
+}
+
+func (r *gitserverEvictionResolver) Gitserver() string { return r.eviction.Gitserver }
+
+func (r *gitserverEvictionResolver) EvictedAt() DateTime { return DateTime{Time: r.eviction.EvictedAt} }
+
+func (r *gitserverEvictionResolver) Reason() string { return r.eviction.Reason }
+
+func (r *gitserverEvictionResolver) SizeBytes() float64 { return float64(r.eviction.SizeBytes) }
+
+func (r *gitserverEvictionResolver) LastUsedAt() DateTime { return DateTime{Time: r.eviction.LastUsed} }
+
+func (r *gitserverEvictionResolver) AccessScore() float64 { return r.eviction.AccessScore }
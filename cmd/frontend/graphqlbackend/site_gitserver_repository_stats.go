@@ -0,0 +1,33 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+func (r *siteResolver) GitserverRepositoryStats(ctx context.Context, args *struct{ First int32 }) ([]*gitserverRepositoryStatsResolver, error) {
+	// 🚨 SECURITY: Fetch errors may contain details about the code host, so
+	// only site admins may see them.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if args.First <= 0 {
+		return []*gitserverRepositoryStatsResolver{}, nil
+	}
+	stats, err := gitserver.DefaultClient.ListRepoStats(ctx, int(args.First))
+	if err != nil {
+		if len(stats) == 0 {
+			return nil, err
+		}
+		// Show the statistics of the gitservers that responded.
+		log15.Warn("failed to list repository statistics of some gitservers", "error", err)
+	}
+	resolvers := make([]*gitserverRepositoryStatsResolver, len(stats))
+	for i := range stats {
+		resolvers[i] = &gitserverRepositoryStatsResolver{stats: stats[i]}
+	}
+	return resolvers, nil
+}
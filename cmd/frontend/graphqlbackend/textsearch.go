@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-langserver/pkg/lsp"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
 
@@ -66,10 +67,23 @@ type FileMatchResolver struct {
 	JPath        string       `json:"Path"`
 	JLineMatches []*lineMatch `json:"LineMatches"`
 	JLimitHit    bool         `json:"LimitHit"`
-	symbols      []*searchSymbolResult
-	uri          string
-	Repo         *types.Repo
-	CommitID     api.CommitID
+	// JMultilineMatches are only set for patterns that can match text
+	// spanning several lines, which only searcher can find.
+	JMultilineMatches []*multilineMatch `json:"MultilineMatches"`
+	// JContextRanges are only set if context lines were requested with
+	// "context:".
+	JContextRanges []*contextRange `json:"ContextRanges"`
+	// JBlob is the Git blob object ID of the file's content. It is only set
+	// by searcher for files with line matches.
+	JBlob string `json:"Blob"`
+	// revisions are the searched revisions of the repository that contain
+	// this file with the same content. It is only set when a search covers
+	// several revisions of the repository (e.g. repo:foo@*refs/heads/release/*).
+	revisions []string
+	symbols   []*searchSymbolResult
+	uri       string
+	Repo      *types.Repo
+	CommitID  api.CommitID
 	// InputRev is the Git revspec that the user originally requested to search. It is used to
 	// preserve the original revision specifier from the user instead of navigating them to the
 	// absolute commit ID when they select a result.
@@ -127,6 +141,18 @@ func (fm *FileMatchResolver) LineMatches() []*lineMatch {
 	return fm.JLineMatches
 }
 
+func (fm *FileMatchResolver) MultilineMatches() []*multilineMatch {
+	return fm.JMultilineMatches
+}
+
+func (fm *FileMatchResolver) ContextRanges() []*contextRange {
+	return fm.JContextRanges
+}
+
+func (fm *FileMatchResolver) Revisions() []string {
+	return fm.revisions
+}
+
 func (fm *FileMatchResolver) LimitHit() bool {
 	return fm.JLimitHit
 }
@@ -181,6 +207,44 @@ func (lm *lineMatch) LimitHit() bool {
 	return lm.JLimitHit
 }
 
+// multilineMatch is a match that may span several lines, as returned by
+// searcher.
+type multilineMatch struct {
+	JPreview string            `json:"Preview"`
+	JStart   multilinePosition `json:"Start"`
+	JEnd     multilinePosition `json:"End"`
+}
+
+type multilinePosition struct {
+	Line      int `json:"Line"`
+	Character int `json:"Character"`
+}
+
+func (mm *multilineMatch) Preview() string {
+	return mm.JPreview
+}
+
+func (mm *multilineMatch) Range() *rangeResolver {
+	return &rangeResolver{lsp.Range{
+		Start: lsp.Position{Line: mm.JStart.Line, Character: mm.JStart.Character},
+		End:   lsp.Position{Line: mm.JEnd.Line, Character: mm.JEnd.Character},
+	}}
+}
+
+// contextRange is a run of consecutive lines around one or more line matches.
+type contextRange struct {
+	JStartLine int32  `json:"StartLine"`
+	JPreview   string `json:"Preview"`
+}
+
+func (cr *contextRange) StartLine() int32 {
+	return cr.JStartLine
+}
+
+func (cr *contextRange) Preview() string {
+	return cr.JPreview
+}
+
 var mockTextSearch func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*FileMatchResolver, limitHit bool, err error)
 
 // textSearch searches repo@commit with p.
@@ -206,6 +270,7 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 		"FetchTimeout":    []string{fetchTimeout.String()},
 		"Languages":       p.Languages,
 		"CombyRule":       []string{p.CombyRule},
+		"FilePaths":       p.FilePaths,
 	}
 	if deadline, ok := ctx.Deadline(); ok {
 		t, err := deadline.MarshalText()
@@ -233,6 +298,12 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 	if p.PathPatternsAreCaseSensitive {
 		q.Set("PathPatternsAreCaseSensitive", "true")
 	}
+	if p.ContextBefore > 0 {
+		q.Set("ContextBefore", strconv.Itoa(p.ContextBefore))
+	}
+	if p.ContextAfter > 0 {
+		q.Set("ContextAfter", strconv.Itoa(p.ContextAfter))
+	}
 	// TEMP BACKCOMPAT: always set even if false so that searcher can distinguish new frontends that send
 	// these fields from old frontends that do not (and provide a default in the latter case).
 	q.Set("PatternMatchesContent", strconv.FormatBool(p.PatternMatchesContent))
@@ -533,6 +604,14 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 		}
 	}
 
+	// Zoekt reports matches line by line, so patterns that match text
+	// spanning several lines are only searched by searcher.
+	if len(zoektRepos) > 0 && args.PatternInfo.IsMultiline() {
+		tr.LazyPrintf("multi-line pattern, using searcher for %d indexed repos", len(zoektRepos))
+		searcherRepos = append(searcherRepos, zoektRepos...)
+		zoektRepos = nil
+	}
+
 	var (
 		// TODO: convert wg to an errgroup
 		wg                sync.WaitGroup
@@ -552,6 +631,16 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 				return a > b
 			})
 			unflattened = append(unflattened, matches)
+
+			// Stream the matches that fit within the limit, since
+			// flattenFileMatches drops the rest.
+			if remaining := int(args.PatternInfo.FileMatchLimit) - flattenedSize; remaining > 0 {
+				streamed := matches
+				if len(streamed) > remaining {
+					streamed = streamed[:remaining]
+				}
+				sendSearchResults(ctx, fileMatchesToSearchResults(streamed))
+			}
 			flattenedSize += len(matches)
 
 			// Stop searching once we have found enough matches. This does
@@ -566,6 +655,17 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 		}
 	}
 
+	// addRevisionMatches adds the matches of one of the searched revisions
+	// of a repository. Once all revisions have been searched, their merged
+	// matches are added with addMatches. It assumes the caller holds mu.
+	addRevisionMatches := func(revs *revisionMatches, matches []*FileMatchResolver) {
+		revs.matches = append(revs.matches, matches...)
+		revs.pending--
+		if revs.pending == 0 {
+			addMatches(mergeRevisionFileMatches(revs.matches))
+		}
+	}
+
 	// callSearcherOverRepos calls searcher on a set of repos.
 	// searcherReposFilteredFiles is an optional map of {repo name => file list}
 	// that forces the searcher to only include the file list in the
@@ -601,19 +701,29 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 			textSearchLimiter.SetLimit(len(eps) * 32)
 		}
 
-	outer:
-		for _, repoAllRevs := range searcherRepos {
-			if len(repoAllRevs.Revs) == 0 {
-				continue
-			}
-
+		// searchRepo starts the searches of the revisions of a repository. It
+		// returns false if ctx was canceled before all of them were started.
+		searchRepo := func(repoAllRevs *search.RepositoryRevisions) (bool, error) {
 			revSpecs, err := repoAllRevs.ExpandedRevSpecs(ctx)
 			if err != nil {
-				return err
+				return false, err
 			}
 
 			if len(revSpecs) >= 2 && !conf.SearchMultipleRevisionsPerRepository() {
-				return errMultipleRevsNotSupported
+				return false, errMultipleRevsNotSupported
+			}
+
+			// When several revisions of a repository are searched, their
+			// matches are merged before they are added, so that a file with
+			// the same content in several revisions is only reported once.
+			var revs *revisionMatches
+			if len(revSpecs) >= 2 {
+				revs = &revisionMatches{pending: 1}
+				defer func() {
+					mu.Lock()
+					addRevisionMatches(revs, nil)
+					mu.Unlock()
+				}()
 			}
 
 			for _, rev := range revSpecs {
@@ -621,7 +731,7 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 				// looping through searcherRepos.
 				limitCtx, limitDone, acquireErr := textSearchLimiter.Acquire(ctx)
 				if acquireErr != nil {
-					break outer
+					return false, nil
 				}
 
 				// Make a new repoRev for just the operation of searching this revspec.
@@ -633,11 +743,16 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 					if v, ok := searcherReposFilteredFiles[string(repoRev.Repo.Name)]; ok {
 						patternCopy := *args.PatternInfo
 						args.PatternInfo = &patternCopy
-						includePatternsCopy := []string{}
-						args.PatternInfo.IncludePatterns = append(includePatternsCopy, v...)
+						args.PatternInfo.FilePaths = append([]string{}, v...)
 					}
 				}
 
+				if revs != nil {
+					mu.Lock()
+					revs.pending++
+					mu.Unlock()
+				}
+
 				wg.Add(1)
 				go func(ctx context.Context, done context.CancelFunc) {
 					defer wg.Done()
@@ -650,6 +765,11 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 					}
 					mu.Lock()
 					defer mu.Unlock()
+					if revs != nil {
+						// Release this revision on every path, so that the
+						// matches of the other revisions are still added.
+						defer func() { addRevisionMatches(revs, matches) }()
+					}
 					if ctx.Err() == nil {
 						common.searched = append(common.searched, repoRev.Repo)
 					}
@@ -665,6 +785,7 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 							// handle this here, not in handleRepoSearchResult, because different callers of
 							// handleRepoSearchResult (for different result types) currently all need to
 							// handle cancellations differently.
+							matches = nil
 							return
 						}
 						if searchErr == nil {
@@ -673,9 +794,23 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 							cancel()
 						}
 					}
-					addMatches(matches)
+					if revs == nil {
+						addMatches(matches)
+					}
 				}(limitCtx, limitDone) // ends the Go routine for a call to searcher for a repo
 			} // ends the for loop iterating over repo's revs
+			return true, nil
+		}
+
+		for _, repoAllRevs := range searcherRepos {
+			if len(repoAllRevs.Revs) == 0 {
+				continue
+			}
+			if ok, err := searchRepo(repoAllRevs); err != nil {
+				return err
+			} else if !ok {
+				break
+			}
 		} // ends the for loop iterating over repos
 		return nil
 	} // ends callSearcherOverRepos
@@ -780,6 +915,45 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 	return flattened, common, nil
 }
 
+// revisionMatches collects the matches of the revisions of a repository that
+// are searched concurrently.
+type revisionMatches struct {
+	pending int // the number of revisions still being searched, plus 1 while searches are started
+	matches []*FileMatchResolver
+}
+
+// mergeRevisionFileMatches merges the file matches of several revisions of a
+// repository. Matches of a file with the same path and content (Git blob) in
+// several revisions are merged into the match of the first revision (by
+// name), and the revisions are listed in the revisions of the merged match.
+func mergeRevisionFileMatches(matches []*FileMatchResolver) []*FileMatchResolver {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return inputRevOf(matches[i]) < inputRevOf(matches[j])
+	})
+
+	type fileKey struct {
+		path, blob string
+	}
+	var (
+		merged []*FileMatchResolver
+		seen   = map[fileKey]*FileMatchResolver{}
+	)
+	for _, fm := range matches {
+		rev := inputRevOf(fm)
+		key := fileKey{path: fm.JPath, blob: fm.JBlob}
+		if existing, ok := seen[key]; ok && fm.JBlob != "" {
+			if n := len(existing.revisions); n == 0 || existing.revisions[n-1] != rev {
+				existing.revisions = append(existing.revisions, rev)
+			}
+			continue
+		}
+		fm.revisions = []string{rev}
+		seen[key] = fm
+		merged = append(merged, fm)
+	}
+	return merged
+}
+
 func flattenFileMatches(unflattened [][]*FileMatchResolver, fileMatchLimit int) []*FileMatchResolver {
 	// Return early so we don't have to worry about empty lists in later
 	// calculations.
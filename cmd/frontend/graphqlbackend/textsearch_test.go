@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -371,6 +372,63 @@ func TestSearchFilesInRepos(t *testing.T) {
 	}
 }
 
+func TestSearchFilesInRepos_multiline(t *testing.T) {
+	var searched []api.RepoName
+	var mu sync.Mutex
+	mockSearchFilesInRepo = func(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*FileMatchResolver, limitHit bool, err error) {
+		mu.Lock()
+		searched = append(searched, repo.Name)
+		mu.Unlock()
+		return []*FileMatchResolver{{uri: "git://" + string(repo.Name) + "?" + rev + "#main.go"}}, false, nil
+	}
+	defer func() { mockSearchFilesInRepo = nil }()
+
+	zoekt := &searchbackend.Zoekt{Client: &fakeSearcher{
+		result: &zoekt.SearchResult{},
+		repos: &zoekt.RepoList{Repos: []*zoekt.RepoListEntry{{
+			Repository: zoekt.Repository{
+				Name:     "foo/indexed",
+				Branches: []zoekt.RepositoryBranch{{Name: "HEAD", Version: "deadbeef"}},
+			},
+		}}},
+	}}
+
+	for _, tt := range []struct {
+		pattern string
+		want    []api.RepoName
+	}{
+		{pattern: `foo\s+bar`, want: []api.RepoName{"foo/unindexed"}},
+		{pattern: `foo\nbar`, want: []api.RepoName{"foo/indexed", "foo/unindexed"}},
+	} {
+		t.Run(tt.pattern, func(t *testing.T) {
+			searched = nil
+			q, err := query.ParseAndCheck(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			args := &search.TextParameters{
+				PatternInfo: &search.TextPatternInfo{
+					FileMatchLimit:         defaultMaxSearchResults,
+					Pattern:                tt.pattern,
+					IsRegExp:               true,
+					PathPatternsAreRegExps: true,
+				},
+				Repos:        makeRepositoryRevisions("foo/indexed", "foo/unindexed"),
+				Query:        q,
+				Zoekt:        zoekt,
+				SearcherURLs: endpoint.Static("test"),
+			}
+			if _, _, err := searchFilesInRepos(context.Background(), args); err != nil {
+				t.Fatal(err)
+			}
+			sort.Slice(searched, func(i, j int) bool { return searched[i] < searched[j] })
+			if !reflect.DeepEqual(searched, tt.want) {
+				t.Errorf("searcher searched %v, want %v", searched, tt.want)
+			}
+		})
+	}
+}
+
 func TestSearchFilesInRepos_multipleRevsPerRepo(t *testing.T) {
 	mockSearchFilesInRepo = func(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*FileMatchResolver, limitHit bool, err error) {
 		repoName := repo.Name
@@ -434,6 +492,126 @@ func TestSearchFilesInRepos_multipleRevsPerRepo(t *testing.T) {
 	}
 }
 
+func TestSearchFilesInRepos_mergeRevisions(t *testing.T) {
+	mockSearchFilesInRepo = func(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*FileMatchResolver, limitHit bool, err error) {
+		// release/1 and release/3 contain the same main.go.
+		blob := "b1"
+		if rev == "release/2" {
+			blob = "b2"
+		}
+		return []*FileMatchResolver{{
+			uri:      "git://" + string(repo.Name) + "?" + rev + "#main.go",
+			JPath:    "main.go",
+			JBlob:    blob,
+			Repo:     repo,
+			InputRev: &rev,
+		}}, false, nil
+	}
+	defer func() { mockSearchFilesInRepo = nil }()
+
+	trueVal := true
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExperimentalFeatures: &schema.ExperimentalFeatures{SearchMultipleRevisionsPerRepository: &trueVal},
+	}})
+	defer conf.Mock(nil)
+
+	q, err := query.ParseAndCheck("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &search.TextParameters{
+		PatternInfo: &search.TextPatternInfo{
+			FileMatchLimit: defaultMaxSearchResults,
+			Pattern:        "foo",
+		},
+		Repos:        makeRepositoryRevisions("foo@*refs/heads/release/*"),
+		Query:        q,
+		Zoekt:        &searchbackend.Zoekt{Client: &fakeSearcher{repos: &zoekt.RepoList{}}},
+		SearcherURLs: endpoint.Static("test"),
+	}
+	args.Repos[0].ListRefs = func(context.Context, gitserver.Repo) ([]git.Ref, error) {
+		return []git.Ref{{Name: "refs/heads/release/1"}, {Name: "refs/heads/release/2"}, {Name: "refs/heads/release/3"}, {Name: "refs/heads/master"}}, nil
+	}
+	results, common, err := searchFilesInRepos(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string][]string{}
+	for _, result := range results {
+		got[result.uri] = result.Revisions()
+	}
+	want := map[string][]string{
+		"git://foo?release/1#main.go": {"release/1", "release/3"},
+		"git://foo?release/2#main.go": {"release/2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if common.resultCount != 2 {
+		t.Errorf("got result count %d, want 2", common.resultCount)
+	}
+}
+
+func TestSearchFilesInRepos_mergeRevisionsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockSearchFilesInRepo = func(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*FileMatchResolver, limitHit bool, err error) {
+		// The search is canceled while release/2 is searched.
+		if rev == "release/2" {
+			cancel()
+			return nil, false, context.Canceled
+		}
+		return []*FileMatchResolver{{
+			uri:      "git://" + string(repo.Name) + "?" + rev + "#main.go",
+			JPath:    "main.go",
+			JBlob:    "b1",
+			Repo:     repo,
+			InputRev: &rev,
+		}}, false, nil
+	}
+	defer func() { mockSearchFilesInRepo = nil }()
+
+	trueVal := true
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExperimentalFeatures: &schema.ExperimentalFeatures{SearchMultipleRevisionsPerRepository: &trueVal},
+	}})
+	defer conf.Mock(nil)
+
+	q, err := query.ParseAndCheck("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &search.TextParameters{
+		PatternInfo: &search.TextPatternInfo{
+			FileMatchLimit: defaultMaxSearchResults,
+			Pattern:        "foo",
+		},
+		Repos:        makeRepositoryRevisions("foo@*refs/heads/release/*"),
+		Query:        q,
+		Zoekt:        &searchbackend.Zoekt{Client: &fakeSearcher{repos: &zoekt.RepoList{}}},
+		SearcherURLs: endpoint.Static("test"),
+	}
+	args.Repos[0].ListRefs = func(context.Context, gitserver.Repo) ([]git.Ref, error) {
+		return []git.Ref{{Name: "refs/heads/release/1"}, {Name: "refs/heads/release/2"}, {Name: "refs/heads/release/3"}}, nil
+	}
+	results, _, err := searchFilesInRepos(ctx, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The matches of the revisions searched before the search was canceled
+	// are still reported.
+	if len(results) != 1 || results[0].uri != "git://foo?release/1#main.go" {
+		var got []string
+		for _, result := range results {
+			got = append(got, result.uri)
+		}
+		t.Errorf("got results %v, want the match in release/1", got)
+	}
+}
+
 func TestRepoShouldBeSearched(t *testing.T) {
 	mockTextSearch = func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*FileMatchResolver, limitHit bool, err error) {
 		repoName := repo.Name
@@ -630,6 +808,44 @@ func Test_zoektSearchHEAD(t *testing.T) {
 	}
 }
 
+func Test_zoektSearchHEAD_contextLines(t *testing.T) {
+	repos := makeRepositoryRevisions("foo/bar")
+	repos[0].SetIndexedHEADCommit("abc")
+
+	searcher := &fakeSearcher{result: &zoekt.SearchResult{Files: []zoekt.FileMatch{{
+		Repository: "foo/bar",
+		FileName:   "main.go",
+		Content:    []byte("package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println()\n}\n"),
+		LineMatches: []zoekt.LineMatch{
+			{Line: []byte(`import "fmt"`), LineNumber: 3, LineFragments: []zoekt.LineFragmentMatch{{LineOffset: 8, MatchLength: 3}}},
+			{Line: []byte("\tfmt.Println()"), LineNumber: 6, LineFragments: []zoekt.LineFragmentMatch{{LineOffset: 1, MatchLength: 3}}},
+		},
+	}}}}
+
+	patternInfo := &search.TextPatternInfo{Pattern: "fmt", FileMatchLimit: defaultMaxSearchResults, PathPatternsAreRegExps: true, ContextBefore: 1}
+	if opts := zoektSearchOpts(1, patternInfo); !opts.Whole {
+		t.Error("zoekt was not asked for the content of matching files")
+	}
+	args := &search.TextParameters{
+		PatternInfo: patternInfo,
+		Zoekt:       &searchbackend.Zoekt{Client: searcher},
+	}
+	fms, _, _, err := zoektSearchHEAD(context.Background(), args, repos, false, time.Since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fms) != 1 {
+		t.Fatalf("got %d file matches, want 1", len(fms))
+	}
+	want := []*contextRange{
+		{JStartLine: 1, JPreview: "\nimport \"fmt\""},
+		{JStartLine: 4, JPreview: "func main() {\n\tfmt.Println()"},
+	}
+	if !reflect.DeepEqual(fms[0].JContextRanges, want) {
+		t.Errorf("got context ranges %+v, want %+v", fms[0].JContextRanges, want)
+	}
+}
+
 // repoURLsFakeSearcher fakes a searcher for use in
 // createNewRepoSetWithRepoHasFileInputs. It only supports setting the
 // RepoURLs field in search results, and will only evaluate search queries
@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/gituri"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"github.com/sourcegraph/sourcegraph/internal/search/contextlines"
 	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 )
@@ -69,6 +70,12 @@ func zoektSearchOpts(k int, query *search.TextPatternInfo) zoekt.SearchOptions {
 		searchOpts.MaxWallTime *= time.Duration(3 * float64(query.FileMatchLimit) / float64(defaultMaxSearchResults))
 	}
 
+	if !query.IsStructuralPat && (query.ContextBefore > 0 || query.ContextAfter > 0) {
+		// Zoekt only returns the matching lines, so we ask for the whole
+		// content of matching files to extract the context lines.
+		searchOpts.Whole = true
+	}
+
 	return searchOpts
 }
 
@@ -246,19 +253,39 @@ func zoektSearchHEAD(ctx context.Context, args *search.TextParameters, repos []*
 			}
 		}
 		matches[i] = &FileMatchResolver{
-			JPath:        file.FileName,
-			JLineMatches: lines,
-			JLimitHit:    fileLimitHit,
-			uri:          fileMatchURI(repoRev.Repo.Name, "", file.FileName),
-			symbols:      symbols,
-			Repo:         repoRev.Repo,
-			CommitID:     repoRev.IndexedHEADCommit(),
+			JPath:          file.FileName,
+			JLineMatches:   lines,
+			JLimitHit:      fileLimitHit,
+			JContextRanges: zoektContextRanges(file.Content, lines, args.PatternInfo),
+			uri:            fileMatchURI(repoRev.Repo.Name, "", file.FileName),
+			symbols:        symbols,
+			Repo:           repoRev.Repo,
+			CommitID:       repoRev.IndexedHEADCommit(),
 		}
 	}
 
 	return matches, limitHit, reposLimitHit, nil
 }
 
+// zoektContextRanges returns the context lines requested by p around the line
+// matches of a file with the given content. Content is only returned by Zoekt
+// if context lines were requested (see zoektSearchOpts).
+func zoektContextRanges(content []byte, lines []*lineMatch, p *search.TextPatternInfo) []*contextRange {
+	if content == nil || len(lines) == 0 {
+		return nil
+	}
+	lineNumbers := make([]int, len(lines))
+	for i, lm := range lines {
+		lineNumbers[i] = int(lm.JLineNumber)
+	}
+	ranges := contextlines.Ranges(content, lineNumbers, p.ContextBefore, p.ContextAfter)
+	res := make([]*contextRange, len(ranges))
+	for i, r := range ranges {
+		res[i] = &contextRange{JStartLine: int32(r.StartLine), JPreview: r.Preview}
+	}
+	return res
+}
+
 // createNewRepoSetWithRepoHasFileInputs mutates repoSet such that it accounts
 // for the `repohasfile` and `-repohasfile` flags that may have been passed in
 // the query. As a convenience it returns the mutated RepoSet.
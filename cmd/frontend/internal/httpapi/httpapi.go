@@ -48,6 +48,9 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 
 	m.Get(apirouter.RepoRefresh).Handler(trace.TraceRoute(handler(serveRepoRefresh)))
 
+	m.Get(apirouter.RepoGitInfoRefs).Handler(trace.TraceRoute(handler(serveRepoGit("info/refs"))))
+	m.Get(apirouter.RepoGitUploadPack).Handler(trace.TraceRoute(handler(serveRepoGit("git-upload-pack"))))
+
 	if githubWebhook != nil {
 		m.Get(apirouter.GitHubWebhooks).Handler(trace.TraceRoute(githubWebhook))
 	}
@@ -56,11 +59,17 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 		m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.TraceRoute(bitbucketServerWebhook))
 	}
 
+	m.Get(apirouter.GitHubPushWebhooks).Handler(trace.TraceRoute(handler(servePushWebhook(listGitHubPushWebhookSecrets, gitHubPushedRepo))))
+	m.Get(apirouter.GitLabPushWebhooks).Handler(trace.TraceRoute(handler(servePushWebhook(listGitLabPushWebhookSecrets, gitLabPushedRepo))))
+	m.Get(apirouter.BitbucketServerPushWebhooks).Handler(trace.TraceRoute(handler(servePushWebhook(listBitbucketServerPushWebhookSecrets, bitbucketServerPushedRepo))))
+
 	if envvar.SourcegraphDotComMode() {
 		m.Path("/updates").Methods("GET", "POST").Name("updatecheck").Handler(trace.TraceRoute(http.HandlerFunc(updatecheck.Handler)))
 	}
 
 	m.Get(apirouter.GraphQL).Handler(trace.TraceRoute(handler(serveGraphQL(schema))))
+	m.Get(apirouter.SearchStream).Handler(trace.TraceRoute(handler(serveSearchStream)))
+	m.Get(apirouter.SearchExport).Handler(trace.TraceRoute(handler(serveSearchExport)))
 
 	if lsifServerProxy != nil {
 		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(lsifServerProxy.UploadHandler))
@@ -119,6 +128,8 @@ func NewInternalHandler(m *mux.Router, schema *graphql.Schema) http.Handler {
 	m.Get(apirouter.SavedQueriesGetInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesGetInfo)))
 	m.Get(apirouter.SavedQueriesSetInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesSetInfo)))
 	m.Get(apirouter.SavedQueriesDeleteInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesDeleteInfo)))
+	m.Get(apirouter.SavedQueriesLatestRun).Handler(trace.TraceRoute(handler(serveSavedQueriesGetLatestRun)))
+	m.Get(apirouter.SavedQueriesAddRun).Handler(trace.TraceRoute(handler(serveSavedQueriesAddRun)))
 	m.Get(apirouter.OrgsListUsers).Handler(trace.TraceRoute(handler(serveOrgsListUsers)))
 	m.Get(apirouter.OrgsGetByName).Handler(trace.TraceRoute(handler(serveOrgsGetByName)))
 	m.Get(apirouter.UsersGetByUsername).Handler(trace.TraceRoute(handler(serveUsersGetByUsername)))
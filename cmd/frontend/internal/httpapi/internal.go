@@ -385,6 +385,69 @@ func serveSavedQueriesDeleteInfo(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+func serveSavedQueriesGetLatestRun(w http.ResponseWriter, r *http.Request) error {
+	var key string
+	err := json.NewDecoder(r.Body).Decode(&key)
+	if err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	savedSearchID, err := strconv.ParseInt(key, 10, 32)
+	if err != nil {
+		return errors.Wrap(err, "ParseInt")
+	}
+	run, err := db.SavedSearchRuns.GetLatest(r.Context(), int32(savedSearchID))
+	if err != nil {
+		return errors.Wrap(err, "SavedSearchRuns.GetLatest")
+	}
+	var result *api.SavedQueryRun
+	if run != nil {
+		result = &api.SavedQueryRun{
+			Key:          key,
+			Query:        run.Query,
+			ExecutedAt:   run.ExecutedAt,
+			ExecDuration: run.ExecDuration,
+			ResultCount:  run.ResultCount,
+			LimitHit:     run.LimitHit,
+			Results:      run.Results,
+			Added:        run.Added,
+			Removed:      run.Removed,
+		}
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
+func serveSavedQueriesAddRun(w http.ResponseWriter, r *http.Request) error {
+	var run *api.SavedQueryRun
+	err := json.NewDecoder(r.Body).Decode(&run)
+	if err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	savedSearchID, err := strconv.ParseInt(run.Key, 10, 32)
+	if err != nil {
+		return errors.Wrap(err, "ParseInt")
+	}
+	err = db.SavedSearchRuns.Create(r.Context(), &db.SavedSearchRun{
+		SavedSearchID: int32(savedSearchID),
+		Query:         run.Query,
+		ExecutedAt:    run.ExecutedAt,
+		ExecDuration:  run.ExecDuration,
+		ResultCount:   run.ResultCount,
+		LimitHit:      run.LimitHit,
+		Results:       run.Results,
+		Added:         run.Added,
+		Removed:       run.Removed,
+	})
+	if err != nil {
+		return errors.Wrap(err, "SavedSearchRuns.Create")
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("OK"))
+	return nil
+}
+
 func serveSettingsGetForSubject(w http.ResponseWriter, r *http.Request) error {
 	var subject api.SettingsSubject
 	if err := json.NewDecoder(r.Body).Decode(&subject); err != nil {
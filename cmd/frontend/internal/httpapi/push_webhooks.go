@@ -0,0 +1,243 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	gh "github.com/google/go-github/v28/github"
+	"github.com/pkg/errors"
+	"gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+)
+
+// maxPushWebhookSize is the largest push webhook payload we accept. Code
+// hosts truncate the commits in large pushes, so payloads are small.
+const maxPushWebhookSize = 25 * 1024 * 1024
+
+// pushWebhookSecret is the push webhook secret of an external service and the
+// ID of the code host whose repositories it may notify us about.
+type pushWebhookSecret struct {
+	secret    string
+	serviceID string
+}
+
+// pushedRepoFunc authenticates a push webhook request with one of secrets and
+// returns the repository which was pushed to, or nil if the request is not
+// about a push.
+type pushedRepoFunc func(r *http.Request, payload []byte, secrets []pushWebhookSecret) (*api.ExternalRepoSpec, error)
+
+// servePushWebhook returns a handler which receives webhooks which code hosts
+// send when repositories are pushed to, and updates those repositories as
+// soon as possible instead of waiting for their next scheduled update.
+func servePushWebhook(listSecrets func(context.Context) ([]pushWebhookSecret, error), pushedRepo pushedRepoFunc) func(http.ResponseWriter, *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		payload, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPushWebhookSize))
+		if err != nil {
+			return err
+		}
+
+		// 🚨 SECURITY: The secrets are only used to authenticate the request,
+		// they are never returned to the client.
+		secrets, err := listSecrets(r.Context())
+		if err != nil {
+			return err
+		}
+		if len(secrets) == 0 {
+			return &errcode.HTTPErr{Status: http.StatusUnauthorized, Err: errors.New("no push webhook secret is configured")}
+		}
+
+		spec, err := pushedRepo(r, payload, secrets)
+		if err != nil {
+			return err
+		}
+		if spec == nil {
+			return nil // Not a push.
+		}
+
+		if _, err := repoupdater.DefaultClient.EnqueueExternalRepoUpdate(r.Context(), *spec); err != nil {
+			if errcode.IsNotFound(err) {
+				// Webhooks are often configured for all repositories of an
+				// organization, not all of which are mirrored.
+				log15.Debug("push webhook for unknown repository", "repo", spec)
+				return nil
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// listGitHubPushWebhookSecrets returns the push webhook secrets of the GitHub
+// external services.
+//
+// 🚨 SECURITY: Listing external services requires the caller to be a site
+// admin, but only the secrets and code host URLs are read from them.
+func listGitHubPushWebhookSecrets(ctx context.Context) ([]pushWebhookSecret, error) {
+	conns, err := db.ExternalServices.ListGitHubConnections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var secrets []pushWebhookSecret
+	for _, c := range conns {
+		secrets = appendPushWebhookSecret(secrets, c.PushWebhookSecret, c.Url)
+	}
+	return secrets, nil
+}
+
+// listGitLabPushWebhookSecrets returns the push webhook secrets of the GitLab
+// external services.
+func listGitLabPushWebhookSecrets(ctx context.Context) ([]pushWebhookSecret, error) {
+	conns, err := db.ExternalServices.ListGitLabConnections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var secrets []pushWebhookSecret
+	for _, c := range conns {
+		secrets = appendPushWebhookSecret(secrets, c.PushWebhookSecret, c.Url)
+	}
+	return secrets, nil
+}
+
+// listBitbucketServerPushWebhookSecrets returns the push webhook secrets of
+// the Bitbucket Server external services.
+func listBitbucketServerPushWebhookSecrets(ctx context.Context) ([]pushWebhookSecret, error) {
+	conns, err := db.ExternalServices.ListBitbucketServerConnections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var secrets []pushWebhookSecret
+	for _, c := range conns {
+		secrets = appendPushWebhookSecret(secrets, c.PushWebhookSecret, c.Url)
+	}
+	return secrets, nil
+}
+
+// appendPushWebhookSecret appends the push webhook secret of an external
+// service with the given code host URL, if it has one.
+func appendPushWebhookSecret(secrets []pushWebhookSecret, secret, rawURL string) []pushWebhookSecret {
+	if secret == "" {
+		return secrets
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		log15.Warn("invalid code host URL for push webhooks", "url", rawURL, "error", err)
+		return secrets
+	}
+	return append(secrets, pushWebhookSecret{
+		secret:    secret,
+		serviceID: extsvc.NormalizeBaseURL(u).String(),
+	})
+}
+
+// validateHubSignature returns the secret which the X-Hub-Signature header of
+// r was computed with, as used by GitHub and Bitbucket Server.
+func validateHubSignature(r *http.Request, payload []byte, secrets []pushWebhookSecret) (*pushWebhookSecret, error) {
+	sig := r.Header.Get("X-Hub-Signature")
+	for i := range secrets {
+		if gh.ValidateSignature(sig, payload, []byte(secrets[i].secret)) == nil {
+			return &secrets[i], nil
+		}
+	}
+	return nil, &errcode.HTTPErr{Status: http.StatusUnauthorized, Err: errors.New("invalid webhook signature")}
+}
+
+// gitHubPushedRepo handles GitHub push events.
+func gitHubPushedRepo(r *http.Request, payload []byte, secrets []pushWebhookSecret) (*api.ExternalRepoSpec, error) {
+	s, err := validateHubSignature(r, payload, secrets)
+	if err != nil {
+		return nil, err
+	}
+	if gh.WebHookType(r) != "push" {
+		return nil, nil
+	}
+	var e gh.PushEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+	}
+	// Our GitHub repositories are identified by their GraphQL node ID.
+	if e.Repo == nil || e.Repo.GetNodeID() == "" {
+		return nil, &errcode.HTTPErr{Status: http.StatusBadRequest, Err: errors.New("push event without repository")}
+	}
+	return &api.ExternalRepoSpec{
+		ID:          e.Repo.GetNodeID(),
+		ServiceType: github.ServiceType,
+		ServiceID:   s.serviceID,
+	}, nil
+}
+
+// gitLabPushedRepo handles GitLab push and tag push events.
+func gitLabPushedRepo(r *http.Request, payload []byte, secrets []pushWebhookSecret) (*api.ExternalRepoSpec, error) {
+	// GitLab sends the secret itself rather than a signature.
+	token := []byte(r.Header.Get("X-Gitlab-Token"))
+	var s *pushWebhookSecret
+	for i := range secrets {
+		if subtle.ConstantTimeCompare(token, []byte(secrets[i].secret)) == 1 {
+			s = &secrets[i]
+			break
+		}
+	}
+	if s == nil {
+		return nil, &errcode.HTTPErr{Status: http.StatusUnauthorized, Err: errors.New("invalid webhook token")}
+	}
+
+	var e struct {
+		ObjectKind string `json:"object_kind"`
+		ProjectID  int    `json:"project_id"`
+	}
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+	}
+	if e.ObjectKind != "push" && e.ObjectKind != "tag_push" {
+		return nil, nil
+	}
+	if e.ProjectID == 0 {
+		return nil, &errcode.HTTPErr{Status: http.StatusBadRequest, Err: errors.New("push event without project")}
+	}
+	return &api.ExternalRepoSpec{
+		ID:          strconv.Itoa(e.ProjectID),
+		ServiceType: gitlab.ServiceType,
+		ServiceID:   s.serviceID,
+	}, nil
+}
+
+// bitbucketServerPushedRepo handles Bitbucket Server repo:refs_changed events,
+// which are sent for pushes.
+func bitbucketServerPushedRepo(r *http.Request, payload []byte, secrets []pushWebhookSecret) (*api.ExternalRepoSpec, error) {
+	s, err := validateHubSignature(r, payload, secrets)
+	if err != nil {
+		return nil, err
+	}
+	if r.Header.Get("X-Event-Key") != "repo:refs_changed" {
+		return nil, nil
+	}
+	var e struct {
+		Repository struct {
+			ID int `json:"id"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+	}
+	if e.Repository.ID == 0 {
+		return nil, &errcode.HTTPErr{Status: http.StatusBadRequest, Err: errors.New("push event without repository")}
+	}
+	return &api.ExternalRepoSpec{
+		ID:          strconv.Itoa(e.Repository.ID),
+		ServiceType: bitbucketserver.ServiceType,
+		ServiceID:   s.serviceID,
+	}, nil
+}
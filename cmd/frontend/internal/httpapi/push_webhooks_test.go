@@ -0,0 +1,162 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+)
+
+func TestPushWebhooks(t *testing.T) {
+	c := newTest()
+
+	db.Mocks.ExternalServices.List = func(opt db.ExternalServicesListOptions) ([]*types.ExternalService, error) {
+		switch opt.Kinds[0] {
+		case "GITHUB":
+			return []*types.ExternalService{
+				{Kind: "GITHUB", Config: `{"url": "https://github.example.com", "token": "t"}`},
+				{Kind: "GITHUB", Config: `{"url": "https://github.com", "token": "t", "pushWebhookSecret": "gh-secret"}`},
+			}, nil
+		case "GITLAB":
+			return []*types.ExternalService{
+				{Kind: "GITLAB", Config: `{"url": "https://gitlab.com", "token": "t", "pushWebhookSecret": "gl-secret"}`},
+			}, nil
+		case "BITBUCKETSERVER":
+			return []*types.ExternalService{
+				{Kind: "BITBUCKETSERVER", Config: `{"url": "https://bitbucket.example.com/", "token": "t", "pushWebhookSecret": "bbs-secret"}`},
+			}, nil
+		}
+		return nil, nil
+	}
+	defer func() { db.Mocks.ExternalServices.List = nil }()
+
+	var enqueued []api.ExternalRepoSpec
+	repoupdater.MockEnqueueExternalRepoUpdate = func(ctx context.Context, spec api.ExternalRepoSpec) (*protocol.RepoUpdateResponse, error) {
+		enqueued = append(enqueued, spec)
+		return &protocol.RepoUpdateResponse{}, nil
+	}
+	defer func() { repoupdater.MockEnqueueExternalRepoUpdate = nil }()
+
+	sign := func(secret, payload string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write([]byte(payload))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	const (
+		githubPush = `{"ref": "refs/heads/master", "repository": {"id": 1, "node_id": "MDEwOlJlcG9zaXRvcnkx"}}`
+		gitlabPush = `{"object_kind": "push", "project_id": 15, "project": {"id": 15}}`
+		bbsPush    = `{"eventKey": "repo:refs_changed", "repository": {"id": 84}}`
+	)
+
+	tests := []struct {
+		name       string
+		path       string
+		header     map[string]string
+		payload    string
+		wantStatus int
+		want       *api.ExternalRepoSpec
+	}{
+		{
+			name: "github push",
+			path: "/github-push-webhooks",
+			header: map[string]string{
+				"X-Github-Event":  "push",
+				"X-Hub-Signature": sign("gh-secret", githubPush),
+			},
+			payload:    githubPush,
+			wantStatus: http.StatusOK,
+			want:       &api.ExternalRepoSpec{ID: "MDEwOlJlcG9zaXRvcnkx", ServiceType: "github", ServiceID: "https://github.com/"},
+		},
+		{
+			name: "github ping",
+			path: "/github-push-webhooks",
+			header: map[string]string{
+				"X-Github-Event":  "ping",
+				"X-Hub-Signature": sign("gh-secret", `{}`),
+			},
+			payload:    `{}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "github wrong secret",
+			path: "/github-push-webhooks",
+			header: map[string]string{
+				"X-Github-Event":  "push",
+				"X-Hub-Signature": sign("other", githubPush),
+			},
+			payload:    githubPush,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "gitlab push",
+			path: "/gitlab-push-webhooks",
+			header: map[string]string{
+				"X-Gitlab-Event": "Push Hook",
+				"X-Gitlab-Token": "gl-secret",
+			},
+			payload:    gitlabPush,
+			wantStatus: http.StatusOK,
+			want:       &api.ExternalRepoSpec{ID: "15", ServiceType: "gitlab", ServiceID: "https://gitlab.com/"},
+		},
+		{
+			name: "gitlab wrong token",
+			path: "/gitlab-push-webhooks",
+			header: map[string]string{
+				"X-Gitlab-Event": "Push Hook",
+				"X-Gitlab-Token": "gh-secret",
+			},
+			payload:    gitlabPush,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "bitbucket server push",
+			path: "/bitbucket-server-push-webhooks",
+			header: map[string]string{
+				"X-Event-Key":     "repo:refs_changed",
+				"X-Hub-Signature": sign("bbs-secret", bbsPush),
+			},
+			payload:    bbsPush,
+			wantStatus: http.StatusOK,
+			want:       &api.ExternalRepoSpec{ID: "84", ServiceType: "bitbucketServer", ServiceID: "https://bitbucket.example.com/"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			enqueued = nil
+			req, err := http.NewRequest("POST", test.path, strings.NewReader(test.payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range test.header {
+				req.Header.Set(k, v)
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != test.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+			if test.want == nil {
+				if len(enqueued) != 0 {
+					t.Errorf("got enqueued %v, want none", enqueued)
+				}
+				return
+			}
+			if len(enqueued) != 1 || enqueued[0] != *test.want {
+				t.Errorf("got enqueued %v, want %v", enqueued, *test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/handlerutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// serveRepoGit returns a handler which proxies git smart HTTP requests for
+// op ("info/refs" or "git-upload-pack") to the gitserver holding the
+// repository, so that git clients can clone and fetch repositories from
+// Sourcegraph instead of the code host.
+func serveRepoGit(op string) func(http.ResponseWriter, *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		// 🚨 SECURITY: GetRepo only returns repositories the user has access
+		// to. gitserver doesn't check permissions.
+		repo, err := handlerutil.GetRepo(r.Context(), mux.Vars(r))
+		if err != nil {
+			return err
+		}
+
+		addr := gitserver.DefaultClient.AddrForRepo(r.Context(), repo.Name)
+		director := func(req *http.Request) {
+			req.URL.Scheme = "http"
+			req.URL.Host = addr
+			req.URL.Path = "/git/" + string(repo.Name) + "/" + op
+
+			// 🚨 SECURITY: The user's credentials for Sourcegraph are not
+			// for gitserver.
+			req.Header.Del("Authorization")
+			req.Header.Del("Cookie")
+		}
+
+		// The response is from git, not JSON.
+		w.Header().Del("Content-Type")
+
+		gitserver.DefaultReverseProxy.ServeHTTP(repo.Name, r.Method, op, director, w, r)
+		return nil
+	}
+}
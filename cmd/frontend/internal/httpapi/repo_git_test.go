@@ -0,0 +1,97 @@
+package httpapi
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+func TestRepoGit(t *testing.T) {
+	c := newTest()
+
+	gitServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h := r.Header.Get("Authorization"); h != "" {
+			t.Errorf("got Authorization header %q sent to gitserver", h)
+		}
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.RequestURI()))
+	}))
+	defer gitServer.Close()
+	u, _ := url.Parse(gitServer.URL)
+
+	origAddrs := gitserver.DefaultClient.Addrs
+	gitserver.DefaultClient.Addrs = func(context.Context) []string { return []string{u.Host} }
+	defer func() { gitserver.DefaultClient.Addrs = origAddrs }()
+
+	backend.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		if name == "github.com/gorilla/mux" {
+			return &types.Repo{ID: 2, Name: name}, nil
+		}
+		return nil, &errcode.Mock{Message: "repo not found", IsNotFound: true}
+	}
+	defer func() { backend.Mocks.Repos.GetByName = nil }()
+
+	tests := []struct {
+		name, method, path string
+		wantStatus         int
+		wantBody           string
+	}{
+		{
+			name:       "info refs",
+			method:     "GET",
+			path:       "/repos/github.com/gorilla/mux/-/git/info/refs?service=git-upload-pack",
+			wantStatus: http.StatusOK,
+			wantBody:   "GET /git/github.com/gorilla/mux/info/refs?service=git-upload-pack",
+		},
+		{
+			name:       "upload pack",
+			method:     "POST",
+			path:       "/repos/github.com/gorilla/mux/-/git/git-upload-pack",
+			wantStatus: http.StatusOK,
+			wantBody:   "POST /git/github.com/gorilla/mux/git-upload-pack",
+		},
+		{
+			name:       "inaccessible repo",
+			method:     "GET",
+			path:       "/repos/github.com/foo/secret/-/git/info/refs?service=git-upload-pack",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest(test.method, test.path, strings.NewReader(""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "token abc")
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != test.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+			if test.wantBody == "" {
+				return
+			}
+			body, _ := ioutil.ReadAll(resp.Body)
+			if string(body) != test.wantBody {
+				t.Errorf("got body %q, want %q", body, test.wantBody)
+			}
+			if ct := resp.Header["Content-Type"]; len(ct) != 1 || ct[0] != "application/x-git-upload-pack-advertisement" {
+				t.Errorf("got Content-Type %q", ct)
+			}
+		})
+	}
+}
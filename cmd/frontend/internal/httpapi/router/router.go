@@ -10,22 +10,33 @@ const (
 	LSIFUpload = "lsif.upload"
 	GraphQL    = "graphql"
 
+	SearchStream = "search.stream"
+	SearchExport = "search.export"
+
 	SrcCliVersion  = "src-cli.version"
 	SrcCliDownload = "src-cli.download"
 
 	Registry = "registry"
 
-	RepoShield  = "repo.shield"
-	RepoRefresh = "repo.refresh"
-	Telemetry   = "telemetry"
+	RepoShield        = "repo.shield"
+	RepoRefresh       = "repo.refresh"
+	RepoGitInfoRefs   = "repo.git.info-refs"
+	RepoGitUploadPack = "repo.git.upload-pack"
+	Telemetry         = "telemetry"
 
 	GitHubWebhooks          = "github.webhooks"
 	BitbucketServerWebhooks = "bitbucketServer.webhooks"
 
+	GitHubPushWebhooks          = "github.push-webhooks"
+	GitLabPushWebhooks          = "gitlab.push-webhooks"
+	BitbucketServerPushWebhooks = "bitbucketServer.push-webhooks"
+
 	SavedQueriesListAll    = "internal.saved-queries.list-all"
 	SavedQueriesGetInfo    = "internal.saved-queries.get-info"
 	SavedQueriesSetInfo    = "internal.saved-queries.set-info"
 	SavedQueriesDeleteInfo = "internal.saved-queries.delete-info"
+	SavedQueriesLatestRun  = "internal.saved-queries.get-latest-run"
+	SavedQueriesAddRun     = "internal.saved-queries.add-run"
 	SettingsGetForSubject  = "internal.settings.get-for-subject"
 	OrgsListUsers          = "internal.orgs.list-users"
 	OrgsGetByName          = "internal.orgs.get-by-name"
@@ -62,8 +73,13 @@ func New(base *mux.Router) *mux.Router {
 
 	addRegistryRoute(base)
 	addGraphQLRoute(base)
+	base.Path("/search/stream").Methods("GET").Name(SearchStream)
+	base.Path("/search/export").Methods("GET").Name(SearchExport)
 	base.Path("/github-webhooks").Methods("POST").Name(GitHubWebhooks)
 	base.Path("/bitbucket-server-webhooks").Methods("POST").Name(BitbucketServerWebhooks)
+	base.Path("/github-push-webhooks").Methods("POST").Name(GitHubPushWebhooks)
+	base.Path("/gitlab-push-webhooks").Methods("POST").Name(GitLabPushWebhooks)
+	base.Path("/bitbucket-server-push-webhooks").Methods("POST").Name(BitbucketServerPushWebhooks)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
 	base.Path("/src-cli/version").Methods("GET").Name(SrcCliVersion)
 	base.Path("/src-cli/{rest:.*}").Methods("GET").Name(SrcCliDownload)
@@ -77,6 +93,10 @@ func New(base *mux.Router) *mux.Router {
 	repo.Path("/shield").Methods("GET").Name(RepoShield)
 	repo.Path("/refresh").Methods("POST").Name(RepoRefresh)
 
+	// Git smart HTTP, so that the repo can be cloned from /.api/repos/{Repo}/-/git.
+	repo.Path("/git/info/refs").Methods("GET").Name(RepoGitInfoRefs)
+	repo.Path("/git/git-upload-pack").Methods("POST").Name(RepoGitUploadPack)
+
 	return base
 }
 
@@ -92,6 +112,8 @@ func NewInternal(base *mux.Router) *mux.Router {
 	base.Path("/saved-queries/get-info").Methods("POST").Name(SavedQueriesGetInfo)
 	base.Path("/saved-queries/set-info").Methods("POST").Name(SavedQueriesSetInfo)
 	base.Path("/saved-queries/delete-info").Methods("POST").Name(SavedQueriesDeleteInfo)
+	base.Path("/saved-queries/get-latest-run").Methods("POST").Name(SavedQueriesLatestRun)
+	base.Path("/saved-queries/add-run").Methods("POST").Name(SavedQueriesAddRun)
 	base.Path("/settings/get-for-subject").Methods("POST").Name(SettingsGetForSubject)
 	base.Path("/orgs/list-users").Methods("POST").Name(OrgsListUsers)
 	base.Path("/orgs/get-by-name").Methods("POST").Name(OrgsGetByName)
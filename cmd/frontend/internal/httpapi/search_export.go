@@ -0,0 +1,69 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/searchexport"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// serveSearchExport streams the complete results of a search. The form values
+// are q (the search query), v (the query syntax version, default V2), t (the
+// pattern type) and format (jsonl or csv, default jsonl).
+func serveSearchExport(w http.ResponseWriter, r *http.Request) error {
+	if !actor.FromContext(r.Context()).IsAuthenticated() {
+		return &errcode.HTTPErr{Status: http.StatusUnauthorized, Err: fmt.Errorf("search exports require an authenticated user")}
+	}
+	format, err := searchexport.ParseFormat(r.FormValue("format"))
+	if err != nil {
+		return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+	}
+	args := &graphqlbackend.SearchArgs{
+		Query:   r.FormValue("q"),
+		Version: r.FormValue("v"),
+	}
+	if args.Query == "" {
+		return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: fmt.Errorf("missing search query")}
+	}
+	if args.Version == "" {
+		args.Version = "V2"
+	}
+	if t := r.FormValue("t"); t != "" {
+		args.PatternType = &t
+	}
+
+	ew := &exportResponseWriter{w: w, format: format}
+	_, err = searchexport.Write(r.Context(), ew, args, format)
+	if err != nil && !ew.started {
+		return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+	}
+	// Errors after the first write are the last row of the export.
+	return nil
+}
+
+// exportResponseWriter sets the headers of a search export before its first
+// write, so that errors that happen before any results are written can still
+// be responded with.
+type exportResponseWriter struct {
+	w       http.ResponseWriter
+	format  searchexport.Format
+	started bool
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.w.Header().Set("Content-Type", w.format.ContentType())
+		w.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="search-export.%s"`, w.format))
+	}
+	return w.w.Write(p)
+}
+
+func (w *exportResponseWriter) Flush() {
+	if f, ok := w.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
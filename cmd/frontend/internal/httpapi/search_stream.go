@@ -0,0 +1,153 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// serveSearchStream runs a search and streams its results to the client as
+// server-sent events. The query parameters are q (the search query), v (the
+// query syntax version, default V2) and t (the pattern type).
+//
+// The events are:
+//
+//   - matches: a JSON array of graphqlbackend.StreamMatch
+//   - progress: a JSON graphqlbackend.SearchProgress
+//   - alert: a JSON searchStreamAlert
+//   - error: a JSON searchStreamError
+//   - done: a JSON searchStreamDone, always the last event
+func serveSearchStream(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+	args := &graphqlbackend.SearchArgs{
+		Query:   q.Get("q"),
+		Version: q.Get("v"),
+	}
+	if args.Version == "" {
+		args.Version = "V2"
+	}
+	if t := q.Get("t"); t != "" {
+		args.PatternType = &t
+	}
+
+	ew, err := newEventStreamWriter(w)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	results, err := graphqlbackend.StreamSearch(r.Context(), args, func(event graphqlbackend.SearchEvent) {
+		if len(event.Results) > 0 {
+			ew.event("matches", graphqlbackend.StreamMatches(event.Results))
+		}
+		if event.Progress != nil {
+			ew.event("progress", event.Progress)
+		}
+	})
+
+	done := searchStreamDone{ElapsedMilliseconds: time.Since(start).Milliseconds()}
+	if err != nil {
+		ew.event("error", searchStreamError{Message: err.Error()})
+	} else if results != nil {
+		if alert := results.Alert(); alert != nil {
+			a := searchStreamAlert{Title: alert.Title()}
+			if d := alert.Description(); d != nil {
+				a.Description = *d
+			}
+			if pqs := alert.ProposedQueries(); pqs != nil {
+				for _, pq := range *pqs {
+					p := searchStreamProposedQuery{Query: pq.Query()}
+					if d := pq.Description(); d != nil {
+						p.Description = *d
+					}
+					a.ProposedQueries = append(a.ProposedQueries, p)
+				}
+			}
+			ew.event("alert", a)
+		}
+		done.MatchCount = results.MatchCount()
+		done.LimitHit = results.LimitHit()
+		done.Progress = graphqlbackend.SearchProgress{
+			Repositories: int(results.RepositoriesCount()),
+			Searched:     len(results.RepositoriesSearched()),
+			Indexed:      len(results.IndexedRepositoriesSearched()),
+			Cloning:      len(results.Cloning()),
+			Missing:      len(results.Missing()),
+			Timedout:     len(results.Timedout()),
+			LimitHit:     done.LimitHit,
+		}
+	}
+	ew.event("done", done)
+	return nil
+}
+
+type searchStreamError struct {
+	Message string `json:"message"`
+}
+
+type searchStreamDone struct {
+	MatchCount          int32                         `json:"matchCount"`
+	LimitHit            bool                          `json:"limitHit"`
+	ElapsedMilliseconds int64                         `json:"elapsedMilliseconds"`
+	Progress            graphqlbackend.SearchProgress `json:"progress"`
+}
+
+type searchStreamAlert struct {
+	Title           string                      `json:"title"`
+	Description     string                      `json:"description,omitempty"`
+	ProposedQueries []searchStreamProposedQuery `json:"proposedQueries,omitempty"`
+}
+
+type searchStreamProposedQuery struct {
+	Description string `json:"description,omitempty"`
+	Query       string `json:"query"`
+}
+
+// eventStreamWriter writes server-sent events, flushing after each one.
+type eventStreamWriter struct {
+	w     http.ResponseWriter
+	flush func()
+	err   error // the first write error, after which events are dropped
+}
+
+func newEventStreamWriter(w http.ResponseWriter) (*eventStreamWriter, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("streaming is not supported by the response writer")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	// Opt out of the gzip middleware, which buffers small writes until it
+	// has enough data to compress and would hold back the first events.
+	w.Header().Set("Content-Encoding", "identity")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &eventStreamWriter{w: w, flush: flusher.Flush}, nil
+}
+
+// event writes an event with the given name and JSON-encoded data.
+func (ew *eventStreamWriter) event(name string, data interface{}) {
+	if ew.err != nil {
+		return
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		log15.Error("streaming search: failed to encode event", "event", name, "error", err)
+		return
+	}
+	if _, err := fmt.Fprintf(ew.w, "event: %s\ndata: %s\n\n", name, b); err != nil {
+		// The client went away. The search is canceled along with the
+		// request context.
+		ew.err = err
+		return
+	}
+	ew.flush()
+}
@@ -0,0 +1,28 @@
+package httpapi
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEventStreamWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ew, err := newEventStreamWriter(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ew.event("progress", map[string]int{"searched": 2})
+	ew.event("done", searchStreamDone{MatchCount: 3})
+
+	if got, want := rec.Header().Get("Content-Type"), "text/event-stream"; got != want {
+		t.Errorf("got Content-Type %q, want %q", got, want)
+	}
+	want := "event: progress\ndata: {\"searched\":2}\n\n" +
+		"event: done\ndata: {\"matchCount\":3,\"limitHit\":false,\"elapsedMilliseconds\":0,\"progress\":{\"repositories\":0,\"searched\":0,\"indexed\":0,\"cloning\":0,\"missing\":0,\"timedout\":0,\"limitHit\":false}}\n\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("got body\n%s\nwant\n%s", got, want)
+	}
+	if !rec.Flushed {
+		t.Error("events were not flushed")
+	}
+}
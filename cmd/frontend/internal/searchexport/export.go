@@ -0,0 +1,88 @@
+// Package searchexport writes the complete result sets of searches as JSONL
+// or CSV.
+//
+// Exports are streamed to the client while the search runs, so they don't
+// keep any state on the frontend and any replica can serve them.
+package searchexport
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+// exportTimeout is how long a single export may run.
+const exportTimeout = time.Hour
+
+// exportSearch is graphqlbackend.ExportSearch, overridden in tests.
+var exportSearch = graphqlbackend.ExportSearch
+
+// flusher is implemented by writers that buffer, like http.ResponseWriter.
+type flusher interface {
+	Flush()
+}
+
+// Write runs the search described by args to completion and writes its
+// results to w in the given format, one row per line match, symbol or
+// matching path. The rows of each page of results are flushed to w as they
+// are found. It returns the number of rows written.
+//
+// If the search fails before anything was written to w, Write returns the
+// error without writing anything, so that the caller can report it.
+// Otherwise the error is also written as the last row, which only has its
+// error column set.
+func Write(ctx context.Context, w io.Writer, args *graphqlbackend.SearchArgs, format Format) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+
+	cw := &countingWriter{w: w}
+	rw, err := newRowWriter(cw, format)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	err = exportSearch(ctx, args, func(matches []graphqlbackend.StreamMatch) error {
+		for _, m := range matches {
+			for _, r := range rows(m) {
+				if err := rw.write(r); err != nil {
+					return err
+				}
+				n++
+			}
+		}
+		return flush(w, rw)
+	})
+	if err != nil {
+		if cw.n > 0 {
+			_ = rw.write(row{Error: err.Error()})
+			_ = flush(w, rw)
+		}
+		return n, err
+	}
+	return n, flush(w, rw)
+}
+
+func flush(w io.Writer, rw rowWriter) error {
+	if err := rw.flush(); err != nil {
+		return err
+	}
+	if f, ok := w.(flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
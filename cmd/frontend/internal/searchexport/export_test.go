@@ -0,0 +1,102 @@
+package searchexport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+func TestWrite(t *testing.T) {
+	exportSearch = func(ctx context.Context, args *graphqlbackend.SearchArgs, fn func([]graphqlbackend.StreamMatch) error) error {
+		if args.Query == "fail" {
+			return errors.New("boom")
+		}
+		if err := fn([]graphqlbackend.StreamMatch{
+			{
+				Repository:  "r",
+				Commit:      "c",
+				Path:        "a.go",
+				LineMatches: []graphqlbackend.StreamLineMatch{{LineNumber: 0, Preview: `foo, "bar"`}},
+			},
+		}); err != nil {
+			return err
+		}
+		if err := fn([]graphqlbackend.StreamMatch{
+			{Repository: "r", Commit: "c", Path: "foo.go"},
+			{
+				Repository: "r",
+				Commit:     "c",
+				Path:       "b.go",
+				Symbols:    []graphqlbackend.StreamSymbol{{Name: "Foo", Kind: "FUNCTION", Line: 3}},
+			},
+		}); err != nil {
+			return err
+		}
+		if args.Query == "fail later" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+	defer func() { exportSearch = graphqlbackend.ExportSearch }()
+
+	tests := []struct {
+		name    string
+		query   string
+		format  Format
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "csv",
+			query:  "foo",
+			format: FormatCSV,
+			want: `repository,commit,path,line,preview,symbolKind,error
+r,c,a.go,1,"foo, ""bar""",,
+r,c,foo.go,,,,
+r,c,b.go,3,Foo,FUNCTION,
+`,
+		},
+		{
+			name:   "jsonl",
+			query:  "foo",
+			format: FormatJSONL,
+			want: `{"repository":"r","commit":"c","path":"a.go","line":1,"preview":"foo, \"bar\""}
+{"repository":"r","commit":"c","path":"foo.go"}
+{"repository":"r","commit":"c","path":"b.go","line":3,"preview":"Foo","symbolKind":"FUNCTION"}
+`,
+		},
+		{
+			name:    "error before results",
+			query:   "fail",
+			format:  FormatCSV,
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:   "error after results",
+			query:  "fail later",
+			format: FormatJSONL,
+			want: `{"repository":"r","commit":"c","path":"a.go","line":1,"preview":"foo, \"bar\""}
+{"repository":"r","commit":"c","path":"foo.go"}
+{"repository":"r","commit":"c","path":"b.go","line":3,"preview":"Foo","symbolKind":"FUNCTION"}
+{"error":"boom"}
+`,
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			_, err := Write(context.Background(), &buf, &graphqlbackend.SearchArgs{Query: test.query}, test.format)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, test.wantErr)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("got\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
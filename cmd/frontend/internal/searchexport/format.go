@@ -0,0 +1,127 @@
+package searchexport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+// Format is the file format of an export.
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+)
+
+// ParseFormat returns the format named s. The empty string means JSONL.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatJSONL:
+		return FormatJSONL, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("invalid export format %q (valid formats are: jsonl, csv)", s)
+	}
+}
+
+// ContentType returns the MIME type of files in the format.
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson; charset=utf-8"
+}
+
+// row is a single exported match: a line match, a symbol or a file whose
+// path matched. The last row of a failed export only has its Error set.
+type row struct {
+	Repository string `json:"repository,omitempty"`
+	Commit     string `json:"commit,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Line       int    `json:"line,omitempty"`    // 1-based, or 0 for path matches
+	Preview    string `json:"preview,omitempty"` // the matched line or the symbol name
+	SymbolKind string `json:"symbolKind,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+var csvHeader = []string{"repository", "commit", "path", "line", "preview", "symbolKind", "error"}
+
+// rows returns the rows for a match.
+func rows(m graphqlbackend.StreamMatch) []row {
+	base := row{Repository: m.Repository, Commit: m.Commit, Path: m.Path}
+	var rs []row
+	for _, lm := range m.LineMatches {
+		r := base
+		r.Line = int(lm.LineNumber) + 1
+		r.Preview = lm.Preview
+		rs = append(rs, r)
+	}
+	for _, s := range m.Symbols {
+		r := base
+		r.Line = s.Line
+		r.Preview = s.Name
+		r.SymbolKind = s.Kind
+		rs = append(rs, r)
+	}
+	if len(rs) == 0 {
+		rs = append(rs, base)
+	}
+	return rs
+}
+
+// rowWriter writes rows in a format.
+type rowWriter interface {
+	write(row) error
+	// flush writes any buffered data to the underlying writer.
+	flush() error
+}
+
+func newRowWriter(w io.Writer, format Format) (rowWriter, error) {
+	if format == FormatCSV {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return nil, err
+		}
+		return &csvRowWriter{w: cw}, nil
+	}
+	bw := bufio.NewWriter(w)
+	return &jsonlRowWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+}
+
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvRowWriter) write(r row) error {
+	line := ""
+	if r.Line > 0 {
+		line = strconv.Itoa(r.Line)
+	}
+	return w.w.Write([]string{r.Repository, r.Commit, r.Path, line, r.Preview, r.SymbolKind, r.Error})
+}
+
+func (w *csvRowWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type jsonlRowWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (w *jsonlRowWriter) write(r row) error {
+	// Encode terminates each value with a newline.
+	return w.enc.Encode(r)
+}
+
+func (w *jsonlRowWriter) flush() error {
+	return w.w.Flush()
+}
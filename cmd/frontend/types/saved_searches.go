@@ -10,4 +10,7 @@ type SavedSearch struct {
 	UserID          *int32  // if non-nil, the owner is this user. UserID/OrgID are mutually exclusive.
 	OrgID           *int32  // if non-nil, the owner is this organization. UserID/OrgID are mutually exclusive.
 	SlackWebhookURL *string // if non-nil && NotifySlack == true, indicates that this Slack webhook URL should be used instead of the owners default Slack webhook.
+	NotifyWebhook   bool    // whether or not to notify the owner(s) of this saved search by posting to WebhookURL
+	WebhookURL      *string // if non-nil && NotifyWebhook == true, the URL that notifications are posted to.
+	WebhookSecret   *string // if non-empty, the secret used to sign webhook payloads. Never returned to clients.
 }
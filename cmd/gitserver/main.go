@@ -21,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	gitserverclient "github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/tracer"
 )
 
@@ -29,6 +30,9 @@ var (
 	runRepoCleanup, _ = strconv.ParseBool(env.Get("SRC_RUN_REPO_CLEANUP", "", "Periodically remove inactive repositories."))
 	wantPctFree       = env.Get("SRC_REPOS_DESIRED_PERCENT_FREE", "10", "Target percentage of free space on disk.")
 	janitorInterval   = env.Get("SRC_REPOS_JANITOR_INTERVAL", "1m", "Interval between cleanup runs")
+	maintInterval     = env.Get("SRC_REPOS_MAINTENANCE_INTERVAL", "24h", "Interval between git gc, commit-graph and bitmap maintenance of each repository. 0 disables maintenance.")
+	maintConcurrency  = env.Get("SRC_REPOS_MAINTENANCE_CONCURRENCY", "1", "Maximum number of repositories maintained at the same time.")
+	archiveCacheSize  = env.Get("SRC_ARCHIVE_CACHE_SIZE_MB", "0", "Maximum size of the cache of recently requested archives in megabytes. 0 disables the cache.")
 )
 
 func main() {
@@ -47,10 +51,31 @@ func main() {
 	if err != nil {
 		log.Fatalf("parsing $SRC_REPOS_DESIRED_PERCENT_FREE: %v", err)
 	}
+	maintInterval2, err := time.ParseDuration(maintInterval)
+	if err != nil {
+		log.Fatalf("parsing $SRC_REPOS_MAINTENANCE_INTERVAL: %v", err)
+	}
+	maintConcurrency2, err := strconv.Atoi(maintConcurrency)
+	if err != nil {
+		log.Fatalf("parsing $SRC_REPOS_MAINTENANCE_CONCURRENCY: %v", err)
+	}
+	archiveCacheSize2, err := strconv.ParseInt(archiveCacheSize, 10, 64)
+	if err != nil {
+		log.Fatalf("parsing $SRC_ARCHIVE_CACHE_SIZE_MB: %v", err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Fatalf("failed to get hostname: %s", err)
+	}
 	gitserver := server.Server{
 		ReposDir:                reposDir,
 		DeleteStaleRepositories: runRepoCleanup,
 		DesiredPercentFree:      wantPctFree2,
+		Hostname:                hostname,
+		GitServers:              gitserverclient.DefaultClient,
+		MaintenanceInterval:     maintInterval2,
+		MaintenanceConcurrency:  maintConcurrency2,
+		ArchiveCacheSizeBytes:   archiveCacheSize2 * 1024 * 1024,
 	}
 	gitserver.RegisterMetrics()
 
@@ -77,6 +102,12 @@ func main() {
 			time.Sleep(janitorInterval2)
 		}
 	}()
+	go func() {
+		for {
+			gitserver.Maintainer()
+			time.Sleep(janitorInterval2)
+		}
+	}()
 
 	port := "3178"
 	host := ""
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/diskcache"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func init() {
+	prometheus.MustRegister(archiveCacheRequests)
+}
+
+var archiveCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "gitserver",
+	Name:      "archive_cache_requests",
+	Help:      "number of archive requests served from the archive cache, by whether the archive was cached",
+}, []string{"result"})
+
+// archiveArgs returns the arguments of the git archive command which
+// produces an archive of treeish.
+func archiveArgs(treeish, format string, paths []string) []string {
+	args := []string{
+		"archive",
+
+		// Suppresses fatal error when the repo contains paths matching **/.git/** and instead
+		// includes those files (to allow archiving invalid such repos). This is unexpected
+		// behavior; the --worktree-attributes flag should merely let us specify a gitattributes
+		// file that contains `**/.git/** export-ignore`, but it actually makes everything work as
+		// desired. Tested by the "repo with .git dir" test case.
+		"--worktree-attributes",
+
+		"--format=" + format,
+	}
+
+	if format == "zip" {
+		// Compression level of 0 (no compression) seems to perform the
+		// best overall on fast network links, but this has not been tuned
+		// thoroughly.
+		args = append(args, "-0")
+	}
+
+	args = append(args, treeish, "--")
+	return append(args, paths...)
+}
+
+// newArchiveCache returns the store for cached archives, which lives in our
+// temporary directory and is thus cleared when gitserver restarts.
+func (s *Server) newArchiveCache() *diskcache.Store {
+	return &diskcache.Store{
+		Dir:       filepath.Join(s.ReposDir, tempDirName, "archives"),
+		Component: "gitserver-archive",
+
+		// The archive is shared by all requests waiting for it, so one of
+		// them going away must not cancel it.
+		BackgroundTimeout: longGitCommandTimeout,
+	}
+}
+
+// serveCachedArchive serves the archive of treeish from the archive cache,
+// creating it if it is missing. Concurrent requests for the same archive wait
+// for a single git archive command. Range requests are supported so that
+// clients can resume large downloads.
+//
+// It returns false without writing a response if the archive can't be served
+// from the cache, for example because the repository is not cloned or
+// treeish does not exist. The caller should then run git archive through
+// exec, which reports those cases.
+func (s *Server) serveCachedArchive(w http.ResponseWriter, r *http.Request, repo api.RepoName, treeish, format string, paths []string) bool {
+	repo = protocol.NormalizeRepo(repo)
+	dir := s.dir(repo)
+	if _, cloning := s.locker.Status(dir); cloning || !repoCloned(dir) {
+		return false
+	}
+
+	// Cache archives by object ID rather than by treeish, which may be a
+	// branch or other ref which moves.
+	oid, err := resolveObjectID(r.Context(), dir, treeish)
+	if err != nil {
+		return false
+	}
+	args := archiveArgs(oid, format, paths)
+	h := sha256.Sum256([]byte(string(repo) + "\x00" + strings.Join(args, "\x00")))
+	key := hex.EncodeToString(h[:])
+
+	result := "hit"
+	f, err := s.archiveCache.OpenWithPath(r.Context(), key, func(ctx context.Context, path string) error {
+		result = "miss"
+		return createArchive(ctx, dir, args, path)
+	})
+	if err != nil {
+		archiveCacheRequests.WithLabelValues("error").Inc()
+		log15.Warn("failed to create cached archive", "repo", repo, "treeish", treeish, "error", err)
+		return false
+	}
+	defer f.Close()
+	archiveCacheRequests.WithLabelValues(result).Inc()
+	s.recordAccess(repo)
+
+	contentType := "application/x-tar"
+	if format == "zip" {
+		contentType = "application/zip"
+	}
+	w.Header().Set("Content-Type", contentType)
+	// The ETag lets clients resume downloads with If-Range, and identifies
+	// the response as a cached archive, which has no exec trailers.
+	w.Header().Set("ETag", `"`+key+`"`)
+	// We don't set Last-Modified since reading a cached archive updates its
+	// modification time.
+	http.ServeContent(w, r, "", time.Time{}, f)
+	return true
+}
+
+// resolveObjectID returns the object ID treeish refers to in the repository
+// at dir.
+func resolveObjectID(ctx context.Context, dir GitDir, treeish string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, shortGitCommandTimeout([]string{"rev-parse"}))
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", treeish+"^{object}")
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", wrapCmdError(cmd, err)
+	}
+	oid := strings.TrimSpace(string(out))
+	if !isAbsoluteRevision(oid) {
+		return "", errors.Errorf("unexpected object ID %q", oid)
+	}
+	return oid, nil
+}
+
+// createArchive writes the output of git archive with args to path.
+func createArchive(ctx context.Context, dir GitDir, args []string, path string) error {
+	if isPartialClone(dir) {
+		if err := prefetchArchiveBlobs(ctx, dir, args); err != nil {
+			log15.Warn("failed to prefetch blobs for archive", "dir", dir, "error", err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = string(dir)
+	cmd.Stdout = f
+	cmd.Stderr = &limitWriter{W: &stderr, N: 1024}
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "git archive failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return f.Close()
+}
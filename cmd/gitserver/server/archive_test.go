@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestHandleArchive_Cached(t *testing.T) {
+	remote, cleanup1 := tmpDir(t)
+	defer cleanup1()
+
+	cmd := func(dir string, name string, arg ...string) string {
+		t.Helper()
+		c := exec.Command(name, arg...)
+		c.Dir = dir
+		c.Env = []string{
+			"GIT_COMMITTER_NAME=a",
+			"GIT_COMMITTER_EMAIL=a@a.com",
+			"GIT_AUTHOR_NAME=a",
+			"GIT_AUTHOR_EMAIL=a@a.com",
+		}
+		b, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s %s failed: %s", name, strings.Join(arg, " "), err)
+		}
+		return strings.TrimSpace(string(b))
+	}
+	cmd(remote, "git", "init", ".")
+	cmd(remote, "sh", "-c", "echo hello > README")
+	cmd(remote, "git", "add", ".")
+	cmd(remote, "git", "commit", "-m", "first")
+	head := cmd(remote, "git", "rev-parse", "HEAD")
+
+	reposDir, cleanup2 := tmpDir(t)
+	defer cleanup2()
+	s := &Server{
+		ReposDir:              reposDir,
+		ArchiveCacheSizeBytes: 1 << 30,
+	}
+	h := s.Handler()
+
+	const repo = api.RepoName("example.com/foo/bar")
+	if _, err := s.cloneRepo(context.Background(), repo, remote, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := func(treeish string, header http.Header) *httptest.ResponseRecorder {
+		q := url.Values{"repo": {string(repo)}, "treeish": {treeish}, "format": {"tar"}}
+		req := httptest.NewRequest("GET", "/archive?"+q.Encode(), nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Concurrent requests for the same commit share one cached archive,
+	// whichever way they refer to it.
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 4)
+	for i := range responses {
+		treeish := "HEAD"
+		if i%2 == 1 {
+			treeish = head
+		}
+		wg.Add(1)
+		go func(i int, treeish string) {
+			defer wg.Done()
+			responses[i] = archive(treeish, nil)
+		}(i, treeish)
+	}
+	wg.Wait()
+	full := responses[0]
+	for i, rr := range responses {
+		if rr.Code != http.StatusOK || rr.Header().Get("ETag") == "" {
+			t.Fatalf("response %d: got status %d and ETag %q, want 200 with an ETag", i, rr.Code, rr.Header().Get("ETag"))
+		}
+		if rr.Header().Get("ETag") != full.Header().Get("ETag") || rr.Body.String() != full.Body.String() {
+			t.Errorf("response %d differs from the first response", i)
+		}
+	}
+	if files, _ := ioutil.ReadDir(s.archiveCache.Dir); len(files) != 1 {
+		t.Errorf("got %d files in the archive cache, want 1", len(files))
+	}
+
+	// Downloads can be resumed.
+	rr := archive("HEAD", http.Header{
+		"Range":    {"bytes=100-"},
+		"If-Range": {full.Header().Get("ETag")},
+	})
+	if rr.Code != http.StatusPartialContent {
+		t.Fatalf("got status %d for range request, want 206", rr.Code)
+	}
+	if rr.Body.String() != full.Body.String()[100:] {
+		t.Error("range response is not the rest of the archive")
+	}
+
+	// Unknown revisions are reported by git archive as before.
+	rr = archive("doesnotexist", nil)
+	if rr.Header().Get("ETag") != "" || rr.Header().Get("Trailer") == "" {
+		t.Errorf("got uncached response headers %v for unknown revision, want exec trailers", rr.Header())
+	}
+}
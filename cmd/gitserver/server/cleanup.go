@@ -7,10 +7,10 @@ import (
 	"hash/fnv"
 	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -29,6 +30,7 @@ import (
 func init() {
 	prometheus.MustRegister(reposRemoved)
 	prometheus.MustRegister(reposRecloned)
+	prometheus.MustRegister(reposEvicted)
 }
 
 const (
@@ -53,12 +55,23 @@ var reposRecloned = prometheus.NewCounter(prometheus.CounterOpts{
 	Help:      "number of repos removed and recloned due to age",
 })
 
+var reposEvicted = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "gitserver",
+	Name:      "repos_evicted",
+	Help:      "number of repos removed during cleanup to free up disk space",
+})
+
 // cleanupRepos walks the repos directory and performs maintenance tasks:
 //
 // 1. Remove corrupt repos.
 // 2. Remove stale lock files.
 // 3. Remove inactive repos on sourcegraph.com
-// 4. Reclone repos after a while. (simulate git gc)
+// 4. Remove repos which moved to other gitservers. (when rebalancing)
+// 5. Reclone repos after a while. (simulate git gc)
+// 6. Shrink the archive cache.
+//
+// Git maintenance runs separately, see Maintainer.
 func (s *Server) cleanupRepos() {
 	bCtx, bCancel := s.serverContext()
 	defer bCancel()
@@ -79,6 +92,29 @@ func (s *Server) cleanupRepos() {
 		return true, nil
 	}
 
+	rebalancing := s.rebalancingEnabled()
+	maybeRemoveRebalanced := func(dir GitDir) (done bool, err error) {
+		if !rebalancing {
+			return false, nil
+		}
+
+		ctx, cancel := context.WithTimeout(bCtx, time.Minute)
+		defer cancel()
+
+		repo := s.name(dir)
+		rebalanced, err := s.isRebalanced(ctx, repo)
+		if !rebalanced || err != nil {
+			return false, err
+		}
+
+		log15.Info("removing repo which moved to another gitserver", "repo", repo)
+		if err := s.removeRepoDirectory(dir); err != nil {
+			return true, err
+		}
+		reposRebalanced.Inc()
+		return true, nil
+	}
+
 	ensureGitAttributes := func(dir GitDir) (done bool, err error) {
 		return false, setGitAttributes(dir)
 	}
@@ -129,7 +165,17 @@ func (s *Server) cleanupRepos() {
 			return false, errors.Wrap(err, "failed to get remote URL")
 		}
 
-		if _, err := s.cloneRepo(ctx, repo, remoteURL, &cloneOptions{Block: true, Overwrite: true}); err != nil {
+		limits, err := getCloneOptions(dir)
+		if err != nil {
+			return false, err
+		}
+
+		overrides, err := getGitOverrides(dir)
+		if err != nil {
+			return false, err
+		}
+
+		if _, err := s.cloneRepo(ctx, repo, remoteURL, &cloneOptions{Block: true, Overwrite: true, Limits: limits, Overrides: overrides}); err != nil {
 			return true, err
 		}
 		reposRecloned.Inc()
@@ -179,6 +225,9 @@ func (s *Server) cleanupRepos() {
 		// If git is interrupted it can leave lock files lying around. It does
 		// not clean these up, and instead fails commands.
 		{"remove stale locks", removeStaleLocks},
+		// When the set of gitservers changes, repositories move to other
+		// gitservers. Once they have a clone, ours is no longer needed.
+		{"maybe remove rebalanced", maybeRemoveRebalanced},
 		// We always want to have the same git attributes file at
 		// info/attributes.
 		{"ensure git attributes", ensureGitAttributes},
@@ -187,6 +236,9 @@ func (s *Server) cleanupRepos() {
 		// these problems. git gc is slow and resource intensive. It is
 		// cheaper and faster to just reclone the repository.
 		{"maybe reclone", maybeReclone},
+		// Between reclones, keep the repository well packed and write the
+		// indexes which speed up history walks. These run once the walk is
+		// done, with bounded concurrency.
 	}
 
 	err := bestEffortWalk(s.ReposDir, func(dir string, fi os.FileInfo) error {
@@ -220,6 +272,16 @@ func (s *Server) cleanupRepos() {
 		log15.Error("cleanup: error iterating over repositories", "error", err)
 	}
 
+	if err := s.saveAccesses(); err != nil {
+		log15.Error("cleanup: error saving repository access scores", "error", err)
+	}
+
+	if s.archiveCache != nil {
+		if _, err := s.archiveCache.Evict(s.ArchiveCacheSizeBytes); err != nil {
+			log15.Error("cleanup: error evicting cached archives", "error", err)
+		}
+	}
+
 	if s.DiskSizer == nil {
 		s.DiskSizer = &StatDiskSizer{}
 	}
@@ -342,30 +404,34 @@ func device(f string) (int64, error) {
 	return int64(stat.Dev), nil
 }
 
-// freeUpSpace removes git directories under ReposDir, in order from least
-// recently to most recently used, until it has freed howManyBytesToFree.
+// freeUpSpace removes cached archives and then git directories under
+// ReposDir, in order from least to most valuable (see evictionCandidates),
+// until it has freed howManyBytesToFree.
 func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
-	// Get the git directories and their mod times.
+	if howManyBytesToFree <= 0 {
+		return nil
+	}
+
+	// Cached archives are cheap to recreate, so they are removed before any
+	// repository.
+	spaceFreed, err := s.evictArchives(howManyBytesToFree)
+	if err != nil {
+		return errors.Wrap(err, "evicting cached archives")
+	}
+	if spaceFreed >= howManyBytesToFree {
+		return nil
+	}
+
 	gitDirs, err := s.findGitDirs()
 	if err != nil {
 		return errors.Wrap(err, "finding git dirs")
 	}
-	dirModTimes := make(map[GitDir]time.Time, len(gitDirs))
-	for _, d := range gitDirs {
-		mt, err := gitDirModTime(d)
-		if err != nil {
-			return errors.Wrap(err, "computing mod time of git dir")
-		}
-		dirModTimes[d] = mt
+	candidates, err := s.evictionCandidates(gitDirs)
+	if err != nil {
+		return err
 	}
 
-	// Sort the repos from least to most recently used.
-	sort.Slice(gitDirs, func(i, j int) bool {
-		return dirModTimes[gitDirs[i]].Before(dirModTimes[gitDirs[j]])
-	})
-
 	// Remove repos until howManyBytesToFree is met or exceeded.
-	var spaceFreed int64
 	mountPoint, err := findMountPoint(s.ReposDir)
 	if err != nil {
 		return errors.Wrap(err, "finding mount point")
@@ -374,18 +440,28 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 	if err != nil {
 		return errors.Wrap(err, "getting disk size")
 	}
-	for _, d := range gitDirs {
+	for _, c := range candidates {
 		if spaceFreed >= howManyBytesToFree {
 			return nil
 		}
-		delta, err := dirSize(string(d))
+		delta, err := dirSize(string(c.dir))
 		if err != nil {
-			return errors.Wrapf(err, "computing size of directory %s", d)
+			return errors.Wrapf(err, "computing size of directory %s", c.dir)
 		}
-		if err := s.removeRepoDirectory(d); err != nil {
+		if err := s.removeRepoDirectory(c.dir); err != nil {
 			return errors.Wrap(err, "removing repo directory")
 		}
 		spaceFreed += delta
+		c.size = delta
+		s.logEviction(protocol.Eviction{
+			Repo:        c.repo,
+			EvictedAt:   time.Now(),
+			Reason:      evictionReason(c, howManyBytesToFree, s.DesiredPercentFree),
+			SizeBytes:   delta,
+			LastUsed:    c.lastUsed,
+			AccessScore: c.score,
+		})
+		reposEvicted.Inc()
 
 		// Report the new disk usage situation after removing this repo.
 		actualFreeBytes, err := s.DiskSizer.BytesFreeOnDisk(mountPoint)
@@ -393,9 +469,10 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 			return errors.Wrap(err, "finding the amount of space free on disk")
 		}
 		G := float64(1024 * 1024 * 1024)
-		log15.Warn("cleanup: removed least recently used repo",
-			"repo", d,
-			"how old", time.Since(dirModTimes[d]),
+		log15.Warn("cleanup: removed least valuable repo",
+			"repo", c.repo,
+			"last used", time.Since(c.lastUsed),
+			"access score", c.score,
 			"free space in GiB", float64(actualFreeBytes)/G,
 			"actual percent of disk space free", float64(actualFreeBytes)/float64(diskSizeBytes)*100.0,
 			"desired percent of disk space free", float64(s.DesiredPercentFree),
@@ -410,6 +487,40 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 	return nil
 }
 
+// evictArchives removes the oldest cached archives until howManyBytesToFree
+// is freed or the archive cache is empty. It returns the number of bytes
+// freed.
+func (s *Server) evictArchives(howManyBytesToFree int64) (int64, error) {
+	if s.archiveCache == nil {
+		return 0, nil
+	}
+	before, err := s.archiveCacheSize()
+	if err != nil {
+		return 0, err
+	}
+	maxCacheSizeBytes := before - howManyBytesToFree
+	if maxCacheSizeBytes < 0 {
+		maxCacheSizeBytes = 0
+	}
+	if _, err := s.archiveCache.Evict(maxCacheSizeBytes); err != nil {
+		return 0, err
+	}
+	after, err := s.archiveCacheSize()
+	if err != nil {
+		return 0, err
+	}
+	if before > after {
+		log15.Warn("cleanup: removed cached archives", "space freed in bytes", before-after)
+	}
+	return before - after, nil
+}
+
+// archiveCacheSize returns the size of the cached archives in bytes.
+func (s *Server) archiveCacheSize() (int64, error) {
+	stats, err := s.archiveCache.Evict(math.MaxInt64)
+	return stats.CacheSize, err
+}
+
 func gitDirModTime(d GitDir) (time.Time, error) {
 	head, err := os.Stat(d.Path("HEAD"))
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path"
@@ -16,6 +17,9 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 const (
@@ -559,6 +563,7 @@ func TestFreeUpSpace(t *testing.T) {
 
 		// Check.
 		assertPaths(t, rd,
+			".repo-evictions.json",
 			".tmp",
 			"repo2/.git/HEAD",
 			"repo2/.git/space_eater")
@@ -566,6 +571,11 @@ func TestFreeUpSpace(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
+		fi, err := os.Stat(filepath.Join(rd, evictionsFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rds -= fi.Size()
 		wantSize := int64(1000)
 		if rds > wantSize {
 			t.Errorf("repo dir size is %d, want no more than %d", rds, wantSize)
@@ -573,6 +583,143 @@ func TestFreeUpSpace(t *testing.T) {
 	})
 }
 
+func TestFreeUpSpace_Archives(t *testing.T) {
+	rd, err := ioutil.TempDir("", "freeUpSpace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rd)
+	if err := makeFakeRepo(filepath.Join(rd, "repo"), 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		ReposDir:              rd,
+		DiskSizer:             &fakeDiskSizer{},
+		ArchiveCacheSizeBytes: 1 << 30,
+	}
+	s.archiveCache = s.newArchiveCache()
+	if err := os.MkdirAll(s.archiveCache.Dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.zip", "b.zip"} {
+		if err := ioutil.WriteFile(filepath.Join(s.archiveCache.Dir, name), make([]byte, 1000), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Archives are removed before repositories.
+	if err := s.freeUpSpace(1500); err != nil {
+		t.Fatal(err)
+	}
+	assertPaths(t, rd,
+		".tmp/archives",
+		"repo/.git/HEAD",
+		"repo/.git/space_eater")
+
+	// Repositories are removed once there are no archives left.
+	if err := s.freeUpSpace(1000); err != nil {
+		t.Fatal(err)
+	}
+	assertPaths(t, rd,
+		".repo-evictions.json",
+		".tmp/archives")
+}
+
+func TestFreeUpSpace_Eviction(t *testing.T) {
+	rd, err := ioutil.TempDir("", "freeUpSpace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rd)
+	for _, name := range []string{"pinned", "used", "unused"} {
+		if err := makeFakeRepo(filepath.Join(rd, name), 1000); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Make the pinned repo the least recently used one.
+	if err := os.Chtimes(filepath.Join(rd, "pinned", ".git", "HEAD"), time.Now(), time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{GitServerPinnedRepos: []string{"pinned"}}})
+	defer conf.Mock(nil)
+
+	s := &Server{
+		ReposDir:           rd,
+		DesiredPercentFree: 10,
+		DiskSizer:          &fakeDiskSizer{},
+	}
+	s.recordAccess("used")
+	if err := s.freeUpSpace(1000); err != nil {
+		t.Fatal(err)
+	}
+
+	assertPaths(t, rd,
+		".repo-evictions.json",
+		".tmp",
+		"pinned/.git/HEAD",
+		"pinned/.git/space_eater",
+		"used/.git/HEAD",
+		"used/.git/space_eater")
+
+	evictions := s.evictions.evictions
+	if len(evictions) != 1 || evictions[0].Repo != "unused" || evictions[0].SizeBytes != 1000 {
+		t.Fatalf("got evictions %+v, want unused", evictions)
+	}
+	if !strings.Contains(evictions[0].Reason, "never accessed") {
+		t.Errorf("got reason %q", evictions[0].Reason)
+	}
+
+	// Evictions are loaded after a restart.
+	restarted := &Server{ReposDir: rd}
+	rec := httptest.NewRecorder()
+	restarted.handleEvictions(rec, httptest.NewRequest("GET", "/evictions", nil))
+	if !strings.Contains(rec.Body.String(), `"repo":"unused"`) {
+		t.Errorf("got evictions %s after restart, want unused", rec.Body.String())
+	}
+
+	// Only pinned repos are left after the used one.
+	if err := s.freeUpSpace(2000); err == nil {
+		t.Fatal("want error since pinned repos can't be removed")
+	}
+	assertPaths(t, rd,
+		".repo-evictions.json",
+		".tmp",
+		"pinned/.git/HEAD",
+		"pinned/.git/space_eater")
+}
+
+func TestRepoAccess(t *testing.T) {
+	now := time.Now()
+	a := repoAccess{Score: 4, Last: now}
+	if got := a.scoreAt(now.Add(2 * accessHalfLife)); got != 1 {
+		t.Errorf("got score %v after two half-lives, want 1", got)
+	}
+	if got := (repoAccess{}).scoreAt(now); got != 0 {
+		t.Errorf("got score %v for no accesses, want 0", got)
+	}
+
+	rd, err := ioutil.TempDir("", "repoAccess")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rd)
+
+	s := &Server{ReposDir: rd}
+	s.recordAccess("a")
+	s.recordAccess("a")
+	if err := s.saveAccesses(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Scores are loaded after a restart.
+	s = &Server{ReposDir: rd}
+	if got := s.repoAccess("a").Score; got < 1.99 || got > 2 {
+		t.Errorf("got score %v after restart, want 2", got)
+	}
+}
+
 func makeFakeRepo(d string, sizeBytes int) error {
 	gd := filepath.Join(d, ".git")
 	if err := os.MkdirAll(gd, 0700); err != nil {
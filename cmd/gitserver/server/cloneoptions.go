@@ -0,0 +1,194 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+)
+
+// cloneOptionsIsZero reports whether opts clone a repo in full.
+func cloneOptionsIsZero(opts *protocol.CloneOptions) bool {
+	return opts == nil || *opts == protocol.CloneOptions{}
+}
+
+// cloneOptionsEqual reports whether a and b clone a repo the same way. A nil
+// value is equal to the zero value.
+func cloneOptionsEqual(a, b *protocol.CloneOptions) bool {
+	if cloneOptionsIsZero(a) || cloneOptionsIsZero(b) {
+		return cloneOptionsIsZero(a) && cloneOptionsIsZero(b)
+	}
+	return *a == *b
+}
+
+// cloneOptionsArgs returns the arguments to git clone and git fetch for a
+// partial or shallow clone.
+func cloneOptionsArgs(opts *protocol.CloneOptions) []string {
+	if opts == nil {
+		return nil
+	}
+	var args []string
+	if opts.BlobSizeLimit != "" {
+		args = append(args, "--filter=blob:limit="+opts.BlobSizeLimit)
+	}
+	if opts.Depth > 0 {
+		args = append(args, "--depth="+strconv.Itoa(opts.Depth))
+	}
+	return args
+}
+
+// getCloneOptions returns the options the repository at dir was cloned with.
+func getCloneOptions(dir GitDir) (*protocol.CloneOptions, error) {
+	var opts protocol.CloneOptions
+	limit, err := gitConfigGet(dir, "sourcegraph.cloneBlobSizeLimit")
+	if err != nil {
+		return nil, err
+	}
+	opts.BlobSizeLimit = strings.TrimSpace(limit)
+
+	depth, err := gitConfigGet(dir, "sourcegraph.cloneDepth")
+	if err != nil {
+		return nil, err
+	}
+	if depth = strings.TrimSpace(depth); depth != "" {
+		if opts.Depth, err = strconv.Atoi(depth); err != nil {
+			return nil, errors.Wrap(err, "invalid clone depth")
+		}
+	}
+	return &opts, nil
+}
+
+// setCloneOptions stores the options the repository at dir was cloned with.
+func setCloneOptions(dir GitDir, opts *protocol.CloneOptions) error {
+	if opts == nil {
+		opts = &protocol.CloneOptions{}
+	}
+	if opts.BlobSizeLimit != "" {
+		if err := gitConfigSet(dir, "sourcegraph.cloneBlobSizeLimit", opts.BlobSizeLimit); err != nil {
+			return err
+		}
+	} else if err := gitConfigUnset(dir, "sourcegraph.cloneBlobSizeLimit"); err != nil {
+		return err
+	}
+	if opts.Depth > 0 {
+		return gitConfigSet(dir, "sourcegraph.cloneDepth", strconv.Itoa(opts.Depth))
+	}
+	return gitConfigUnset(dir, "sourcegraph.cloneDepth")
+}
+
+// cloneOptionsChanged reports whether the repository at dir must be recloned
+// because it was cloned with other options than opts. It is false if opts is
+// nil.
+func (s *Server) cloneOptionsChanged(dir GitDir, opts *protocol.CloneOptions) bool {
+	// Clones using refspec overrides are always full.
+	if opts == nil || useRefspecOverrides() {
+		return false
+	}
+	current, err := getCloneOptions(dir)
+	if err != nil {
+		log15.Warn("failed to get clone options", "repo", s.name(dir), "error", err)
+		return false
+	}
+	return !cloneOptionsEqual(current, opts)
+}
+
+var promisorRemotePattern = lazyregexp.New(`(?mi)^\s*promisor\s*=\s*true\s*$`)
+
+// isPartialClone reports whether the repository at dir is a partial clone, so
+// that git fetches missing objects from its remote when they are needed. It
+// reads the config file directly rather than running git since it is called
+// for every exec.
+func isPartialClone(dir GitDir) bool {
+	config, err := ioutil.ReadFile(dir.Path("config"))
+	return err == nil && promisorRemotePattern.Match(config)
+}
+
+// isShallowClone reports whether the repository at dir is a shallow clone.
+func isShallowClone(dir GitDir) bool {
+	_, err := os.Stat(dir.Path("shallow"))
+	return err == nil
+}
+
+// prefetchArchiveBlobs fetches the blobs missing from a partial clone which
+// the git archive command with the given args needs in a single fetch. Without
+// this git fetches each missing blob separately.
+func prefetchArchiveBlobs(ctx context.Context, dir GitDir, args []string) error {
+	sep := -1
+	for i, arg := range args {
+		if arg == "--" {
+			sep = i
+			break
+		}
+	}
+	if sep < 1 {
+		return nil
+	}
+	treeish, paths := args[sep-1], args[sep+1:]
+
+	// rev-list prints missing objects instead of fetching them.
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--objects", "--no-walk", "--missing=print", treeish)
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.Wrap(wrapCmdError(cmd, err), "failed to list missing objects")
+	}
+	var missing []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "?") {
+			missing = append(missing, line[1:])
+		}
+	}
+	if len(missing) > 0 && len(paths) > 0 {
+		if missing, err = blobsInPaths(ctx, dir, treeish, paths, missing); err != nil {
+			return err
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	// This is the command git runs itself to fetch missing objects.
+	cmd = exec.CommandContext(ctx, "git", "-c", "fetch.negotiationAlgorithm=noop", "fetch", "origin", "--no-tags", "--no-write-fetch-head", "--recurse-submodules=no", "--filter=blob:none", "--stdin")
+	cmd.Dir = string(dir)
+	cmd.Stdin = strings.NewReader(strings.Join(missing, "\n") + "\n")
+	if output, err := runWithRemoteOpts(ctx, cmd, nil); err != nil {
+		return errors.Wrapf(err, "failed to fetch missing blobs. Output: %s", string(output))
+	}
+	return nil
+}
+
+// blobsInPaths returns the blobs among oids which are in the given paths of
+// treeish.
+func blobsInPaths(ctx context.Context, dir GitDir, treeish string, paths, oids []string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"ls-tree", "-r", "-z", "--full-tree", treeish, "--"}, paths...)...)
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrap(wrapCmdError(cmd, err), "failed to list paths")
+	}
+	inPaths := make(map[string]bool)
+	for _, entry := range bytes.Split(out, []byte{0}) {
+		// Each entry is "<mode> <type> <oid>\t<path>".
+		if fields := strings.Fields(string(bytes.SplitN(entry, []byte{'\t'}, 2)[0])); len(fields) == 3 {
+			inPaths[fields[2]] = true
+		}
+	}
+	var filtered []string
+	for _, oid := range oids {
+		if inPaths[oid] {
+			filtered = append(filtered, oid)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+)
+
+func TestCloneRepo_CloneOptions(t *testing.T) {
+	remote, cleanup1 := tmpDir(t)
+	defer cleanup1()
+
+	cmd := func(dir string, name string, arg ...string) string {
+		t.Helper()
+		c := exec.Command(name, arg...)
+		c.Dir = dir
+		c.Env = []string{
+			"GIT_COMMITTER_NAME=a",
+			"GIT_COMMITTER_EMAIL=a@a.com",
+			"GIT_AUTHOR_NAME=a",
+			"GIT_AUTHOR_EMAIL=a@a.com",
+		}
+		b, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s %s failed: %s", name, strings.Join(arg, " "), err)
+		}
+		return strings.TrimSpace(string(b))
+	}
+	cmd(remote, "git", "init", ".")
+	cmd(remote, "git", "config", "uploadpack.allowFilter", "true")
+	cmd(remote, "git", "config", "uploadpack.allowAnySHA1InWant", "true")
+	cmd(remote, "sh", "-c", "echo small > small.txt && head -c 4096 /dev/zero > big.bin")
+	cmd(remote, "git", "add", ".")
+	cmd(remote, "git", "commit", "-m", "first")
+	cmd(remote, "sh", "-c", "echo smaller > small.txt")
+	cmd(remote, "git", "commit", "-am", "second")
+
+	reposDir, cleanup2 := tmpDir(t)
+	defer cleanup2()
+	s := &Server{
+		ReposDir:         reposDir,
+		ctx:              context.Background(),
+		locker:           &RepositoryLocker{},
+		cloneLimiter:     mutablelimiter.New(1),
+		cloneableLimiter: mutablelimiter.New(1),
+	}
+
+	// Shallow clones only work with the file:// protocol for local remotes.
+	const repo = api.RepoName("example.com/foo/bar")
+	limits := &protocol.CloneOptions{BlobSizeLimit: "1k", Depth: 1}
+	if _, err := s.cloneRepo(context.Background(), repo, "file://"+remote, &cloneOptions{Block: true, Limits: limits}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := s.dir(repo)
+	if got := cmd(string(dir), "git", "rev-list", "--count", "HEAD"); got != "1" {
+		t.Errorf("got %s commits, want 1 in shallow clone", got)
+	}
+	if !isPartialClone(dir) || !isShallowClone(dir) {
+		t.Errorf("got partial %v, shallow %v, want both", isPartialClone(dir), isShallowClone(dir))
+	}
+	missing := func() string {
+		return cmd(string(dir), "sh", "-c", "git rev-list --objects --no-walk --missing=print HEAD | grep '^?' || true")
+	}
+	if missing() == "" {
+		t.Error("want big.bin to be missing from partial clone")
+	}
+
+	if s.cloneOptionsChanged(dir, &protocol.CloneOptions{BlobSizeLimit: "1k", Depth: 1}) {
+		t.Error("want clone options unchanged")
+	}
+	if !s.cloneOptionsChanged(dir, &protocol.CloneOptions{}) {
+		t.Error("want clone options changed")
+	}
+	if s.cloneOptionsChanged(dir, nil) {
+		t.Error("want nil clone options to keep the clone")
+	}
+
+	// Archives fetch missing blobs first.
+	if err := prefetchArchiveBlobs(context.Background(), dir, []string{"archive", "--format=zip", "HEAD", "--", "big.bin"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := missing(); got != "" {
+		t.Errorf("got missing objects %q after prefetch, want none", got)
+	}
+}
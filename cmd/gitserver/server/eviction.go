@@ -0,0 +1,290 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+const (
+	// accessHalfLife is how quickly the access score of a repository
+	// decays. It is long enough that a quiet weekend doesn't make a busy
+	// repository look unused.
+	accessHalfLife = 7 * 24 * time.Hour
+
+	// accessFileName is the file in ReposDir which access scores are saved
+	// in, so that they survive restarts.
+	accessFileName = ".repo-access.json"
+
+	// evictionsFileName is the file in ReposDir which recent evictions are
+	// saved in, so that they survive restarts.
+	evictionsFileName = ".repo-evictions.json"
+
+	// maxEvictions is the number of recent evictions a gitserver remembers.
+	maxEvictions = 100
+)
+
+// repoAccess records how frequently a repository is accessed.
+type repoAccess struct {
+	// Score is the number of accesses as of Last, where each access counts
+	// half as much for every accessHalfLife since it happened.
+	Score float64   `json:"score"`
+	Last  time.Time `json:"last"`
+}
+
+// scoreAt returns the access score at time t.
+func (a repoAccess) scoreAt(t time.Time) float64 {
+	if a.Last.IsZero() {
+		return 0
+	}
+	return a.Score * math.Exp2(-t.Sub(a.Last).Hours()/accessHalfLife.Hours())
+}
+
+// accessTracker records how frequently repositories are accessed by exec and
+// archive requests. The zero value is ready to use.
+type accessTracker struct {
+	mu       sync.Mutex
+	loaded   bool
+	dirty    bool
+	accesses map[api.RepoName]repoAccess
+}
+
+// load reads the saved access scores from ReposDir the first time it is
+// called. The caller must hold t.mu.
+func (t *accessTracker) load(reposDir string) {
+	if t.loaded {
+		return
+	}
+	t.loaded = true
+	t.accesses = make(map[api.RepoName]repoAccess)
+	b, err := ioutil.ReadFile(filepath.Join(reposDir, accessFileName))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log15.Warn("failed to read repository access scores", "error", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(b, &t.accesses); err != nil {
+		log15.Warn("failed to read repository access scores", "error", err)
+	}
+}
+
+// recordAccess records an access of repo, which makes it less likely to be
+// removed to free up disk space.
+func (s *Server) recordAccess(repo api.RepoName) {
+	t := &s.accesses
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.load(s.ReposDir)
+	now := time.Now()
+	t.accesses[repo] = repoAccess{Score: t.accesses[repo].scoreAt(now) + 1, Last: now}
+	t.dirty = true
+}
+
+func (s *Server) repoAccess(repo api.RepoName) repoAccess {
+	t := &s.accesses
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.load(s.ReposDir)
+	return t.accesses[repo]
+}
+
+// saveAccesses saves the access scores to ReposDir if they changed. Scores
+// which decayed to almost nothing, such as those of deleted repositories,
+// are dropped.
+func (s *Server) saveAccesses() error {
+	t := &s.accesses
+	t.mu.Lock()
+	if !t.dirty {
+		t.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	for repo, a := range t.accesses {
+		if a.scoreAt(now) < 0.01 {
+			delete(t.accesses, repo)
+		}
+	}
+	b, err := json.Marshal(t.accesses)
+	t.dirty = false
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(writeReposDirFile(s.ReposDir, accessFileName, b), "saving repository access scores")
+}
+
+// writeReposDirFile atomically replaces the file name in reposDir with b.
+func writeReposDirFile(reposDir, name string, b []byte) error {
+	tmp, err := ioutil.TempFile(reposDir, name)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(reposDir, name))
+}
+
+// evictionCandidate is a repository which may be removed to free up disk
+// space.
+type evictionCandidate struct {
+	dir      GitDir
+	repo     api.RepoName
+	lastUsed time.Time
+	score    float64
+	size     int64
+
+	// value is how valuable it is to keep the clone. Candidates are removed
+	// in order of increasing value.
+	value float64
+}
+
+// evictionCandidates returns the repositories which may be removed to free
+// up disk space, from least to most valuable. Pinned repositories are never
+// removed.
+//
+// The value of a repository is its access score weighted by the logarithm of
+// its size, since large repositories take longer to clone again. Repositories
+// with the same value, such as those which were never accessed, are ordered
+// from least to most recently used.
+func (s *Server) evictionCandidates(gitDirs []GitDir) ([]*evictionCandidate, error) {
+	pinned := make(map[api.RepoName]bool)
+	for _, name := range conf.Get().GitServerPinnedRepos {
+		pinned[protocol.NormalizeRepo(api.RepoName(name))] = true
+	}
+
+	now := time.Now()
+	candidates := make([]*evictionCandidate, 0, len(gitDirs))
+	for _, d := range gitDirs {
+		repo := s.name(d)
+		if pinned[protocol.NormalizeRepo(repo)] {
+			continue
+		}
+		mt, err := gitDirModTime(d)
+		if err != nil {
+			return nil, errors.Wrap(err, "computing mod time of git dir")
+		}
+		access := s.repoAccess(repo)
+		c := &evictionCandidate{
+			dir:      d,
+			repo:     repo,
+			lastUsed: mt,
+			score:    access.scoreAt(now),
+		}
+		if access.Last.After(c.lastUsed) {
+			c.lastUsed = access.Last
+		}
+		// Sizes of unused repositories don't change their value, so we
+		// avoid walking them.
+		if c.score > 0 {
+			if c.size, err = dirSize(string(d)); err != nil {
+				return nil, errors.Wrapf(err, "computing size of directory %s", d)
+			}
+			c.value = c.score * math.Log2(2+float64(c.size)/(1024*1024))
+		}
+		candidates = append(candidates, c)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].value != candidates[j].value {
+			return candidates[i].value < candidates[j].value
+		}
+		return candidates[i].lastUsed.Before(candidates[j].lastUsed)
+	})
+	return candidates, nil
+}
+
+// evictionLog remembers the most recent evictions, oldest first. It is saved
+// in ReposDir. The zero value is ready to use.
+type evictionLog struct {
+	mu        sync.Mutex
+	loaded    bool
+	evictions []protocol.Eviction
+}
+
+// load reads the saved evictions from ReposDir the first time it is called.
+// The caller must hold l.mu.
+func (l *evictionLog) load(reposDir string) {
+	if l.loaded {
+		return
+	}
+	l.loaded = true
+	b, err := ioutil.ReadFile(filepath.Join(reposDir, evictionsFileName))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log15.Warn("failed to read repository evictions", "error", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(b, &l.evictions); err != nil {
+		log15.Warn("failed to read repository evictions", "error", err)
+	}
+}
+
+func (s *Server) logEviction(e protocol.Eviction) {
+	l := &s.evictions
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.load(s.ReposDir)
+	l.evictions = append(l.evictions, e)
+	if len(l.evictions) > maxEvictions {
+		l.evictions = l.evictions[len(l.evictions)-maxEvictions:]
+	}
+
+	b, err := json.Marshal(l.evictions)
+	if err == nil {
+		err = writeReposDirFile(s.ReposDir, evictionsFileName, b)
+	}
+	if err != nil {
+		log15.Warn("failed to save repository evictions", "error", err)
+	}
+}
+
+// evictionReason explains why c was removed.
+func evictionReason(c *evictionCandidate, howManyBytesToFree int64, desiredPercentFree int) string {
+	G := float64(1024 * 1024 * 1024)
+	used := "never accessed"
+	if c.score > 0 {
+		used = fmt.Sprintf("access score %.2f", c.score)
+	}
+	return fmt.Sprintf("least valuable repository (%s, last used %s ago) when %.2f GiB had to be freed to keep %d%% of disk space free",
+		used, time.Since(c.lastUsed).Round(time.Minute), float64(howManyBytesToFree)/G, desiredPercentFree)
+}
+
+// handleEvictions returns the repositories this gitserver recently removed to
+// free up disk space, most recent first.
+func (s *Server) handleEvictions(w http.ResponseWriter, r *http.Request) {
+	l := &s.evictions
+	l.mu.Lock()
+	l.load(s.ReposDir)
+	evictions := make([]protocol.Eviction, len(l.evictions))
+	for i, e := range l.evictions {
+		evictions[len(evictions)-1-i] = e
+	}
+	l.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(evictions); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// gitOverridesFetchCommands are the executables which the fetch command of
+// git overrides may run. Fetch commands come from external service
+// configurations, which site admins can edit, so only the operator of
+// gitserver may allow executables.
+var gitOverridesFetchCommands = strings.Fields(env.Get("SRC_GIT_OVERRIDES_FETCH_COMMANDS", "", "Executables which the fetchCommand of gitOverrides in external service configurations may run. Space separated. fetchCommand is rejected if empty."))
+
+// gitOverridesConfigKeys are the lowercase git config keys which git overrides
+// may set. Keys which make git run commands (such as core.sshCommand),
+// redirect fetches (url.*.insteadOf) or change gitserver's own state
+// (sourcegraph.*) are not allowed.
+var gitOverridesConfigKeys = map[string]bool{
+	"core.bigfilethreshold":    true,
+	"core.compression":         true,
+	"core.deltabasecachelimit": true,
+	"core.packedgitlimit":      true,
+	"core.packedgitwindowsize": true,
+	"fetch.fsckobjects":        true,
+	"fetch.recursesubmodules":  true,
+	"fetch.unpacklimit":        true,
+	"http.extraheader":         true,
+	"http.lowspeedlimit":       true,
+	"http.lowspeedtime":        true,
+	"http.postbuffer":          true,
+	"http.version":             true,
+	"lfs.concurrenttransfers":  true,
+	"lfs.fetchexclude":         true,
+	"lfs.fetchinclude":         true,
+	"pack.deltacachesize":      true,
+	"pack.packsizelimit":       true,
+	"pack.threads":             true,
+	"pack.windowmemory":        true,
+	"protocol.version":         true,
+	"transfer.fsckobjects":     true,
+	"transfer.unpacklimit":     true,
+}
+
+// gitOverridesIsZero reports whether o don't change how a repo is cloned and
+// fetched.
+func gitOverridesIsZero(o *protocol.GitOverrides) bool {
+	return o == nil || (len(o.Config) == 0 && o.FetchCommand == "" && !o.FetchLFS && len(o.Refspecs) == 0)
+}
+
+// validateGitOverrides returns an error if o would interfere with how
+// gitserver manages clones.
+func validateGitOverrides(o *protocol.GitOverrides) error {
+	if o == nil {
+		return nil
+	}
+	for key := range o.Config {
+		if !gitOverridesConfigKeys[strings.ToLower(key)] {
+			return fmt.Errorf("git config key %q is not allowed", key)
+		}
+	}
+	for _, refspec := range o.Refspecs {
+		if refspec == "" || strings.HasPrefix(refspec, "-") {
+			return fmt.Errorf("invalid refspec %q", refspec)
+		}
+	}
+	if o.FetchCommand != "" {
+		if err := validateFetchCommand(o.FetchCommand); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateFetchCommand returns an error if the executable of the fetch
+// command isn't in gitOverridesFetchCommands.
+func validateFetchCommand(command string) error {
+	parts := strings.Fields(command)
+	if len(parts) == 0 {
+		return errors.New("empty fetch command")
+	}
+	for _, name := range gitOverridesFetchCommands {
+		if parts[0] == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("fetch command %q is not allowed by $SRC_GIT_OVERRIDES_FETCH_COMMANDS", parts[0])
+}
+
+// gitOverridesCloneArgs returns the arguments to git clone which set the git
+// config of o.
+func gitOverridesCloneArgs(o *protocol.GitOverrides) []string {
+	if o == nil {
+		return nil
+	}
+	var args []string
+	for _, key := range sortedConfigKeys(o.Config) {
+		args = append(args, "-c", key+"="+o.Config[key])
+	}
+	return args
+}
+
+// gitOverridesFetchCmd returns the command which fetches a repo with o, or
+// nil if o don't replace git fetch. The fetch command is validated again,
+// since the executables which are allowed may have changed since o was
+// stored.
+func gitOverridesFetchCmd(ctx context.Context, o *protocol.GitOverrides) (*exec.Cmd, error) {
+	if o == nil || o.FetchCommand == "" {
+		return nil, nil
+	}
+	if err := validateFetchCommand(o.FetchCommand); err != nil {
+		return nil, err
+	}
+	parts := strings.Fields(o.FetchCommand)
+	return exec.CommandContext(ctx, parts[0], parts[1:]...), nil
+}
+
+// fetchLFSCmd returns the command which fetches the Git LFS objects of all
+// refs of a repo.
+func fetchLFSCmd(ctx context.Context, dir GitDir) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", "lfs", "fetch", "--all", "origin")
+	cmd.Dir = string(dir)
+	return cmd
+}
+
+// getGitOverrides returns the overrides the repository at dir is cloned and
+// fetched with, or nil if it has none.
+func getGitOverrides(dir GitDir) (*protocol.GitOverrides, error) {
+	value, err := gitConfigGet(dir, "sourcegraph.gitOverrides")
+	if err != nil {
+		return nil, err
+	}
+	if value = strings.TrimSpace(value); value == "" {
+		return nil, nil
+	}
+	var o protocol.GitOverrides
+	if err := json.Unmarshal([]byte(value), &o); err != nil {
+		return nil, errors.Wrap(err, "invalid git overrides")
+	}
+	return &o, nil
+}
+
+// setGitOverrides stores o as the overrides the repository at dir is cloned
+// and fetched with, and sets their git config. Git config of previous
+// overrides which o no longer set is removed.
+func setGitOverrides(dir GitDir, o *protocol.GitOverrides) error {
+	if err := validateGitOverrides(o); err != nil {
+		return err
+	}
+	prev, err := getGitOverrides(dir)
+	if err != nil {
+		return err
+	}
+
+	if prev != nil {
+		for _, key := range sortedConfigKeys(prev.Config) {
+			if o != nil {
+				if _, ok := o.Config[key]; ok {
+					continue
+				}
+			}
+			if err := gitConfigUnset(dir, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	if gitOverridesIsZero(o) {
+		return gitConfigUnset(dir, "sourcegraph.gitOverrides")
+	}
+	for _, key := range sortedConfigKeys(o.Config) {
+		if err := gitConfigSet(dir, key, o.Config[key]); err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	return gitConfigSet(dir, "sourcegraph.gitOverrides", string(b))
+}
+
+func sortedConfigKeys(config map[string]string) []string {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+)
+
+func TestGitOverrides(t *testing.T) {
+	remote, cleanup1 := tmpDir(t)
+	defer cleanup1()
+
+	cmd := func(dir string, name string, arg ...string) string {
+		t.Helper()
+		c := exec.Command(name, arg...)
+		c.Dir = dir
+		c.Env = []string{
+			"GIT_COMMITTER_NAME=a",
+			"GIT_COMMITTER_EMAIL=a@a.com",
+			"GIT_AUTHOR_NAME=a",
+			"GIT_AUTHOR_EMAIL=a@a.com",
+		}
+		b, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s %s failed: %s", name, strings.Join(arg, " "), err)
+		}
+		return strings.TrimSpace(string(b))
+	}
+	cmd(remote, "git", "init", ".")
+	cmd(remote, "sh", "-c", "echo hello > README")
+	cmd(remote, "git", "add", ".")
+	cmd(remote, "git", "commit", "-m", "first")
+
+	reposDir, cleanup2 := tmpDir(t)
+	defer cleanup2()
+	s := &Server{
+		ReposDir:         reposDir,
+		ctx:              context.Background(),
+		locker:           &RepositoryLocker{},
+		cloneLimiter:     mutablelimiter.New(1),
+		cloneableLimiter: mutablelimiter.New(1),
+		repoUpdateLocks:  make(map[api.RepoName]*locks),
+	}
+
+	const repo = api.RepoName("example.com/foo/bar")
+	overrides := &protocol.GitOverrides{
+		Config:   map[string]string{"http.postBuffer": "524288000"},
+		Refspecs: []string{"+refs/changes/*:refs/changes/*"},
+	}
+	if _, err := s.cloneRepo(context.Background(), repo, remote, &cloneOptions{Block: true, Overrides: overrides}); err != nil {
+		t.Fatal(err)
+	}
+	dir := s.dir(repo)
+
+	config := func(key string) string {
+		t.Helper()
+		value, err := gitConfigGet(dir, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(value)
+	}
+	if got := config("http.postBuffer"); got != "524288000" {
+		t.Errorf("got http.postBuffer %q after clone, want 524288000", got)
+	}
+	if got, err := getGitOverrides(dir); err != nil || len(got.Refspecs) != 1 {
+		t.Fatalf("got overrides %+v and error %v after clone, want the refspecs", got, err)
+	}
+
+	// Fetches include the extra refspecs.
+	cmd(remote, "git", "update-ref", "refs/changes/01/1/1", "HEAD")
+	if err := s.doRepoUpdate(context.Background(), repo, remote); err != nil {
+		t.Fatal(err)
+	}
+	cmd(string(dir), "git", "rev-parse", "--verify", "refs/changes/01/1/1")
+
+	// Git config of removed overrides is unset.
+	if err := setGitOverrides(dir, &protocol.GitOverrides{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := config("http.postBuffer"); got != "" {
+		t.Errorf("got http.postBuffer %q after removing overrides, want unset", got)
+	}
+	if got, err := getGitOverrides(dir); err != nil || got != nil {
+		t.Errorf("got overrides %+v and error %v after removing them, want none", got, err)
+	}
+
+	// Our own config, config which runs commands and fetch commands which
+	// the operator didn't allow can't be set.
+	for _, o := range []*protocol.GitOverrides{
+		{Config: map[string]string{"sourcegraph.cloneDepth": "1"}},
+		{Config: map[string]string{"-c": "x"}},
+		{Config: map[string]string{"core.sshCommand": "touch /tmp/pwned"}},
+		{Config: map[string]string{"url.https://evil.example.com/.insteadOf": "https://example.com/"}},
+		{Refspecs: []string{"--upload-pack=touch /tmp/pwned"}},
+		{FetchCommand: " "},
+		{FetchCommand: "sh -c true"},
+	} {
+		if err := setGitOverrides(dir, o); err == nil {
+			t.Errorf("got no error setting invalid overrides %+v", o)
+		}
+	}
+}
+
+func TestGitOverridesFetchCmd(t *testing.T) {
+	defer func(orig []string) { gitOverridesFetchCommands = orig }(gitOverridesFetchCommands)
+	gitOverridesFetchCommands = []string{"/usr/local/bin/fetch-repo"}
+
+	o := &protocol.GitOverrides{FetchCommand: "/usr/local/bin/fetch-repo --all"}
+	cmd, err := gitOverridesFetchCmd(context.Background(), o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(cmd.Args, " "), o.FetchCommand; got != want {
+		t.Errorf("got command %q, want %q", got, want)
+	}
+
+	// Stored overrides are checked again after the operator disallows their
+	// fetch command.
+	gitOverridesFetchCommands = nil
+	if _, err := gitOverridesFetchCmd(context.Background(), o); err == nil {
+		t.Error("got no error for a fetch command which is no longer allowed")
+	}
+}
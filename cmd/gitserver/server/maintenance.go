@@ -0,0 +1,238 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+func init() {
+	prometheus.MustRegister(maintenanceTasks)
+	prometheus.MustRegister(maintenanceDuration)
+}
+
+var maintenanceTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "gitserver",
+	Name:      "maintenance_tasks",
+	Help:      "number of git maintenance tasks run on repositories",
+}, []string{"task", "status"})
+
+var maintenanceDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "src",
+	Subsystem: "gitserver",
+	Name:      "maintenance_duration_seconds",
+	Help:      "time taken by git maintenance tasks",
+	Buckets:   []float64{1, 5, 15, 60, 300, 900, 1800, 3600},
+}, []string{"task"})
+
+// maxMaintenanceErrorLength is the length at which maintenance errors are
+// truncated before they are stored in the git config of a repository.
+const maxMaintenanceErrorLength = 1000
+
+// maintenanceTask is a git command run on repositories by the Maintainer.
+type maintenanceTask struct {
+	Name string
+	Args []string
+
+	// FullCloneOnly is true if the task can't run on shallow or partial
+	// clones, which are missing objects.
+	FullCloneOnly bool
+}
+
+var maintenanceTasksToRun = []maintenanceTask{
+	// Packs loose objects and consolidates packfiles if there are enough of
+	// them. We run it in the foreground so that the tasks below see its
+	// result and our concurrency limit applies to it.
+	{Name: "gc", Args: []string{"-c", "gc.autoDetach=false", "gc", "--auto", "--quiet"}},
+	// Commit-graphs with Bloom filters of changed paths speed up walking
+	// history, most notably path-limited git log as used by commit search.
+	{Name: "commit-graph", Args: []string{"commit-graph", "write", "--reachable", "--changed-paths"}},
+	// A multi-pack-index with a reachability bitmap speeds up counting
+	// objects, e.g. when serving fetches, without repacking everything into
+	// a single packfile. Bitmaps require all reachable objects.
+	{Name: "multi-pack-index", Args: []string{"multi-pack-index", "write", "--bitmap"}, FullCloneOnly: true},
+}
+
+// maintenanceDue returns true if the repository at dir has not been
+// maintained within s.MaintenanceInterval. It always returns false if
+// maintenance is disabled.
+func (s *Server) maintenanceDue(dir GitDir) (bool, error) {
+	if s.MaintenanceInterval <= 0 {
+		return false, nil
+	}
+	last, err := getMaintenanceTime(dir)
+	if err != nil {
+		return false, err
+	}
+	if last.IsZero() {
+		// Never maintained. Use the clone time so freshly cloned
+		// repositories, which are already well packed, are not maintained
+		// straight away.
+		if last, err = getRecloneTime(dir); err != nil {
+			return false, err
+		}
+	}
+	// Add a jitter to spread out maintenance of repos cloned at the same
+	// time.
+	return time.Since(last) > s.MaintenanceInterval+jitterDuration(string(dir), s.MaintenanceInterval/4), nil
+}
+
+// maintainDueRepos runs the maintenance tasks on the repositories which are
+// due for maintenance.
+func (s *Server) maintainDueRepos() {
+	if s.MaintenanceInterval <= 0 {
+		return
+	}
+
+	ctx, cancel := s.serverContext()
+	defer cancel()
+
+	dirs, err := s.findGitDirs()
+	if err != nil {
+		log15.Error("maintenance: error finding repositories", "error", err)
+		return
+	}
+	var due []GitDir
+	for _, dir := range dirs {
+		ok, err := s.maintenanceDue(dir)
+		if err != nil {
+			log15.Error("maintenance: error checking if maintenance is due", "repo", dir, "error", err)
+			continue
+		}
+		if ok {
+			due = append(due, dir)
+		}
+	}
+	s.maintainRepos(ctx, due)
+}
+
+// maintainRepos runs the maintenance tasks on dirs, running at most
+// s.MaintenanceConcurrency repositories at a time.
+func (s *Server) maintainRepos(ctx context.Context, dirs []GitDir) {
+	concurrency := s.MaintenanceConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, dir := range dirs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(dir GitDir) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := s.maintainRepo(ctx, dir); err != nil {
+				log15.Error("maintenance: error running git maintenance", "repo", dir, "error", err)
+			}
+		}(dir)
+	}
+	wg.Wait()
+}
+
+// maintainRepo runs the maintenance tasks on the repository at dir and
+// records when it did so and the first error, if any, in its git config.
+// Repositories which are being cloned or which were removed are skipped.
+func (s *Server) maintainRepo(ctx context.Context, dir GitDir) error {
+	if _, cloning := s.locker.Status(dir); cloning {
+		return nil
+	}
+	if _, err := os.Stat(string(dir)); os.IsNotExist(err) {
+		return nil
+	}
+
+	opts, err := getCloneOptions(dir)
+	if err != nil {
+		return err
+	}
+	fullClone := opts.BlobSizeLimit == "" && opts.Depth == 0
+
+	var taskErr error
+	for _, task := range maintenanceTasksToRun {
+		if task.FullCloneOnly && !fullClone {
+			continue
+		}
+		if err := runMaintenanceTask(ctx, dir, task); err != nil {
+			taskErr = err
+			break
+		}
+	}
+
+	// We record the time even if a task failed so that we don't constantly
+	// retry expensive tasks on repositories they fail for.
+	if err := setMaintenanceTime(dir, time.Now()); err != nil {
+		return err
+	}
+	if taskErr == nil {
+		return gitConfigUnset(dir, "sourcegraph.maintenanceError")
+	}
+	msg := taskErr.Error()
+	if len(msg) > maxMaintenanceErrorLength {
+		msg = msg[:maxMaintenanceErrorLength]
+	}
+	if err := gitConfigSet(dir, "sourcegraph.maintenanceError", msg); err != nil {
+		return err
+	}
+	return taskErr
+}
+
+func runMaintenanceTask(ctx context.Context, dir GitDir, task maintenanceTask) error {
+	ctx, cancel := context.WithTimeout(ctx, longGitCommandTimeout)
+	defer cancel()
+
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, "git", task.Args...)
+	cmd.Dir = string(dir)
+	out, err := cmd.CombinedOutput()
+	maintenanceDuration.WithLabelValues(task.Name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		maintenanceTasks.WithLabelValues(task.Name, "failure").Inc()
+		return errors.Wrapf(err, "git %s failed: %s", task.Name, strings.TrimSpace(string(out)))
+	}
+	maintenanceTasks.WithLabelValues(task.Name, "success").Inc()
+	return nil
+}
+
+// setMaintenanceTime sets the time the Maintainer last ran the maintenance tasks
+// on a repository.
+func setMaintenanceTime(dir GitDir, now time.Time) error {
+	err := gitConfigSet(dir, "sourcegraph.maintenanceTimestamp", strconv.FormatInt(now.Unix(), 10))
+	if err != nil {
+		return errors.Wrap(err, "failed to update maintenanceTimestamp")
+	}
+	return nil
+}
+
+// getMaintenanceTime returns the time the Maintainer last ran the maintenance
+// tasks on a repository, or the zero time if it never did.
+func getMaintenanceTime(dir GitDir) (time.Time, error) {
+	value, err := gitConfigGet(dir, "sourcegraph.maintenanceTimestamp")
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to determine maintenance timestamp")
+	}
+	if value = strings.TrimSpace(value); value == "" {
+		return time.Time{}, nil
+	}
+	sec, err := strconv.ParseInt(value, 10, 0)
+	if err != nil {
+		// Treat a bad value like a missing one, so the repository is
+		// maintained and the value overwritten.
+		return time.Time{}, nil
+	}
+	return time.Unix(sec, 0), nil
+}
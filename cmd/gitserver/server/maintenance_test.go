@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+)
+
+func TestMaintainRepos(t *testing.T) {
+	remote, cleanup1 := tmpDir(t)
+	defer cleanup1()
+
+	cmd := func(dir string, name string, arg ...string) string {
+		t.Helper()
+		c := exec.Command(name, arg...)
+		c.Dir = dir
+		c.Env = []string{
+			"GIT_COMMITTER_NAME=a",
+			"GIT_COMMITTER_EMAIL=a@a.com",
+			"GIT_AUTHOR_NAME=a",
+			"GIT_AUTHOR_EMAIL=a@a.com",
+		}
+		b, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s %s failed: %s", name, strings.Join(arg, " "), err)
+		}
+		return strings.TrimSpace(string(b))
+	}
+	cmd(remote, "git", "init", ".")
+	cmd(remote, "sh", "-c", "echo hello > README")
+	cmd(remote, "git", "add", ".")
+	cmd(remote, "git", "commit", "-m", "first")
+	cmd(remote, "sh", "-c", "echo world >> README")
+	cmd(remote, "git", "commit", "-am", "second")
+
+	reposDir, cleanup2 := tmpDir(t)
+	defer cleanup2()
+	s := &Server{
+		ReposDir:            reposDir,
+		MaintenanceInterval: time.Hour,
+		ctx:                 context.Background(),
+		locker:              &RepositoryLocker{},
+		cloneLimiter:        mutablelimiter.New(1),
+		cloneableLimiter:    mutablelimiter.New(1),
+	}
+
+	const (
+		full    = api.RepoName("example.com/foo/full")
+		shallow = api.RepoName("example.com/foo/shallow")
+	)
+	for repo, opts := range map[api.RepoName]*protocol.CloneOptions{
+		full:    nil,
+		shallow: {Depth: 1},
+	} {
+		if _, err := s.cloneRepo(context.Background(), repo, "file://"+remote, &cloneOptions{Block: true, Limits: opts}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fullDir, shallowDir := s.dir(full), s.dir(shallow)
+
+	// Freshly cloned repositories are not due.
+	for _, dir := range []GitDir{fullDir, shallowDir} {
+		if due, err := s.maintenanceDue(dir); err != nil || due {
+			t.Fatalf("got due %v and error %v for fresh clone %s, want not due", due, err, dir)
+		}
+	}
+	for _, dir := range []GitDir{fullDir, shallowDir} {
+		if err := setRecloneTime(dir, time.Now().Add(-2*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		if due, err := s.maintenanceDue(dir); err != nil || !due {
+			t.Fatalf("got due %v and error %v for old clone %s, want due", due, err, dir)
+		}
+	}
+
+	s.Maintainer()
+
+	exists := func(dir GitDir, glob string) bool {
+		matches, err := filepath.Glob(dir.Path("objects", glob))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(matches) > 0
+	}
+	if !exists(fullDir, "info/commit-graph") {
+		t.Error("full clone has no commit-graph")
+	}
+	if !exists(fullDir, "pack/multi-pack-index-*.bitmap") {
+		t.Error("full clone has no multi-pack-index bitmap")
+	}
+	if exists(shallowDir, "pack/multi-pack-index*") {
+		t.Error("shallow clone has a multi-pack-index, want none")
+	}
+
+	for _, dir := range []GitDir{fullDir, shallowDir} {
+		if due, err := s.maintenanceDue(dir); err != nil || due {
+			t.Errorf("got due %v and error %v for maintained %s, want not due", due, err, dir)
+		}
+		if msg, _ := gitConfigGet(dir, "sourcegraph.maintenanceError"); msg != "" {
+			t.Errorf("got maintenance error %q for %s, want none", msg, dir)
+		}
+	}
+
+	// Failures are recorded, and repositories are not retried until the
+	// next interval.
+	if err := setMaintenanceTime(fullDir, time.Now().Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(fullDir.Path("objects", "info", "commit-graph.lock"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.maintainRepo(context.Background(), fullDir); err == nil {
+		t.Error("got no error with a locked commit-graph")
+	}
+	if msg, _ := gitConfigGet(fullDir, "sourcegraph.maintenanceError"); !strings.Contains(msg, "commit-graph") {
+		t.Errorf("got maintenance error %q, want commit-graph failure", msg)
+	}
+	if due, _ := s.maintenanceDue(fullDir); due {
+		t.Error("repository with failed maintenance is due straight away")
+	}
+
+	// Maintenance is disabled without an interval.
+	s.MaintenanceInterval = 0
+	if due, err := s.maintenanceDue(shallowDir); err != nil || due {
+		t.Errorf("got due %v and error %v with maintenance disabled, want not due", due, err)
+	}
+}
@@ -0,0 +1,236 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+var (
+	reposCopiedFromPeer = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "gitserver",
+		Name:      "repos_copied_from_peer",
+		Help:      "number of repos cloned from another gitserver instead of the code host",
+	})
+	reposRebalanced = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "gitserver",
+		Name:      "repos_rebalanced",
+		Help:      "number of repos removed during cleanup because they moved to another gitserver",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(reposCopiedFromPeer)
+	prometheus.MustRegister(reposRebalanced)
+}
+
+// rebalancingEnabled reports whether repositories are copied between
+// gitservers when the set of gitservers changes.
+func (s *Server) rebalancingEnabled() bool {
+	return s.GitServers != nil && conf.Get().GitServerRebalancing
+}
+
+// handleBundle streams a git bundle of all refs of a repository. It is used by
+// other gitservers to copy the repository instead of cloning it from the code
+// host.
+func (s *Server) handleBundle(w http.ResponseWriter, r *http.Request) {
+	repo := protocol.NormalizeRepo(api.RepoName(r.URL.Query().Get("repo")))
+	if repo == "" {
+		http.Error(w, "missing repo", http.StatusBadRequest)
+		return
+	}
+	dir := s.dir(repo)
+	if !repoCloned(dir) {
+		http.Error(w, "repository not cloned", http.StatusNotFound)
+		return
+	}
+	if isPartialClone(dir) || isShallowClone(dir) {
+		// A bundle can't contain the objects missing from the clone.
+		http.Error(w, "repository is a partial or shallow clone", http.StatusConflict)
+		return
+	}
+
+	var stderr bytes.Buffer
+	stdout := &writeCounter{w: w}
+	cmd := exec.CommandContext(r.Context(), "git", "bundle", "create", "-", "--all")
+	cmd.Dir = string(dir)
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if _, err := runCommand(r.Context(), cmd); err != nil {
+		log15.Error("failed to create bundle", "repo", repo, "error", err, "stderr", stderr.String())
+		if stdout.n == 0 {
+			http.Error(w, fmt.Sprintf("failed to create bundle: %s", stderr.String()), http.StatusInternalServerError)
+		}
+		// Otherwise the receiving gitserver fails to clone the truncated
+		// bundle.
+	}
+}
+
+// cloneFromPeer clones repo into tmpPath from another gitserver which has a
+// clone of it, and points the clone's remote at remoteURL. It returns the
+// address of the gitserver the repository was copied from, or "" if no other
+// gitserver has a clone.
+func (s *Server) cloneFromPeer(ctx context.Context, repo api.RepoName, remoteURL, tmpPath string) (string, error) {
+	peer, err := s.findPeerWithClone(ctx, repo)
+	if err != nil || peer == "" {
+		return "", err
+	}
+
+	bundle, err := ioutil.TempFile(filepath.Dir(tmpPath), "bundle-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(bundle.Name())
+	defer bundle.Close()
+
+	resp, err := s.peerRequest(ctx, peer, "GET", "bundle?"+url.Values{"repo": {string(repo)}}.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 200))
+		return "", errors.Errorf("bundle from %s: http status %d: %s", peer, resp.StatusCode, body)
+	}
+	if _, err := io.Copy(bundle, resp.Body); err != nil {
+		return "", errors.Wrapf(err, "bundle from %s", peer)
+	}
+	if err := bundle.Close(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "clone", "--mirror", bundle.Name(), tmpPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", errors.Wrapf(err, "clone from bundle failed. Output: %s", string(output))
+	}
+	cmd = exec.CommandContext(ctx, "git", "remote", "set-url", "origin", remoteURL)
+	cmd.Dir = tmpPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", errors.Wrapf(err, "failed to set remote URL. Output: %s", newURLRedactor(remoteURL).redact(string(output)))
+	}
+	return peer, nil
+}
+
+// findPeerWithClone returns the address of another gitserver which has a
+// clone of repo, or "" if there is none.
+func (s *Server) findPeerWithClone(ctx context.Context, repo api.RepoName) (string, error) {
+	var lastErr error
+	for _, addr := range s.GitServers.Addrs(ctx) {
+		if hostnameMatch(s.Hostname, addr) {
+			continue
+		}
+		cloned, err := s.peerIsRepoCloned(ctx, addr, repo)
+		if err != nil {
+			// The peer may be gone, which is why we are cloning.
+			lastErr = err
+			continue
+		}
+		if cloned {
+			return addr, nil
+		}
+	}
+	if lastErr != nil {
+		log15.Debug("failed to ask some gitservers whether they have a clone", "repo", repo, "error", lastErr)
+	}
+	return "", nil
+}
+
+func (s *Server) peerIsRepoCloned(ctx context.Context, addr string, repo api.RepoName) (bool, error) {
+	resp, err := s.peerRequest(ctx, addr, "POST", "is-repo-cloned", &protocol.IsRepoClonedRequest{Repo: repo})
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, errors.Errorf("is-repo-cloned on %s: http status %d", addr, resp.StatusCode)
+	}
+}
+
+func (s *Server) peerRequest(ctx context.Context, addr, method, op string, payload interface{}) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, "http://"+addr+"/"+op, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "gitserver")
+	return s.GitServers.HTTPClient.Do(req.WithContext(ctx))
+}
+
+// isRebalanced reports whether repo belongs to other gitservers which have all
+// cloned it, so that the clone on this gitserver can be removed. It is false
+// if this gitserver can't find its own address among the gitservers.
+func (s *Server) isRebalanced(ctx context.Context, repo api.RepoName) (bool, error) {
+	addrs := s.GitServers.Addrs(ctx)
+	self := false
+	for _, addr := range addrs {
+		if hostnameMatch(s.Hostname, addr) {
+			self = true
+			break
+		}
+	}
+	if !self {
+		return false, nil
+	}
+
+	owners := s.GitServers.AddrsForRepo(ctx, repo)
+	for _, addr := range owners {
+		if hostnameMatch(s.Hostname, addr) {
+			return false, nil
+		}
+	}
+	for _, addr := range owners {
+		cloned, err := s.peerIsRepoCloned(ctx, addr, repo)
+		if err != nil || !cloned {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// hostnameMatch reports whether addr is an address of the host with the given
+// hostname. For example, "gitserver-1" matches "gitserver-1:3178" and
+// "gitserver-1.gitserver:3178", but not "gitserver-10:3178".
+func hostnameMatch(hostname, addr string) bool {
+	if hostname == "" {
+		return false
+	}
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	return host == hostname || strings.HasPrefix(host, hostname+".")
+}
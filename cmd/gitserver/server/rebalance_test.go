@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestHostnameMatch(t *testing.T) {
+	tests := []struct {
+		hostname, addr string
+		want           bool
+	}{
+		{"gitserver-1", "gitserver-1", true},
+		{"gitserver-1", "gitserver-1:3178", true},
+		{"gitserver-1", "gitserver-1.gitserver:3178", true},
+		{"gitserver-1", "gitserver-10:3178", false},
+		{"gitserver-1", "gitserver-2.gitserver:3178", false},
+		{"", "gitserver-1:3178", false},
+	}
+	for _, test := range tests {
+		if got := hostnameMatch(test.hostname, test.addr); got != test.want {
+			t.Errorf("hostnameMatch(%q, %q) = %v, want %v", test.hostname, test.addr, got, test.want)
+		}
+	}
+}
+
+func TestCloneFromPeer(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{GitServerRebalancing: true}})
+	defer conf.Mock(nil)
+
+	remote, cleanup1 := tmpDir(t)
+	defer cleanup1()
+
+	cmd := func(dir string, name string, arg ...string) string {
+		t.Helper()
+		c := exec.Command(name, arg...)
+		c.Dir = dir
+		c.Env = []string{
+			"GIT_COMMITTER_NAME=a",
+			"GIT_COMMITTER_EMAIL=a@a.com",
+			"GIT_AUTHOR_NAME=a",
+			"GIT_AUTHOR_EMAIL=a@a.com",
+		}
+		b, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s %s failed: %s", name, strings.Join(arg, " "), err)
+		}
+		return strings.TrimSpace(string(b))
+	}
+	cmd(remote, "git", "init", ".")
+	cmd(remote, "git", "commit", "--allow-empty", "-m", "first")
+	wantCommit := cmd(remote, "git", "rev-parse", "HEAD")
+
+	// The peer clones the repository from the code host.
+	peerDir, cleanup2 := tmpDir(t)
+	defer cleanup2()
+	peer := &Server{ReposDir: peerDir}
+	srv := httptest.NewServer(peer.Handler())
+	defer srv.Close()
+	const repo = api.RepoName("example.com/foo/bar")
+	if _, err := peer.cloneRepo(context.Background(), repo, remote, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Commits made on the code host afterwards are not on the peer, so if
+	// they are not in our clone it was copied from the peer.
+	cmd(remote, "git", "commit", "--allow-empty", "-m", "second")
+
+	reposDir, cleanup3 := tmpDir(t)
+	defer cleanup3()
+	peerURL, _ := url.Parse(srv.URL)
+	s := &Server{
+		ReposDir:         reposDir,
+		Hostname:         "gitserver-1",
+		ctx:              context.Background(),
+		locker:           &RepositoryLocker{},
+		cloneLimiter:     mutablelimiter.New(1),
+		cloneableLimiter: mutablelimiter.New(1),
+		GitServers: &gitserver.Client{
+			Addrs:      func(context.Context) []string { return []string{"gitserver-1:3178", peerURL.Host} },
+			HTTPClient: http.DefaultClient,
+		},
+	}
+	if _, err := s.cloneRepo(context.Background(), repo, remote, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := string(s.dir(repo))
+	if got := cmd(dir, "git", "rev-parse", "HEAD"); got != wantCommit {
+		t.Errorf("got HEAD %s, want %s from peer", got, wantCommit)
+	}
+	if got := cmd(dir, "git", "config", "remote.origin.url"); got != remote {
+		t.Errorf("got remote URL %q, want %q", got, remote)
+	}
+}
+
+func TestIsRebalanced(t *testing.T) {
+	addrs := []string{"gitserver-0:3178", "gitserver-1:3178"}
+	const repo = api.RepoName("example.com/foo/bar")
+
+	owner := (&gitserver.Client{Addrs: func(context.Context) []string { return addrs }}).AddrForRepo(context.Background(), repo)
+	var owningHost, otherHost string
+	for _, addr := range addrs {
+		host := strings.TrimSuffix(addr, ":3178")
+		if addr == owner {
+			owningHost = host
+		} else {
+			otherHost = host
+		}
+	}
+
+	tests := []struct {
+		name           string
+		hostname       string
+		ownerHasClone  bool
+		wantRebalanced bool
+	}{
+		{name: "moved and cloned by owner", hostname: otherHost, ownerHasClone: true, wantRebalanced: true},
+		{name: "moved but not cloned by owner yet", hostname: otherHost, ownerHasClone: false, wantRebalanced: false},
+		{name: "owned by us", hostname: owningHost, ownerHasClone: true, wantRebalanced: false},
+		{name: "unknown hostname", hostname: "gitserver-9", ownerHasClone: true, wantRebalanced: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &Server{
+				Hostname: test.hostname,
+				GitServers: &gitserver.Client{
+					Addrs: func(context.Context) []string { return addrs },
+					HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+						if r.URL.Host != owner || r.URL.Path != "/is-repo-cloned" {
+							t.Errorf("unexpected request to %s", r.URL)
+						}
+						rec := httptest.NewRecorder()
+						if !test.ownerHasClone {
+							rec.WriteHeader(http.StatusNotFound)
+						}
+						return rec.Result(), nil
+					}),
+				},
+			}
+			rebalanced, err := s.isRebalanced(context.Background(), repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rebalanced != test.wantRebalanced {
+				t.Errorf("got rebalanced %v, want %v", rebalanced, test.wantRebalanced)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// maxFetchErrorLength is the length at which fetch errors are truncated
+// before they are stored in the git config of a repository.
+const maxFetchErrorLength = 1000
+
+// setLastFetch records how long the last fetch of the repository at dir took
+// and why it failed, if it did. The output of the fetch command is included
+// in the error, with credentials in remoteURL redacted.
+func setLastFetch(dir GitDir, remoteURL string, duration time.Duration, fetchErr error, output []byte) error {
+	if err := gitConfigSet(dir, "sourcegraph.lastFetchDuration", duration.String()); err != nil {
+		return err
+	}
+	if fetchErr == nil {
+		return gitConfigUnset(dir, "sourcegraph.lastFetchError")
+	}
+	msg := fetchErr.Error()
+	if output = bytes.TrimSpace(output); len(output) > 0 {
+		msg += ": " + string(output)
+	}
+	msg = newURLRedactor(remoteURL).redact(msg)
+	if len(msg) > maxFetchErrorLength {
+		msg = msg[:maxFetchErrorLength]
+	}
+	return gitConfigSet(dir, "sourcegraph.lastFetchError", msg)
+}
+
+// getLastFetch returns how long the last fetch of the repository at dir took
+// and why it failed, if it did.
+func getLastFetch(dir GitDir) (time.Duration, string, error) {
+	value, err := gitConfigGet(dir, "sourcegraph.lastFetchDuration")
+	if err != nil {
+		return 0, "", err
+	}
+	var duration time.Duration
+	if value = strings.TrimSpace(value); value != "" {
+		if duration, err = time.ParseDuration(value); err != nil {
+			return 0, "", errors.Wrap(err, "invalid last fetch duration")
+		}
+	}
+	fetchErr, err := gitConfigGet(dir, "sourcegraph.lastFetchError")
+	if err != nil {
+		return 0, "", err
+	}
+	return duration, strings.TrimSpace(fetchErr), nil
+}
+
+// lastGCTime returns when git gc last repacked the repository at dir. git
+// repack updates objects/info/packs, which clones don't have.
+func lastGCTime(dir GitDir) (*time.Time, error) {
+	fi, err := os.Stat(dir.Path("objects", "info", "packs"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	t := fi.ModTime()
+	return &t, nil
+}
+
+// countObjects sets the object counts of stats from `git count-objects -v`.
+func countObjects(ctx context.Context, dir GitDir, stats *protocol.RepoStats) error {
+	cmd := exec.CommandContext(ctx, "git", "count-objects", "-v")
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.Wrap(wrapCmdError(cmd, err), "failed to count objects")
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		// Each line is "<name>: <value>", with sizes in KiB.
+		parts := strings.SplitN(scanner.Text(), ": ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		n, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch parts[0] {
+		case "count":
+			stats.LooseObjects = n
+		case "size":
+			stats.LooseSizeBytes = n * 1024
+		case "in-pack":
+			stats.PackedObjects = n
+		case "packs":
+			stats.PackfileCount = int(n)
+		}
+	}
+	return nil
+}
+
+// repoStats returns statistics about the clone of repo. It returns
+// os.ErrNotExist if the repository is not cloned.
+func (s *Server) repoStats(ctx context.Context, repo api.RepoName) (*protocol.RepoStats, error) {
+	dir := s.dir(repo)
+	if !repoCloned(dir) {
+		return nil, os.ErrNotExist
+	}
+	size, err := dirSize(string(dir))
+	if err != nil {
+		return nil, err
+	}
+	return gitDirStats(ctx, dir, repo, size)
+}
+
+// gitDirStats returns statistics about the clone of repo at dir, which uses
+// size bytes of disk space.
+func gitDirStats(ctx context.Context, dir GitDir, repo api.RepoName, size int64) (*protocol.RepoStats, error) {
+	stats := &protocol.RepoStats{Repo: repo, SizeBytes: size}
+	if err := countObjects(ctx, dir, stats); err != nil {
+		return nil, err
+	}
+	var err error
+	if stats.LastFetchDuration, stats.LastFetchError, err = getLastFetch(dir); err != nil {
+		return nil, err
+	}
+	if stats.LastGC, err = lastGCTime(dir); err != nil {
+		return nil, err
+	}
+
+	if lastFetched, err := repoLastFetched(dir); err != nil {
+		log15.Warn("error computing last-fetched date", "repo", repo, "err", err)
+	} else {
+		stats.LastFetched = &lastFetched
+	}
+	if cloneTime, err := getRecloneTime(dir); err != nil {
+		log15.Warn("error getting reclone time", "repo", repo, "err", err)
+	} else {
+		stats.CloneTime = &cloneTime
+	}
+	return stats, nil
+}
+
+// handleRepoStats returns statistics about a repository clone. It responds
+// with 404 Not Found if the repository is not cloned.
+func (s *Server) handleRepoStats(w http.ResponseWriter, r *http.Request) {
+	var req protocol.RepoStatsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := s.repoStats(r.Context(), protocol.NormalizeRepo(req.Repo))
+	if os.IsNotExist(err) {
+		http.Error(w, "repository not cloned", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleListRepoStats returns statistics about all repository clones on this
+// gitserver, largest first. The optional limit query parameter limits the
+// number of repositories returned. Sizes are cheap to compute compared to the
+// other statistics, so those are only computed for the largest repositories
+// within the limit. Repositories whose statistics can't be computed are
+// skipped.
+func (s *Server) handleListRepoStats(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			http.Error(w, fmt.Sprintf("invalid limit %q", v), http.StatusBadRequest)
+			return
+		}
+	}
+
+	gitDirs, err := s.findGitDirs()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type sizedDir struct {
+		dir  GitDir
+		repo api.RepoName
+		size int64
+	}
+	dirs := make([]sizedDir, 0, len(gitDirs))
+	for _, dir := range gitDirs {
+		if err := r.Context().Err(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		if !repoCloned(dir) {
+			// Removed or still cloning.
+			continue
+		}
+		repo := s.name(dir)
+		size, err := dirSize(string(dir))
+		if err != nil {
+			log15.Warn("failed to compute repository size", "repo", repo, "error", err)
+			continue
+		}
+		dirs = append(dirs, sizedDir{dir: dir, repo: repo, size: size})
+	}
+
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i].size != dirs[j].size {
+			return dirs[i].size > dirs[j].size
+		}
+		return dirs[i].repo < dirs[j].repo
+	})
+	if limit > 0 && len(dirs) > limit {
+		dirs = dirs[:limit]
+	}
+
+	all := make([]*protocol.RepoStats, 0, len(dirs))
+	for _, d := range dirs {
+		if err := r.Context().Err(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		stats, err := gitDirStats(r.Context(), d.dir, d.repo, d.size)
+		if err != nil {
+			log15.Warn("failed to compute repository statistics", "repo", d.repo, "error", err)
+			continue
+		}
+		all = append(all, stats)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(all); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -32,7 +32,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/diskcache"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
@@ -116,6 +118,31 @@ type Server struct {
 	// DiskSizer tells how much disk is free and how large the disk is.
 	DiskSizer DiskSizer
 
+	// Hostname is the hostname of this gitserver. It is used to find this
+	// gitserver's address among the gitserver addresses when rebalancing
+	// repositories.
+	Hostname string
+
+	// GitServers is a client for all gitservers, including this one. If
+	// non-nil and the site configuration enables gitServerRebalancing,
+	// repositories are copied from other gitservers instead of being cloned
+	// from the code host where possible, and clones of repositories which
+	// moved to other gitservers are removed by the Janitor.
+	GitServers *gitserver.Client
+
+	// MaintenanceInterval is how often the Maintainer runs git gc --auto and
+	// writes commit-graphs and bitmap indexes for each repository. Zero
+	// disables maintenance.
+	MaintenanceInterval time.Duration
+
+	// MaintenanceConcurrency is the maximum number of repositories the
+	// Maintainer maintains at the same time. It defaults to 1.
+	MaintenanceConcurrency int
+
+	// ArchiveCacheSizeBytes is the size the Janitor shrinks the cache of
+	// recently requested archives to. Zero disables the cache.
+	ArchiveCacheSizeBytes int64
+
 	// skipCloneForTests is set by tests to avoid clones.
 	skipCloneForTests bool
 
@@ -138,6 +165,15 @@ type Server struct {
 
 	repoUpdateLocksMu sync.Mutex // protects the map below and also updates to locks.once
 	repoUpdateLocks   map[api.RepoName]*locks
+
+	// accesses tracks how frequently repositories are used, and evictions
+	// the repositories removed to free up disk space.
+	accesses  accessTracker
+	evictions evictionLog
+
+	// archiveCache caches archives served by handleArchive. It is nil if
+	// ArchiveCacheSizeBytes is zero.
+	archiveCache *diskcache.Store
 }
 
 type locks struct {
@@ -199,6 +235,9 @@ func (s *Server) Handler() http.Handler {
 	s.ctx, s.cancel = context.WithCancel(context.Background())
 	s.locker = &RepositoryLocker{}
 	s.repoUpdateLocks = make(map[api.RepoName]*locks)
+	if s.ArchiveCacheSizeBytes > 0 {
+		s.archiveCache = s.newArchiveCache()
+	}
 
 	// GitMaxConcurrentClones controls the maximum number of clones that
 	// can happen at once on a single gitserver.
@@ -230,10 +269,15 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/is-repo-cloneable", s.handleIsRepoCloneable)
 	mux.HandleFunc("/is-repo-cloned", s.handleIsRepoCloned)
 	mux.HandleFunc("/repos", s.handleRepoInfo)
+	mux.HandleFunc("/repo-stats", s.handleRepoStats)
+	mux.HandleFunc("/list-repo-stats", s.handleListRepoStats)
 	mux.HandleFunc("/delete", s.handleRepoDelete)
 	mux.HandleFunc("/repo-update", s.handleRepoUpdate)
 	mux.HandleFunc("/getGitolitePhabricatorMetadata", s.handleGetGitolitePhabricatorMetadata)
 	mux.HandleFunc("/create-commit-from-patch", s.handleCreateCommitFromPatch)
+	mux.HandleFunc("/bundle", s.handleBundle)
+	mux.HandleFunc("/git/", s.handleGit)
+	mux.HandleFunc("/evictions", s.handleEvictions)
 	mux.HandleFunc("/ping", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -245,6 +289,14 @@ func (s *Server) Janitor() {
 	s.cleanupRepos()
 }
 
+// Maintainer runs git maintenance tasks on the repositories in s.ReposDir
+// which are due for maintenance. It runs separately from the Janitor because
+// maintaining large repositories can take a long time, which must not delay
+// freeing up disk space.
+func (s *Server) Maintainer() {
+	s.maintainDueRepos()
+}
+
 // Stop cancels the running background jobs and returns when done.
 func (s *Server) Stop() {
 	// idempotent so we can just always set and cancel
@@ -403,7 +455,7 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 		// optimistically, we assume that our cloning attempt might
 		// succeed.
 		resp.CloneInProgress = true
-		_, err := s.cloneRepo(ctx, req.Repo, req.URL, &cloneOptions{Block: true})
+		_, err := s.cloneRepo(ctx, req.Repo, req.URL, &cloneOptions{Block: true, Limits: req.CloneOptions, Overrides: req.GitOverrides})
 		if err != nil {
 			log15.Warn("error cloning repo", "repo", req.Repo, "err", err)
 			resp.Error = err.Error()
@@ -412,7 +464,16 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 		resp.Cloned = true
 		var statusErr, updateErr error
 
-		if debounce(req.Repo, req.Since) {
+		if req.GitOverrides != nil {
+			updateErr = setGitOverrides(dir, req.GitOverrides)
+		}
+
+		if updateErr != nil {
+			log15.Warn("error setting git overrides", "repo", req.Repo, "err", updateErr)
+		} else if s.cloneOptionsChanged(dir, req.CloneOptions) {
+			log15.Info("recloning repo with changed clone options", "repo", req.Repo, "options", req.CloneOptions)
+			_, updateErr = s.cloneRepo(ctx, req.Repo, req.URL, &cloneOptions{Block: true, Overwrite: true, Limits: req.CloneOptions, Overrides: req.GitOverrides})
+		} else if debounce(req.Repo, req.Since) {
 			updateErr = s.doRepoUpdate(ctx, req.Repo, req.URL)
 		}
 
@@ -470,32 +531,14 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req := &protocol.ExecRequest{
-		Repo: api.RepoName(repo),
-		Args: []string{
-			"archive",
-
-			// Suppresses fatal error when the repo contains paths matching **/.git/** and instead
-			// includes those files (to allow archiving invalid such repos). This is unexpected
-			// behavior; the --worktree-attributes flag should merely let us specify a gitattributes
-			// file that contains `**/.git/** export-ignore`, but it actually makes everything work as
-			// desired. Tested by the "repo with .git dir" test case.
-			"--worktree-attributes",
-
-			"--format=" + format,
-		},
+	if s.archiveCache != nil && s.serveCachedArchive(w, r, api.RepoName(repo), treeish, format, paths) {
+		return
 	}
 
-	if format == "zip" {
-		// Compression level of 0 (no compression) seems to perform the
-		// best overall on fast network links, but this has not been tuned
-		// thoroughly.
-		req.Args = append(req.Args, "-0")
+	req := &protocol.ExecRequest{
+		Repo: api.RepoName(repo),
+		Args: archiveArgs(treeish, format, paths),
 	}
-
-	req.Args = append(req.Args, treeish, "--")
-	req.Args = append(req.Args, paths...)
-
 	s.exec(w, r, req)
 }
 
@@ -671,12 +714,25 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	stdoutW := &writeCounter{w: w}
 	stderrW := &writeCounter{w: &limitWriter{W: &stderrBuf, N: 1024}}
 
+	s.recordAccess(req.Repo)
+
 	cmdStart = time.Now()
 	cmd := exec.CommandContext(ctx, "git", req.Args...)
 	cmd.Dir = string(dir)
 	cmd.Stdout = stdoutW
 	cmd.Stderr = stderrW
 
+	// git fetches objects missing from partial clones from the code host
+	// when the command needs them.
+	if isPartialClone(dir) {
+		if len(req.Args) > 0 && req.Args[0] == "archive" {
+			if err := prefetchArchiveBlobs(ctx, dir, req.Args); err != nil {
+				log15.Warn("failed to prefetch blobs for archive", "repo", req.Repo, "error", err)
+			}
+		}
+		configureRemoteGitCommand(cmd, tlsExternal().(*tlsConfig))
+	}
+
 	exitStatus, execErr = runCommand(ctx, cmd)
 
 	status = strconv.Itoa(exitStatus)
@@ -724,6 +780,13 @@ type cloneOptions struct {
 
 	// Overwrite will overwrite the existing clone.
 	Overwrite bool
+
+	// Limits makes a partial or shallow clone. If nil, the repo is cloned
+	// in full.
+	Limits *protocol.CloneOptions
+
+	// Overrides change how the repo is cloned and fetched.
+	Overrides *protocol.GitOverrides
 }
 
 // cloneRepo issues a git clone command for the given repo. It is
@@ -807,25 +870,57 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		tmpPath = filepath.Join(tmpPath, ".git")
 		tmp := GitDir(tmpPath)
 
-		var cmd *exec.Cmd
-		if useRefspecOverrides() {
-			cmd, err = refspecOverridesCloneCmd(ctx, url, tmpPath)
+		var limits *protocol.CloneOptions
+		var overrides *protocol.GitOverrides
+		if opts != nil {
+			limits = opts.Limits
+			overrides = opts.Overrides
+		}
+		if err := validateGitOverrides(overrides); err != nil {
+			return err
+		}
+
+		// When rebalancing, copy new clones from another gitserver if one
+		// has the repository, to avoid cloning it from the code host again.
+		var peer string
+		if !overwrite && !useRefspecOverrides() && cloneOptionsIsZero(limits) && gitOverridesIsZero(overrides) && s.rebalancingEnabled() {
+			lock.SetStatus("copying from another gitserver")
+			peer, err = s.cloneFromPeer(ctx, repo, url, tmpPath)
 			if err != nil {
-				return err
+				log15.Warn("failed to copy repo from another gitserver, cloning from code host", "repo", repo, "error", redactor.redact(err.Error()))
+				if err := os.RemoveAll(tmpPath); err != nil {
+					return err
+				}
+				peer = ""
 			}
-		} else {
-			cmd = exec.CommandContext(ctx, "git", "clone", "--mirror", "--progress", url, tmpPath)
 		}
-		// see issue #7322: skip LFS content in repositories with Git LFS configured
-		cmd.Env = append(cmd.Env, "GIT_LFS_SKIP_SMUDGE=1")
-		log15.Info("cloning repo", "repo", repo, "tmp", tmpPath, "dst", dstPath)
 
-		pr, pw := io.Pipe()
-		defer pw.Close()
-		go readCloneProgress(redactor, lock, pr)
+		if peer != "" {
+			log15.Info("copied repo from another gitserver", "repo", repo, "peer", peer, "tmp", tmpPath, "dst", dstPath)
+			reposCopiedFromPeer.Inc()
+		} else {
+			var cmd *exec.Cmd
+			if useRefspecOverrides() {
+				cmd, err = refspecOverridesCloneCmd(ctx, url, tmpPath)
+				if err != nil {
+					return err
+				}
+			} else {
+				args := append([]string{"clone", "--mirror", "--progress"}, cloneOptionsArgs(limits)...)
+				args = append(args, gitOverridesCloneArgs(overrides)...)
+				cmd = exec.CommandContext(ctx, "git", append(args, url, tmpPath)...)
+			}
+			// see issue #7322: skip LFS content in repositories with Git LFS configured
+			cmd.Env = append(cmd.Env, "GIT_LFS_SKIP_SMUDGE=1")
+			log15.Info("cloning repo", "repo", repo, "tmp", tmpPath, "dst", dstPath)
+
+			pr, pw := io.Pipe()
+			defer pw.Close()
+			go readCloneProgress(redactor, lock, pr)
 
-		if output, err := runWithRemoteOpts(ctx, cmd, pw); err != nil {
-			return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+			if output, err := runWithRemoteOpts(ctx, cmd, pw); err != nil {
+				return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+			}
 		}
 
 		removeBadRefs(ctx, tmp)
@@ -840,6 +935,27 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 			return err
 		}
 
+		// Remember how the repo was cloned, so that fetches keep it
+		// partial or shallow and it is recloned when the options change.
+		if !cloneOptionsIsZero(limits) && !useRefspecOverrides() {
+			if err := setCloneOptions(tmp, limits); err != nil {
+				return err
+			}
+		}
+
+		// Remember the overrides, so that fetches and reclones use them.
+		if !gitOverridesIsZero(overrides) {
+			if err := setGitOverrides(tmp, overrides); err != nil {
+				return err
+			}
+			if overrides.FetchLFS {
+				lock.SetStatus("fetching Git LFS objects")
+				if output, err := runWithRemoteOpts(ctx, fetchLFSCmd(ctx, tmp), nil); err != nil {
+					return errors.Wrapf(err, "fetching Git LFS objects failed. Output: %s", redactor.redact(string(output)))
+				}
+			}
+		}
+
 		if overwrite {
 			// remove the current repo by putting it into our temporary directory
 			err := renameAndSync(dstPath, filepath.Join(filepath.Dir(tmpPath), "old"))
@@ -1302,15 +1418,45 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		}
 	}
 
+	overrides, err := getGitOverrides(dir)
+	if err != nil {
+		log15.Warn("Failed to get git overrides", "repo", repo, "error", err)
+	}
+
+	overridesCmd, err := gitOverridesFetchCmd(ctx, overrides)
+	if err != nil {
+		return errors.Wrap(err, "invalid git overrides")
+	}
+
 	configRemoteOpts := true
 	var cmd *exec.Cmd
-	if customCmd := customFetchCmd(ctx, url); customCmd != nil {
+	if overridesCmd != nil {
+		cmd = overridesCmd
+		configRemoteOpts = false
+	} else if customCmd := customFetchCmd(ctx, url); customCmd != nil {
 		cmd = customCmd
 		configRemoteOpts = false
 	} else if useRefspecOverrides() {
 		cmd = refspecOverridesFetchCmd(ctx, url)
 	} else {
-		cmd = exec.CommandContext(ctx, "git", "fetch", "--prune", url, "+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*", "+refs/pull/*:refs/pull/*", "+refs/sourcegraph/*:refs/sourcegraph/*")
+		args := []string{"fetch", "--prune", url}
+		if opts, err := getCloneOptions(dir); err != nil {
+			log15.Warn("Failed to get clone options", "repo", repo, "error", err)
+		} else if !cloneOptionsIsZero(opts) {
+			// Partial clones only fetch with their filter from the
+			// configured remote, which has url by now.
+			if opts.BlobSizeLimit != "" {
+				args[2] = "origin"
+			}
+			if opts.Depth > 0 {
+				args = append(args, "--depth="+strconv.Itoa(opts.Depth))
+			}
+		}
+		args = append(args, "+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*", "+refs/pull/*:refs/pull/*", "+refs/sourcegraph/*:refs/sourcegraph/*")
+		if overrides != nil {
+			args = append(args, overrides.Refspecs...)
+		}
+		cmd = exec.CommandContext(ctx, "git", args...)
 	}
 	cmd.Dir = string(dir)
 
@@ -1320,11 +1466,23 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 	// when the cleanup happens, just that it does.
 	defer s.cleanTmpFiles(dir)
 
-	if output, err := runWith(ctx, cmd, configRemoteOpts, nil); err != nil {
+	fetchStart := time.Now()
+	output, err := runWith(ctx, cmd, configRemoteOpts, nil)
+	if err := setLastFetch(dir, url, time.Since(fetchStart), err, output); err != nil {
+		log15.Warn("Failed to record last fetch", "repo", repo, "error", err)
+	}
+	if err != nil {
 		log15.Error("Failed to update", "repo", repo, "error", err, "output", string(output))
 		return errors.Wrap(err, "failed to update")
 	}
 
+	if overrides != nil && overrides.FetchLFS {
+		if output, err := runWithRemoteOpts(ctx, fetchLFSCmd(ctx, dir), nil); err != nil {
+			log15.Error("Failed to fetch Git LFS objects", "repo", repo, "error", err, "output", newURLRedactor(url).redact(string(output)))
+			return errors.Wrap(err, "failed to fetch Git LFS objects")
+		}
+	}
+
 	removeBadRefs(ctx, dir)
 
 	// Update the last-changed stamp.
@@ -1337,7 +1495,7 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 	// try to fetch HEAD from origin
 	cmd = exec.CommandContext(ctx, "git", "remote", "show", url)
 	cmd.Dir = path.Join(s.ReposDir, string(repo))
-	output, err := runWithRemoteOpts(ctx, cmd, nil)
+	output, err = runWithRemoteOpts(ctx, cmd, nil)
 	if err != nil {
 		log15.Error("Failed to fetch remote info", "repo", repo, "error", err, "output", string(output))
 		return errors.Wrap(err, "failed to fetch remote info")
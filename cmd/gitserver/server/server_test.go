@@ -105,9 +105,11 @@ func TestRequest(t *testing.T) {
 	s := &Server{ReposDir: "/testroot", skipCloneForTests: true}
 	h := s.Handler()
 
+	origRepoCloned := repoCloned
 	repoCloned = func(dir GitDir) bool {
 		return dir == s.dir("github.com/gorilla/mux") || dir == s.dir("my-mux")
 	}
+	defer func() { repoCloned = origRepoCloned }()
 
 	testRepoExists = func(ctx context.Context, url string) error {
 		if url == "https://github.com/nicksnyder/go-i18n.git" {
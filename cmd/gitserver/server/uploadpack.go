@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+var uploadPackRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "gitserver",
+	Name:      "upload_pack_requests_total",
+	Help:      "number of git smart HTTP requests served",
+}, []string{"op", "status"})
+
+func init() {
+	prometheus.MustRegister(uploadPackRequests)
+}
+
+// handleGit serves a read-only git smart HTTP endpoint per repository, so
+// that git clients can clone and fetch from gitserver:
+//
+//	GET /git/{repo}/info/refs?service=git-upload-pack
+//	POST /git/{repo}/git-upload-pack
+//
+// Both protocol v0 and v2 are supported. Only git-upload-pack is served,
+// pushes are rejected. It does not check permissions, which is left to the
+// frontend proxying the requests.
+func (s *Server) handleGit(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/git/")
+	var (
+		repo api.RepoName
+		op   string
+	)
+	switch {
+	case strings.HasSuffix(path, "/info/refs") && r.Method == "GET":
+		repo, op = api.RepoName(strings.TrimSuffix(path, "/info/refs")), "info-refs"
+	case strings.HasSuffix(path, "/git-upload-pack") && r.Method == "POST":
+		repo, op = api.RepoName(strings.TrimSuffix(path, "/git-upload-pack")), "upload-pack"
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	status := http.StatusOK
+	defer func() {
+		uploadPackRequests.WithLabelValues(op, fmt.Sprint(status)).Inc()
+	}()
+
+	if op == "info-refs" && r.URL.Query().Get("service") != "git-upload-pack" {
+		status = http.StatusForbidden
+		http.Error(w, "only git-upload-pack is supported", status)
+		return
+	}
+
+	repo = protocol.NormalizeRepo(repo)
+	dir := s.dir(repo)
+	if !repoCloned(dir) {
+		status = http.StatusNotFound
+		http.Error(w, "repository not cloned", status)
+		return
+	}
+
+	args := []string{
+		// Clients may make partial clones of our clones.
+		"-c", "uploadpack.allowFilter=true",
+		"upload-pack", "--stateless-rpc",
+	}
+	if op == "info-refs" {
+		args = append(args, "--advertise-refs")
+	}
+	cmd := exec.CommandContext(r.Context(), "git", append(args, ".")...)
+	cmd.Dir = string(dir)
+
+	// Objects missing from our partial clones are fetched from the code
+	// host.
+	if isPartialClone(dir) {
+		configureRemoteGitCommand(cmd, tlsExternal().(*tlsConfig))
+	}
+
+	// git upload-pack speaks protocol v2 if the client asks for it.
+	v2 := false
+	if gitProtocol := r.Header.Get("Git-Protocol"); gitProtocol != "" {
+		cmd.Env = append(cmd.Env, "GIT_PROTOCOL="+gitProtocol)
+		v2 = strings.Contains(gitProtocol, "version=2")
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &limitWriter{W: &stderr, N: 1024}
+	stdout := &writeCounter{w: w}
+	cmd.Stdout = stdout
+
+	w.Header().Set("Cache-Control", "no-cache")
+	if op == "info-refs" {
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+		w.WriteHeader(http.StatusOK)
+		// Protocol v2 clients don't expect the service announcement.
+		if !v2 {
+			_, _ = io.WriteString(w, "001e# service=git-upload-pack\n0000")
+		}
+	} else {
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				status = http.StatusBadRequest
+				http.Error(w, err.Error(), status)
+				return
+			}
+			defer gz.Close()
+			body = gz
+		}
+		cmd.Stdin = body
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-result")
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if _, err := runCommand(r.Context(), cmd); err != nil {
+		// We already sent the headers, so the client sees a truncated
+		// response.
+		status = http.StatusInternalServerError
+		log15.Error("git upload-pack failed", "repo", repo, "op", op, "error", err, "stderr", stderr.String(), "written", stdout.n)
+	}
+}
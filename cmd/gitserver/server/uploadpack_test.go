@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+)
+
+func TestHandleGit(t *testing.T) {
+	remote, cleanup1 := tmpDir(t)
+	defer cleanup1()
+
+	cmd := func(dir string, name string, arg ...string) string {
+		t.Helper()
+		c := exec.Command(name, arg...)
+		c.Dir = dir
+		c.Env = []string{
+			"GIT_COMMITTER_NAME=a",
+			"GIT_COMMITTER_EMAIL=a@a.com",
+			"GIT_AUTHOR_NAME=a",
+			"GIT_AUTHOR_EMAIL=a@a.com",
+		}
+		b, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s %s failed: %s", name, strings.Join(arg, " "), b)
+		}
+		return strings.TrimSpace(string(b))
+	}
+	cmd(remote, "git", "init", ".")
+	cmd(remote, "git", "commit", "--allow-empty", "-m", "first")
+	wantCommit := cmd(remote, "git", "rev-parse", "HEAD")
+
+	reposDir, cleanup2 := tmpDir(t)
+	defer cleanup2()
+	s := &Server{
+		ReposDir:         reposDir,
+		ctx:              context.Background(),
+		locker:           &RepositoryLocker{},
+		cloneLimiter:     mutablelimiter.New(1),
+		cloneableLimiter: mutablelimiter.New(1),
+	}
+	const repo = api.RepoName("example.com/foo/bar")
+	if _, err := s.cloneRepo(context.Background(), repo, remote, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	clonesDir, cleanup3 := tmpDir(t)
+	defer cleanup3()
+	for _, version := range []string{"0", "2"} {
+		t.Run("protocol v"+version, func(t *testing.T) {
+			cmd(clonesDir, "git", "-c", "protocol.version="+version, "clone", srv.URL+"/git/"+string(repo), "v"+version)
+			if got := cmd(filepath.Join(clonesDir, "v"+version), "git", "rev-parse", "HEAD"); got != wantCommit {
+				t.Errorf("got HEAD %s, want %s", got, wantCommit)
+			}
+		})
+	}
+
+	tests := []struct {
+		name, method, path string
+		wantStatus         int
+	}{
+		{"push", "GET", "/git/example.com/foo/bar/info/refs?service=git-receive-pack", http.StatusForbidden},
+		{"not cloned", "GET", "/git/example.com/foo/baz/info/refs?service=git-upload-pack", http.StatusNotFound},
+		{"unknown path", "GET", "/git/example.com/foo/bar/HEAD", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest(test.method, srv.URL+test.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != test.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+		})
+	}
+}
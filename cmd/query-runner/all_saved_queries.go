@@ -117,6 +117,11 @@ func notifySavedQueryWasCreatedOrUpdated(oldValue, newValue api.SavedQuerySpecAn
 				log15.Error("Failed to send unsubscribed Slack notification.", "recipient", removedRecipient, "error", err)
 			}
 		}
+		if removedRecipient.webhook {
+			if err := webhookNotifyUnsubscribed(ctx, removedRecipient, oldValue); err != nil {
+				log15.Error("Failed to send unsubscribed webhook notification.", "recipient", removedRecipient, "error", err)
+			}
+		}
 	}
 	for _, addedRecipient := range addedRecipients {
 		if addedRecipient.email {
@@ -129,6 +134,11 @@ func notifySavedQueryWasCreatedOrUpdated(oldValue, newValue api.SavedQuerySpecAn
 				log15.Error("Failed to send subscribed Slack notification.", "recipient", addedRecipient, "error", err)
 			}
 		}
+		if addedRecipient.webhook {
+			if err := webhookNotifySubscribed(ctx, addedRecipient, newValue); err != nil {
+				log15.Error("Failed to send subscribed webhook notification.", "recipient", addedRecipient, "error", err)
+			}
+		}
 	}
 	return nil
 }
@@ -162,6 +172,10 @@ func serveTestNotification(w http.ResponseWriter, r *http.Request) {
 			writeError(w, fmt.Errorf("error sending slack notifications to %s: %s", recipient.spec, err))
 			return
 		}
+		if err := webhookNotify(r.Context(), recipient, newWebhookPayload(webhookEventTest, args.SavedSearch), args.SavedSearch.Config); err != nil {
+			writeError(w, fmt.Errorf("error sending webhook notifications to %s: %s", recipient.spec, err))
+			return
+		}
 	}
 
 	log15.Info("saved query test notification sent", "spec", args.SavedSearch.Spec, "key", args.SavedSearch.Spec.Key)
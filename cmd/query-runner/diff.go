@@ -0,0 +1,188 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// resultFingerprints returns the sorted, deduplicated fingerprints of the
+// given search results. A file match has one fingerprint per matched line
+// (or a single one if only its path matched), and a commit or diff match has
+// one fingerprint for its commit.
+func resultFingerprints(results []interface{}) []api.SavedQueryResult {
+	seen := map[api.SavedQueryResult]struct{}{}
+	var fingerprints []api.SavedQueryResult
+	add := func(r api.SavedQueryResult) {
+		if _, ok := seen[r]; ok {
+			return
+		}
+		seen[r] = struct{}{}
+		fingerprints = append(fingerprints, r)
+	}
+
+	for _, result := range results {
+		rs, err := extractFingerprints(result)
+		if err != nil {
+			log15.Warn("failed to fingerprint search result", "error", err)
+			continue
+		}
+		for _, r := range rs {
+			add(r)
+		}
+	}
+
+	sort.Slice(fingerprints, func(i, j int) bool {
+		return lessSavedQueryResult(fingerprints[i], fingerprints[j])
+	})
+	return fingerprints
+}
+
+// extractFingerprints extracts the fingerprints from a single search result,
+// as decoded from the GraphQL response of gqlSearchQuery.
+func extractFingerprints(result interface{}) ([]api.SavedQueryResult, error) {
+	m, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected search result %T", result)
+	}
+	typeName, _ := m["__typename"].(string)
+	switch typeName {
+	case "FileMatch":
+		repo, _ := m["repository"].(map[string]interface{})
+		file, _ := m["file"].(map[string]interface{})
+		repoName, _ := repo["name"].(string)
+		path, _ := file["path"].(string)
+		if repoName == "" || path == "" {
+			return nil, fmt.Errorf("file match without repository or path")
+		}
+		lineMatches, _ := m["lineMatches"].([]interface{})
+		if len(lineMatches) == 0 {
+			return []api.SavedQueryResult{{Repo: repoName, Path: path}}, nil
+		}
+		fingerprints := make([]api.SavedQueryResult, 0, len(lineMatches))
+		for _, lm := range lineMatches {
+			lm, _ := lm.(map[string]interface{})
+			preview, _ := lm["preview"].(string)
+			sum := sha256.Sum256([]byte(preview))
+			fingerprints = append(fingerprints, api.SavedQueryResult{
+				Repo:     repoName,
+				Path:     path,
+				LineHash: hex.EncodeToString(sum[:]),
+			})
+		}
+		return fingerprints, nil
+
+	case "CommitSearchResult":
+		commit, _ := m["commit"].(map[string]interface{})
+		repo, _ := commit["repository"].(map[string]interface{})
+		repoName, _ := repo["name"].(string)
+		oid, _ := commit["oid"].(string)
+		if repoName == "" || oid == "" {
+			return nil, fmt.Errorf("commit match without repository or oid")
+		}
+		return []api.SavedQueryResult{{Repo: repoName, Commit: oid}}, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected result __typename %q", typeName)
+	}
+}
+
+// diffResults diffs the sorted fingerprints old against new, returning the
+// results which newly appeared and disappeared.
+func diffResults(old, new []api.SavedQueryResult) (added, removed []api.SavedQueryResult) {
+	i, j := 0, 0
+	for i < len(old) && j < len(new) {
+		switch {
+		case old[i] == new[j]:
+			i++
+			j++
+		case lessSavedQueryResult(old[i], new[j]):
+			removed = append(removed, old[i])
+			i++
+		default:
+			added = append(added, new[j])
+			j++
+		}
+	}
+	removed = append(removed, old[i:]...)
+	added = append(added, new[j:]...)
+	return added, removed
+}
+
+// diffRun diffs the results of a run against the previous run of the same
+// query, returning the results which newly appeared and disappeared.
+//
+// If a run hits the search result limit, its results are an arbitrary subset
+// of all results. Runs that hit the limit don't report removed results, and
+// neither they nor the run after them report new results, since they can't
+// be told apart from results that were beyond the limit.
+func diffRun(prevRun *api.SavedQueryRun, results []api.SavedQueryResult, limitHit bool) (added, removed []api.SavedQueryResult) {
+	if limitHit {
+		return nil, nil
+	}
+	added, removed = diffResults(prevRun.Results, results)
+	if prevRun.LimitHit {
+		added = nil
+	}
+	return added, removed
+}
+
+func lessSavedQueryResult(a, b api.SavedQueryResult) bool {
+	if a.Repo != b.Repo {
+		return a.Repo < b.Repo
+	}
+	if a.Path != b.Path {
+		return a.Path < b.Path
+	}
+	if a.Commit != b.Commit {
+		return a.Commit < b.Commit
+	}
+	return a.LineHash < b.LineHash
+}
+
+// maxListedResults is the maximum number of added or removed results listed
+// in a single notification.
+const maxListedResults = 10
+
+// describeResults returns a human-readable line for each file or commit in
+// results, in order. Multiple matched lines in the same file are described by
+// a single line. At most maxListedResults lines are returned, and the number
+// of files or commits which were left out.
+func describeResults(results []api.SavedQueryResult) (lines []string, omitted int) {
+	var (
+		descriptions []string
+		counts       = map[string]int{}
+	)
+	for _, r := range results {
+		var d string
+		if r.Commit != "" {
+			oid := r.Commit
+			if len(oid) > 7 {
+				oid = oid[:7]
+			}
+			d = fmt.Sprintf("%s@%s", r.Repo, oid)
+		} else {
+			d = fmt.Sprintf("%s › %s", r.Repo, r.Path)
+		}
+		if counts[d] == 0 {
+			descriptions = append(descriptions, d)
+		}
+		counts[d]++
+	}
+
+	for i, d := range descriptions {
+		if i == maxListedResults {
+			return lines, len(descriptions) - maxListedResults
+		}
+		if n := counts[d]; n > 1 {
+			d = fmt.Sprintf("%s (%d lines)", d, n)
+		}
+		lines = append(lines, d)
+	}
+	return lines, 0
+}
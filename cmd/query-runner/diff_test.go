@@ -0,0 +1,138 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestResultFingerprints(t *testing.T) {
+	results := []interface{}{
+		map[string]interface{}{
+			"__typename": "FileMatch",
+			"repository": map[string]interface{}{"name": "r"},
+			"file":       map[string]interface{}{"path": "b.go"},
+			"lineMatches": []interface{}{
+				map[string]interface{}{"preview": "foo"},
+				map[string]interface{}{"preview": "bar"},
+				map[string]interface{}{"preview": "foo"},
+			},
+		},
+		map[string]interface{}{
+			"__typename":  "FileMatch",
+			"repository":  map[string]interface{}{"name": "r"},
+			"file":        map[string]interface{}{"path": "a.go"},
+			"lineMatches": []interface{}{},
+		},
+		map[string]interface{}{
+			"__typename": "CommitSearchResult",
+			"commit": map[string]interface{}{
+				"repository": map[string]interface{}{"name": "q"},
+				"oid":        "deadbeef",
+			},
+		},
+		map[string]interface{}{
+			"__typename": "Repository",
+		},
+	}
+
+	got := resultFingerprints(results)
+	want := []api.SavedQueryResult{
+		{Repo: "q", Commit: "deadbeef"},
+		{Repo: "r", Path: "a.go"},
+		// sha256("foo") and sha256("bar"), sorted.
+		{Repo: "r", Path: "b.go", LineHash: "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"},
+		{Repo: "r", Path: "b.go", LineHash: "fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	a := api.SavedQueryResult{Repo: "r", Path: "a.go"}
+	b := api.SavedQueryResult{Repo: "r", Path: "b.go"}
+	c := api.SavedQueryResult{Repo: "r", Path: "c.go"}
+	d := api.SavedQueryResult{Repo: "s", Commit: "deadbeef"}
+
+	tests := []struct {
+		name                   string
+		old, new               []api.SavedQueryResult
+		wantAdded, wantRemoved []api.SavedQueryResult
+	}{
+		{name: "empty"},
+		{name: "unchanged", old: []api.SavedQueryResult{a, b}, new: []api.SavedQueryResult{a, b}},
+		{name: "first results", new: []api.SavedQueryResult{a, b}, wantAdded: []api.SavedQueryResult{a, b}},
+		{name: "all removed", old: []api.SavedQueryResult{a, b}, wantRemoved: []api.SavedQueryResult{a, b}},
+		{
+			name:        "added and removed",
+			old:         []api.SavedQueryResult{a, c},
+			new:         []api.SavedQueryResult{b, c, d},
+			wantAdded:   []api.SavedQueryResult{b, d},
+			wantRemoved: []api.SavedQueryResult{a},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			added, removed := diffResults(test.old, test.new)
+			if !reflect.DeepEqual(added, test.wantAdded) {
+				t.Errorf("got added %+v, want %+v", added, test.wantAdded)
+			}
+			if !reflect.DeepEqual(removed, test.wantRemoved) {
+				t.Errorf("got removed %+v, want %+v", removed, test.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestDiffRun(t *testing.T) {
+	a := api.SavedQueryResult{Repo: "r", Path: "a.go"}
+	b := api.SavedQueryResult{Repo: "r", Path: "b.go"}
+
+	tests := []struct {
+		name                   string
+		prevLimitHit, limitHit bool
+		wantAdded, wantRemoved []api.SavedQueryResult
+	}{
+		{name: "complete", wantAdded: []api.SavedQueryResult{b}, wantRemoved: []api.SavedQueryResult{a}},
+		{name: "limit hit", limitHit: true},
+		{name: "previous limit hit", prevLimitHit: true, wantRemoved: []api.SavedQueryResult{a}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prevRun := &api.SavedQueryRun{Results: []api.SavedQueryResult{a}, LimitHit: test.prevLimitHit}
+			added, removed := diffRun(prevRun, []api.SavedQueryResult{b}, test.limitHit)
+			if !reflect.DeepEqual(added, test.wantAdded) {
+				t.Errorf("got added %+v, want %+v", added, test.wantAdded)
+			}
+			if !reflect.DeepEqual(removed, test.wantRemoved) {
+				t.Errorf("got removed %+v, want %+v", removed, test.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestDescribeResults(t *testing.T) {
+	results := []api.SavedQueryResult{
+		{Repo: "q", Commit: "0123456789abcdef"},
+		{Repo: "r", Path: "a.go", LineHash: "1"},
+		{Repo: "r", Path: "a.go", LineHash: "2"},
+		{Repo: "r", Path: "b.go"},
+	}
+	lines, omitted := describeResults(results)
+	want := []string{"q@0123456", "r › a.go (2 lines)", "r › b.go"}
+	if !reflect.DeepEqual(lines, want) || omitted != 0 {
+		t.Errorf("got %q (%d omitted), want %q (0 omitted)", lines, omitted, want)
+	}
+
+	results = nil
+	for i := 0; i < maxListedResults+3; i++ {
+		results = append(results, api.SavedQueryResult{Repo: "r", Path: strings.Repeat("a", i+1)})
+	}
+	lines, omitted = describeResults(results)
+	if len(lines) != maxListedResults || omitted != 3 {
+		t.Errorf("got %d lines (%d omitted), want %d lines (3 omitted)", len(lines), omitted, maxListedResults)
+	}
+}
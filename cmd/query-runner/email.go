@@ -43,24 +43,28 @@ func (n *notifier) emailNotify(ctx context.Context) {
 				ownership = "your organization's"
 			}
 
-			plural := ""
-			if n.results.Data.Search.Results.ApproximateResultCount != "1" {
-				plural = "s"
-			}
+			added, addedOmitted := describeResults(n.added)
+			removed, removedOmitted := describeResults(n.removed)
 			if err := sendEmail(ctx, recipient.spec.userID, "results", newSearchResultsEmailTemplates, struct {
-				URL                    string
-				Description            string
-				Query                  string
-				ApproximateResultCount string
-				Ownership              string
-				PluralResults          string
+				URL            string
+				Description    string
+				Query          string
+				Summary        string
+				Ownership      string
+				Added          []string
+				AddedOmitted   int
+				Removed        []string
+				RemovedOmitted int
 			}{
-				URL:                    searchURL(n.newQuery, utmSourceEmail),
-				Description:            n.query.Description,
-				Query:                  n.query.Query,
-				ApproximateResultCount: n.results.Data.Search.Results.ApproximateResultCount,
-				Ownership:              ownership,
-				PluralResults:          plural,
+				URL:            searchURL(n.query.Query, utmSourceEmail),
+				Description:    n.query.Description,
+				Query:          n.query.Query,
+				Summary:        n.summary(),
+				Ownership:      ownership,
+				Added:          added,
+				AddedOmitted:   addedOmitted,
+				Removed:        removed,
+				RemovedOmitted: removedOmitted,
 			}); err != nil {
 				log15.Error("Failed to send email notification for new saved search results.", "userID", recipient.spec.userID, "error", err)
 			}
@@ -69,20 +73,42 @@ func (n *notifier) emailNotify(ctx context.Context) {
 }
 
 var newSearchResultsEmailTemplates = txemail.MustValidate(txtypes.Templates{
-	Subject: `[{{.ApproximateResultCount}} new result{{.PluralResults}}] {{.Description}}`,
+	Subject: `[{{.Summary}}] {{.Description}}`,
 	Text: `
-{{.ApproximateResultCount}} new search result{{.PluralResults}} found for {{.Ownership}} saved search:
+{{.Summary}} found for {{.Ownership}} saved search:
 
   "{{.Description}}"
-
-View the new result{{.PluralResults}} on Sourcegraph: {{.URL}}
+{{if .Added}}
+New results:
+{{range .Added}}
+  + {{.}}{{end}}{{if .AddedOmitted}}
+  ...and {{.AddedOmitted}} more{{end}}
+{{end}}{{if .Removed}}
+Removed results:
+{{range .Removed}}
+  - {{.}}{{end}}{{if .RemovedOmitted}}
+  ...and {{.RemovedOmitted}} more{{end}}
+{{end}}
+View the search results on Sourcegraph: {{.URL}}
 `,
 	HTML: `
-<strong>{{.ApproximateResultCount}}</strong> new search result{{.PluralResults}} found for {{.Ownership}} saved search:
+<strong>{{.Summary}}</strong> found for {{.Ownership}} saved search:
 
 <p style="padding-left: 16px">&quot;{{.Description}}&quot;</p>
-
-<p><a href="{{.URL}}">View the new result{{.PluralResults}} on Sourcegraph</a></p>
+{{if .Added}}
+<p>New results:</p>
+<ul>{{range .Added}}
+<li>{{.}}</li>{{end}}{{if .AddedOmitted}}
+<li>...and {{.AddedOmitted}} more</li>{{end}}
+</ul>
+{{end}}{{if .Removed}}
+<p>Removed results:</p>
+<ul>{{range .Removed}}
+<li>{{.}}</li>{{end}}{{if .RemovedOmitted}}
+<li>...and {{.RemovedOmitted}} more</li>{{end}}
+</ul>
+{{end}}
+<p><a href="{{.URL}}">View the search results on Sourcegraph</a></p>
 `,
 })
 
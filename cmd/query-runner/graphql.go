@@ -5,10 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/url"
-	"runtime"
-	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 
@@ -35,6 +32,12 @@ const gqlSearchQuery = `query Search(
 				__typename
 				... on FileMatch {
 					resource
+					repository {
+						name
+					}
+					file {
+						path
+					}
 					limitHit
 					lineMatches {
 						preview
@@ -113,6 +116,7 @@ type gqlSearchResponse struct {
 		Search struct {
 			Results struct {
 				ApproximateResultCount string
+				LimitHit               bool
 				Cloning                []*api.Repo
 				Timedout               []*api.Repo
 				Results                []interface{}
@@ -162,38 +166,3 @@ func gqlURL(queryName string) (string, error) {
 	u.RawQuery = queryName
 	return u.String(), nil
 }
-
-// extractTime extracts the time from the given search result.
-func extractTime(result interface{}) (t *time.Time, err error) {
-	// Use recover because we assume the data structure here a lot, for less
-	// error checking.
-	defer func() {
-		if r := recover(); r != nil {
-			// Same as net/http
-			const size = 64 << 10
-			buf := make([]byte, size)
-			buf = buf[:runtime.Stack(buf, false)]
-			log.Printf("failed to extract time from search result: %v\n%s", r, buf)
-		}
-		err = fmt.Errorf("failed to extract time from search result")
-	}()
-
-	m := result.(map[string]interface{})
-	typeName := m["__typename"].(string)
-	switch typeName {
-	case "CommitSearchResult":
-		commit := m["commit"].(map[string]interface{})
-		author := commit["author"].(map[string]interface{})
-		date := author["date"].(string)
-
-		// For now, our graphql API commit authorship date is in Go default time format.
-		goTimeFormat := "2006-01-02 15:04:05.999999999 -0700 MST"
-		t, err := time.Parse(date, goTimeFormat)
-		if err != nil {
-			return nil, err
-		}
-		return &t, nil
-	default:
-		return nil, fmt.Errorf("unexpected result __typename %q", typeName)
-	}
-}
@@ -11,7 +11,6 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
@@ -80,7 +79,8 @@ func writeError(w http.ResponseWriter, err error) {
 }
 
 // Useful for debugging e.g. email and slack notifications. Set it to true and
-// it will send one notification on server startup, effectively.
+// it will send one notification on server startup, effectively, by treating
+// all results of the first saved query that runs as new.
 var debugPretendSavedQueryResultsExist = false
 
 var executor = &executorT{}
@@ -142,12 +142,6 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 		// No need to run this query because there will be nobody to notify.
 		return nil
 	}
-	if !strings.Contains(query.Query, "type:diff") && !strings.Contains(query.Query, "type:commit") {
-		// TODO(slimsag): we temporarily do not support non-commit search
-		// queries, since those do not support the after:"time" operator.
-		return nil
-	}
-
 	info, err := api.InternalClient.SavedQueriesGetInfo(ctx, query.Query)
 	if err != nil {
 		return errors.Wrap(err, "SavedQueriesGetInfo")
@@ -175,21 +169,9 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 		}
 	}
 
-	// Construct a new query which finds search results introduced after the
-	// last time we queried.
-	var latestKnownResult time.Time
-	if info != nil {
-		latestKnownResult = info.LatestResult
-	} else {
-		// We've never executed this search query before, so use the current
-		// time. We'll most certainly find nothing, which is okay.
-		latestKnownResult = time.Now()
-	}
-	afterTime := latestKnownResult.UTC().Format(time.RFC3339)
-	newQuery := strings.Join([]string{query.Query, fmt.Sprintf(`after:"%s"`, afterTime)}, " ")
-	if debugPretendSavedQueryResultsExist {
-		debugPretendSavedQueryResultsExist = false
-		newQuery = query.Query
+	prevRun, err := api.InternalClient.SavedQueriesGetLatestRun(ctx, spec.Key)
+	if err != nil {
+		return errors.Wrap(err, "SavedQueriesGetLatestRun")
 	}
 
 	// Perform the search and mark the saved query as having been executed in
@@ -197,11 +179,23 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 	// fails in order to avoid e.g. failed saved queries from executing
 	// constantly and potentially causing harm to the system. We'll retry at
 	// our normal interval, regardless of errors.
-	v, execDuration, searchErr := performSearch(ctx, newQuery)
+	executedAt := time.Now()
+	v, execDuration, searchErr := performSearch(ctx, query.Query)
+	var added, removed []api.SavedQueryResult
+	if searchErr == nil {
+		added, removed, err = recordRun(ctx, spec, query, prevRun, v, executedAt, execDuration)
+		if err != nil {
+			return err
+		}
+	}
+	latestResult := executedAt
+	if len(added) == 0 && info != nil {
+		latestResult = info.LatestResult
+	}
 	if err := api.InternalClient.SavedQueriesSetInfo(ctx, &api.SavedQueryInfo{
 		Query:        query.Query,
 		LastExecuted: time.Now(),
-		LatestResult: latestResultTime(info, v, searchErr),
+		LatestResult: latestResult,
 		ExecDuration: execDuration,
 	}); err != nil {
 		return errors.Wrap(err, "SavedQueriesSetInfo")
@@ -215,13 +209,47 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 	// that we don't block other search queries from running in sequence (which
 	// is done intentionally, to ensure no overloading of searcher/gitserver).
 	go func() {
-		if err := notify(context.Background(), spec, query, newQuery, v); err != nil {
+		if err := notify(context.Background(), spec, query, added, removed); err != nil {
 			log15.Error("executor: failed to send notifications", "error", err)
 		}
 	}()
 	return nil
 }
 
+// recordRun fingerprints the results of a saved query run, diffs them against
+// the previous run and records the run. It returns the results which appeared
+// and disappeared since the previous run.
+func recordRun(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, prevRun *api.SavedQueryRun, v *gqlSearchResponse, executedAt time.Time, execDuration time.Duration) (added, removed []api.SavedQueryResult, err error) {
+	results := resultFingerprints(v.Data.Search.Results.Results)
+	limitHit := v.Data.Search.Results.LimitHit
+	if prevRun != nil && prevRun.Query == query.Query {
+		if limitHit {
+			log15.Warn("executor: saved query hit the result limit, not reporting new or removed results", "query", query.Query)
+		}
+		added, removed = diffRun(prevRun, results, limitHit)
+	} else if debugPretendSavedQueryResultsExist {
+		debugPretendSavedQueryResultsExist = false
+		added = results
+	}
+	// Otherwise this is the first run of the query (or the query was edited),
+	// so its results are the baseline to diff future runs against.
+
+	if err := api.InternalClient.SavedQueriesAddRun(ctx, &api.SavedQueryRun{
+		Key:          spec.Key,
+		Query:        query.Query,
+		ExecutedAt:   executedAt,
+		ExecDuration: execDuration,
+		ResultCount:  len(v.Data.Search.Results.Results),
+		LimitHit:     limitHit,
+		Results:      results,
+		Added:        added,
+		Removed:      removed,
+	}); err != nil {
+		return nil, nil, errors.Wrap(err, "SavedQueriesAddRun")
+	}
+	return added, removed, nil
+}
+
 func performSearch(ctx context.Context, query string) (v *gqlSearchResponse, execDuration time.Duration, err error) {
 	attempts := 0
 	for {
@@ -254,33 +282,15 @@ func performSearch(ctx context.Context, query string) (v *gqlSearchResponse, exe
 	}
 }
 
-func latestResultTime(prevInfo *api.SavedQueryInfo, v *gqlSearchResponse, searchErr error) time.Time {
-	if searchErr != nil || len(v.Data.Search.Results.Results) == 0 {
-		// Error performing the search, or there were no results. Assume the
-		// previous info's result time.
-		if prevInfo != nil {
-			return prevInfo.LatestResult
-		}
-		return time.Now()
-	}
-
-	// Results are ordered chronologically, so first result is the latest.
-	t, err := extractTime(v.Data.Search.Results.Results[0])
-	if err != nil {
-		// Error already logged by extractTime.
-		return time.Now()
-	}
-	return *t
-}
-
 var externalURL *url.URL
 
-// notify handles sending notifications for new search results.
-func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, newQuery string, results *gqlSearchResponse) error {
-	if len(results.Data.Search.Results.Results) == 0 {
+// notify handles sending notifications for search results which appeared or
+// disappeared since the previous run.
+func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, added, removed []api.SavedQueryResult) error {
+	if len(added) == 0 && len(removed) == 0 {
 		return nil
 	}
-	log15.Info("sending notifications", "new_results", len(results.Data.Search.Results.Results), "description", query.Description)
+	log15.Info("sending notifications", "new_results", len(added), "removed_results", len(removed), "description", query.Description)
 
 	// Determine which users to notify.
 	recipients, err := getNotificationRecipients(ctx, spec, query)
@@ -288,32 +298,52 @@ func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSave
 		return err
 	}
 
-	// Send slack notifications.
 	n := &notifier{
 		spec:       spec,
 		query:      query,
-		newQuery:   newQuery,
-		results:    results,
+		added:      added,
+		removed:    removed,
 		recipients: recipients,
 	}
 
-	// Send Slack and email notifications.
+	// Send Slack, email and webhook notifications.
 	n.slackNotify(ctx)
 	n.emailNotify(ctx)
+	n.webhookNotify(ctx)
 	return nil
 }
 
 type notifier struct {
-	spec       api.SavedQueryIDSpec
-	query      api.ConfigSavedQuery
-	newQuery   string
-	results    *gqlSearchResponse
-	recipients recipients
+	spec           api.SavedQueryIDSpec
+	query          api.ConfigSavedQuery
+	added, removed []api.SavedQueryResult
+	recipients     recipients
+}
+
+// summary returns a short description of the added and removed results, such
+// as "3 new results and 1 removed result".
+func (n *notifier) summary() string {
+	count := func(n int, adjective string) string {
+		plural := ""
+		if n != 1 {
+			plural = "s"
+		}
+		return fmt.Sprintf("%d %s result%s", n, adjective, plural)
+	}
+	switch {
+	case len(n.removed) == 0:
+		return count(len(n.added), "new")
+	case len(n.added) == 0:
+		return count(len(n.removed), "removed")
+	default:
+		return count(len(n.added), "new") + " and " + count(len(n.removed), "removed")
+	}
 }
 
 const (
-	utmSourceEmail = "saved-search-email"
-	utmSourceSlack = "saved-search-slack"
+	utmSourceEmail   = "saved-search-email"
+	utmSourceSlack   = "saved-search-slack"
+	utmSourceWebhook = "saved-search-webhook"
 )
 
 func searchURL(query, utmSource string) string {
@@ -23,13 +23,14 @@ func (r recipientSpec) String() string {
 // recipient describes a recipient of a saved search notification and the type of notifications
 // they're configured to receive.
 type recipient struct {
-	spec  recipientSpec // the recipient's identity
-	email bool          // send an email to the recipient
-	slack bool          // post a Slack message to the recipient
+	spec    recipientSpec // the recipient's identity
+	email   bool          // send an email to the recipient
+	slack   bool          // post a Slack message to the recipient
+	webhook bool          // post a JSON payload to the saved search's webhook URL
 }
 
 func (r *recipient) String() string {
-	return fmt.Sprintf("{%s email:%v slack:%v}", r.spec, r.email, r.slack)
+	return fmt.Sprintf("{%s email:%v slack:%v webhook:%v}", r.spec, r.email, r.slack, r.webhook)
 }
 
 // getNotificationRecipients retrieves the list of recipients who should receive notifications for
@@ -41,9 +42,10 @@ func getNotificationRecipients(ctx context.Context, spec api.SavedQueryIDSpec, q
 	switch {
 	case spec.Subject.User != nil:
 		recipients.add(recipient{
-			spec:  recipientSpec{userID: *spec.Subject.User},
-			email: query.Notify,
-			slack: query.NotifySlack,
+			spec:    recipientSpec{userID: *spec.Subject.User},
+			email:   query.Notify,
+			slack:   query.NotifySlack,
+			webhook: query.NotifyWebhook,
 		})
 
 	case spec.Subject.Org != nil:
@@ -62,8 +64,9 @@ func getNotificationRecipients(ctx context.Context, spec api.SavedQueryIDSpec, q
 		}
 
 		recipients.add(recipient{
-			spec:  recipientSpec{orgID: *spec.Subject.Org},
-			slack: query.NotifySlack,
+			spec:    recipientSpec{orgID: *spec.Subject.Org},
+			slack:   query.NotifySlack,
+			webhook: query.NotifyWebhook,
 		})
 	}
 
@@ -80,6 +83,7 @@ func (rs *recipients) add(r recipient) {
 			// Merge into existing recipient.
 			r2.email = r2.email || r.email
 			r2.slack = r2.slack || r.slack
+			r2.webhook = r2.webhook || r.webhook
 			return
 		}
 	}
@@ -113,17 +117,19 @@ func diffNotificationRecipients(old, new recipients) (removed, added recipients)
 			return nil, nil
 		}
 		removed = &recipient{
-			spec:  spec,
-			email: old.email && !new.email,
-			slack: old.slack && !new.slack,
+			spec:    spec,
+			email:   old.email && !new.email,
+			slack:   old.slack && !new.slack,
+			webhook: old.webhook && !new.webhook,
 		}
 		if *removed == empty {
 			removed = nil
 		}
 		added = &recipient{
-			spec:  spec,
-			email: new.email && !old.email,
-			slack: new.slack && !old.slack,
+			spec:    spec,
+			email:   new.email && !old.email,
+			slack:   new.slack && !old.slack,
+			webhook: new.webhook && !old.webhook,
 		}
 		if *added == empty {
 			added = nil
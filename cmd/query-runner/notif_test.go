@@ -45,8 +45,9 @@ func TestGetNotificationRecipients(t *testing.T) {
 				Subject: api.SettingsSubject{Org: &onetwothree},
 			},
 			api.ConfigSavedQuery{
-				Notify:      true,
-				NotifySlack: true,
+				Notify:        true,
+				NotifySlack:   true,
+				NotifyWebhook: true,
 			},
 		)
 		if err != nil {
@@ -56,7 +57,7 @@ func TestGetNotificationRecipients(t *testing.T) {
 			{spec: recipientSpec{userID: 1}, email: true},
 			{spec: recipientSpec{userID: 2}, email: true},
 			{spec: recipientSpec{userID: 3}, email: true},
-			{spec: recipientSpec{orgID: 123}, slack: true},
+			{spec: recipientSpec{orgID: 123}, slack: true, webhook: true},
 		}; !reflect.DeepEqual(recipients, want) {
 			t.Errorf("got %v, want %v", recipients, want)
 		}
@@ -122,6 +123,12 @@ func TestDiffNotificationRecipients(t *testing.T) {
 			wantRemoved: nil,
 			wantAdded:   recipients{{spec: recipientSpec{userID: 1}, slack: true}},
 		},
+		{
+			old:         recipients{{spec: recipientSpec{userID: 1}, slack: true}},
+			new:         recipients{{spec: recipientSpec{userID: 1}, slack: true, webhook: true}},
+			wantRemoved: nil,
+			wantAdded:   recipients{{spec: recipientSpec{userID: 1}, webhook: true}},
+		},
 		{
 			old:         recipients{{spec: recipientSpec{userID: 1}, email: true}},
 			new:         recipients{{spec: recipientSpec{orgID: 2}, slack: true}},
@@ -11,17 +11,23 @@ import (
 )
 
 func (n *notifier) slackNotify(ctx context.Context) {
-	plural := ""
-	if n.results.Data.Search.Results.ApproximateResultCount != "1" {
-		plural = "s"
-	}
-
-	text := fmt.Sprintf(`*%s* new result%s found for saved search <%s|"%s">`,
-		n.results.Data.Search.Results.ApproximateResultCount,
-		plural,
-		searchURL(n.newQuery, utmSourceSlack),
+	text := fmt.Sprintf(`*%s* found for saved search <%s|"%s">`,
+		n.summary(),
+		searchURL(n.query.Query, utmSourceSlack),
 		n.query.Description,
 	)
+	for _, l := range []struct {
+		prefix  string
+		results []api.SavedQueryResult
+	}{{"+", n.added}, {"-", n.removed}} {
+		lines, omitted := describeResults(l.results)
+		for _, line := range lines {
+			text += fmt.Sprintf("\n%s `%s`", l.prefix, line)
+		}
+		if omitted > 0 {
+			text += fmt.Sprintf("\n%s _...and %d more_", l.prefix, omitted)
+		}
+	}
 	for _, recipient := range n.recipients {
 		if err := slackNotify(ctx, recipient, text, n.query.SlackWebhookURL); err != nil {
 			log15.Error("Failed to post Slack notification message.", "recipient", recipient, "text", text, "error", err)
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+const (
+	// webhookMaxAttempts is the number of times a webhook notification is
+	// posted before giving up.
+	webhookMaxAttempts = 5
+
+	webhookEventResults      = "results"
+	webhookEventSubscribed   = "subscribed"
+	webhookEventUnsubscribed = "unsubscribed"
+	webhookEventTest         = "test"
+)
+
+var (
+	// webhookInitialBackoff is the time to wait before retrying a failed
+	// webhook notification. It doubles after every failed attempt.
+	webhookInitialBackoff = 2 * time.Second
+
+	// webhookClient only connects to public addresses, so that saved search
+	// webhooks can't be used to reach services on the internal network.
+	webhookClient = func() *http.Client {
+		cli, err := httpcli.NewFactory(nil, httpcli.PublicAddressesOnlyOpt).Client()
+		if err != nil {
+			panic(err)
+		}
+		cli.Timeout = 30 * time.Second
+		return cli
+	}()
+)
+
+// webhookPayload is the JSON body posted to a saved search's webhook URL.
+type webhookPayload struct {
+	// Event is one of "results", "subscribed", "unsubscribed" or "test".
+	Event       string                 `json:"event"`
+	SavedSearch webhookSavedSearch     `json:"savedSearch"`
+	URL         string                 `json:"url"`
+	Summary     string                 `json:"summary,omitempty"`
+	Added       []api.SavedQueryResult `json:"added,omitempty"`
+	Removed     []api.SavedQueryResult `json:"removed,omitempty"`
+}
+
+type webhookSavedSearch struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Query       string `json:"query"`
+}
+
+func newWebhookPayload(event string, query api.SavedQuerySpecAndConfig) *webhookPayload {
+	return &webhookPayload{
+		Event: event,
+		SavedSearch: webhookSavedSearch{
+			ID:          query.Spec.Key,
+			Description: query.Config.Description,
+			Query:       query.Config.Query,
+		},
+		URL: searchURL(query.Config.Query, utmSourceWebhook),
+	}
+}
+
+func (n *notifier) webhookNotify(ctx context.Context) {
+	payload := newWebhookPayload(webhookEventResults, api.SavedQuerySpecAndConfig{Spec: n.spec, Config: n.query})
+	payload.Summary = n.summary()
+	payload.Added = n.added
+	payload.Removed = n.removed
+	for _, recipient := range n.recipients {
+		if err := webhookNotify(ctx, recipient, payload, n.query); err != nil {
+			log15.Error("Failed to post webhook notification.", "recipient", recipient, "error", err)
+		}
+	}
+}
+
+func webhookNotifySubscribed(ctx context.Context, recipient *recipient, query api.SavedQuerySpecAndConfig) error {
+	return webhookNotify(ctx, recipient, newWebhookPayload(webhookEventSubscribed, query), query.Config)
+}
+
+func webhookNotifyUnsubscribed(ctx context.Context, recipient *recipient, query api.SavedQuerySpecAndConfig) error {
+	return webhookNotify(ctx, recipient, newWebhookPayload(webhookEventUnsubscribed, query), query.Config)
+}
+
+func webhookNotify(ctx context.Context, recipient *recipient, payload *webhookPayload, query api.ConfigSavedQuery) error {
+	if !recipient.webhook {
+		return nil
+	}
+
+	if query.WebhookURL == nil || *query.WebhookURL == "" {
+		return fmt.Errorf("unable to send webhook notification because recipient (%s) has no webhook URL configured", recipient.spec)
+	}
+	var secret string
+	if query.WebhookSecret != nil {
+		secret = *query.WebhookSecret
+	}
+	if err := postWebhook(ctx, *query.WebhookURL, secret, payload); err != nil {
+		return err
+	}
+	// TODO(Dan): find all users in the recipient list and log events for all of them
+	logEvent(0, "SavedSearchWebhookNotificationSent", payload.Event)
+	return nil
+}
+
+// postWebhook posts payload as JSON to webhookURL, retrying with exponential
+// backoff on network errors, rate limiting and server errors. If secret is
+// non-empty, the body is signed with HMAC-SHA256 and the signature is sent in
+// the X-Sourcegraph-Signature header.
+func postWebhook(ctx context.Context, webhookURL, secret string, payload *webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "webhook: marshal json")
+	}
+
+	backoff := webhookInitialBackoff
+	for attempt := 1; ; attempt++ {
+		retry, err := postWebhookOnce(ctx, webhookURL, secret, payload.Event, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt == webhookMaxAttempts {
+			return errors.Wrapf(err, "webhook: giving up after %d attempt(s)", attempt)
+		}
+		log15.Warn("webhook: failed to post notification (retrying)", "url", webhookURL, "attempt", attempt, "backoff", backoff, "error", err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// postWebhookOnce makes a single attempt at posting body to webhookURL. It
+// returns whether a failed attempt should be retried.
+func postWebhookOnce(ctx context.Context, webhookURL, secret, event string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest("POST", webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Sourcegraph-Saved-Search-Webhook")
+	req.Header.Set("X-Sourcegraph-Event", event)
+	if secret != "" {
+		req.Header.Set("X-Sourcegraph-Signature", "sha256="+webhookSignature(secret, body))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		// Retrying won't make a disallowed address allowed.
+		return !errors.Is(err, httpcli.ErrPrivateAddress), err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected HTTP response status %d", resp.StatusCode)
+}
+
+// webhookSignature returns the hex-encoded HMAC-SHA256 of body keyed by
+// secret.
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
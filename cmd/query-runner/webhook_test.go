@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+func TestPostWebhook(t *testing.T) {
+	defer func(d time.Duration) { webhookInitialBackoff = d }(webhookInitialBackoff)
+	webhookInitialBackoff = time.Millisecond
+
+	// The test server listens on a loopback address, which webhookClient
+	// doesn't connect to.
+	defer func(cli *http.Client) { webhookClient = cli }(webhookClient)
+	webhookClient = &http.Client{}
+
+	payload := &webhookPayload{
+		Event:       webhookEventResults,
+		SavedSearch: webhookSavedSearch{ID: "1", Description: "d", Query: "q"},
+		URL:         "https://sourcegraph.example.com/search?q=q",
+		Summary:     "1 new result",
+		Added:       []api.SavedQueryResult{{Repo: "r", Commit: "deadbeef"}},
+	}
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantErr      bool
+		wantAttempts int
+	}{
+		{name: "success", statuses: []int{200}, wantAttempts: 1},
+		{name: "retry server errors", statuses: []int{500, 429, 204}, wantAttempts: 3},
+		{name: "no retry on client errors", statuses: []int{400}, wantErr: true, wantAttempts: 1},
+		{name: "give up", statuses: []int{503}, wantErr: true, wantAttempts: webhookMaxAttempts},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attempts := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := r.Header.Get("X-Sourcegraph-Signature"), "sha256="+webhookSignature("s3cret", body); got != want {
+					t.Errorf("got signature %q, want %q", got, want)
+				}
+				if got := r.Header.Get("X-Sourcegraph-Event"); got != webhookEventResults {
+					t.Errorf("got event header %q, want %q", got, webhookEventResults)
+				}
+				var got webhookPayload
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(&got, payload) {
+					t.Errorf("got payload %+v, want %+v", got, payload)
+				}
+
+				status := test.statuses[len(test.statuses)-1]
+				if attempts < len(test.statuses) {
+					status = test.statuses[attempts]
+				}
+				attempts++
+				w.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			err := postWebhook(context.Background(), srv.URL, "s3cret", payload)
+			if (err != nil) != test.wantErr {
+				t.Errorf("got error %v, want error: %v", err, test.wantErr)
+			}
+			if attempts != test.wantAttempts {
+				t.Errorf("got %d attempts, want %d", attempts, test.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestPostWebhook_internalAddresses(t *testing.T) {
+	defer func(d time.Duration) { webhookInitialBackoff = d }(webhookInitialBackoff)
+	webhookInitialBackoff = time.Millisecond
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("webhook was posted to a loopback address")
+	}))
+	defer srv.Close()
+
+	payload := &webhookPayload{Event: webhookEventTest}
+	localhost := "http://localhost:" + strconv.Itoa(srv.Listener.Addr().(*net.TCPAddr).Port)
+	for _, u := range []string{srv.URL, localhost, "http://169.254.169.254/latest/meta-data", "http://10.0.0.1/hook"} {
+		err := postWebhook(context.Background(), u, "", payload)
+		if !errors.Is(err, httpcli.ErrPrivateAddress) {
+			t.Errorf("got error %v posting to %s, want ErrPrivateAddress", err, u)
+		} else if !strings.Contains(err.Error(), "after 1 attempt(s)") {
+			t.Errorf("got error %v posting to %s, want no retries", err, u)
+		}
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	// echo -n '{"event":"test"}' | openssl dgst -sha256 -hmac secret
+	want := "8419ab361b37d61b696d008ef7549a18325132dae5da84c7424e8e1c590d0498"
+	if got := webhookSignature("secret", []byte(`{"event":"test"}`)); got != want {
+		t.Errorf("got signature %q, want %q", got, want)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws/endpoints"
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
@@ -30,7 +31,8 @@ type AWSCodeCommitSource struct {
 	awsRegion    endpoints.Region
 	client       *awscodecommit.Client
 
-	exclude map[string]bool
+	exclude      map[string]bool
+	gitOverrides gitOverrides
 }
 
 // NewAWSCodeCommitSource returns a new AWSCodeCommitSource from the given external service.
@@ -90,6 +92,9 @@ func newAWSCodeCommitSource(svc *ExternalService, c *schema.AWSCodeCommitConnect
 		exclude:   exclude,
 		client:    awscodecommit.NewClient(awsConfig),
 	}
+	for _, o := range c.GitOverrides {
+		s.gitOverrides = append(s.gitOverrides, gitOverride(*o))
+	}
 
 	var ok bool
 	s.awsPartition, ok = endpoints.DefaultPartitions().ForRegion(c.Region)
@@ -120,19 +125,20 @@ func (s *AWSCodeCommitSource) makeRepo(r *awscodecommit.Repository) (*Repo, erro
 	cloneURL := s.authenticatedRemoteURL(r)
 	serviceID := awscodecommit.ServiceID(s.awsPartition, s.awsRegion, r.AccountID)
 
-	return &Repo{
+	return s.gitOverrides.apply(&Repo{
 		Name:         string(reposource.AWSRepoName(s.config.RepositoryPathPattern, r.Name)),
 		URI:          string(reposource.AWSRepoName("", r.Name)),
 		ExternalRepo: awscodecommit.ExternalRepoSpec(r, serviceID),
 		Description:  r.Description,
 		Sources: map[string]*SourceInfo{
 			urn: {
-				ID:       urn,
-				CloneURL: cloneURL,
+				ID:           urn,
+				CloneURL:     cloneURL,
+				CloneOptions: (*gitserverprotocol.CloneOptions)(s.config.CloneOptions),
 			},
 		},
 		Metadata: r,
-	}, nil
+	}, urn), nil
 }
 
 // authenticatedRemoteURL returns the repository's Git remote URL with the
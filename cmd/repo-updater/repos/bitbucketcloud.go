@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
@@ -26,6 +27,7 @@ type BitbucketCloudSource struct {
 	config          *schema.BitbucketCloudConnection
 	exclude         map[string]bool
 	excludePatterns []*regexp.Regexp
+	gitOverrides    gitOverrides
 	client          *bitbucketcloud.Client
 }
 
@@ -81,11 +83,17 @@ func newBitbucketCloudSource(svc *ExternalService, c *schema.BitbucketCloudConne
 	client.Username = c.Username
 	client.AppPassword = c.AppPassword
 
+	var overrides gitOverrides
+	for _, o := range c.GitOverrides {
+		overrides = append(overrides, gitOverride(*o))
+	}
+
 	return &BitbucketCloudSource{
 		svc:             svc,
 		config:          c,
 		exclude:         exclude,
 		excludePatterns: excludePatterns,
+		gitOverrides:    overrides,
 		client:          client,
 	}, nil
 }
@@ -109,8 +117,13 @@ func (s BitbucketCloudSource) makeRepo(r *bitbucketcloud.Repo) *Repo {
 	}
 	host = extsvc.NormalizeBaseURL(host)
 
+	var defaultBranch string
+	if r.MainBranch != nil {
+		defaultBranch = r.MainBranch.Name
+	}
+
 	urn := s.svc.URN()
-	return &Repo{
+	return s.gitOverrides.apply(&Repo{
 		Name: string(reposource.BitbucketCloudRepoName(
 			s.config.RepositoryPathPattern,
 			host.Hostname(),
@@ -126,17 +139,20 @@ func (s BitbucketCloudSource) makeRepo(r *bitbucketcloud.Repo) *Repo {
 			ServiceType: bitbucketcloud.ServiceType,
 			ServiceID:   host.String(),
 		},
-		Description: r.Description,
-		Fork:        r.Parent != nil,
-		Private:     r.IsPrivate,
+		Description:   r.Description,
+		Language:      r.Language,
+		Fork:          r.Parent != nil,
+		Private:       r.IsPrivate,
+		DefaultBranch: defaultBranch,
 		Sources: map[string]*SourceInfo{
 			urn: {
-				ID:       urn,
-				CloneURL: s.authenticatedRemoteURL(r),
+				ID:           urn,
+				CloneURL:     s.authenticatedRemoteURL(r),
+				CloneOptions: (*gitserverprotocol.CloneOptions)(s.config.CloneOptions),
 			},
 		},
 		Metadata: r,
-	}
+	}, urn)
 }
 
 // authenticatedRemoteURL returns the repository's Git remote URL with the configured
@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
@@ -29,6 +30,7 @@ type BitbucketServerSource struct {
 	config          *schema.BitbucketServerConnection
 	exclude         map[string]bool
 	excludePatterns []*regexp.Regexp
+	gitOverrides    gitOverrides
 	client          *bitbucketserver.Client
 }
 
@@ -87,11 +89,17 @@ func newBitbucketServerSource(svc *ExternalService, c *schema.BitbucketServerCon
 	client.Username = c.Username
 	client.Password = c.Password
 
+	var overrides gitOverrides
+	for _, o := range c.GitOverrides {
+		overrides = append(overrides, gitOverride(*o))
+	}
+
 	return &BitbucketServerSource{
 		svc:             svc,
 		config:          c,
 		exclude:         exclude,
 		excludePatterns: excludePatterns,
+		gitOverrides:    overrides,
 		client:          client,
 	}, nil
 }
@@ -252,7 +260,7 @@ func (s BitbucketServerSource) ExternalServices() ExternalServices {
 	return ExternalServices{s.svc}
 }
 
-func (s BitbucketServerSource) makeRepo(repo *bitbucketserver.Repo, isArchived bool) *Repo {
+func (s BitbucketServerSource) makeRepo(repo *bitbucketserver.Repo, labels []string) *Repo {
 	host, err := url.Parse(s.config.Url)
 	if err != nil {
 		// This should never happen
@@ -301,7 +309,7 @@ func (s BitbucketServerSource) makeRepo(repo *bitbucketserver.Repo, isArchived b
 
 	urn := s.svc.URN()
 
-	return &Repo{
+	return s.gitOverrides.apply(&Repo{
 		Name: string(reposource.BitbucketServerRepoName(
 			s.config.RepositoryPathPattern,
 			host.Hostname(),
@@ -321,16 +329,18 @@ func (s BitbucketServerSource) makeRepo(repo *bitbucketserver.Repo, isArchived b
 		},
 		Description: repo.Name,
 		Fork:        repo.Origin != nil,
-		Archived:    isArchived,
+		Archived:    hasArchivedLabel(labels),
 		Private:     !repo.Public,
+		Topics:      normalizeTopics(labels),
 		Sources: map[string]*SourceInfo{
 			urn: {
-				ID:       urn,
-				CloneURL: cloneURL,
+				ID:           urn,
+				CloneURL:     cloneURL,
+				CloneOptions: (*gitserverprotocol.CloneOptions)(s.config.CloneOptions),
 			},
 		},
 		Metadata: repo,
-	}
+	}, urn)
 }
 
 func (s *BitbucketServerSource) excludes(r *bitbucketserver.Repo) bool {
@@ -354,13 +364,14 @@ func (s *BitbucketServerSource) excludes(r *bitbucketserver.Repo) bool {
 }
 
 func (s *BitbucketServerSource) listAllRepos(ctx context.Context, results chan SourceResult) {
-	// "archived" label is a convention used at some customers for indicating
-	// a repository is archived (like github's archived state). This is not
-	// returned in the normal repository listing endpoints, so we need to
-	// fetch it separately.
-	archived, err := s.listAllLabeledRepos(ctx, "archived")
+	// Labels are the topics of repositories, and the "archived" label is a
+	// convention used at some customers for indicating a repository is
+	// archived (like github's archived state). They are not returned in the
+	// normal repository listing endpoints, so we need to fetch them
+	// separately.
+	labels, err := s.listAllRepoLabels(ctx)
 	if err != nil {
-		results <- SourceResult{Source: s, Err: errors.Wrap(err, "failed to list repos with archived label")}
+		results <- SourceResult{Source: s, Err: errors.Wrap(err, "failed to list repo labels")}
 		return
 	}
 
@@ -443,8 +454,7 @@ func (s *BitbucketServerSource) listAllRepos(ctx context.Context, results chan S
 
 		for _, repo := range r.repos {
 			if !seen[repo.ID] && !s.excludes(repo) {
-				_, isArchived := archived[repo.ID]
-				results <- SourceResult{Source: s, Repo: s.makeRepo(repo, isArchived)}
+				results <- SourceResult{Source: s, Repo: s.makeRepo(repo, labels[repo.ID])}
 				seen[repo.ID] = true
 			}
 		}
@@ -452,6 +462,59 @@ func (s *BitbucketServerSource) listAllRepos(ctx context.Context, results chan S
 	}
 }
 
+// listAllRepoLabels returns the labels of all labeled repos by repo ID. It
+// takes a paged request per label of the instance. If the labels of the
+// instance can't be listed, only the "archived" label is looked up.
+func (s *BitbucketServerSource) listAllRepoLabels(ctx context.Context) (map[int][]string, error) {
+	names, err := s.listAllLabels(ctx)
+	if err != nil {
+		// Older versions of bitbucket do not support listing labels.
+		if !bitbucketserver.IsNotFound(err) {
+			log15.Warn("failed to list bitbucketserver labels, only syncing the archived label", "url", s.config.Url, "error", err)
+		}
+		names = []string{"archived"}
+	}
+
+	labels := map[int][]string{}
+	for _, name := range names {
+		ids, err := s.listAllLabeledRepos(ctx, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "label %q", name)
+		}
+		for id := range ids {
+			labels[id] = append(labels[id], name)
+		}
+	}
+	return labels, nil
+}
+
+// listAllLabels returns the names of all labels of the instance.
+func (s *BitbucketServerSource) listAllLabels(ctx context.Context) ([]string, error) {
+	var names []string
+	next := &bitbucketserver.PageToken{Limit: 1000}
+	for next.HasMore() {
+		page, token, err := s.client.Labels(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range page {
+			names = append(names, l.Name)
+		}
+		next = token
+	}
+	return names, nil
+}
+
+// hasArchivedLabel reports whether labels include the "archived" label.
+func hasArchivedLabel(labels []string) bool {
+	for _, l := range labels {
+		if l == "archived" {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *BitbucketServerSource) listAllLabeledRepos(ctx context.Context, label string) (map[int]struct{}, error) {
 	ids := map[int]struct{}{}
 	next := &bitbucketserver.PageToken{Limit: 1000}
@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/testutil"
@@ -62,7 +65,7 @@ func TestBitbucketServerSource_MakeRepo(t *testing.T) {
 
 			var got []*Repo
 			for _, r := range repos {
-				got = append(got, s.makeRepo(r, false))
+				got = append(got, s.makeRepo(r, nil))
 			}
 
 			path := filepath.Join("testdata", "bitbucketserver-repos-"+name+".golden")
@@ -156,6 +159,56 @@ func TestBitbucketServerSource_Exclude(t *testing.T) {
 	}
 }
 
+func TestBitbucketServerSource_ListAllRepoLabels(t *testing.T) {
+	newSource := func(t *testing.T, listLabels bool) (*BitbucketServerSource, func()) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/rest/api/1.0/labels":
+				if !listLabels {
+					http.Error(w, "forbidden", http.StatusForbidden)
+					return
+				}
+				_, _ = w.Write([]byte(`{"isLastPage": true, "values": [{"name": "archived"}, {"name": "go"}]}`))
+			case "/rest/api/1.0/labels/archived/labeled":
+				_, _ = w.Write([]byte(`{"isLastPage": true, "values": [{"id": 1}]}`))
+			case "/rest/api/1.0/labels/go/labeled":
+				_, _ = w.Write([]byte(`{"isLastPage": true, "values": [{"id": 1}, {"id": 2}]}`))
+			default:
+				http.Error(w, r.URL.Path+" not found", http.StatusNotFound)
+			}
+		}))
+		svc := ExternalService{ID: 1, Kind: "BITBUCKETSERVER"}
+		s, err := newBitbucketServerSource(&svc, &schema.BitbucketServerConnection{Url: srv.URL, Token: "secret"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s, srv.Close
+	}
+
+	for _, tc := range []struct {
+		name       string
+		listLabels bool
+		want       map[int][]string
+	}{
+		{"all labels", true, map[int][]string{1: {"archived", "go"}, 2: {"go"}}},
+		// Without the labels of the instance, only archived repos are found.
+		{"labels can't be listed", false, map[int][]string{1: {"archived"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, done := newSource(t, tc.listLabels)
+			defer done()
+
+			got, err := s.listAllRepoLabels(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("labels mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestBitbucketServerSource_LoadChangesets(t *testing.T) {
 	instanceURL := os.Getenv("BITBUCKET_SERVER_URL")
 	if instanceURL == "" {
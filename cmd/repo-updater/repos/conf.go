@@ -13,3 +13,23 @@ func GetUpdateInterval() time.Duration {
 	}
 	return time.Duration(v) * time.Minute
 }
+
+// GetFullSyncInterval returns how often all repos of code hosts that support
+// incremental listing are listed. Zero means on every sync.
+func GetFullSyncInterval() time.Duration {
+	return time.Duration(conf.Get().RepoListFullSyncInterval) * time.Minute
+}
+
+// GetDeletionSafeguard returns the thresholds above which the repos a sync
+// would delete are quarantined instead.
+func GetDeletionSafeguard() DeletionSafeguard {
+	c := conf.Get().RepoDeletionSafeguard
+	if c == nil {
+		return DeletionSafeguard{}
+	}
+	return DeletionSafeguard{
+		MaxDeletions:          c.MaxDeletions,
+		MaxDeletionPercentage: c.MaxDeletionPercentage,
+		ConsistentSyncs:       c.ConsistentSyncs,
+	}
+}
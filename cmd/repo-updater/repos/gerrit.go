@@ -0,0 +1,220 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// A GerritSource yields repositories from a single Gerrit connection configured
+// in Sourcegraph via the external services configuration.
+type GerritSource struct {
+	svc             *ExternalService
+	config          *schema.GerritConnection
+	baseURL         *url.URL
+	exclude         map[string]bool
+	excludePatterns []*regexp.Regexp
+	gitOverrides    gitOverrides
+	client          *gerrit.Client
+}
+
+// NewGerritSource returns a new GerritSource from the given external service.
+func NewGerritSource(svc *ExternalService, cf *httpcli.Factory) (*GerritSource, error) {
+	var c schema.GerritConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, fmt.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGerritSource(svc, &c, cf)
+}
+
+func newGerritSource(svc *ExternalService, c *schema.GerritConnection, cf *httpcli.Factory) (*GerritSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	var opts []httpcli.Opt
+	if c.Certificate != "" {
+		opts = append(opts, httpcli.NewCertPoolOpt(c.Certificate))
+	}
+
+	cli, err := cf.Doer(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := make(map[string]bool, len(c.Exclude))
+	var excludePatterns []*regexp.Regexp
+	for _, r := range c.Exclude {
+		if r.Name != "" {
+			exclude[r.Name] = true
+		}
+
+		if r.Pattern != "" {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, err
+			}
+			excludePatterns = append(excludePatterns, re)
+		}
+	}
+
+	client := gerrit.NewClient(baseURL, cli)
+	client.Username = c.Username
+	client.Password = c.Password
+
+	var overrides gitOverrides
+	for _, o := range c.GitOverrides {
+		overrides = append(overrides, gitOverride(*o))
+	}
+
+	return &GerritSource{
+		svc:             svc,
+		config:          c,
+		baseURL:         baseURL,
+		exclude:         exclude,
+		excludePatterns: excludePatterns,
+		gitOverrides:    overrides,
+		client:          client,
+	}, nil
+}
+
+// ListRepos returns all Gerrit projects accessible to all connections configured
+// in Sourcegraph via the external services configuration.
+func (s GerritSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	if len(s.config.Projects) > 0 {
+		s.listProjects(ctx, results)
+		return
+	}
+	s.listAllProjects(ctx, results)
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s GerritSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+func (s GerritSource) makeRepo(p *gerrit.Project) *Repo {
+	urn := s.svc.URN()
+	return s.gitOverrides.apply(&Repo{
+		Name: string(reposource.GerritRepoName(
+			s.config.RepositoryPathPattern,
+			s.baseURL.Hostname(),
+			p.Name,
+		)),
+		URI: string(reposource.GerritRepoName(
+			"",
+			s.baseURL.Hostname(),
+			p.Name,
+		)),
+		// Gerrit identifies projects by name only.
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          p.Name,
+			ServiceType: gerrit.ServiceType,
+			ServiceID:   s.baseURL.String(),
+		},
+		Description: p.Description,
+		Archived:    p.State == gerrit.ProjectStateReadOnly,
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:           urn,
+				CloneURL:     s.authenticatedRemoteURL(p),
+				CloneOptions: (*gitserverprotocol.CloneOptions)(s.config.CloneOptions),
+			},
+		},
+		Metadata: p,
+	}, urn)
+}
+
+// authenticatedRemoteURL returns the project's Git remote URL with the configured
+// Gerrit username and HTTP password inserted in the URL userinfo.
+func (s *GerritSource) authenticatedRemoteURL(p *gerrit.Project) string {
+	u := *s.baseURL
+	if s.config.Username == "" {
+		u.Path += p.Name
+		return u.String()
+	}
+	// Gerrit serves authenticated Git requests under /a/, like its API.
+	u.Path += "a/" + p.Name
+	u.User = url.UserPassword(s.config.Username, s.config.Password)
+	return u.String()
+}
+
+func (s *GerritSource) excludes(p *gerrit.Project) bool {
+	if s.exclude[p.Name] {
+		return true
+	}
+
+	for _, re := range s.excludePatterns {
+		if re.MatchString(p.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// listProjects sends the configured projects to results.
+func (s *GerritSource) listProjects(ctx context.Context, results chan SourceResult) {
+	for _, name := range s.config.Projects {
+		p, err := s.client.Project(ctx, name)
+		if err != nil {
+			if e, ok := errors.Cause(err).(interface{ NotFound() bool }); ok && e.NotFound() {
+				log15.Warn("skipping missing gerrit.projects entry:", "name", name, "err", err)
+				continue
+			}
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gerrit.projects: name=%q", name)}
+			continue
+		}
+
+		if !s.excludes(p) {
+			results <- SourceResult{Source: s, Repo: s.makeRepo(p)}
+		}
+	}
+}
+
+// listAllProjects sends all projects visible to the configured user to
+// results, except for Gerrit's own and hidden projects.
+func (s *GerritSource) listAllProjects(ctx context.Context, results chan SourceResult) {
+	for skip := 0; ; {
+		projects, more, err := s.client.Projects(ctx, skip)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gerrit.projects: skip=%d", skip)}
+			return
+		}
+
+		for _, p := range projects {
+			if isGerritInternalProject(p.Name) || p.State == gerrit.ProjectStateHidden || s.excludes(p) {
+				continue
+			}
+			results <- SourceResult{Source: s, Repo: s.makeRepo(p)}
+		}
+
+		if !more || len(projects) == 0 || ctx.Err() != nil {
+			return
+		}
+		skip += len(projects)
+	}
+}
+
+// isGerritInternalProject reports whether the project named name holds
+// Gerrit's own configuration rather than code.
+func isGerritInternalProject(name string) bool {
+	return name == "All-Projects" || name == "All-Users"
+}
@@ -0,0 +1,100 @@
+package repos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGerritSource_ListRepos(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case "/a/projects/":
+			switch r.URL.Query().Get("S") {
+			case "0":
+				_, _ = w.Write([]byte(`)]}'
+{
+  "All-Projects": {"id": "All-Projects", "state": "ACTIVE"},
+  "All-Users": {"id": "All-Users", "state": "ACTIVE"},
+  "hidden": {"id": "hidden", "state": "HIDDEN"},
+  "tools/gerrit": {"id": "tools%2Fgerrit", "description": "Code review", "state": "ACTIVE", "_more_projects": true}
+}`))
+			case "4":
+				_, _ = w.Write([]byte(`)]}'
+{
+  "plugins/replication": {"id": "plugins%2Freplication", "state": "READ_ONLY"},
+  "sandbox/alice": {"id": "sandbox%2Falice", "state": "ACTIVE"}
+}`))
+			default:
+				http.Error(w, "unexpected page", http.StatusBadRequest)
+			}
+		case "/a/projects/tools%2Fgerrit":
+			_, _ = w.Write([]byte(")]}'\n" + `{"id": "tools%2Fgerrit", "name": "tools/gerrit", "state": "ACTIVE"}`))
+		default:
+			http.Error(w, r.URL.Path+" not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	config := func(extra string) string {
+		return `{
+			"url": "` + srv.URL + `/",
+			"username": "alice",
+			"password": "secret",
+			"repositoryPathPattern": "gerrit/{name}",
+			"exclude": [{"pattern": "^sandbox/"}]` + extra + `
+		}`
+	}
+
+	for _, tc := range []struct {
+		name   string
+		config string
+		want   map[string]string
+	}{
+		{
+			name:   "all projects",
+			config: config(""),
+			want: map[string]string{
+				"gerrit/tools/gerrit":        "http://alice:secret@" + srv.Listener.Addr().String() + "/a/tools/gerrit",
+				"gerrit/plugins/replication": "http://alice:secret@" + srv.Listener.Addr().String() + "/a/plugins/replication",
+			},
+		},
+		{
+			name:   "configured projects",
+			config: config(`, "projects": ["tools/gerrit", "missing"]`),
+			want: map[string]string{
+				"gerrit/tools/gerrit": "http://alice:secret@" + srv.Listener.Addr().String() + "/a/tools/gerrit",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			src, err := NewGerritSource(&ExternalService{ID: 8, Kind: "GERRIT", Config: tc.config}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repos, err := listAll(context.Background(), src)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[string]string{}
+			for _, r := range repos {
+				got[r.Name] = r.CloneURLs()[0]
+				if r.Name == "gerrit/plugins/replication" && !r.Archived {
+					t.Error("read-only project is not archived")
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("repos mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// A GiteaSource yields repositories from a single Gitea connection configured
+// in Sourcegraph via the external services configuration.
+type GiteaSource struct {
+	svc             *ExternalService
+	config          *schema.GiteaConnection
+	baseURL         *url.URL
+	exclude         map[string]bool
+	excludePatterns []*regexp.Regexp
+	gitOverrides    gitOverrides
+	client          *gitea.Client
+}
+
+// NewGiteaSource returns a new GiteaSource from the given external service.
+func NewGiteaSource(svc *ExternalService, cf *httpcli.Factory) (*GiteaSource, error) {
+	var c schema.GiteaConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, fmt.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGiteaSource(svc, &c, cf)
+}
+
+func newGiteaSource(svc *ExternalService, c *schema.GiteaConnection, cf *httpcli.Factory) (*GiteaSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	var opts []httpcli.Opt
+	if c.Certificate != "" {
+		opts = append(opts, httpcli.NewCertPoolOpt(c.Certificate))
+	}
+
+	cli, err := cf.Doer(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := make(map[string]bool, len(c.Exclude))
+	var excludePatterns []*regexp.Regexp
+	for _, r := range c.Exclude {
+		if r.Name != "" {
+			exclude[strings.ToLower(r.Name)] = true
+		}
+
+		if r.Id != 0 {
+			exclude[strconv.Itoa(r.Id)] = true
+		}
+
+		if r.Pattern != "" {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, err
+			}
+			excludePatterns = append(excludePatterns, re)
+		}
+	}
+
+	client := gitea.NewClient(baseURL, cli)
+	client.Token = c.Token
+
+	var overrides gitOverrides
+	for _, o := range c.GitOverrides {
+		overrides = append(overrides, gitOverride(*o))
+	}
+
+	return &GiteaSource{
+		svc:             svc,
+		config:          c,
+		baseURL:         baseURL,
+		exclude:         exclude,
+		excludePatterns: excludePatterns,
+		gitOverrides:    overrides,
+		client:          client,
+	}, nil
+}
+
+// ListRepos returns all Gitea repositories accessible to all connections configured
+// in Sourcegraph via the external services configuration.
+func (s GiteaSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	unfiltered := make(chan *giteaResult)
+	go func() {
+		s.listAllRepos(ctx, unfiltered)
+		close(unfiltered)
+	}()
+
+	seen := make(map[int64]bool)
+	for res := range unfiltered {
+		if res.err != nil {
+			results <- SourceResult{Source: s, Err: res.err}
+			continue
+		}
+		if !seen[res.repo.ID] && !s.excludes(res.repo) {
+			results <- SourceResult{Source: s, Repo: s.makeRepo(res.repo)}
+			seen[res.repo.ID] = true
+		}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s GiteaSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+func (s GiteaSource) makeRepo(r *gitea.Repo) *Repo {
+	urn := s.svc.URN()
+	return s.gitOverrides.apply(&Repo{
+		Name: string(reposource.GiteaRepoName(
+			s.config.RepositoryPathPattern,
+			s.baseURL.Hostname(),
+			r.FullName,
+		)),
+		URI: string(reposource.GiteaRepoName(
+			"",
+			s.baseURL.Hostname(),
+			r.FullName,
+		)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          strconv.FormatInt(r.ID, 10),
+			ServiceType: gitea.ServiceType,
+			ServiceID:   s.baseURL.String(),
+		},
+		Description: r.Description,
+		Fork:        r.Fork,
+		Archived:    r.Archived,
+		Private:     r.Private,
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:           urn,
+				CloneURL:     s.authenticatedRemoteURL(r),
+				CloneOptions: (*gitserverprotocol.CloneOptions)(s.config.CloneOptions),
+			},
+		},
+		Metadata: r,
+	}, urn)
+}
+
+// authenticatedRemoteURL returns the repository's Git remote URL with the configured
+// Gitea access token inserted in the URL userinfo.
+func (s *GiteaSource) authenticatedRemoteURL(repo *gitea.Repo) string {
+	if s.config.GitURLType == "ssh" {
+		if repo.SSHURL != "" {
+			return repo.SSHURL
+		}
+		return fmt.Sprintf("git@%s:%s.git", s.baseURL.Hostname(), repo.FullName)
+	}
+
+	if s.config.Token == "" {
+		return repo.CloneURL
+	}
+	u, err := url.Parse(repo.CloneURL)
+	if err != nil {
+		log15.Warn("Error adding authentication to Gitea repository Git remote URL.", "url", repo.CloneURL, "error", err)
+		return repo.CloneURL
+	}
+	u.User = url.User(s.config.Token)
+	return u.String()
+}
+
+func (s *GiteaSource) excludes(r *gitea.Repo) bool {
+	if s.exclude[strings.ToLower(r.FullName)] || s.exclude[strconv.FormatInt(r.ID, 10)] {
+		return true
+	}
+
+	for _, re := range s.excludePatterns {
+		if re.MatchString(r.FullName) {
+			return true
+		}
+	}
+	return false
+}
+
+type giteaResult struct {
+	err  error
+	repo *gitea.Repo
+}
+
+// giteaPager returns the repositories on a given page, and whether there may
+// be a next page.
+type giteaPager func(page int) (repos []*gitea.Repo, hasNext bool, err error)
+
+// paginate sends all the repositories from the given giteaPager to results.
+func (s *GiteaSource) paginate(ctx context.Context, what string, results chan *giteaResult, pager giteaPager) {
+	for page := 1; ; page++ {
+		repos, hasNext, err := pager(page)
+		if err != nil {
+			results <- &giteaResult{err: errors.Wrapf(err, "gitea.%s: page=%d", what, page)}
+			return
+		}
+
+		for _, r := range repos {
+			results <- &giteaResult{repo: r}
+		}
+
+		if !hasNext || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (s *GiteaSource) listRepos(ctx context.Context, repos []string, results chan *giteaResult) {
+	for _, nameWithOwner := range repos {
+		parts := strings.SplitN(nameWithOwner, "/", 2)
+		if len(parts) != 2 {
+			results <- &giteaResult{err: errors.Errorf("gitea.repos: invalid repository name %q, want owner/name", nameWithOwner)}
+			continue
+		}
+
+		repo, err := s.client.Repo(ctx, parts[0], parts[1])
+		if err != nil {
+			if e, ok := errors.Cause(err).(interface{ NotFound() bool }); ok && e.NotFound() {
+				log15.Warn("skipping missing gitea.repos entry:", "name", nameWithOwner, "err", err)
+				continue
+			}
+			results <- &giteaResult{err: errors.Wrapf(err, "gitea.repos: name=%q", nameWithOwner)}
+			continue
+		}
+
+		results <- &giteaResult{repo: repo}
+	}
+}
+
+func (s *GiteaSource) listRepositoryQuery(ctx context.Context, query string, results chan *giteaResult) {
+	switch query {
+	case "none":
+		// nothing
+	case "affiliated":
+		s.paginate(ctx, "affiliated", results, func(page int) ([]*gitea.Repo, bool, error) {
+			return s.client.UserRepos(ctx, page)
+		})
+	case "all":
+		s.paginate(ctx, "all", results, func(page int) ([]*gitea.Repo, bool, error) {
+			return s.client.SearchRepos(ctx, "", page)
+		})
+	default:
+		s.paginate(ctx, "search", results, func(page int) ([]*gitea.Repo, bool, error) {
+			return s.client.SearchRepos(ctx, query, page)
+		})
+	}
+}
+
+func (s *GiteaSource) listAllRepos(ctx context.Context, results chan *giteaResult) {
+	s.listRepos(ctx, s.config.Repos, results)
+
+	// Admins normally add to end of lists, so end of list most likely has new
+	// repos => stream them first.
+	for i := len(s.config.RepositoryQuery) - 1; i >= 0; i-- {
+		s.listRepositoryQuery(ctx, s.config.RepositoryQuery[i], results)
+	}
+
+	for i := len(s.config.Orgs) - 1; i >= 0; i-- {
+		org := s.config.Orgs[i]
+		s.paginate(ctx, "orgs", results, func(page int) ([]*gitea.Repo, bool, error) {
+			return s.client.OrgRepos(ctx, org, page)
+		})
+	}
+}
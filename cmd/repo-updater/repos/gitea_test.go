@@ -0,0 +1,89 @@
+package repos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestGiteaSource_ListRepos(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Lists end with an empty page.
+		if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/orgs/acme/repos":
+			_, _ = w.Write([]byte(`[
+				{"id": 1, "full_name": "acme/api", "clone_url": "` + "http://" + r.Host + `/acme/api.git"},
+				{"id": 2, "full_name": "acme/archive-old", "archived": true, "clone_url": "` + "http://" + r.Host + `/acme/archive-old.git"},
+				{"id": 3, "full_name": "acme/secret", "private": true, "clone_url": "` + "http://" + r.Host + `/acme/secret.git"}
+			]`))
+		case "/api/v1/repos/alice/dotfiles":
+			_, _ = w.Write([]byte(`{"id": 4, "full_name": "alice/dotfiles", "fork": true, "clone_url": "` + "http://" + r.Host + `/alice/dotfiles.git"}`))
+		case "/api/v1/user/repos":
+			// Repos listed several times are only yielded once.
+			_, _ = w.Write([]byte(`[{"id": 1, "full_name": "acme/api", "clone_url": "` + "http://" + r.Host + `/acme/api.git"}]`))
+		default:
+			http.Error(w, r.URL.Path+" not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	svc := &ExternalService{
+		ID:   7,
+		Kind: "GITEA",
+		Config: `{
+			"url": "` + srv.URL + `",
+			"token": "secret",
+			"orgs": ["acme"],
+			"repos": ["alice/dotfiles", "alice/missing"],
+			"repositoryQuery": ["affiliated"],
+			"exclude": [{"id": 3}, {"pattern": "^acme/archive-"}]
+		}`,
+	}
+	src, err := NewGiteaSource(svc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := listAll(context.Background(), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Name < repos[j].Name })
+
+	host := srv.URL[len("http://"):]
+	type summary struct {
+		Name         string
+		ExternalRepo api.ExternalRepoSpec
+		Fork         bool
+		CloneURL     string
+	}
+	var got []summary
+	for _, r := range repos {
+		got = append(got, summary{r.Name, r.ExternalRepo, r.Fork, r.CloneURLs()[0]})
+	}
+	want := []summary{
+		{
+			Name:         "127.0.0.1/acme/api",
+			ExternalRepo: api.ExternalRepoSpec{ID: "1", ServiceType: "gitea", ServiceID: srv.URL + "/"},
+			CloneURL:     "http://secret@" + host + "/acme/api.git",
+		},
+		{
+			Name:         "127.0.0.1/alice/dotfiles",
+			ExternalRepo: api.ExternalRepoSpec{ID: "4", ServiceType: "gitea", ServiceID: srv.URL + "/"},
+			Fork:         true,
+			CloneURL:     "http://secret@" + host + "/alice/dotfiles.git",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("repos mismatch (-want +got):\n%s", diff)
+	}
+}
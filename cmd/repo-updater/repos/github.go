@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
@@ -30,6 +31,7 @@ type GithubSource struct {
 	config          *schema.GitHubConnection
 	exclude         map[string]bool
 	excludePatterns []*regexp.Regexp
+	gitOverrides    gitOverrides
 	githubDotCom    bool
 	baseURL         *url.URL
 	client          *github.Client
@@ -100,11 +102,17 @@ func newGithubSource(svc *ExternalService, c *schema.GitHubConnection, cf *httpc
 		}
 	}
 
+	var overrides gitOverrides
+	for _, o := range c.GitOverrides {
+		overrides = append(overrides, gitOverride(*o))
+	}
+
 	return &GithubSource{
 		svc:              svc,
 		config:           c,
 		exclude:          exclude,
 		excludePatterns:  excludePatterns,
+		gitOverrides:     overrides,
 		baseURL:          baseURL,
 		githubDotCom:     githubDotCom,
 		client:           github.NewClient(apiURL, c.Token, cli),
@@ -121,9 +129,22 @@ type githubResult struct {
 // ListRepos returns all Github repositories accessible to all connections configured
 // in Sourcegraph via the external services configuration.
 func (s GithubSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	s.list(ctx, time.Time{}, results)
+}
+
+// ListReposSince returns the Github repositories that were pushed to since the
+// given time. The repositories configured in `repos` and those selected by
+// the `public` repositoryQuery are always returned.
+func (s GithubSource) ListReposSince(ctx context.Context, since time.Time, results chan SourceResult) {
+	s.list(ctx, since, results)
+}
+
+// list sends the repositories pushed to since the given time to results,
+// or all of them if since is zero.
+func (s GithubSource) list(ctx context.Context, since time.Time, results chan SourceResult) {
 	unfiltered := make(chan *githubResult)
 	go func() {
-		s.listAllRepositories(ctx, unfiltered)
+		s.listAllRepositories(ctx, since, unfiltered)
 		close(unfiltered)
 	}()
 
@@ -264,8 +285,16 @@ func (s GithubSource) GetRepo(ctx context.Context, nameWithOwner string) (*Repo,
 }
 
 func (s GithubSource) makeRepo(r *github.Repository) *Repo {
+	var language, defaultBranch string
+	if r.PrimaryLanguage != nil {
+		language = r.PrimaryLanguage.Name
+	}
+	if r.DefaultBranchRef != nil {
+		defaultBranch = r.DefaultBranchRef.Name
+	}
+
 	urn := s.svc.URN()
-	return &Repo{
+	return s.gitOverrides.apply(&Repo{
 		Name: string(reposource.GitHubRepoName(
 			s.config.RepositoryPathPattern,
 			s.originalHostname,
@@ -276,19 +305,24 @@ func (s GithubSource) makeRepo(r *github.Repository) *Repo {
 			s.originalHostname,
 			r.NameWithOwner,
 		)),
-		ExternalRepo: github.ExternalRepoSpec(r, *s.baseURL),
-		Description:  r.Description,
-		Fork:         r.IsFork,
-		Archived:     r.IsArchived,
-		Private:      r.IsPrivate,
+		ExternalRepo:  github.ExternalRepoSpec(r, *s.baseURL),
+		Description:   r.Description,
+		Language:      language,
+		Fork:          r.IsFork,
+		Archived:      r.IsArchived,
+		Private:       r.IsPrivate,
+		Topics:        normalizeTopics(r.Topics()),
+		Stars:         r.Stargazers.TotalCount,
+		DefaultBranch: defaultBranch,
 		Sources: map[string]*SourceInfo{
 			urn: {
-				ID:       urn,
-				CloneURL: s.authenticatedRemoteURL(r),
+				ID:           urn,
+				CloneURL:     s.authenticatedRemoteURL(r),
+				CloneOptions: (*gitserverprotocol.CloneOptions)(s.config.CloneOptions),
 			},
 		},
 		Metadata: r,
-	}
+	}, urn)
 }
 
 // authenticatedRemoteURL returns the repository's Git remote URL with the configured
@@ -366,7 +400,7 @@ func (s *GithubSource) paginate(ctx context.Context, results chan *githubResult,
 // by hitting the /orgs/:org/repos endpoint.
 //
 // It returns an error if the request fails on the first page.
-func (s *GithubSource) listOrg(ctx context.Context, org string, results chan *githubResult) {
+func (s *GithubSource) listOrg(ctx context.Context, org string, since time.Time, results chan *githubResult) {
 	var oerr error
 	s.paginate(ctx, results, func(page int) (repos []*github.Repository, hasNext bool, cost int, err error) {
 		defer func() {
@@ -388,11 +422,14 @@ func (s *GithubSource) listOrg(ctx context.Context, org string, results chan *gi
 				"retryAfter", retry,
 			)
 		}()
+		if !since.IsZero() {
+			return s.client.ListOrgRepositoriesPushedSince(ctx, org, since, page)
+		}
 		return s.client.ListOrgRepositories(ctx, org, page)
 	})
 
 	// Handle 404 from org repos endpoint by trying user repos endpoint
-	if oerr != nil && s.listUser(ctx, org, results) != nil {
+	if oerr != nil && s.listUser(ctx, org, since, results) != nil {
 		results <- &githubResult{
 			err: oerr,
 		}
@@ -403,7 +440,7 @@ func (s *GithubSource) listOrg(ctx context.Context, org string, results chan *gi
 // by hitting the /users/:user/repos endpoint.
 //
 // It returns an error if the request fails on the first page.
-func (s *GithubSource) listUser(ctx context.Context, user string, results chan *githubResult) (fail error) {
+func (s *GithubSource) listUser(ctx context.Context, user string, since time.Time, results chan *githubResult) (fail error) {
 	s.paginate(ctx, results, func(page int) (repos []*github.Repository, hasNext bool, cost int, err error) {
 		defer func() {
 			if err != nil && page == 1 {
@@ -420,6 +457,9 @@ func (s *GithubSource) listUser(ctx context.Context, user string, results chan *
 				"retryAfter", retry,
 			)
 		}()
+		if !since.IsZero() {
+			return s.client.ListUserRepositoriesPushedSince(ctx, user, since, page)
+		}
 		return s.client.ListUserRepositories(ctx, user, page)
 	})
 	return
@@ -514,7 +554,7 @@ func (s *GithubSource) listPublic(ctx context.Context, results chan *githubResul
 //
 // Affiliation is present if the user: (1) owns the repo, (2) is apart of an org that
 // the repo belongs to, or (3) is a collaborator.
-func (s *GithubSource) listAffiliated(ctx context.Context, results chan *githubResult) {
+func (s *GithubSource) listAffiliated(ctx context.Context, since time.Time, results chan *githubResult) {
 	s.paginate(ctx, results, func(page int) (repos []*github.Repository, hasNext bool, cost int, err error) {
 		defer func() {
 			remaining, reset, retry, _ := s.client.RateLimit.Get()
@@ -527,6 +567,9 @@ func (s *GithubSource) listAffiliated(ctx context.Context, results chan *githubR
 				"retryAfter", retry,
 			)
 		}()
+		if !since.IsZero() {
+			return s.client.ListAffiliatedRepositoriesPushedSince(ctx, since, page)
+		}
 		return s.client.ListAffiliatedRepositories(ctx, page)
 	})
 }
@@ -600,13 +643,13 @@ func matchOrg(q string) string {
 // - `none`: disables `repositoryQuery`
 // Inputs other than these three keywords will be queried using
 // GitHub advanced repository search (endpoint: /search/repositories)
-func (s *GithubSource) listRepositoryQuery(ctx context.Context, query string, results chan *githubResult) {
+func (s *GithubSource) listRepositoryQuery(ctx context.Context, query string, since time.Time, results chan *githubResult) {
 	switch query {
 	case "public":
 		s.listPublic(ctx, results)
 		return
 	case "affiliated":
-		s.listAffiliated(ctx, results)
+		s.listAffiliated(ctx, since, results)
 		return
 	case "none":
 		// nothing
@@ -621,10 +664,14 @@ func (s *GithubSource) listRepositoryQuery(ctx context.Context, query string, re
 	// If the org repo list API fails, we
 	// try the user repo list API.
 	if org := matchOrg(query); org != "" {
-		s.listOrg(ctx, org, results)
+		s.listOrg(ctx, org, since, results)
 		return
 	}
 
+	if !since.IsZero() {
+		query += " pushed:>=" + since.UTC().Format(time.RFC3339)
+	}
+
 	// Run the query as a GitHub advanced repository search
 	// (https://github.com/search/advanced).
 	s.listSearch(ctx, query, results)
@@ -632,17 +679,19 @@ func (s *GithubSource) listRepositoryQuery(ctx context.Context, query string, re
 
 // listAllRepositories returns the repositories from the given `orgs`, `repos`, and
 // `repositoryQuery` config options excluding the ones specified by `exclude`.
-func (s *GithubSource) listAllRepositories(ctx context.Context, results chan *githubResult) {
+// If since isn't zero, only the repositories pushed to since then are returned
+// where the GitHub API allows filtering them.
+func (s *GithubSource) listAllRepositories(ctx context.Context, since time.Time, results chan *githubResult) {
 	s.listRepos(ctx, s.config.Repos, results)
 
 	// Admins normally add to end of lists, so end of list most likely has new
 	// repos => stream them first.
 	for i := len(s.config.RepositoryQuery) - 1; i >= 0; i-- {
-		s.listRepositoryQuery(ctx, s.config.RepositoryQuery[i], results)
+		s.listRepositoryQuery(ctx, s.config.RepositoryQuery[i], since, results)
 	}
 
 	for i := len(s.config.Orgs) - 1; i >= 0; i-- {
-		s.listOrg(ctx, s.config.Orgs[i], results)
+		s.listOrg(ctx, s.config.Orgs[i], since, results)
 	}
 }
 
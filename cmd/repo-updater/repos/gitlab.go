@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
@@ -27,6 +28,7 @@ type GitLabSource struct {
 	exclude             map[string]bool
 	baseURL             *url.URL // URL with path /api/v4 (no trailing slash)
 	nameTransformations reposource.NameTransformations
+	gitOverrides        gitOverrides
 	client              *gitlab.Client
 }
 
@@ -77,12 +79,18 @@ func newGitLabSource(svc *ExternalService, c *schema.GitLabConnection, cf *httpc
 		return nil, err
 	}
 
+	var overrides gitOverrides
+	for _, o := range c.GitOverrides {
+		overrides = append(overrides, gitOverride(*o))
+	}
+
 	return &GitLabSource{
 		svc:                 svc,
 		config:              c,
 		exclude:             exclude,
 		baseURL:             baseURL,
 		nameTransformations: nts,
+		gitOverrides:        overrides,
 		client:              gitlab.NewClientProvider(baseURL, cli).GetPATClient(c.Token, ""),
 	}, nil
 }
@@ -90,7 +98,13 @@ func newGitLabSource(svc *ExternalService, c *schema.GitLabConnection, cf *httpc
 // ListRepos returns all GitLab repositories accessible to all connections configured
 // in Sourcegraph via the external services configuration.
 func (s GitLabSource) ListRepos(ctx context.Context, results chan SourceResult) {
-	s.listAllProjects(ctx, results)
+	s.listAllProjects(ctx, time.Time{}, results)
+}
+
+// ListReposSince returns the GitLab projects with activity since the given
+// time. The projects configured in `projects` are always returned.
+func (s GitLabSource) ListReposSince(ctx context.Context, since time.Time, results chan SourceResult) {
+	s.listAllProjects(ctx, since, results)
 }
 
 // GetRepo returns the GitLab repository with the given pathWithNamespace.
@@ -114,7 +128,7 @@ func (s GitLabSource) ExternalServices() ExternalServices {
 
 func (s GitLabSource) makeRepo(proj *gitlab.Project) *Repo {
 	urn := s.svc.URN()
-	return &Repo{
+	return s.gitOverrides.apply(&Repo{
 		Name: string(reposource.GitLabRepoName(
 			s.config.RepositoryPathPattern,
 			s.baseURL.Hostname(),
@@ -127,19 +141,23 @@ func (s GitLabSource) makeRepo(proj *gitlab.Project) *Repo {
 			proj.PathWithNamespace,
 			s.nameTransformations,
 		)),
-		ExternalRepo: gitlab.ExternalRepoSpec(proj, *s.baseURL),
-		Description:  proj.Description,
-		Fork:         proj.ForkedFromProject != nil,
-		Archived:     proj.Archived,
-		Private:      proj.Visibility == "private",
+		ExternalRepo:  gitlab.ExternalRepoSpec(proj, *s.baseURL),
+		Description:   proj.Description,
+		Fork:          proj.ForkedFromProject != nil,
+		Archived:      proj.Archived,
+		Private:       proj.Visibility == "private",
+		Topics:        normalizeTopics(proj.TagList),
+		Stars:         proj.StarCount,
+		DefaultBranch: proj.DefaultBranch,
 		Sources: map[string]*SourceInfo{
 			urn: {
-				ID:       urn,
-				CloneURL: s.authenticatedRemoteURL(proj),
+				ID:           urn,
+				CloneURL:     s.authenticatedRemoteURL(proj),
+				CloneOptions: (*gitserverprotocol.CloneOptions)(s.config.CloneOptions),
 			},
 		},
 		Metadata: proj,
-	}
+	}, urn)
 }
 
 // authenticatedRemoteURL returns the GitLab projects's Git remote URL with the configured GitLab personal access
@@ -165,7 +183,7 @@ func (s *GitLabSource) excludes(p *gitlab.Project) bool {
 	return s.exclude[p.PathWithNamespace] || s.exclude[strconv.Itoa(p.ID)]
 }
 
-func (s *GitLabSource) listAllProjects(ctx context.Context, results chan SourceResult) {
+func (s *GitLabSource) listAllProjects(ctx context.Context, since time.Time, results chan SourceResult) {
 	type batch struct {
 		projs []*gitlab.Project
 		err   error
@@ -229,7 +247,7 @@ func (s *GitLabSource) listAllProjects(ctx context.Context, results chan SourceR
 		go func(projectQuery string) {
 			defer wg.Done()
 
-			url, err := projectQueryToURL(projectQuery, perPage) // first page URL
+			url, err := projectQueryToURL(projectQuery, perPage, since) // first page URL
 			if err != nil {
 				ch <- batch{err: errors.Wrapf(err, "invalid GitLab projectQuery=%q", projectQuery)}
 				return
@@ -280,7 +298,10 @@ func (s *GitLabSource) listAllProjects(ctx context.Context, results chan SourceR
 
 var schemeOrHostNotEmptyErr = errors.New("scheme and host should be empty")
 
-func projectQueryToURL(projectQuery string, perPage int) (string, error) {
+// projectQueryToURL returns the URL of the first page of projects returned by
+// projectQuery. If since isn't zero, only projects with activity since then
+// are requested.
+func projectQueryToURL(projectQuery string, perPage int, since time.Time) (string, error) {
 	// If all we have is the URL query, prepend "projects"
 	if strings.HasPrefix(projectQuery, "?") {
 		projectQuery = "projects" + projectQuery
@@ -297,6 +318,9 @@ func projectQueryToURL(projectQuery string, perPage int) (string, error) {
 	}
 	q := u.Query()
 	q.Set("per_page", strconv.Itoa(perPage))
+	if !since.IsZero() {
+		q.Set("last_activity_after", since.UTC().Format(time.RFC3339))
+	}
 	u.RawQuery = q.Encode()
 
 	return u.String(), nil
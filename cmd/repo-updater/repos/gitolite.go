@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
@@ -25,9 +26,10 @@ type GitoliteSource struct {
 	conn *schema.GitoliteConnection
 	// We ask gitserver to talk to gitolite because it holds the ssh keys
 	// required for authentication.
-	cli       *gitserver.Client
-	blacklist *regexp.Regexp
-	exclude   map[string]bool
+	cli          *gitserver.Client
+	blacklist    *regexp.Regexp
+	exclude      map[string]bool
+	gitOverrides gitOverrides
 }
 
 // NewGitoliteSource returns a new GitoliteSource from the given external service.
@@ -61,12 +63,18 @@ func NewGitoliteSource(svc *ExternalService, cf *httpcli.Factory) (*GitoliteSour
 		}
 	}
 
+	var overrides gitOverrides
+	for _, o := range c.GitOverrides {
+		overrides = append(overrides, gitOverride(*o))
+	}
+
 	return &GitoliteSource{
-		svc:       svc,
-		conn:      &c,
-		cli:       gitserver.NewClient(hc),
-		blacklist: blacklist,
-		exclude:   exclude,
+		svc:          svc,
+		conn:         &c,
+		cli:          gitserver.NewClient(hc),
+		blacklist:    blacklist,
+		exclude:      exclude,
+		gitOverrides: overrides,
 	}, nil
 }
 
@@ -101,18 +109,19 @@ func (s GitoliteSource) excludes(gr *gitolite.Repo, r *Repo) bool {
 func (s GitoliteSource) makeRepo(repo *gitolite.Repo) *Repo {
 	urn := s.svc.URN()
 	name := string(reposource.GitoliteRepoName(s.conn.Prefix, repo.Name))
-	return &Repo{
+	return s.gitOverrides.apply(&Repo{
 		Name:         name,
 		URI:          name,
 		ExternalRepo: gitolite.ExternalRepoSpec(repo, gitolite.ServiceID(s.conn.Host)),
 		Sources: map[string]*SourceInfo{
 			urn: {
-				ID:       urn,
-				CloneURL: repo.URL,
+				ID:           urn,
+				CloneURL:     repo.URL,
+				CloneOptions: (*gitserverprotocol.CloneOptions)(s.conn.CloneOptions),
 			},
 		},
 		Metadata: repo,
-	}
+	}, urn)
 }
 
 // GitolitePhabricatorMetadataSyncer creates Phabricator repos (in the phabricator_repo table) for each Gitolite
@@ -0,0 +1,58 @@
+package repos
+
+import (
+	"strings"
+
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// gitOverride is an entry of the gitOverrides option of an external service.
+// The generated schema types of all kinds of external services convert to it.
+type gitOverride struct {
+	Config       map[string]string
+	FetchCommand string
+	FetchLFS     bool
+	Refspecs     []string
+	Repos        []string
+}
+
+// gitOverrides are the entries of the gitOverrides option of an external
+// service.
+type gitOverrides []gitOverride
+
+// forRepo returns the overrides of the first entry which applies to the repo
+// named name, or nil if none does.
+func (overrides gitOverrides) forRepo(name string) *gitserverprotocol.GitOverrides {
+	for _, o := range overrides {
+		if !o.appliesTo(name) {
+			continue
+		}
+		return &gitserverprotocol.GitOverrides{
+			Config:       o.Config,
+			FetchCommand: o.FetchCommand,
+			FetchLFS:     o.FetchLFS,
+			Refspecs:     o.Refspecs,
+		}
+	}
+	return nil
+}
+
+func (o *gitOverride) appliesTo(name string) bool {
+	if len(o.Repos) == 0 {
+		return true
+	}
+	for _, r := range o.Repos {
+		if strings.EqualFold(r, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply sets the git overrides of the source urn of r and returns r.
+func (overrides gitOverrides) apply(r *Repo, urn string) *Repo {
+	if src := r.Sources[urn]; src != nil {
+		src.GitOverrides = overrides.forRepo(r.Name)
+	}
+	return r
+}
@@ -0,0 +1,41 @@
+package repos
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestGitOverrides_forRepo(t *testing.T) {
+	overrides := gitOverrides{
+		{Repos: []string{"github.com/foo/lfs"}, FetchLFS: true},
+		{Refspecs: []string{"+refs/changes/*:refs/changes/*"}},
+	}
+
+	for _, tc := range []struct {
+		name string
+		want *gitserverprotocol.GitOverrides
+	}{
+		{
+			name: "github.com/foo/lfs",
+			want: &gitserverprotocol.GitOverrides{FetchLFS: true},
+		},
+		{
+			name: "github.com/Foo/LFS",
+			want: &gitserverprotocol.GitOverrides{FetchLFS: true},
+		},
+		{
+			name: "github.com/foo/bar",
+			want: &gitserverprotocol.GitOverrides{Refspecs: []string{"+refs/changes/*:refs/changes/*"}},
+		},
+	} {
+		if diff := cmp.Diff(tc.want, overrides.forRepo(tc.name)); diff != "" {
+			t.Errorf("%s: overrides mismatch (-want +got):\n%s", tc.name, diff)
+		}
+	}
+
+	if got := overrides[:1].forRepo("github.com/foo/bar"); got != nil {
+		t.Errorf("got overrides %+v for unlisted repo, want nil", got)
+	}
+}
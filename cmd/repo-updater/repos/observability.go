@@ -22,11 +22,15 @@ type ErrorLogger interface {
 // with error logging, Prometheus metrics and tracing.
 func ObservedSource(l ErrorLogger, m SourceMetrics) func(Source) Source {
 	return func(s Source) Source {
-		return &observedSource{
+		o := &observedSource{
 			Source:  s,
 			metrics: m,
 			log:     l,
 		}
+		if _, ok := s.(IncrementalSource); ok {
+			return &observedIncrementalSource{o}
+		}
+		return o
 	}
 }
 
@@ -98,8 +102,27 @@ func NewSourceMetrics() SourceMetrics {
 	}
 }
 
+// An observedIncrementalSource is an observedSource that wraps an
+// IncrementalSource.
+type observedIncrementalSource struct {
+	*observedSource
+}
+
 // ListRepos calls into the inner Source registers the observed results.
 func (o *observedSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	o.observe(results, func(uncounted chan SourceResult) {
+		o.Source.ListRepos(ctx, uncounted)
+	})
+}
+
+// ListReposSince calls into the inner IncrementalSource registers the observed results.
+func (o *observedIncrementalSource) ListReposSince(ctx context.Context, since time.Time, results chan SourceResult) {
+	o.observe(results, func(uncounted chan SourceResult) {
+		o.Source.(IncrementalSource).ListReposSince(ctx, since, uncounted)
+	})
+}
+
+func (o *observedSource) observe(results chan SourceResult, list func(chan SourceResult)) {
 	var (
 		err   error
 		count float64
@@ -113,7 +136,7 @@ func (o *observedSource) ListRepos(ctx context.Context, results chan SourceResul
 
 	uncounted := make(chan SourceResult)
 	go func() {
-		o.Source.ListRepos(ctx, uncounted)
+		list(uncounted)
 		close(uncounted)
 	}()
 
@@ -163,6 +186,8 @@ type StoreMetrics struct {
 	UpsertExternalServices *OperationMetrics
 	ListExternalServices   *OperationMetrics
 	ListAllRepoNames       *OperationMetrics
+
+	UpdateExternalServiceSyncTimes *OperationMetrics
 }
 
 // NewStoreMetrics returns StoreMetrics that need to be registered
@@ -289,6 +314,26 @@ func NewStoreMetrics() StoreMetrics {
 				Help:      "Total number of errors when listing external_services",
 			}, []string{}),
 		},
+		UpdateExternalServiceSyncTimes: &OperationMetrics{
+			Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_update_external_service_sync_times_duration_seconds",
+				Help:      "Time spent updating the sync times of external services",
+			}, []string{}),
+			Count: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_update_external_service_sync_times_total",
+				Help:      "Total number of external services whose sync times were updated",
+			}, []string{}),
+			Errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_update_external_service_sync_times_errors_total",
+				Help:      "Total number of errors when updating the sync times of external services",
+			}, []string{}),
+		},
 		ListAllRepoNames: &OperationMetrics{
 			Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 				Namespace: "src",
@@ -427,6 +472,31 @@ func (o *ObservedStore) UpsertExternalServices(ctx context.Context, svcs ...*Ext
 	return o.store.UpsertExternalServices(ctx, svcs...)
 }
 
+// UpdateExternalServiceSyncTimes calls into the inner Store and registers the observed results.
+func (o *ObservedStore) UpdateExternalServiceSyncTimes(ctx context.Context, svcs ...*ExternalService) (err error) {
+	tr, ctx := o.trace(ctx, "Store.UpdateExternalServiceSyncTimes")
+	tr.LogFields(
+		otlog.Int("count", len(svcs)),
+		otlog.Object("urns", ExternalServices(svcs).URNs()),
+	)
+
+	defer func(began time.Time) {
+		secs := time.Since(began).Seconds()
+		count := float64(len(svcs))
+
+		o.metrics.UpdateExternalServiceSyncTimes.Observe(secs, count, &err)
+		log(o.log, "store.update-external-service-sync-times", &err,
+			"count", len(svcs),
+			"names", ExternalServices(svcs).DisplayNames(),
+		)
+
+		tr.SetError(err)
+		tr.Finish()
+	}(time.Now())
+
+	return o.store.UpdateExternalServiceSyncTimes(ctx, svcs...)
+}
+
 // ListRepos calls into the inner Store and registers the observed results.
 func (o *ObservedStore) ListRepos(ctx context.Context, args StoreListReposArgs) (rs []*Repo, err error) {
 	tr, ctx := o.trace(ctx, "Store.ListRepos")
@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
@@ -19,9 +20,10 @@ import (
 // A OtherSource yields repositories from a single Other connection configured
 // in Sourcegraph via the external services configuration.
 type OtherSource struct {
-	svc    *ExternalService
-	conn   *schema.OtherExternalServiceConnection
-	client httpcli.Doer
+	svc          *ExternalService
+	conn         *schema.OtherExternalServiceConnection
+	client       httpcli.Doer
+	gitOverrides gitOverrides
 }
 
 // NewOtherSource returns a new OtherSource from the given external service.
@@ -40,7 +42,12 @@ func NewOtherSource(svc *ExternalService, cf *httpcli.Factory) (*OtherSource, er
 		return nil, err
 	}
 
-	return &OtherSource{svc: svc, conn: &c, client: cli}, nil
+	var overrides gitOverrides
+	for _, o := range c.GitOverrides {
+		overrides = append(overrides, gitOverride(*o))
+	}
+
+	return &OtherSource{svc: svc, conn: &c, client: cli, gitOverrides: overrides}, nil
 }
 
 // ListRepos returns all Other repositories accessible to all connections configured
@@ -125,7 +132,7 @@ func (s OtherSource) otherRepoFromCloneURL(urn string, u *url.URL) (*Repo, error
 	u.Path, u.RawQuery = "", ""
 	serviceID := u.String()
 
-	return &Repo{
+	return s.gitOverrides.apply(&Repo{
 		Name: string(repoName),
 		URI:  repoURI,
 		ExternalRepo: api.ExternalRepoSpec{
@@ -135,11 +142,12 @@ func (s OtherSource) otherRepoFromCloneURL(urn string, u *url.URL) (*Repo, error
 		},
 		Sources: map[string]*SourceInfo{
 			urn: {
-				ID:       urn,
-				CloneURL: repoURL,
+				ID:           urn,
+				CloneURL:     repoURL,
+				CloneOptions: (*gitserverprotocol.CloneOptions)(s.conn.CloneOptions),
 			},
 		},
-	}, nil
+	}, urn), nil
 }
 
 func (s OtherSource) srcExpose(ctx context.Context) ([]*Repo, error) {
@@ -188,7 +196,8 @@ func (s OtherSource) srcExpose(ctx context.Context) ([]*Repo, error) {
 			urn: {
 				ID: urn,
 				// TODO we should allow this to be set
-				CloneURL: clonePrefix + strings.TrimPrefix(r.URI, "/") + "/.git",
+				CloneURL:     clonePrefix + strings.TrimPrefix(r.URI, "/") + "/.git",
+				CloneOptions: (*gitserverprotocol.CloneOptions)(s.conn.CloneOptions),
 			},
 		}
 
@@ -196,6 +205,7 @@ func (s OtherSource) srcExpose(ctx context.Context) ([]*Repo, error) {
 		if r.Name == "" {
 			r.Name = r.URI
 		}
+		r.Sources[urn].GitOverrides = s.gitOverrides.forRepo(r.Name)
 	}
 
 	return data.Items, nil
@@ -3,6 +3,7 @@ package repos
 import (
 	"container/heap"
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -107,6 +108,14 @@ type configuredRepo2 struct {
 	URL  string
 	ID   api.RepoID
 	Name api.RepoName
+
+	// CloneOptions are the options gitserver clones the repo with. If nil,
+	// gitserver keeps the options of an existing clone.
+	CloneOptions *gitserverprotocol.CloneOptions
+
+	// GitOverrides change how gitserver clones and fetches the repo. If nil,
+	// gitserver keeps the overrides of an existing clone.
+	GitOverrides *gitserverprotocol.GitOverrides
 }
 
 // notifyChanBuffer controls the buffer size of notification channels.
@@ -208,7 +217,7 @@ func (s *updateScheduler) runUpdateLoop(ctx context.Context) {
 
 // requestRepoUpdate sends a request to gitserver to request an update.
 var requestRepoUpdate = func(ctx context.Context, repo *configuredRepo2, since time.Duration) (*gitserverprotocol.RepoUpdateResponse, error) {
-	return gitserver.DefaultClient.RequestRepoUpdate(ctx, gitserver.Repo{Name: repo.Name, URL: repo.URL}, since)
+	return gitserver.DefaultClient.RequestRepoUpdate(ctx, gitserver.Repo{Name: repo.Name, URL: repo.URL, CloneOptions: repo.CloneOptions, GitOverrides: repo.GitOverrides}, since)
 }
 
 // configuredLimiter returns a mutable limiter that is
@@ -253,6 +262,12 @@ func (s *updateScheduler) UpdateFromDiff(diff Diff) {
 		s.upsert(r, false)
 	}
 
+	// Quarantined repos are still searchable, so they stay scheduled.
+	for _, r := range diff.Quarantined {
+		known++
+		s.upsert(r, false)
+	}
+
 	schedKnownRepos.Set(float64(known))
 }
 
@@ -287,8 +302,34 @@ func configuredRepo2FromRepo(r *Repo) *configuredRepo2 {
 		Name: api.RepoName(r.Name),
 	}
 
-	if urls := r.CloneURLs(); len(urls) > 0 {
-		repo.URL = urls[0]
+	// Sources are visited in a stable order so that repos from several
+	// external services with different clone options aren't recloned with
+	// each update.
+	ids := make([]string, 0, len(r.Sources))
+	for id := range r.Sources {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		src := r.Sources[id]
+		if src == nil || src.CloneURL == "" {
+			continue
+		}
+		repo.URL = src.CloneURL
+		// Sources without clone options clone in full, which must be
+		// explicit so that gitserver reclones repos when the options
+		// are removed.
+		repo.CloneOptions = &gitserverprotocol.CloneOptions{}
+		if src.CloneOptions != nil {
+			*repo.CloneOptions = *src.CloneOptions
+		}
+		// Likewise for overrides, so that removing them takes effect.
+		repo.GitOverrides = &gitserverprotocol.GitOverrides{}
+		if src.GitOverrides != nil {
+			*repo.GitOverrides = *src.GitOverrides
+		}
+		break
 	}
 
 	return &repo
@@ -302,6 +343,14 @@ func (s *updateScheduler) UpdateOnce(id api.RepoID, name api.RepoName, url strin
 		Name: name,
 		URL:  url,
 	}
+
+	s.schedule.mu.Lock()
+	if update := s.schedule.index[id]; update != nil {
+		repo.CloneOptions = update.Repo.CloneOptions
+		repo.GitOverrides = update.Repo.GitOverrides
+	}
+	s.schedule.mu.Unlock()
+
 	schedManualFetch.Inc()
 	s.updateQueue.enqueue(repo, priorityHigh)
 }
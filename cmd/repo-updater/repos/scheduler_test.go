@@ -551,8 +551,14 @@ func verifyQueue(t *testing.T, s *updateScheduler, expected []*repoUpdate) {
 }
 
 func Test_updateScheduler_UpdateFromDiff(t *testing.T) {
-	a := &configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
-	b := &configuredRepo2{ID: 2, Name: "b", URL: "b.com"}
+	a := &configuredRepo2{ID: 1, Name: "a", URL: "a.com", CloneOptions: &gitserverprotocol.CloneOptions{}, GitOverrides: &gitserverprotocol.GitOverrides{}}
+	b := &configuredRepo2{
+		ID:           2,
+		Name:         "b",
+		URL:          "b.com",
+		CloneOptions: &gitserverprotocol.CloneOptions{Depth: 1},
+		GitOverrides: &gitserverprotocol.GitOverrides{Refspecs: []string{"+refs/pull/*:refs/pull/*"}},
+	}
 
 	tests := []struct {
 		name            string
@@ -593,7 +599,7 @@ func Test_updateScheduler_UpdateFromDiff(t *testing.T) {
 						ID:   b.ID,
 						Name: string(b.Name),
 						Sources: map[string]*SourceInfo{
-							string(b.Name): {CloneURL: b.URL},
+							string(b.Name): {CloneURL: b.URL, CloneOptions: b.CloneOptions, GitOverrides: b.GitOverrides},
 						},
 					},
 				},
@@ -626,7 +632,7 @@ func Test_updateScheduler_UpdateFromDiff(t *testing.T) {
 						ID:   b.ID,
 						Name: string(b.Name),
 						Sources: map[string]*SourceInfo{
-							string(b.Name): {CloneURL: b.URL},
+							string(b.Name): {CloneURL: b.URL, CloneOptions: b.CloneOptions, GitOverrides: b.GitOverrides},
 						},
 					},
 				},
@@ -61,6 +61,10 @@ func NewSource(svc *ExternalService, cf *httpcli.Factory) (Source, error) {
 		return NewBitbucketServerSource(svc, cf)
 	case "bitbucketcloud":
 		return NewBitbucketCloudSource(svc, cf)
+	case "gitea":
+		return NewGiteaSource(svc, cf)
+	case "gerrit":
+		return NewGerritSource(svc, cf)
 	case "gitolite":
 		return NewGitoliteSource(svc, cf)
 	case "phabricator":
@@ -87,6 +91,27 @@ type Source interface {
 	ExternalServices() ExternalServices
 }
 
+// An IncrementalSource is a Source that can list only the repos that changed
+// since a given time, which is much cheaper on large code hosts.
+type IncrementalSource interface {
+	Source
+	// ListReposSince sends at least all the repos that changed since the
+	// given time over the passed in channel as SourceResults. Since it can't
+	// know about deleted repos, the absence of a repo doesn't mean anything.
+	ListReposSince(ctx context.Context, since time.Time, results chan SourceResult)
+}
+
+// listReposSince is a Source whose ListRepos method only lists the repos
+// of an IncrementalSource that changed since a given time.
+type listReposSince struct {
+	IncrementalSource
+	since time.Time
+}
+
+func (s listReposSince) ListRepos(ctx context.Context, results chan SourceResult) {
+	s.ListReposSince(ctx, s.since, results)
+}
+
 // A ChangesetSource can load the latest state of a list of Changesets.
 type ChangesetSource interface {
 	// LoadChangesets loads the given Changesets from the sources and updates
@@ -708,6 +708,19 @@ func TestSources_ListRepos(t *testing.T) {
 					if !reflect.DeepEqual(got, want) {
 						t.Error("mismatch archived state (-want +got):\n", cmp.Diff(want, got))
 					}
+
+					wantTopics := map[string][]string{
+						"vegeta":        nil,
+						"archived-repo": {"archived"},
+					}
+					gotTopics := map[string][]string{}
+					for _, r := range rs {
+						gotTopics[r.Name] = r.Topics
+					}
+
+					if !reflect.DeepEqual(gotTopics, wantTopics) {
+						t.Error("mismatch topics (-want +got):\n", cmp.Diff(wantTopics, gotTopics))
+					}
 				}
 			},
 			err: "<nil>",
@@ -9,12 +9,15 @@ import (
 	"time"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
@@ -24,6 +27,7 @@ import (
 type Store interface {
 	ListExternalServices(context.Context, StoreListExternalServicesArgs) ([]*ExternalService, error)
 	UpsertExternalServices(ctx context.Context, svcs ...*ExternalService) error
+	UpdateExternalServiceSyncTimes(ctx context.Context, svcs ...*ExternalService) error
 
 	ListRepos(context.Context, StoreListReposArgs) ([]*Repo, error)
 	UpsertRepos(ctx context.Context, repos ...*Repo) error
@@ -42,6 +46,8 @@ type StoreListReposArgs struct {
 	Kinds []string
 	// ExternalRepos of repos to list. When zero-valued, this is omitted from the predicate set.
 	ExternalRepos []api.ExternalRepoSpec
+	// PendingDeletion if true lists only repos that are pending deletion. When false, this is omitted from the predicate set.
+	PendingDeletion bool
 	// Limit the total number of repos returned. Zero means no limit
 	Limit int64
 	// PerPage determines the number of repos returned on each page. Zero means it defaults to 10000.
@@ -162,7 +168,9 @@ SELECT
   config,
   created_at,
   updated_at,
-  deleted_at
+  deleted_at,
+  last_sync_at,
+  last_full_sync_at
 FROM external_services
 WHERE id > %s
 AND %s
@@ -296,6 +304,48 @@ SET
 RETURNING *
 `
 
+// UpdateExternalServiceSyncTimes updates the LastSyncAt and LastFullSyncAt
+// fields of the given ExternalServices. Their other fields aren't written, so
+// that concurrent changes to their configuration aren't overwritten.
+func (s DBStore) UpdateExternalServiceSyncTimes(ctx context.Context, svcs ...*ExternalService) error {
+	if len(svcs) == 0 {
+		return nil
+	}
+
+	vals := make([]*sqlf.Query, 0, len(svcs))
+	for _, svc := range svcs {
+		vals = append(vals, sqlf.Sprintf(
+			updateExternalServiceSyncTimesQueryValueFmtstr,
+			svc.ID,
+			nullTimeColumn(svc.LastSyncAt.UTC()),
+			nullTimeColumn(svc.LastFullSyncAt.UTC()),
+		))
+	}
+
+	q := sqlf.Sprintf(updateExternalServiceSyncTimesQueryFmtstr, sqlf.Join(vals, ",\n"))
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = scanAll(rows, func(scanner) (last, count int64, err error) { return 0, 0, nil })
+	return err
+}
+
+const updateExternalServiceSyncTimesQueryValueFmtstr = `
+  (%s::bigint, %s::timestamptz, %s::timestamptz)
+`
+
+const updateExternalServiceSyncTimesQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.UpdateExternalServiceSyncTimes
+UPDATE external_services AS e
+SET
+  last_sync_at      = v.last_sync_at,
+  last_full_sync_at = v.last_full_sync_at
+FROM (VALUES %s) AS v(id, last_sync_at, last_full_sync_at)
+WHERE e.id = v.id
+`
+
 // ListRepos lists all stored repos that match the given arguments.
 func (s DBStore) ListRepos(ctx context.Context, args StoreListReposArgs) (repos []*Repo, _ error) {
 	return repos, s.paginate(ctx, args.Limit, args.PerPage, listReposQuery(args),
@@ -327,6 +377,11 @@ SELECT
   archived,
   fork,
   private,
+  topics,
+  stars,
+  default_branch,
+  pending_deletion_at,
+  missing_syncs,
   sources,
   metadata
 FROM repo
@@ -374,6 +429,10 @@ func listReposQuery(args StoreListReposArgs) paginatedQuery {
 		preds = append(preds, sqlf.Sprintf("(%s)", sqlf.Join(er, "\n OR ")))
 	}
 
+	if args.PendingDeletion {
+		preds = append(preds, sqlf.Sprintf("pending_deletion_at IS NOT NULL"))
+	}
+
 	if len(preds) == 0 {
 		preds = append(preds, sqlf.Sprintf("TRUE"))
 	}
@@ -569,6 +628,11 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 		Archived            bool            `json:"archived"`
 		Fork                bool            `json:"fork"`
 		Private             bool            `json:"private"`
+		Topics              []string        `json:"topics"`
+		Stars               int             `json:"stars"`
+		DefaultBranch       *string         `json:"default_branch,omitempty"`
+		PendingDeletionAt   *time.Time      `json:"pending_deletion_at,omitempty"`
+		MissingSyncs        int             `json:"missing_syncs"`
 		Sources             json.RawMessage `json:"sources"`
 		Metadata            json.RawMessage `json:"metadata"`
 	}
@@ -585,6 +649,11 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 			return nil, errors.Wrapf(err, "batchReposQuery: metadata marshalling failed")
 		}
 
+		topics := r.Topics
+		if topics == nil {
+			topics = []string{}
+		}
+
 		records = append(records, record{
 			ID:                  r.ID,
 			Name:                r.Name,
@@ -600,6 +669,11 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 			Archived:            r.Archived,
 			Fork:                r.Fork,
 			Private:             r.Private,
+			Topics:              topics,
+			Stars:               r.Stars,
+			DefaultBranch:       nullStringColumn(r.DefaultBranch),
+			PendingDeletionAt:   nullTimeColumn(r.PendingDeletionAt.UTC()),
+			MissingSyncs:        r.MissingSyncs,
 			Sources:             sources,
 			Metadata:            metadata,
 		})
@@ -644,6 +718,11 @@ WITH batch AS (
       archived              boolean,
       fork                  boolean,
       private               boolean,
+      topics                jsonb,
+      stars                 integer,
+      default_branch        text,
+      pending_deletion_at   timestamptz,
+      missing_syncs         integer,
       sources               jsonb,
       metadata              jsonb
     )
@@ -667,6 +746,11 @@ SET
   archived              = batch.archived,
   fork                  = batch.fork,
   private               = batch.private,
+  topics                = ARRAY(SELECT jsonb_array_elements_text(batch.topics)),
+  stars                 = batch.stars,
+  default_branch        = batch.default_branch,
+  pending_deletion_at   = batch.pending_deletion_at,
+  missing_syncs         = batch.missing_syncs,
   sources               = batch.sources,
   metadata              = batch.metadata
 FROM batch
@@ -704,6 +788,11 @@ INSERT INTO repo (
   archived,
   fork,
   private,
+  topics,
+  stars,
+  default_branch,
+  pending_deletion_at,
+  missing_syncs,
   sources,
   metadata
 )
@@ -721,6 +810,11 @@ SELECT
   archived,
   fork,
   private,
+  ARRAY(SELECT jsonb_array_elements_text(topics)),
+  stars,
+  default_branch,
+  pending_deletion_at,
+  missing_syncs,
   sources,
   metadata
 FROM batch
@@ -802,6 +896,8 @@ func scanExternalService(svc *ExternalService, s scanner) error {
 		&svc.CreatedAt,
 		&dbutil.NullTime{Time: &svc.UpdatedAt},
 		&dbutil.NullTime{Time: &svc.DeletedAt},
+		&dbutil.NullTime{Time: &svc.LastSyncAt},
+		&dbutil.NullTime{Time: &svc.LastFullSyncAt},
 	)
 }
 
@@ -822,6 +918,11 @@ func scanRepo(r *Repo, s scanner) error {
 		&r.Archived,
 		&r.Fork,
 		&r.Private,
+		pq.Array(&r.Topics),
+		&r.Stars,
+		&dbutil.NullString{S: &r.DefaultBranch},
+		&dbutil.NullTime{Time: &r.PendingDeletionAt},
+		&r.MissingSyncs,
 		&sources,
 		&metadata,
 	)
@@ -829,6 +930,10 @@ func scanRepo(r *Repo, s scanner) error {
 		return err
 	}
 
+	if len(r.Topics) == 0 {
+		r.Topics = nil
+	}
+
 	if err = json.Unmarshal(sources, &r.Sources); err != nil {
 		return errors.Wrap(err, "scanRepo: failed to unmarshal sources")
 	}
@@ -845,6 +950,10 @@ func scanRepo(r *Repo, s scanner) error {
 		r.Metadata = new(bitbucketcloud.Repo)
 	case "awscodecommit":
 		r.Metadata = new(awscodecommit.Repository)
+	case "gerrit":
+		r.Metadata = new(gerrit.Project)
+	case "gitea":
+		r.Metadata = new(gitea.Repo)
 	case "gitolite":
 		r.Metadata = new(gitolite.Repo)
 	default:
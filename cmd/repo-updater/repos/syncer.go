@@ -41,6 +41,18 @@ type Syncer struct {
 	// Now is time.Now. Can be set by tests to get deterministic output.
 	Now func() time.Time
 
+	// FullSyncInterval returns how often all repos of external services
+	// whose Sources support incremental listing are listed, which is needed
+	// to detect deleted repos. In between, Sync only lists the repos that
+	// changed since the previous one. It's called on every Sync. If nil or
+	// zero, all repos are listed on every Sync.
+	FullSyncInterval func() time.Duration
+
+	// DeletionSafeguard returns the limits on how many repos Sync may delete
+	// at once. The repos above its thresholds are quarantined instead. It's
+	// called on every Sync. If nil, repos are never quarantined.
+	DeletionSafeguard func() DeletionSafeguard
+
 	// lastSyncErr contains the last error returned by the Sourcer in each
 	// Sync. It's reset with each Sync and if the sync produced no error, it's
 	// set to nil.
@@ -119,8 +131,16 @@ func (s *Syncer) Sync(ctx context.Context) (err error) {
 		}
 	}
 
+	var svcs []*ExternalService
+	if svcs, err = s.Store.ListExternalServices(ctx, StoreListExternalServicesArgs{}); err != nil {
+		return errors.Wrap(err, "syncer.sync.store.list-external-services")
+	}
+
+	began := s.Now()
+	since := s.incrementalSyncs(svcs, began)
+
 	var sourced Repos
-	if sourced, err = s.sourced(ctx, streamingInserter); err != nil {
+	if sourced, err = s.sourced(ctx, svcs, since, streamingInserter); err != nil {
 		return errors.Wrap(err, "syncer.sync.sourced")
 	}
 
@@ -139,13 +159,40 @@ func (s *Syncer) Sync(ctx context.Context) (err error) {
 		return errors.Wrap(err, "syncer.sync.store.list-repos")
 	}
 
-	diff = NewDiff(sourced, stored)
+	incremental := make(map[string]bool, len(since))
+	for urn := range since {
+		incremental[urn] = true
+	}
+
+	// Taken before NewDiff, which sorts the carried over repos in with sourced.
+	found := externalRepoSet(sourced)
+
+	diff = NewDiff(carryOver(sourced, stored, incremental), stored)
+	unquarantine(&diff, found)
+	s.quarantine(&diff, stored, s.deletionSafeguard())
+	if len(diff.Quarantined) > 0 && s.Logger != nil {
+		s.Logger.Warn("syncer.quarantine", "quarantined", len(diff.Quarantined), "deleted", len(diff.Deleted), "stored", len(stored))
+	}
 	upserts := s.upserts(diff)
 
 	if err = store.UpsertRepos(ctx, upserts...); err != nil {
 		return errors.Wrap(err, "syncer.sync.store.upsert-repos")
 	}
 
+	synced := make([]*ExternalService, 0, len(svcs))
+	for _, svc := range svcs {
+		svc = svc.Clone()
+		svc.LastSyncAt = began
+		if _, ok := since[svc.URN()]; !ok {
+			svc.LastFullSyncAt = began
+		}
+		synced = append(synced, svc)
+	}
+
+	if err = store.UpdateExternalServiceSyncTimes(ctx, synced...); err != nil {
+		return errors.Wrap(err, "syncer.sync.store.update-external-service-sync-times")
+	}
+
 	if s.Synced != nil {
 		s.Synced <- diff
 	}
@@ -153,6 +200,63 @@ func (s *Syncer) Sync(ctx context.Context) (err error) {
 	return nil
 }
 
+// DryRun returns the Diff that syncing the given external service would
+// apply to the stored repos, without storing anything. svc is a proposed
+// configuration of an existing external service, or a new one with a zero
+// ID. The repos of other external services stay as they are. Deletions are
+// quarantined by the DeletionSafeguard like in Sync. renamed maps the IDs of
+// the modified repos whose name would change to their current name.
+func (s *Syncer) DryRun(ctx context.Context, svc *ExternalService) (diff Diff, renamed map[api.RepoID]string, err error) {
+	srcs, err := s.Sourcer(svc)
+	if err != nil {
+		return Diff{}, nil, errors.Wrap(err, "syncer.dry-run.sourcer")
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, sourceTimeout)
+	defer cancel()
+
+	sourced, err := listAll(listCtx, srcs)
+	if err != nil {
+		return Diff{}, nil, errors.Wrap(err, "syncer.dry-run.sourced")
+	}
+
+	stored, err := s.Store.ListRepos(ctx, StoreListReposArgs{})
+	if err != nil {
+		return Diff{}, nil, errors.Wrap(err, "syncer.dry-run.store.list-repos")
+	}
+
+	// NewDiff updates the stored repos in place.
+	stored = Repos(stored).Clone()
+
+	names := make(map[api.RepoID]string, len(stored))
+	others := make(map[string]bool)
+	for _, r := range stored {
+		names[r.ID] = r.Name
+		for urn := range r.Sources {
+			if urn != svc.URN() {
+				others[urn] = true
+			}
+		}
+	}
+
+	// Taken before NewDiff, which sorts the carried over repos in with sourced.
+	found := externalRepoSet(sourced)
+
+	diff = NewDiff(carryOver(sourced, stored, others), stored)
+	unquarantine(&diff, found)
+	s.quarantine(&diff, stored, s.deletionSafeguard())
+	diff.Sort()
+
+	renamed = make(map[api.RepoID]string)
+	for _, r := range diff.Modified {
+		if name := names[r.ID]; name != r.Name {
+			renamed[r.ID] = name
+		}
+	}
+
+	return diff, renamed, nil
+}
+
 // SyncSubset runs the syncer on a subset of the stored repositories. It will
 // only sync the repositories with the same name or external service spec as
 // sourcedSubset repositories.
@@ -212,6 +316,7 @@ func (s *Syncer) syncSubset(ctx context.Context, insertOnly bool, sourcedSubset
 	}
 
 	diff = NewDiff(sourcedSubset, storedSubset)
+	unquarantine(&diff, externalRepoSet(sourcedSubset))
 	upserts := s.upserts(diff)
 
 	if err = store.UpsertRepos(ctx, upserts...); err != nil {
@@ -227,7 +332,7 @@ func (s *Syncer) syncSubset(ctx context.Context, insertOnly bool, sourcedSubset
 
 func (s *Syncer) upserts(diff Diff) []*Repo {
 	now := s.Now()
-	upserts := make([]*Repo, 0, len(diff.Added)+len(diff.Deleted)+len(diff.Modified))
+	upserts := make([]*Repo, 0, len(diff.Added)+len(diff.Deleted)+len(diff.Modified)+len(diff.Quarantined))
 
 	for _, repo := range diff.Deleted {
 		repo.UpdatedAt, repo.DeletedAt = now, now
@@ -240,6 +345,11 @@ func (s *Syncer) upserts(diff Diff) []*Repo {
 		upserts = append(upserts, repo)
 	}
 
+	for _, repo := range diff.Quarantined {
+		repo.UpdatedAt = now
+		upserts = append(upserts, repo)
+	}
+
 	for _, repo := range diff.Added {
 		repo.CreatedAt, repo.UpdatedAt, repo.DeletedAt = now, now, time.Time{}
 		upserts = append(upserts, repo)
@@ -254,6 +364,10 @@ type Diff struct {
 	Deleted    Repos
 	Modified   Repos
 	Unmodified Repos
+
+	// Quarantined are the repos that would have been deleted, but are
+	// pending deletion instead because of the Syncer's DeletionSafeguard.
+	Quarantined Repos
 }
 
 // Sort sorts all Diff elements by Repo.IDs.
@@ -263,6 +377,7 @@ func (d *Diff) Sort() {
 		d.Deleted,
 		d.Modified,
 		d.Unmodified,
+		d.Quarantined,
 	} {
 		sort.Sort(ds)
 	}
@@ -273,13 +388,15 @@ func (d Diff) Repos() Repos {
 	all := make(Repos, 0, len(d.Added)+
 		len(d.Deleted)+
 		len(d.Modified)+
-		len(d.Unmodified))
+		len(d.Unmodified)+
+		len(d.Quarantined))
 
 	for _, rs := range []Repos{
 		d.Added,
 		d.Deleted,
 		d.Modified,
 		d.Unmodified,
+		d.Quarantined,
 	} {
 		all = append(all, rs...)
 	}
@@ -351,17 +468,293 @@ func merge(o, n *Repo) {
 	o.Update(n)
 }
 
-func (s *Syncer) sourced(ctx context.Context, observe ...func(*Repo)) ([]*Repo, error) {
-	svcs, err := s.Store.ListExternalServices(ctx, StoreListExternalServicesArgs{})
-	if err != nil {
-		return nil, err
+// DeletionSafeguard limits how many repos a Sync may delete at once, e.g.
+// because a code host returned an incomplete list during an outage.
+type DeletionSafeguard struct {
+	// MaxDeletions is the number of repos a Sync may delete. Zero means no
+	// limit.
+	MaxDeletions int
+	// MaxDeletionPercentage is the percentage of the stored repos of an
+	// external service a Sync may delete. Zero means no limit.
+	MaxDeletionPercentage float64
+	// ConsistentSyncs is the number of consecutive Syncs that must not find
+	// a quarantined repo before it's deleted. Zero means quarantined repos
+	// are only deleted by DeletePendingRepos.
+	ConsistentSyncs int
+}
+
+// Quarantined returns the repos of the given deleted repos which exceed the
+// safeguard's thresholds: all of them if there are more than MaxDeletions,
+// and otherwise the ones of the external services that would lose more than
+// MaxDeletionPercentage of their stored repos. An external service's
+// deletions are thus not diluted by the repos of other external services.
+func (g DeletionSafeguard) Quarantined(deleted, stored Repos) Repos {
+	if len(deleted) == 0 {
+		return nil
+	}
+
+	if g.MaxDeletions > 0 && len(deleted) > g.MaxDeletions {
+		return deleted
+	}
+
+	if g.MaxDeletionPercentage <= 0 {
+		return nil
+	}
+
+	storedByURN := countBySource(stored)
+	exceeded := make(map[string]bool)
+	for urn, n := range countBySource(deleted) {
+		if float64(n)*100/float64(storedByURN[urn]) > g.MaxDeletionPercentage {
+			exceeded[urn] = true
+		}
+	}
+
+	var quarantined Repos
+	for _, r := range deleted {
+		for urn := range r.Sources {
+			if exceeded[urn] {
+				quarantined = append(quarantined, r)
+				break
+			}
+		}
+	}
+	return quarantined
+}
+
+// countBySource returns the number of the given repos of each external
+// service, keyed by their URNs.
+func countBySource(rs Repos) map[string]int {
+	counts := make(map[string]int)
+	for _, r := range rs {
+		for urn := range r.Sources {
+			counts[urn]++
+		}
+	}
+	return counts
+}
+
+// quarantine marks the repos the diff would delete as pending deletion,
+// moving them to diff.Quarantined, if the DeletionSafeguard g quarantines
+// them given the stored repos. Repos that are already pending deletion stay
+// quarantined even if the remaining deletions are below the thresholds, until
+// they were missing for ConsistentSyncs syncs and are deleted after all.
+func (s *Syncer) quarantine(diff *Diff, stored Repos, g DeletionSafeguard) {
+	quarantined := g.Quarantined(diff.Deleted, stored)
+	set := make(map[*Repo]bool, len(diff.Deleted))
+	for _, r := range quarantined {
+		set[r] = true
+	}
+	for _, r := range diff.Deleted {
+		if !r.PendingDeletionAt.IsZero() {
+			set[r] = true
+		}
 	}
 
+	if len(set) == 0 {
+		return
+	}
+
+	now := s.Now()
+	deleted := diff.Deleted[:0]
+
+	for _, r := range diff.Deleted {
+		if !set[r] {
+			deleted = append(deleted, r)
+			continue
+		}
+
+		if r.PendingDeletionAt.IsZero() {
+			r.PendingDeletionAt = now
+		}
+		r.MissingSyncs++
+
+		if n := g.ConsistentSyncs; n > 0 && r.MissingSyncs >= n {
+			deleted = append(deleted, r)
+		} else {
+			diff.Quarantined = append(diff.Quarantined, r)
+		}
+	}
+
+	diff.Deleted = deleted
+}
+
+// deletionSafeguard returns the current DeletionSafeguard of the Syncer.
+func (s *Syncer) deletionSafeguard() DeletionSafeguard {
+	if s.DeletionSafeguard == nil {
+		return DeletionSafeguard{}
+	}
+	return s.DeletionSafeguard()
+}
+
+// unquarantine clears the pending deletion of the repos in the diff that were
+// found again, moving the unmodified ones to diff.Modified so they're stored.
+// Repos whose sources were only carried over stay pending deletion.
+func unquarantine(diff *Diff, found map[api.ExternalRepoSpec]bool) {
+	for _, r := range diff.Modified {
+		if found[r.ExternalRepo] {
+			r.PendingDeletionAt, r.MissingSyncs = time.Time{}, 0
+		}
+	}
+
+	unmodified := diff.Unmodified[:0]
+	for _, r := range diff.Unmodified {
+		if r.PendingDeletionAt.IsZero() || !found[r.ExternalRepo] {
+			unmodified = append(unmodified, r)
+			continue
+		}
+
+		r.PendingDeletionAt, r.MissingSyncs = time.Time{}, 0
+		diff.Modified = append(diff.Modified, r)
+	}
+
+	diff.Unmodified = unmodified
+}
+
+func externalRepoSet(rs Repos) map[api.ExternalRepoSpec]bool {
+	set := make(map[api.ExternalRepoSpec]bool, len(rs))
+	for _, r := range rs {
+		set[r.ExternalRepo] = true
+	}
+	return set
+}
+
+// DeletePendingRepos deletes all stored repos that are pending deletion,
+// which confirms the deletions quarantined by the DeletionSafeguard.
+func (s *Syncer) DeletePendingRepos(ctx context.Context) (diff Diff, err error) {
+	ctx, save := s.observe(ctx, "Syncer.DeletePendingRepos", "")
+	defer save(&diff, &err)
+
+	store := s.Store
+	if tr, ok := s.Store.(Transactor); ok {
+		var txs TxStore
+		if txs, err = tr.Transact(ctx); err != nil {
+			return Diff{}, errors.Wrap(err, "syncer.delete-pending-repos.transact")
+		}
+		defer txs.Done(&err)
+		store = txs
+	}
+
+	if diff.Deleted, err = store.ListRepos(ctx, StoreListReposArgs{PendingDeletion: true}); err != nil {
+		return Diff{}, errors.Wrap(err, "syncer.delete-pending-repos.store.list-repos")
+	}
+
+	if len(diff.Deleted) == 0 {
+		return diff, nil
+	}
+
+	if err = store.UpsertRepos(ctx, s.upserts(diff)...); err != nil {
+		return Diff{}, errors.Wrap(err, "syncer.delete-pending-repos.store.upsert-repos")
+	}
+
+	if s.SubsetSynced != nil {
+		s.SubsetSynced <- diff
+	}
+
+	return diff, nil
+}
+
+// incrementalSyncOverlap is how long before the previous sync incremental
+// listings start, to tolerate clock skew between Sourcegraph and code hosts.
+const incrementalSyncOverlap = 5 * time.Minute
+
+// incrementalSyncs returns the times since which the repos of the given
+// external services can be listed incrementally, keyed by their URNs.
+// External services that must be listed in full aren't included.
+func (s *Syncer) incrementalSyncs(svcs []*ExternalService, now time.Time) map[string]time.Time {
+	since := make(map[string]time.Time, len(svcs))
+	if s.FullSyncInterval == nil {
+		return since
+	}
+	interval := s.FullSyncInterval()
+	if interval <= 0 {
+		return since
+	}
+
+	for _, svc := range svcs {
+		switch {
+		case svc.LastSyncAt.IsZero() || svc.LastFullSyncAt.IsZero():
+			// Never synced before.
+		case svc.UpdatedAt.After(svc.LastFullSyncAt):
+			// The config changed since, so it might select other repos.
+		case now.Sub(svc.LastFullSyncAt) >= interval:
+			// Due for a full sync.
+		default:
+			since[svc.URN()] = svc.LastSyncAt.Add(-incrementalSyncOverlap)
+		}
+	}
+
+	return since
+}
+
+// carryOver returns the given sourced repos together with the sources of the
+// stored repos which belong to the given external services, which weren't
+// listed. Those sources stay as they are, e.g. because their external service
+// was listed incrementally and didn't list the unchanged repos again.
+func carryOver(sourced, stored Repos, urns map[string]bool) Repos {
+	if len(urns) == 0 {
+		return sourced
+	}
+
+	byID := make(map[api.ExternalRepoSpec]*Repo, len(sourced))
+	for _, r := range sourced {
+		byID[r.ExternalRepo] = r
+	}
+
+	for _, old := range stored {
+		for urn, info := range old.Sources {
+			if !urns[urn] {
+				continue
+			}
+
+			r := byID[old.ExternalRepo]
+			if r == nil {
+				r = old.With(func(r *Repo) { r.Sources = map[string]*SourceInfo{} })
+				byID[r.ExternalRepo] = r
+				sourced = append(sourced, r)
+			}
+
+			if _, ok := r.Sources[urn]; !ok {
+				r.Sources[urn] = info
+			}
+		}
+	}
+
+	return sourced
+}
+
+// sourced lists the repos of the given external services. The ones in since
+// are listed incrementally if their Sources support it, otherwise they're
+// removed from it.
+func (s *Syncer) sourced(ctx context.Context, svcs []*ExternalService, since map[string]time.Time, observe ...func(*Repo)) ([]*Repo, error) {
 	srcs, err := s.Sourcer(svcs...)
 	if err != nil {
 		return nil, err
 	}
 
+	// Wrap a copy so the Sourcer's own Sources are left untouched.
+	srcs = append(Sources(nil), srcs...)
+
+	incremental := make(map[string]bool, len(since))
+	for i, src := range srcs {
+		for _, svc := range src.ExternalServices() {
+			t, ok := since[svc.URN()]
+			if !ok {
+				continue
+			}
+
+			if is, ok := src.(IncrementalSource); ok {
+				srcs[i] = listReposSince{IncrementalSource: is, since: t}
+				incremental[svc.URN()] = true
+			}
+		}
+	}
+
+	for urn := range since {
+		if !incremental[urn] {
+			delete(since, urn)
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, sourceTimeout)
 	defer cancel()
 
@@ -433,12 +826,13 @@ func (s *Syncer) observe(ctx context.Context, family, title string) (context.Con
 		now := s.Now()
 		took := s.Now().Sub(began).Seconds()
 
-		fields := make([]otlog.Field, 0, 7)
+		fields := make([]otlog.Field, 0, 9)
 		for state, repos := range map[string]Repos{
-			"added":      d.Added,
-			"modified":   d.Modified,
-			"deleted":    d.Deleted,
-			"unmodified": d.Unmodified,
+			"added":       d.Added,
+			"modified":    d.Modified,
+			"deleted":     d.Deleted,
+			"unmodified":  d.Unmodified,
+			"quarantined": d.Quarantined,
 		} {
 			fields = append(fields, otlog.Int(state+".count", len(repos)))
 			if state != "unmodified" {
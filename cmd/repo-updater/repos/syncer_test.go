@@ -707,6 +707,439 @@ func testSyncSubset(s repos.Store) func(*testing.T) {
 	}
 }
 
+// fakeIncrementalSource is a FakeSource that lists only the changed repos
+// when asked to list incrementally, recording the since cursors it was
+// called with.
+type fakeIncrementalSource struct {
+	*repos.FakeSource
+	changed []*repos.Repo
+	since   []time.Time
+}
+
+func (s *fakeIncrementalSource) ListReposSince(ctx context.Context, since time.Time, results chan repos.SourceResult) {
+	s.since = append(s.since, since)
+	for _, r := range s.changed {
+		results <- repos.SourceResult{Source: s, Repo: r.Clone()}
+	}
+}
+
+func TestSyncer_SyncIncremental(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := repos.NewFakeClock(time.Now(), time.Second)
+
+	store := new(repos.FakeStore)
+	svc := &repos.ExternalService{Kind: "GITHUB", DisplayName: "Github", Config: "{}"}
+	if err := store.UpsertExternalServices(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+
+	newRepo := func(name string) *repos.Repo {
+		return (&repos.Repo{
+			Name:     "github.com/org/" + name,
+			Metadata: &github.Repository{},
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          name,
+				ServiceID:   "https://github.com/",
+				ServiceType: "github",
+			},
+		}).With(repos.Opt.RepoSources(svc.URN()))
+	}
+
+	foo, bar := newRepo("foo"), newRepo("bar")
+	src := &fakeIncrementalSource{
+		FakeSource: repos.NewFakeSource(svc, nil, foo, bar),
+	}
+
+	fullSyncInterval := time.Hour
+	syncer := &repos.Syncer{
+		Store:            store,
+		Sourcer:          repos.NewFakeSourcer(nil, src),
+		Now:              clock.Now,
+		FullSyncInterval: func() time.Duration { return fullSyncInterval },
+	}
+
+	sync := func() (map[string]string, *repos.ExternalService) {
+		t.Helper()
+
+		if err := syncer.Sync(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		rs, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		have := make(map[string]string, len(rs))
+		for _, r := range rs {
+			have[r.Name] = r.Description
+		}
+
+		svcs, err := store.ListExternalServices(ctx, repos.StoreListExternalServicesArgs{IDs: []int64{svc.ID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return have, svcs[0].Clone()
+	}
+
+	// The first sync of a service is always a full one.
+	have, first := sync()
+	if want := map[string]string{foo.Name: "", bar.Name: ""}; !cmp.Equal(have, want) {
+		t.Fatalf("first sync: %s", cmp.Diff(have, want))
+	}
+
+	if first.LastSyncAt.IsZero() || !first.LastFullSyncAt.Equal(first.LastSyncAt) {
+		t.Fatalf("first sync: have sync times (%s, %s), want both set and equal",
+			first.LastSyncAt, first.LastFullSyncAt)
+	}
+
+	if len(src.since) != 0 {
+		t.Fatalf("first sync: listed incrementally since %v", src.since)
+	}
+
+	// Within the full sync interval only changed repos are listed, and the
+	// repos that weren't relisted are kept.
+	changed := foo.With(func(r *repos.Repo) { r.Description = "changed" })
+	src.FakeSource = repos.NewFakeSource(svc, nil, changed)
+	src.changed = []*repos.Repo{changed}
+
+	have, second := sync()
+	if want := map[string]string{foo.Name: "changed", bar.Name: ""}; !cmp.Equal(have, want) {
+		t.Fatalf("incremental sync: %s", cmp.Diff(have, want))
+	}
+
+	if want := []time.Time{first.LastSyncAt.Add(-5 * time.Minute)}; !cmp.Equal(src.since, want) {
+		t.Fatalf("incremental sync: %s", cmp.Diff(src.since, want))
+	}
+
+	if !second.LastSyncAt.After(first.LastSyncAt) || !second.LastFullSyncAt.Equal(first.LastFullSyncAt) {
+		t.Fatalf("incremental sync: have sync times (%s, %s), want only last sync advanced",
+			second.LastSyncAt, second.LastFullSyncAt)
+	}
+
+	// Once the full sync interval elapses, repos missing from the full
+	// listing are deleted. Changes to the interval apply to the next Sync.
+	fullSyncInterval = time.Nanosecond
+
+	have, third := sync()
+	if want := map[string]string{foo.Name: "changed"}; !cmp.Equal(have, want) {
+		t.Fatalf("full sync: %s", cmp.Diff(have, want))
+	}
+
+	if len(src.since) != 1 {
+		t.Fatalf("full sync: listed incrementally since %v", src.since[1:])
+	}
+
+	if !third.LastFullSyncAt.Equal(third.LastSyncAt) {
+		t.Fatalf("full sync: have sync times (%s, %s), want both equal",
+			third.LastSyncAt, third.LastFullSyncAt)
+	}
+}
+
+func TestSyncer_DryRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	store := new(repos.FakeStore)
+	svc := &repos.ExternalService{Kind: "GITHUB", DisplayName: "Github", Config: "{}"}
+	other := &repos.ExternalService{Kind: "GITHUB", DisplayName: "Github other", Config: "{}"}
+	if err := store.UpsertExternalServices(ctx, svc, other); err != nil {
+		t.Fatal(err)
+	}
+
+	newRepo := func(name, id string, svcs ...*repos.ExternalService) *repos.Repo {
+		urns := make([]string, 0, len(svcs))
+		for _, svc := range svcs {
+			urns = append(urns, svc.URN())
+		}
+		return (&repos.Repo{
+			Name:     "github.com/org/" + name,
+			Metadata: &github.Repository{},
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          id,
+				ServiceID:   "https://github.com/",
+				ServiceType: "github",
+			},
+		}).With(repos.Opt.RepoSources(urns...))
+	}
+
+	stored := repos.Repos{
+		newRepo("foo", "foo", svc),
+		newRepo("bar", "bar", svc),
+		newRepo("before", "renamed", svc),
+		newRepo("shared", "shared", svc, other),
+		newRepo("baz", "baz", other),
+	}
+	if err := store.UpsertRepos(ctx, stored.Clone()...); err != nil {
+		t.Fatal(err)
+	}
+
+	// The proposed configuration of svc no longer yields bar and shared.
+	syncer := &repos.Syncer{
+		Store: store,
+		Sourcer: repos.NewFakeSourcer(nil, repos.NewFakeSource(svc, nil,
+			newRepo("foo", "foo", svc),
+			newRepo("after", "renamed", svc),
+			newRepo("new", "new", svc),
+		)),
+	}
+
+	diff, renamed, err := syncer.DryRun(ctx, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		have []string
+		want []string
+	}{
+		{"added", diff.Added.Names(), []string{"github.com/org/new"}},
+		{"deleted", diff.Deleted.Names(), []string{"github.com/org/bar"}},
+		{"modified", diff.Modified.Names(), []string{"github.com/org/after", "github.com/org/shared"}},
+	} {
+		if !cmp.Equal(tc.have, tc.want) {
+			t.Errorf("%s: %s", tc.name, cmp.Diff(tc.have, tc.want))
+		}
+	}
+
+	haveRenamed := make(map[string]string, len(renamed))
+	for _, r := range diff.Modified {
+		if from, ok := renamed[r.ID]; ok {
+			haveRenamed[from] = r.Name
+		}
+	}
+	if want := map[string]string{"github.com/org/before": "github.com/org/after"}; !cmp.Equal(haveRenamed, want) {
+		t.Errorf("renamed: %s", cmp.Diff(haveRenamed, want))
+	}
+
+	// Nothing is stored.
+	rs, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, want := repos.Repos(rs).Names(), stored.Names()
+	sort.Strings(have)
+	sort.Strings(want)
+	if !cmp.Equal(have, want) {
+		t.Errorf("stored repos: %s", cmp.Diff(have, want))
+	}
+
+	// Deletions above the DeletionSafeguard are quarantined, like in Sync.
+	now := time.Now()
+	syncer.Now = func() time.Time { return now }
+	syncer.DeletionSafeguard = func() repos.DeletionSafeguard {
+		return repos.DeletionSafeguard{MaxDeletionPercentage: 10}
+	}
+
+	diff, _, err = syncer.DryRun(ctx, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := diff.Deleted.Names(); len(have) != 0 {
+		t.Errorf("deleted: %v, want none", have)
+	}
+	if have, want := diff.Quarantined.Names(), []string{"github.com/org/bar"}; !cmp.Equal(have, want) {
+		t.Errorf("quarantined: %s", cmp.Diff(have, want))
+	}
+}
+
+func TestDeletionSafeguard_Quarantined(t *testing.T) {
+	t.Parallel()
+
+	// a has 10 stored repos and b has 100, of which the first ones are
+	// deleted.
+	a := &repos.ExternalService{ID: 1, Kind: "GITHUB"}
+	b := &repos.ExternalService{ID: 2, Kind: "GITHUB"}
+
+	newRepos := func(svc *repos.ExternalService, n int) repos.Repos {
+		rs := make(repos.Repos, 0, n)
+		for i := 0; i < n; i++ {
+			rs = append(rs, (&repos.Repo{
+				Name: fmt.Sprintf("github.com/%d/%d", svc.ID, i),
+			}).With(repos.Opt.RepoSources(svc.URN())))
+		}
+		return rs
+	}
+
+	storedA, storedB := newRepos(a, 10), newRepos(b, 100)
+	stored := append(storedA.Clone(), storedB.Clone()...)
+
+	for _, tc := range []struct {
+		name        string
+		safeguard   repos.DeletionSafeguard
+		deleted     repos.Repos
+		quarantined repos.Repos
+	}{
+		{"disabled", repos.DeletionSafeguard{}, stored, nil},
+		{"no deletions", repos.DeletionSafeguard{MaxDeletions: 1, MaxDeletionPercentage: 1}, nil, nil},
+		{"at max deletions", repos.DeletionSafeguard{MaxDeletions: 5}, storedB[:5], nil},
+		{"above max deletions", repos.DeletionSafeguard{MaxDeletions: 5}, append(storedA[:1:1], storedB[:5]...), append(storedA[:1:1], storedB[:5]...)},
+		{"at max percentage", repos.DeletionSafeguard{MaxDeletionPercentage: 10}, append(storedA[:1:1], storedB[:10]...), nil},
+		{"above max percentage", repos.DeletionSafeguard{MaxDeletionPercentage: 10}, storedB[:11], storedB[:11]},
+		{"percentage per external service", repos.DeletionSafeguard{MaxDeletionPercentage: 10}, append(storedA[:2:2], storedB[:5]...), storedA[:2]},
+		{"either threshold", repos.DeletionSafeguard{MaxDeletions: 100, MaxDeletionPercentage: 10}, storedA[:2], storedA[:2]},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			have := tc.safeguard.Quarantined(tc.deleted, stored)
+			if !cmp.Equal(have.Names(), tc.quarantined.Names()) {
+				t.Errorf("quarantined: %s", cmp.Diff(have.Names(), tc.quarantined.Names()))
+			}
+		})
+	}
+}
+
+func TestSyncer_DeletionSafeguard(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := repos.NewFakeClock(time.Now(), time.Second)
+
+	store := new(repos.FakeStore)
+	svc := &repos.ExternalService{Kind: "GITHUB", DisplayName: "Github", Config: "{}"}
+	if err := store.UpsertExternalServices(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+
+	var all repos.Repos
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		all = append(all, (&repos.Repo{
+			Name:     "github.com/org/" + name,
+			Metadata: &github.Repository{},
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          name,
+				ServiceID:   "https://github.com/",
+				ServiceType: "github",
+			},
+		}).With(repos.Opt.RepoSources(svc.URN())))
+	}
+
+	safeguard := repos.DeletionSafeguard{
+		MaxDeletions:    1,
+		ConsistentSyncs: 4,
+	}
+
+	syncer := &repos.Syncer{
+		Store:             store,
+		Now:               clock.Now,
+		DeletionSafeguard: func() repos.DeletionSafeguard { return safeguard },
+	}
+
+	type state struct {
+		Pending      bool
+		MissingSyncs int
+	}
+
+	sync := func(sourced ...*repos.Repo) map[string]state {
+		t.Helper()
+
+		syncer.Sourcer = repos.NewFakeSourcer(nil, repos.NewFakeSource(svc, nil, repos.Repos(sourced).Clone()...))
+		if err := syncer.Sync(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		rs, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		have := make(map[string]state, len(rs))
+		for _, r := range rs {
+			have[r.Name] = state{!r.PendingDeletionAt.IsZero(), r.MissingSyncs}
+		}
+		return have
+	}
+
+	sync(all...)
+
+	// A sync that would delete more repos than allowed quarantines them.
+	have := sync(all[0], all[1])
+	want := map[string]state{
+		all[0].Name: {},
+		all[1].Name: {},
+		all[2].Name: {true, 1},
+		all[3].Name: {true, 1},
+		all[4].Name: {true, 1},
+	}
+	if !cmp.Equal(have, want) {
+		t.Fatalf("quarantined: %s", cmp.Diff(have, want))
+	}
+
+	// Repos that are found again are no longer pending deletion.
+	have = sync(all[0], all[1], all[2])
+	want = map[string]state{
+		all[0].Name: {},
+		all[1].Name: {},
+		all[2].Name: {},
+		all[3].Name: {true, 2},
+		all[4].Name: {true, 2},
+	}
+	if !cmp.Equal(have, want) {
+		t.Fatalf("found again: %s", cmp.Diff(have, want))
+	}
+
+	// Repos pending deletion stay quarantined when the remaining deletions
+	// are below the thresholds.
+	have = sync(all[0], all[1], all[2], all[3])
+	want = map[string]state{
+		all[0].Name: {},
+		all[1].Name: {},
+		all[2].Name: {},
+		all[3].Name: {},
+		all[4].Name: {true, 3},
+	}
+	if !cmp.Equal(have, want) {
+		t.Fatalf("below thresholds: %s", cmp.Diff(have, want))
+	}
+
+	// Repos missing from ConsistentSyncs consecutive syncs are deleted.
+	have = sync(all[0], all[1], all[2], all[3])
+	want = map[string]state{
+		all[0].Name: {},
+		all[1].Name: {},
+		all[2].Name: {},
+		all[3].Name: {},
+	}
+	if !cmp.Equal(have, want) {
+		t.Fatalf("consistent syncs: %s", cmp.Diff(have, want))
+	}
+
+	// Without ConsistentSyncs, quarantined repos are only deleted once
+	// confirmed, and confirmed deletions are applied right away.
+	safeguard.ConsistentSyncs = 0
+	sync(all...)
+
+	for i := 0; i < 5; i++ {
+		have = sync(all[0])
+	}
+	if pending := len(have) - 1; pending != 4 {
+		t.Fatalf("have %d repos pending deletion, want 4", pending)
+	}
+
+	diff, err := syncer.DeletePendingRepos(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if have, want := diff.Deleted.Names(), repos.Repos(all[1:]).Names(); !cmp.Equal(have, want) {
+		t.Errorf("deleted: %s", cmp.Diff(have, want))
+	}
+
+	rs, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if have, want := repos.Repos(rs).Names(), []string{all[0].Name}; !cmp.Equal(have, want) {
+		t.Errorf("stored: %s", cmp.Diff(have, want))
+	}
+}
+
 func TestDiff(t *testing.T) {
 	t.Parallel()
 
@@ -190,6 +190,17 @@ func (s *FakeStore) UpsertExternalServices(ctx context.Context, svcs ...*Externa
 	return nil
 }
 
+// UpdateExternalServiceSyncTimes updates the LastSyncAt and LastFullSyncAt
+// fields of the given ExternalServices.
+func (s *FakeStore) UpdateExternalServiceSyncTimes(ctx context.Context, svcs ...*ExternalService) error {
+	for _, svc := range svcs {
+		if old := s.svcByID[svc.ID]; old != nil {
+			old.LastSyncAt, old.LastFullSyncAt = svc.LastSyncAt, svc.LastFullSyncAt
+		}
+	}
+	return nil
+}
+
 // GetRepoByName looks a repo by its name, returning it if found.
 func (s FakeStore) GetRepoByName(ctx context.Context, name string) (*Repo, error) {
 	if s.GetRepoByNameError != nil {
@@ -258,6 +269,9 @@ func (s FakeStore) ListRepos(ctx context.Context, args StoreListReposArgs) ([]*R
 		if len(externalRepos) > 0 {
 			preds = append(preds, externalRepos[r.ExternalRepo])
 		}
+		if args.PendingDeletion {
+			preds = append(preds, !r.PendingDeletionAt.IsZero())
+		}
 
 		if (args.UseOr && evalOr(preds...)) || (!args.UseOr && evalAnd(preds...)) {
 			repos = append(repos, r)
@@ -16,9 +16,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
 	"github.com/xeipuuv/gojsonschema"
@@ -44,6 +47,14 @@ type ExternalService struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   time.Time
+
+	// LastSyncAt is when the last successful sync of the external service's
+	// repositories began. Sources that support incremental listing use it as
+	// the cursor after which repositories must be listed again.
+	LastSyncAt time.Time
+	// LastFullSyncAt is when the last successful sync that listed all of the
+	// external service's repositories began.
+	LastFullSyncAt time.Time
 }
 
 // URN returns a unique resource identifier of this external service,
@@ -92,6 +103,10 @@ func (e ExternalService) Configuration() (cfg interface{}, _ error) {
 		cfg = &schema.AWSCodeCommitConnection{}
 	case "bitbucketserver":
 		cfg = &schema.BitbucketServerConnection{}
+	case "gerrit":
+		cfg = &schema.GerritConnection{}
+	case "gitea":
+		cfg = &schema.GiteaConnection{}
 	case "github":
 		cfg = &schema.GitHubConnection{}
 	case "gitlab":
@@ -120,6 +135,10 @@ func (e *ExternalService) Exclude(rs ...*Repo) error {
 		return e.excludeBitbucketServerRepos(rs...)
 	case "awscodecommit":
 		return e.excludeAWSCodeCommitRepos(rs...)
+	case "gitea":
+		return e.excludeGiteaRepos(rs...)
+	case "gerrit":
+		return e.excludeGerritProjects(rs...)
 	case "gitolite":
 		return e.excludeGitoliteRepos(rs...)
 	case "other":
@@ -296,6 +315,77 @@ func (e *ExternalService) excludeBitbucketServerRepos(rs ...*Repo) error {
 	})
 }
 
+// excludeGiteaRepos changes the configuration of a Gitea external service to
+// exclude the given repos from being synced.
+func (e *ExternalService) excludeGiteaRepos(rs ...*Repo) error {
+	if len(rs) == 0 {
+		return nil
+	}
+
+	return e.config("gitea", func(v interface{}) (string, interface{}, error) {
+		c := v.(*schema.GiteaConnection)
+		set := make(map[string]bool, len(c.Exclude)*2)
+		for _, ex := range c.Exclude {
+			if ex.Id != 0 {
+				set[strconv.Itoa(ex.Id)] = true
+			}
+
+			if ex.Name != "" {
+				set[strings.ToLower(ex.Name)] = true
+			}
+		}
+
+		for _, r := range rs {
+			repo, ok := r.Metadata.(*gitea.Repo)
+			if !ok {
+				continue
+			}
+
+			name := strings.ToLower(repo.FullName)
+			id := strconv.FormatInt(repo.ID, 10)
+
+			if !set[name] && !set[id] {
+				c.Exclude = append(c.Exclude, &schema.ExcludedGiteaRepo{
+					Name: repo.FullName,
+					Id:   int(repo.ID),
+				})
+				set[id] = true
+				set[name] = true
+			}
+		}
+
+		return "exclude", c.Exclude, nil
+	})
+}
+
+// excludeGerritProjects changes the configuration of a Gerrit external service
+// to exclude the given projects from being synced.
+func (e *ExternalService) excludeGerritProjects(rs ...*Repo) error {
+	if len(rs) == 0 {
+		return nil
+	}
+
+	return e.config("gerrit", func(v interface{}) (string, interface{}, error) {
+		c := v.(*schema.GerritConnection)
+		set := make(map[string]bool, len(c.Exclude))
+		for _, ex := range c.Exclude {
+			if ex.Name != "" {
+				set[ex.Name] = true
+			}
+		}
+
+		for _, r := range rs {
+			p, ok := r.Metadata.(*gerrit.Project)
+			if ok && p.Name != "" && !set[p.Name] {
+				c.Exclude = append(c.Exclude, &schema.ExcludedGerritProject{Name: p.Name})
+				set[p.Name] = true
+			}
+		}
+
+		return "exclude", c.Exclude, nil
+	})
+}
+
 // excludeGitoliteRepos changes the configuration of a Gitolite external service to exclude the
 // given repos from being synced.
 func (e *ExternalService) excludeGitoliteRepos(rs ...*Repo) error {
@@ -462,6 +552,10 @@ func (e ExternalService) schema() string {
 		return schema.AWSCodeCommitSchemaJSON
 	case "bitbucketserver":
 		return schema.BitbucketServerSchemaJSON
+	case "gerrit":
+		return schema.GerritSchemaJSON
+	case "gitea":
+		return schema.GiteaSchemaJSON
 	case "github":
 		return schema.GitHubSchemaJSON
 	case "gitlab":
@@ -551,12 +645,27 @@ type Repo struct {
 	Archived bool
 	// Private is whether the repository is private.
 	Private bool
+	// Topics are the lower-cased topics, tags or labels of the repository on
+	// its code host.
+	Topics []string
+	// Stars is the number of stars the repository has on its code host.
+	Stars int
+	// DefaultBranch is the name of the repository's default branch on its
+	// code host, if known.
+	DefaultBranch string
 	// CreatedAt is when this repository was created on Sourcegraph.
 	CreatedAt time.Time
 	// UpdatedAt is when this repository's metadata was last updated on Sourcegraph.
 	UpdatedAt time.Time
 	// DeletedAt is when this repository was soft-deleted from Sourcegraph.
 	DeletedAt time.Time
+	// PendingDeletionAt is when a sync that would have deleted more
+	// repositories than the DeletionSafeguard allows first didn't find this
+	// repository. It's kept until its deletion is confirmed.
+	PendingDeletionAt time.Time
+	// MissingSyncs is the number of consecutive syncs that didn't find this
+	// repository while it's pending deletion.
+	MissingSyncs int
 	// ExternalRepo identifies this repository by its ID on the external service where it resides (and the external
 	// service itself).
 	ExternalRepo api.ExternalRepoSpec
@@ -570,6 +679,12 @@ type Repo struct {
 type SourceInfo struct {
 	ID       string
 	CloneURL string
+	// CloneOptions are the options to clone the repo with from this source,
+	// or nil to clone it in full.
+	CloneOptions *gitserverprotocol.CloneOptions `json:",omitempty"`
+	// GitOverrides change how the repo is cloned and fetched from this
+	// source, or nil to clone and fetch it the usual way.
+	GitOverrides *gitserverprotocol.GitOverrides `json:",omitempty"`
 }
 
 // ExternalServiceID returns the ID of the external service this
@@ -648,6 +763,18 @@ func (r *Repo) Update(n *Repo) (modified bool) {
 		r.Private, modified = n.Private, true
 	}
 
+	if !equalStrings(r.Topics, n.Topics) {
+		r.Topics, modified = n.Topics, true
+	}
+
+	if r.Stars != n.Stars {
+		r.Stars, modified = n.Stars, true
+	}
+
+	if r.DefaultBranch != n.DefaultBranch {
+		r.DefaultBranch, modified = n.DefaultBranch, true
+	}
+
 	if !reflect.DeepEqual(r.Sources, n.Sources) {
 		r.Sources, modified = n.Sources, true
 	}
@@ -659,12 +786,53 @@ func (r *Repo) Update(n *Repo) (modified bool) {
 	return modified
 }
 
+// equalStrings returns true if a and b have the same elements in the same
+// order, treating nil and empty slices as equal.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTopics lower-cases the given topics and drops empty and
+// duplicate ones, so that they can be matched case-insensitively.
+func normalizeTopics(topics []string) []string {
+	if len(topics) == 0 {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(topics))
+	seen := make(map[string]bool, len(topics))
+	for _, t := range topics {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		normalized = append(normalized, t)
+	}
+
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
+
 // Clone returns a clone of the given repo.
 func (r *Repo) Clone() *Repo {
 	if r == nil {
 		return nil
 	}
 	clone := *r
+	if r.Topics != nil {
+		clone.Topics = append([]string(nil), r.Topics...)
+	}
 	if r.Sources != nil {
 		clone.Sources = make(map[string]*SourceInfo, len(r.Sources))
 		for k, v := range r.Sources {
@@ -7,6 +7,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
@@ -89,6 +91,29 @@ func TestExternalService_Exclude(t *testing.T) {
 		UpdatedAt: now,
 	}
 
+	giteaService := ExternalService{
+		Kind:        "GITEA",
+		DisplayName: "Gitea",
+		Config: `{
+			// Some comment
+			"url": "https://gitea.mycorp.com",
+			"token": "secret"
+		}`,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	gerritService := ExternalService{
+		Kind:        "GERRIT",
+		DisplayName: "Gerrit",
+		Config: `{
+			// Some comment
+			"url": "https://gerrit.mycorp.com"
+		}`,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
 	otherService := ExternalService{
 		Kind:        "OTHER",
 		DisplayName: "Other code hosts",
@@ -174,6 +199,15 @@ func TestExternalService_Exclude(t *testing.T) {
 		{
 			Metadata: &gitolite.Repo{Name: "foo"},
 		},
+		{
+			Metadata: &gitea.Repo{ID: 1, FullName: "org/foo"},
+		},
+		{
+			Metadata: &gitea.Repo{ID: 2, FullName: "org/baz"},
+		},
+		{
+			Metadata: &gerrit.Project{Name: "org/foo"},
+		},
 	}
 
 	var testCases []testCase
@@ -244,6 +278,28 @@ func TestExternalService_Exclude(t *testing.T) {
 					]
 				}`)
 			}),
+			giteaService.With(func(e *ExternalService) {
+				e.Config = formatJSON(t, `
+				{
+					// Some comment
+					"url": "https://gitea.mycorp.com",
+					"token": "secret",
+					"exclude": [
+						{"id": 1},
+						{"name": "org/BAZ"}
+					]
+				}`)
+			}),
+			gerritService.With(func(e *ExternalService) {
+				e.Config = formatJSON(t, `
+				{
+					// Some comment
+					"url": "https://gerrit.mycorp.com",
+					"exclude": [
+						{"name": "org/foo"}
+					]
+				}`)
+			}),
 			&otherService,
 		}
 
@@ -317,6 +373,27 @@ func TestExternalService_Exclude(t *testing.T) {
 					]
 				}`)
 			}),
+			giteaService.With(func(e *ExternalService) {
+				e.Config = formatJSON(t, `
+				{
+					// Some comment
+					"url": "https://gitea.mycorp.com",
+					"token": "secret",
+					"exclude": [
+						{"name": "org/boo"}
+					]
+				}`)
+			}),
+			gerritService.With(func(e *ExternalService) {
+				e.Config = formatJSON(t, `
+				{
+					// Some comment
+					"url": "https://gerrit.mycorp.com",
+					"exclude": [
+						{"name": "org/boo"}
+					]
+				}`)
+			}),
 			otherService.With(func(e *ExternalService) {
 				e.Config = formatJSON(t, `
 				{
@@ -405,6 +482,30 @@ func TestExternalService_Exclude(t *testing.T) {
 						]
 					}`)
 				}),
+				giteaService.With(func(e *ExternalService) {
+					e.Config = formatJSON(t, `
+					{
+						// Some comment
+						"url": "https://gitea.mycorp.com",
+						"token": "secret",
+						"exclude": [
+							{"name": "org/boo"},
+							{"id": 1, "name": "org/foo"},
+							{"id": 2, "name": "org/baz"}
+						]
+					}`)
+				}),
+				gerritService.With(func(e *ExternalService) {
+					e.Config = formatJSON(t, `
+					{
+						// Some comment
+						"url": "https://gerrit.mycorp.com",
+						"exclude": [
+							{"name": "org/boo"},
+							{"name": "org/foo"}
+						]
+					}`)
+				}),
 				otherService.With(func(e *ExternalService) {
 					e.Config = formatJSON(t, `
 					{
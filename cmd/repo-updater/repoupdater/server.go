@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +18,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
@@ -61,6 +64,8 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/enqueue-repo-update", s.handleEnqueueRepoUpdate)
 	mux.HandleFunc("/exclude-repo", s.handleExcludeRepo)
 	mux.HandleFunc("/sync-external-service", s.handleExternalServiceSync)
+	mux.HandleFunc("/external-service-dry-run", s.handleExternalServiceDryRun)
+	mux.HandleFunc("/delete-pending-repos", s.handleDeletePendingRepos)
 	mux.HandleFunc("/status-messages", s.handleStatusMessages)
 	mux.HandleFunc("/enqueue-changeset-sync", s.handleEnqueueChangesetSync)
 	return mux
@@ -272,16 +277,26 @@ func (s *Server) enqueueRepoUpdate(ctx context.Context, req *protocol.RepoUpdate
 		tr.Finish()
 	}()
 
-	rs, err := s.Store.ListRepos(ctx, repos.StoreListReposArgs{Names: []string{string(req.Repo)}})
+	args := repos.StoreListReposArgs{Names: []string{string(req.Repo)}}
+	if req.ExternalRepo != nil {
+		args = repos.StoreListReposArgs{ExternalRepos: []api.ExternalRepoSpec{*req.ExternalRepo}}
+	}
+	rs, err := s.Store.ListRepos(ctx, args)
 	if err != nil {
 		return nil, http.StatusInternalServerError, errors.Wrap(err, "store.list-repos")
 	}
 
 	if len(rs) != 1 {
+		if req.ExternalRepo != nil {
+			return nil, http.StatusNotFound, errors.Errorf("repo %s not found in store", req.ExternalRepo)
+		}
 		return nil, http.StatusNotFound, errors.Errorf("repo %q not found in store", req.Repo)
 	}
 
 	repo := rs[0]
+	if req.ExternalRepo != nil {
+		req.Repo = api.RepoName(repo.Name)
+	}
 	if req.URL == "" {
 		if urls := repo.CloneURLs(); len(urls) > 0 {
 			req.URL = urls[0]
@@ -332,6 +347,68 @@ func (s *Server) handleExternalServiceSync(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+func (s *Server) handleExternalServiceDryRun(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ExternalServiceDryRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	diff, renamed, err := s.Syncer.DryRun(r.Context(), &repos.ExternalService{
+		ID:          req.ExternalService.ID,
+		Kind:        req.ExternalService.Kind,
+		DisplayName: req.ExternalService.DisplayName,
+		Config:      req.ExternalService.Config,
+	})
+	if r.Context().Err() != nil {
+		// client is gone
+		return
+	} else if err != nil {
+		log15.Info("server.external-service-dry-run", "kind", req.ExternalService.Kind, "error", err)
+		respond(w, http.StatusOK, &protocol.ExternalServiceDryRunResult{Error: err.Error()})
+		return
+	}
+
+	res := &protocol.ExternalServiceDryRunResult{
+		Added:       repoNames(diff.Added),
+		Deleted:     repoNames(diff.Deleted),
+		Quarantined: repoNames(diff.Quarantined),
+		Renamed:     []protocol.RepoRename{},
+		Modified:    []api.RepoName{},
+	}
+
+	for _, r := range diff.Modified {
+		if from, ok := renamed[r.ID]; ok {
+			res.Renamed = append(res.Renamed, protocol.RepoRename{From: api.RepoName(from), To: api.RepoName(r.Name)})
+		} else {
+			res.Modified = append(res.Modified, api.RepoName(r.Name))
+		}
+	}
+
+	log15.Info("server.external-service-dry-run", "kind", req.ExternalService.Kind,
+		"added", len(res.Added), "deleted", len(res.Deleted), "quarantined", len(res.Quarantined), "renamed", len(res.Renamed), "modified", len(res.Modified))
+	respond(w, http.StatusOK, res)
+}
+
+func repoNames(rs repos.Repos) []api.RepoName {
+	names := make([]api.RepoName, 0, len(rs))
+	for _, r := range rs {
+		names = append(names, api.RepoName(r.Name))
+	}
+	return names
+}
+
+func (s *Server) handleDeletePendingRepos(w http.ResponseWriter, r *http.Request) {
+	diff, err := s.Syncer.DeletePendingRepos(r.Context())
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	log15.Info("server.delete-pending-repos", "deleted", len(diff.Deleted))
+	respond(w, http.StatusOK, &protocol.DeletePendingReposResponse{Deleted: repoNames(diff.Deleted)})
+}
+
 func externalServiceValidate(ctx context.Context, req *protocol.ExternalServiceSyncRequest) error {
 	if req.ExternalService.DeletedAt != nil {
 		// We don't need to check deleted services.
@@ -653,11 +730,59 @@ func newRepoInfo(r *repos.Repo) (*protocol.RepoInfo, error) {
 			Blob:   webURL + "/browse/{rev}/--/{path}",
 			Commit: webURL + "/commit/{commit}",
 		}
+	case "gitea":
+		repo := r.Metadata.(*gitea.Repo)
+		if repo.HTMLURL == "" {
+			break
+		}
+
+		info.Links = &protocol.RepoLinks{
+			Root:   repo.HTMLURL,
+			Tree:   pathAppend(repo.HTMLURL, "/src/{rev}/{path}"),
+			Blob:   pathAppend(repo.HTMLURL, "/src/{rev}/{path}"),
+			Commit: pathAppend(repo.HTMLURL, "/commit/{commit}"),
+		}
+	case "gerrit":
+		// Gerrit has no code browser of its own, so we link to the one
+		// (usually gitiles) it links to, if any.
+		p := r.Metadata.(*gerrit.Project)
+		root := gerritBrowseURL(r.ExternalRepo.ServiceID, p)
+		if root == "" {
+			break
+		}
+
+		info.Links = &protocol.RepoLinks{
+			Root:   root,
+			Tree:   pathAppend(root, "/+/{rev}/{path}"),
+			Blob:   pathAppend(root, "/+/{rev}/{path}"),
+			Commit: pathAppend(root, "/+/{commit}"),
+		}
 	}
 
 	return &info, nil
 }
 
+// gerritBrowseURL returns the absolute URL of the gitiles web link of the
+// Gerrit project p, or "" if it has none. Gerrit returns web links relative
+// to its baseURL.
+func gerritBrowseURL(baseURL string, p *gerrit.Project) string {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	for _, l := range p.WebLinks {
+		if !strings.EqualFold(l.Name, "browse") && !strings.EqualFold(l.Name, "gitiles") {
+			continue
+		}
+		u, err := url.Parse(l.URL)
+		if err != nil {
+			continue
+		}
+		return base.ResolveReference(u).String()
+	}
+	return ""
+}
+
 func pathAppend(base, p string) string {
 	return strings.TrimRight(base, "/") + p
 }
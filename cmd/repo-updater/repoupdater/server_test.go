@@ -22,6 +22,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -350,11 +352,12 @@ func TestServer_EnqueueRepoUpdate(t *testing.T) {
 	ctx := context.Background()
 
 	type testCase struct {
-		name  string
-		store repos.Store
-		repo  gitserver.Repo
-		res   *protocol.RepoUpdateResponse
-		err   string
+		name         string
+		store        repos.Store
+		repo         gitserver.Repo
+		externalRepo *api.ExternalRepoSpec
+		res          *protocol.RepoUpdateResponse
+		err          string
 	}
 
 	var testCases []testCase
@@ -421,6 +424,31 @@ func TestServer_EnqueueRepoUpdate(t *testing.T) {
 				},
 			}
 		}(),
+		func() testCase {
+			store := new(repos.FakeStore)
+			repo := repo.Clone()
+			must(store.UpsertRepos(ctx, repo))
+			return testCase{
+				name:         "repo is found by external repo spec",
+				store:        store,
+				externalRepo: &repo.ExternalRepo,
+				res: &protocol.RepoUpdateResponse{
+					ID:   repo.ID,
+					Name: repo.Name,
+					URL:  repo.CloneURLs()[0],
+				},
+			}
+		}(),
+		func() testCase {
+			store := new(repos.FakeStore)
+			must(store.UpsertRepos(ctx, repo.Clone()))
+			return testCase{
+				name:         "missing external repo",
+				store:        store,
+				externalRepo: &api.ExternalRepoSpec{ID: "baz", ServiceType: "github", ServiceID: "http://github.com"},
+				err:          `repo ExternalRepoSpec{http://github.com github baz} not found in store`,
+			}
+		}(),
 	)
 
 	for _, tc := range testCases {
@@ -437,7 +465,15 @@ func TestServer_EnqueueRepoUpdate(t *testing.T) {
 				tc.err = "<nil>"
 			}
 
-			res, err := cli.EnqueueRepoUpdate(ctx, tc.repo)
+			var (
+				res *protocol.RepoUpdateResponse
+				err error
+			)
+			if tc.externalRepo != nil {
+				res, err = cli.EnqueueExternalRepoUpdate(ctx, *tc.externalRepo)
+			} else {
+				res, err = cli.EnqueueRepoUpdate(ctx, tc.repo)
+			}
 			if have, want := fmt.Sprint(err), tc.err; have != want {
 				t.Errorf("have err: %q, want: %q", have, want)
 			}
@@ -813,6 +849,51 @@ func TestRepoLookup(t *testing.T) {
 		},
 	}
 
+	giteaRepository := &repos.Repo{
+		Name:      "gitea.example.com/acme/api",
+		URI:       "gitea.example.com/acme/api",
+		CreatedAt: now,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "1",
+			ServiceType: gitea.ServiceType,
+			ServiceID:   "https://gitea.example.com/",
+		},
+		Sources: map[string]*repos.SourceInfo{
+			"extsvc:789": {
+				ID:       "extsvc:789",
+				CloneURL: "https://gitea.example.com/acme/api.git",
+			},
+		},
+		Metadata: &gitea.Repo{
+			ID:       1,
+			FullName: "acme/api",
+			HTMLURL:  "https://gitea.example.com/acme/api",
+			CloneURL: "https://gitea.example.com/acme/api.git",
+		},
+	}
+
+	gerritRepository := &repos.Repo{
+		Name:      "gerrit.example.com/tools/gerrit",
+		URI:       "gerrit.example.com/tools/gerrit",
+		CreatedAt: now,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "tools/gerrit",
+			ServiceType: gerrit.ServiceType,
+			ServiceID:   "https://gerrit.example.com/",
+		},
+		Sources: map[string]*repos.SourceInfo{
+			"extsvc:1011": {
+				ID:       "extsvc:1011",
+				CloneURL: "https://gerrit.example.com/tools/gerrit",
+			},
+		},
+		Metadata: &gerrit.Project{
+			ID:       "tools%2Fgerrit",
+			Name:     "tools/gerrit",
+			WebLinks: []gerrit.WebLink{{Name: "browse", URL: "/plugins/gitiles/tools/gerrit"}},
+		},
+	}
+
 	gitlabRepository := &repos.Repo{
 		Name:        "gitlab.com/gitlab-org/gitaly",
 		Description: "Gitaly is a Git RPC service for handling all the git calls made by GitLab",
@@ -908,6 +989,42 @@ func TestRepoLookup(t *testing.T) {
 				},
 			}},
 		},
+		{
+			name: "found - Gitea",
+			args: protocol.RepoLookupArgs{
+				Repo: api.RepoName("gitea.example.com/acme/api"),
+			},
+			stored: []*repos.Repo{giteaRepository},
+			result: &protocol.RepoLookupResult{Repo: &protocol.RepoInfo{
+				ExternalRepo: giteaRepository.ExternalRepo,
+				Name:         "gitea.example.com/acme/api",
+				VCS:          protocol.VCSInfo{URL: "https://gitea.example.com/acme/api.git"},
+				Links: &protocol.RepoLinks{
+					Root:   "https://gitea.example.com/acme/api",
+					Tree:   "https://gitea.example.com/acme/api/src/{rev}/{path}",
+					Blob:   "https://gitea.example.com/acme/api/src/{rev}/{path}",
+					Commit: "https://gitea.example.com/acme/api/commit/{commit}",
+				},
+			}},
+		},
+		{
+			name: "found - Gerrit",
+			args: protocol.RepoLookupArgs{
+				Repo: api.RepoName("gerrit.example.com/tools/gerrit"),
+			},
+			stored: []*repos.Repo{gerritRepository},
+			result: &protocol.RepoLookupResult{Repo: &protocol.RepoInfo{
+				ExternalRepo: gerritRepository.ExternalRepo,
+				Name:         "gerrit.example.com/tools/gerrit",
+				VCS:          protocol.VCSInfo{URL: "https://gerrit.example.com/tools/gerrit"},
+				Links: &protocol.RepoLinks{
+					Root:   "https://gerrit.example.com/plugins/gitiles/tools/gerrit",
+					Tree:   "https://gerrit.example.com/plugins/gitiles/tools/gerrit/+/{rev}/{path}",
+					Blob:   "https://gerrit.example.com/plugins/gitiles/tools/gerrit/+/{rev}/{path}",
+					Commit: "https://gerrit.example.com/plugins/gitiles/tools/gerrit/+/{commit}",
+				},
+			}},
+		},
 		{
 			name: "found - GitHub.com on Sourcegraph.com",
 			args: protocol.RepoLookupArgs{
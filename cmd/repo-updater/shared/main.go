@@ -82,6 +82,7 @@ func Main(enterpriseInit EnterpriseInit) {
 			m.UpsertRepos,
 			m.ListExternalServices,
 			m.UpsertExternalServices,
+			m.UpdateExternalServiceSyncTimes,
 			m.ListAllRepoNames,
 		} {
 			om.MustRegister(prometheus.DefaultRegisterer)
@@ -173,11 +174,13 @@ func Main(enterpriseInit EnterpriseInit) {
 	gps := repos.NewGitolitePhabricatorMetadataSyncer(store)
 
 	syncer := &repos.Syncer{
-		Store:            store,
-		Sourcer:          src,
-		DisableStreaming: !streamingSyncer,
-		Logger:           log15.Root(),
-		Now:              clock,
+		Store:             store,
+		Sourcer:           src,
+		DisableStreaming:  !streamingSyncer,
+		Logger:            log15.Root(),
+		Now:               clock,
+		FullSyncInterval:  repos.GetFullSyncInterval,
+		DeletionSafeguard: repos.GetDeletionSafeguard,
 	}
 
 	if envvar.SourcegraphDotComMode() {
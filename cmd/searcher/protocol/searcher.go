@@ -105,6 +105,17 @@ type PatternInfo struct {
 
 	// CombyRule is a rule that constrains matching for structural search. It only applies when IsStructuralPat is true.
 	CombyRule string
+
+	// FilePaths are the exact paths of the files that structural search
+	// runs on, when the frontend has already found the candidate files (using
+	// Zoekt). It only applies when IsStructuralPat is true.
+	FilePaths []string
+
+	// ContextBefore and ContextAfter are the number of lines before and
+	// after each matching line that are returned in ContextRanges. They do
+	// not apply to structural search.
+	ContextBefore int
+	ContextAfter  int
 }
 
 func (p *PatternInfo) String() string {
@@ -127,6 +138,12 @@ func (p *PatternInfo) String() string {
 	if p.FileMatchLimit > 0 {
 		args = append(args, fmt.Sprintf("filematchlimit:%d", p.FileMatchLimit))
 	}
+	if p.ContextBefore > 0 {
+		args = append(args, fmt.Sprintf("contextbefore:%d", p.ContextBefore))
+	}
+	if p.ContextAfter > 0 {
+		args = append(args, fmt.Sprintf("contextafter:%d", p.ContextAfter))
+	}
 
 	path := "glob"
 	if p.PathPatternsAreRegExps {
@@ -163,6 +180,21 @@ type FileMatch struct {
 	Path        string
 	LineMatches []LineMatch
 
+	// MultilineMatches are the matches of patterns that can match text
+	// spanning several lines. Each match is also reported per line in
+	// LineMatches.
+	MultilineMatches []MultilineMatch
+
+	// ContextRanges are the matching lines together with the context lines
+	// requested by ContextBefore and ContextAfter. Overlapping ranges are
+	// merged.
+	ContextRanges []ContextRange
+
+	// Blob is the Git blob object ID of the file's content. It is only set
+	// for files with LineMatches, so that the frontend can recognize the same
+	// match in several revisions of a repository.
+	Blob string
+
 	// LimitHit is true if LineMatches may not include all LineMatches.
 	LimitHit bool
 }
@@ -184,3 +216,34 @@ type LineMatch struct {
 	// LimitHit is true if OffsetAndLengths may not include all OffsetAndLengths.
 	LimitHit bool
 }
+
+// MultilineMatch is a match that may span several lines.
+type MultilineMatch struct {
+	// Preview is the content of the lines spanned by the match.
+	Preview string
+
+	// Start is the position of the first character of the match.
+	Start Position
+
+	// End is the position just after the last character of the match.
+	End Position
+}
+
+// Position is a position in a file.
+type Position struct {
+	// Line is the 0-based line number.
+	Line int
+
+	// Character is the 0-based offset in the line, measured in characters,
+	// not bytes.
+	Character int
+}
+
+// ContextRange is a run of consecutive lines around one or more matches.
+type ContextRange struct {
+	// StartLine is the 0-based line number of the first line of the range.
+	StartLine int
+
+	// Preview is the content of the lines in the range.
+	Preview string
+}
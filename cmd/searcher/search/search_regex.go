@@ -3,7 +3,10 @@ package search
 import (
 	"bytes"
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"regexp"
 	"regexp/syntax"
@@ -16,6 +19,8 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/pathmatch"
+	"github.com/sourcegraph/sourcegraph/internal/search/contextlines"
+	"github.com/sourcegraph/sourcegraph/internal/search/multiline"
 	"github.com/sourcegraph/sourcegraph/internal/store"
 
 	opentracing "github.com/opentracing/opentracing-go"
@@ -61,6 +66,14 @@ type readerGrep struct {
 	// re. It is the output of the longestLiteral function. It is only set if
 	// the regex has an empty LiteralPrefix.
 	literalSubstring []byte
+
+	// multiline is true if re can match text spanning several lines. The
+	// matches of such patterns are also reported as MultilineMatches.
+	multiline bool
+
+	// contextBefore and contextAfter are the number of lines around each
+	// matching line reported in ContextRanges.
+	contextBefore, contextAfter int
 }
 
 // compile returns a readerGrep for matching p.
@@ -68,6 +81,7 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 	var (
 		re               *regexp.Regexp
 		literalSubstring []byte
+		isMultiline      bool
 	)
 	if p.Pattern != "" {
 		expr := p.Pattern
@@ -82,19 +96,19 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 			// regex engine to consider newlines for anchors (^$).
 			expr = "(?m:" + expr + ")"
 		}
+		ast, err := syntax.Parse(expr, syntax.Perl)
+		if err != nil {
+			return nil, err
+		}
+		isMultiline = multiline.Regexp(ast)
 		if !p.IsCaseSensitive {
 			// We don't just use (?i) because regexp library doesn't seem
 			// to contain good optimizations for case insensitive
 			// search. Instead we lowercase the input and pattern.
-			re, err := syntax.Parse(expr, syntax.Perl)
-			if err != nil {
-				return nil, err
-			}
-			lowerRegexpASCII(re)
-			expr = re.String()
+			lowerRegexpASCII(ast)
+			expr = ast.String()
 		}
 
-		var err error
 		re, err = regexp.Compile(expr)
 		if err != nil {
 			return nil, err
@@ -126,6 +140,9 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 		ignoreCase:       !p.IsCaseSensitive,
 		matchPath:        matchPath,
 		literalSubstring: literalSubstring,
+		multiline:        isMultiline,
+		contextBefore:    p.ContextBefore,
+		contextAfter:     p.ContextAfter,
 	}, nil
 }
 
@@ -137,6 +154,9 @@ func (rg *readerGrep) Copy() *readerGrep {
 		ignoreCase:       rg.ignoreCase,
 		matchPath:        rg.matchPath,
 		literalSubstring: rg.literalSubstring,
+		multiline:        rg.multiline,
+		contextBefore:    rg.contextBefore,
+		contextAfter:     rg.contextAfter,
 	}
 }
 
@@ -152,10 +172,12 @@ func (rg *readerGrep) matchString(s string) bool {
 	return rg.re.MatchString(s)
 }
 
-// Find returns a LineMatch for each line that matches rg in reader.
+// Find returns a LineMatch for each line that matches rg in reader. If rg can
+// match text spanning several lines, it also returns a MultilineMatch for each
+// match.
 // LimitHit is true if some matches may not have been included in the result.
 // NOTE: This is not safe to use concurrently.
-func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []protocol.LineMatch, limitHit bool, err error) {
+func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []protocol.LineMatch, multilineMatches []protocol.MultilineMatch, limitHit bool, err error) {
 	// fileMatchBuf is what we run match on, fileBuf is the original
 	// data (for Preview).
 	fileBuf := zf.DataFor(f)
@@ -182,7 +204,7 @@ func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []proto
 	// per-line. Additionally if we have a non-empty literalSubstring, we use
 	// that to prune out files since doing bytes.Index is very fast.
 	if !bytes.Contains(fileMatchBuf, rg.literalSubstring) {
-		return nil, false, nil
+		return nil, nil, false, nil
 	}
 
 	locs := rg.re.FindAllIndex(fileMatchBuf, maxLineMatches+1)
@@ -217,6 +239,9 @@ func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []proto
 		lastMatchIndex = matchIndex
 		lastLineNumber = lineNumber
 		matches = appendMatches(matches, fileBuf[lineStart:lineEnd], fileMatchBuf[lineStart:lineEnd], lineNumber, start-lineStart, end-lineStart)
+		if rg.multiline {
+			multilineMatches = append(multilineMatches, multilineMatch(fileBuf, lineStart, lineEnd, lineNumber, start, end))
+		}
 
 		if len(matches) > maxLineMatches {
 			matches = matches[:maxLineMatches]
@@ -224,7 +249,30 @@ func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []proto
 			break
 		}
 	}
-	return matches, limitHit, nil
+	return matches, multilineMatches, limitHit, nil
+}
+
+// multilineMatch returns the match of fileBuf[start:end], which starts on the
+// line with the given number and spans the lines in fileBuf[lineStart:lineEnd].
+func multilineMatch(fileBuf []byte, lineStart, lineEnd, lineNumber, start, end int) protocol.MultilineMatch {
+	endLineNumber := lineNumber + bytes.Count(fileBuf[start:end], []byte{'\n'})
+	endLineStart := lineStart
+	if idx := bytes.LastIndexByte(fileBuf[lineStart:end], '\n'); idx >= 0 {
+		endLineStart = lineStart + idx + 1
+	}
+	return protocol.MultilineMatch{
+		// Copy the preview because fileBuf can't be used after the ZipFile
+		// has been closed (see appendMatches).
+		Preview: string(fileBuf[lineStart:lineEnd]),
+		Start: protocol.Position{
+			Line:      lineNumber,
+			Character: utf8.RuneCount(fileBuf[lineStart:start]),
+		},
+		End: protocol.Position{
+			Line:      endLineNumber,
+			Character: utf8.RuneCount(fileBuf[endLineStart:end]),
+		},
+	}
 }
 
 func hydrateLineNumbers(fileBuf []byte, lastLineNumber, lastMatchIndex, lineStart int, match []int) (lineNumber, matchIndex int) {
@@ -285,12 +333,46 @@ func appendMatches(matches []protocol.LineMatch, fileBuf []byte, matchLineBuf []
 
 // FindZip is a convenience function to run Find on f.
 func (rg *readerGrep) FindZip(zf *store.ZipFile, f *store.SrcFile) (protocol.FileMatch, error) {
-	lm, limitHit, err := rg.Find(zf, f)
-	return protocol.FileMatch{
-		Path:        f.Name,
-		LineMatches: lm,
-		LimitHit:    limitHit,
-	}, err
+	lm, mm, limitHit, err := rg.Find(zf, f)
+	fm := protocol.FileMatch{
+		Path:             f.Name,
+		LineMatches:      lm,
+		MultilineMatches: mm,
+		LimitHit:         limitHit,
+	}
+	if len(lm) > 0 {
+		fileBuf := zf.DataFor(f)
+		fm.ContextRanges = rg.contextRanges(fileBuf, lm)
+		fm.Blob = gitBlobID(fileBuf)
+	}
+	return fm, err
+}
+
+// gitBlobID returns the object ID that Git assigns to a blob with the given
+// content.
+func gitBlobID(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	_, _ = h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// contextRanges returns the lines of fileBuf around the line matches lm, or
+// nil if no context lines were requested.
+func (rg *readerGrep) contextRanges(fileBuf []byte, lm []protocol.LineMatch) []protocol.ContextRange {
+	if len(lm) == 0 || (rg.contextBefore <= 0 && rg.contextAfter <= 0) {
+		return nil
+	}
+	lines := make([]int, len(lm))
+	for i, m := range lm {
+		lines[i] = m.LineNumber
+	}
+	ranges := contextlines.Ranges(fileBuf, lines, rg.contextBefore, rg.contextAfter)
+	res := make([]protocol.ContextRange, len(ranges))
+	for i, r := range ranges {
+		res[i] = protocol.ContextRange{StartLine: r.StartLine, Preview: r.Preview}
+	}
+	return res
 }
 
 // regexSearch concurrently searches files in zr looking for matches using rg.
@@ -595,3 +595,136 @@ func Test_regexSearch(t *testing.T) {
 		})
 	}
 }
+
+func TestFindMultiline(t *testing.T) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "main.go", Method: zip.Store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("package main\n\nfunc main() {\n\tfmt.Println(\"héllo\")\n}\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zf, err := store.MockZipFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pattern string
+		want    []protocol.MultilineMatch
+	}{
+		{pattern: `main\(\)`},
+		{pattern: `\s+fmt`},
+		{
+			pattern: `\{\n\s*fmt`,
+			want: []protocol.MultilineMatch{{
+				Preview: "func main() {\n\tfmt.Println(\"héllo\")",
+				Start:   protocol.Position{Line: 2, Character: 12},
+				End:     protocol.Position{Line: 3, Character: 4},
+			}},
+		},
+		{
+			pattern: `(?s)h.llo.*\}`,
+			want: []protocol.MultilineMatch{{
+				Preview: "\tfmt.Println(\"héllo\")\n}",
+				Start:   protocol.Position{Line: 3, Character: 14},
+				End:     protocol.Position{Line: 4, Character: 1},
+			}},
+		},
+		{
+			pattern: `MAIN\n`,
+			want: []protocol.MultilineMatch{{
+				Preview: "package main\n",
+				Start:   protocol.Position{Line: 0, Character: 8},
+				End:     protocol.Position{Line: 1, Character: 0},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			rg, err := compile(&protocol.PatternInfo{Pattern: tt.pattern, IsRegExp: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			fm, err := rg.FindZip(zf, &zf.Files[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fm.LineMatches) == 0 {
+				t.Fatal("got no line matches")
+			}
+			if !reflect.DeepEqual(fm.MultilineMatches, tt.want) {
+				t.Errorf("got %+v, want %+v", fm.MultilineMatches, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindContext(t *testing.T) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "main.go", Method: zip.Store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zf, err := store.MockZipFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		p    protocol.PatternInfo
+		want []protocol.ContextRange
+	}{
+		{p: protocol.PatternInfo{Pattern: "fmt"}},
+		{
+			p: protocol.PatternInfo{Pattern: "fmt", ContextBefore: 1},
+			want: []protocol.ContextRange{
+				{StartLine: 1, Preview: "\nimport \"fmt\""},
+				{StartLine: 4, Preview: "func main() {\n\tfmt.Println(\"hello\")"},
+			},
+		},
+		{
+			// The ranges around both matches touch, so they are merged.
+			p: protocol.PatternInfo{Pattern: "fmt", ContextBefore: 1, ContextAfter: 1},
+			want: []protocol.ContextRange{
+				{StartLine: 1, Preview: "\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}"},
+			},
+		},
+		{
+			p: protocol.PatternInfo{Pattern: "fmt", ContextAfter: 2},
+			want: []protocol.ContextRange{
+				{StartLine: 2, Preview: "import \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.p.String(), func(t *testing.T) {
+			rg, err := compile(&tt.p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fm, err := rg.FindZip(zf, &zf.Files[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(fm.ContextRanges, tt.want) {
+				t.Errorf("got %+v, want %+v", fm.ContextRanges, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitBlobID(t *testing.T) {
+	// $ printf 'hello\n' | git hash-object --stdin
+	if got, want := gitBlobID([]byte("hello\n")), "ce013625030ba8dba906f756967f9e9ca394464a"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
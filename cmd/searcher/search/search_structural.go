@@ -179,7 +179,10 @@ func languageMetric(matcher string, includePatterns *[]string) string {
 	return "inferred:.generic"
 }
 
-func structuralSearch(ctx context.Context, zipPath, pattern, rule string, languages, includePatterns []string, repo api.RepoName) (matches []protocol.FileMatch, limitHit bool, err error) {
+// structuralSearch runs comby over the files of the archive at zipPath. If
+// filePaths is non-empty, only the files with these paths are searched, and
+// includePatterns are ignored.
+func structuralSearch(ctx context.Context, zipPath, pattern, rule string, languages, includePatterns, filePaths []string, repo api.RepoName) (matches []protocol.FileMatch, limitHit bool, err error) {
 	log15.Info("structural search", "repo", string(repo))
 
 	// Cap the number of forked processes to limit the size of zip contents being mapped to memory. Resolving #7133 could help to lift this restriction.
@@ -193,6 +196,17 @@ func structuralSearch(ctx context.Context, zipPath, pattern, rule string, langua
 		log15.Debug("structural search", "language", languages[0], "matcher", matcher)
 	}
 
+	var wantPaths map[string]struct{}
+	if len(filePaths) > 0 {
+		// Comby only supports filtering files by suffix, so we also pass
+		// the paths as suffixes and drop matches in other files below.
+		wantPaths = make(map[string]struct{}, len(filePaths))
+		for _, path := range filePaths {
+			wantPaths[path] = struct{}{}
+		}
+		includePatterns = filePathSuffixes(filePaths)
+	}
+
 	v := languageMetric(matcher, &includePatterns)
 	requestTotalStructuralSearch.WithLabelValues(v).Inc()
 
@@ -210,6 +224,15 @@ func structuralSearch(ctx context.Context, zipPath, pattern, rule string, langua
 	if err != nil {
 		return nil, false, err
 	}
+	if wantPaths != nil {
+		filtered := combyMatches[:0]
+		for _, m := range combyMatches {
+			if _, ok := wantPaths[m.URI]; ok {
+				filtered = append(filtered, m)
+			}
+		}
+		combyMatches = filtered
+	}
 
 	matches = ToFileMatch(combyMatches)
 	if err != nil {
@@ -218,6 +241,18 @@ func structuralSearch(ctx context.Context, zipPath, pattern, rule string, langua
 	return matches, false, err
 }
 
+// filePathSuffixes returns the comby file patterns (suffixes) that select the
+// files with the given paths, or nil if that is not possible because comby
+// separates file patterns with commas.
+func filePathSuffixes(paths []string) []string {
+	for _, path := range paths {
+		if strings.Contains(path, ",") {
+			return nil
+		}
+	}
+	return paths
+}
+
 var requestTotalStructuralSearch = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Namespace: "searcher",
 	Subsystem: "service",
@@ -67,7 +67,7 @@ func foo(go string) {}
 	for _, tt := range cases {
 		t.Run(tt.Name, func(t *testing.T) {
 			p.Languages = tt.Languages
-			matches, _, err := structuralSearch(context.Background(), zf, p.Pattern, p.CombyRule, p.Languages, p.IncludePatterns, "repo_foo")
+			matches, _, err := structuralSearch(context.Background(), zf, p.Pattern, p.CombyRule, p.Languages, p.IncludePatterns, p.FilePaths, "repo_foo")
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -119,7 +119,7 @@ func foo(real string) {}
 		Pattern:         pattern,
 		IncludePatterns: includePatterns,
 	}
-	m, _, err := structuralSearch(context.Background(), zf, p.Pattern, p.CombyRule, p.Languages, p.IncludePatterns, "foo")
+	m, _, err := structuralSearch(context.Background(), zf, p.Pattern, p.CombyRule, p.Languages, p.IncludePatterns, p.FilePaths, "foo")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -219,7 +219,7 @@ func TestIncludePatterns(t *testing.T) {
 		Pattern:         "",
 		IncludePatterns: includePatterns,
 	}
-	fileMatches, _, err := structuralSearch(context.Background(), zf, p.Pattern, p.CombyRule, p.Languages, p.IncludePatterns, "foo")
+	fileMatches, _, err := structuralSearch(context.Background(), zf, p.Pattern, p.CombyRule, p.Languages, p.IncludePatterns, p.FilePaths, "foo")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -260,7 +260,7 @@ func TestRule(t *testing.T) {
 		CombyRule:       `where :[args] == "success"`,
 	}
 
-	got, _, err := structuralSearch(context.Background(), zf, p.Pattern, p.CombyRule, p.Languages, p.IncludePatterns, "repo")
+	got, _, err := structuralSearch(context.Background(), zf, p.Pattern, p.CombyRule, p.Languages, p.IncludePatterns, p.FilePaths, "repo")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -285,6 +285,55 @@ func TestRule(t *testing.T) {
 
 }
 
+func TestStructuralSearchFilePaths(t *testing.T) {
+	// If we are not on CI skip the test.
+	if os.Getenv("CI") == "" {
+		t.Skip("Not on CI, skipping comby-dependent test")
+	}
+
+	input := map[string]string{
+		"a/main.go":   "func foo(a string) {}",
+		"b/a/main.go": "func foo(b string) {}",
+		"c/main.go":   "func foo(c string) {}",
+	}
+
+	zipData, err := testutil.CreateZip(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zf, cleanup, err := testutil.TempZipFileOnDisk(zipData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	p := &protocol.PatternInfo{
+		Pattern:   "foo(:[args])",
+		FilePaths: []string{"a/main.go", "c/main.go"},
+	}
+	matches, _, err := structuralSearch(context.Background(), zf, p.Pattern, p.CombyRule, p.Languages, p.IncludePatterns, p.FilePaths, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.Path)
+	}
+	sort.Strings(got)
+	if want := []string{"a/main.go", "c/main.go"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got file matches %v, want %v", got, want)
+	}
+}
+
+func TestFilePathSuffixes(t *testing.T) {
+	if got, want := filePathSuffixes([]string{"a/main.go", "b.go"}), []string{"a/main.go", "b.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := filePathSuffixes([]string{"a/main.go", "a,b.go"}); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
 func TestHighlightMultipleLines(t *testing.T) {
 	cases := []struct {
 		Name  string
@@ -100,6 +100,9 @@ type ConfigSavedQuery struct {
 	UserID          *int32  `json:"userID"`
 	OrgID           *int32  `json:"orgID"`
 	SlackWebhookURL *string `json:"slackWebhookURL"`
+	NotifyWebhook   bool    `json:"notifyWebhook,omitempty"`
+	WebhookURL      *string `json:"webhookURL"`
+	WebhookSecret   *string `json:"webhookSecret,omitempty"`
 }
 
 func (sq ConfigSavedQuery) Equals(other ConfigSavedQuery) bool {
@@ -172,6 +175,71 @@ func (c *internalClient) SavedQueriesDeleteInfo(ctx context.Context, query strin
 	return c.postInternal(ctx, "saved-queries/delete-info", query, nil)
 }
 
+// SavedQueryResult is the fingerprint of a single search result found by a
+// saved query. Two results with equal fingerprints are considered the same
+// result across runs of the saved query.
+type SavedQueryResult struct {
+	// Repo is the name of the repository the result was found in.
+	Repo string `json:"repo"`
+
+	// Path is the path of the matched file, if the result is a file match.
+	Path string `json:"path,omitempty"`
+
+	// LineHash is the hex-encoded SHA-256 hash of the matched line's
+	// content, if the result is a line match.
+	LineHash string `json:"lineHash,omitempty"`
+
+	// Commit is the OID of the matched commit, if the result is a commit or
+	// diff match.
+	Commit string `json:"commit,omitempty"`
+}
+
+// SavedQueryRun represents a single execution of a saved query.
+type SavedQueryRun struct {
+	// Key is the key of the saved query that was executed.
+	Key string
+
+	// Query is the search query that was executed.
+	Query string
+
+	// ExecutedAt is the time at which the search query was executed.
+	ExecutedAt time.Time
+
+	// ExecDuration is the amount of time it took for the query to execute.
+	ExecDuration time.Duration
+
+	// ResultCount is the number of results found.
+	ResultCount int
+
+	// LimitHit is whether the search hit its result limit, so that Results
+	// may be incomplete.
+	LimitHit bool
+
+	// Results are the fingerprints of all results found. They are compared
+	// against the next run's results to find new and removed matches.
+	Results []SavedQueryResult
+
+	// Added and Removed are the results which appeared and disappeared since
+	// the previous run.
+	Added, Removed []SavedQueryResult
+}
+
+// SavedQueriesGetLatestRun gets the most recent run of the saved query with
+// the given key. nil is returned if the saved query has never been run.
+func (c *internalClient) SavedQueriesGetLatestRun(ctx context.Context, key string) (*SavedQueryRun, error) {
+	var result *SavedQueryRun
+	err := c.postInternal(ctx, "saved-queries/get-latest-run", key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SavedQueriesAddRun records a run of a saved query.
+func (c *internalClient) SavedQueriesAddRun(ctx context.Context, run *SavedQueryRun) error {
+	return c.postInternal(ctx, "saved-queries/add-run", run, nil)
+}
+
 func (c *internalClient) SettingsGetForSubject(ctx context.Context, subject SettingsSubject) (parsed *schema.Settings, settings *Settings, err error) {
 	err = c.postInternal(ctx, "settings/get-for-subject", subject, &settings)
 	if err == nil {
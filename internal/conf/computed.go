@@ -291,6 +291,16 @@ func SearchSymbolsParallelism() int {
 	return val
 }
 
+// GitServerReplicationFactor returns the number of gitservers each repository
+// is cloned on, which is 1 unless "gitServerReplicationFactor" is configured.
+func GitServerReplicationFactor() int {
+	val := Get().GitServerReplicationFactor
+	if val < 1 {
+		return 1
+	}
+	return val
+}
+
 func PermissionsBackgroundSyncEnabled() bool {
 	val := Get().PermissionsBackgroundSync
 	if val == nil {
@@ -320,6 +330,10 @@ func StructuralSearchEnabled() bool {
 	return val == "enabled"
 }
 
+func AndOrQueryEnabled() bool {
+	return ExperimentalFeatures().AndOrQuery == "enabled"
+}
+
 func SearchMultipleRevisionsPerRepository() bool {
 	x := ExperimentalFeatures()
 	return x.SearchMultipleRevisionsPerRepository != nil && *x.SearchMultipleRevisionsPerRepository
@@ -0,0 +1,45 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type Gerrit struct {
+	*schema.GerritConnection
+}
+
+var _ RepoSource = Gerrit{}
+
+func (c Gerrit) CloneURLToRepoName(cloneURL string) (repoName api.RepoName, err error) {
+	parsedCloneURL, baseURL, match, err := parseURLs(cloneURL, c.Url)
+	if err != nil {
+		return "", err
+	}
+	if !match {
+		return "", nil
+	}
+
+	path := parsedCloneURL.Path
+	if strings.HasPrefix(strings.TrimPrefix(parsedCloneURL.Scheme, "git+"), "http") {
+		// HTTP clone URLs include the path Gerrit is served under, and
+		// authenticated ones are prefixed with /a/.
+		path = strings.TrimPrefix(path, baseURL.Path)
+		path = strings.TrimPrefix(path, "a/")
+	}
+	name := strings.Trim(strings.TrimSuffix(path, ".git"), "/")
+	return GerritRepoName(c.RepositoryPathPattern, baseURL.Hostname(), name), nil
+}
+
+func GerritRepoName(repositoryPathPattern, host, name string) api.RepoName {
+	if repositoryPathPattern == "" {
+		repositoryPathPattern = "{host}/{name}"
+	}
+
+	return api.RepoName(strings.NewReplacer(
+		"{host}", host,
+		"{name}", name,
+	).Replace(repositoryPathPattern))
+}
@@ -226,6 +226,12 @@ type Repo struct {
 	Parent      *Repo  `json:"parent"`
 	IsPrivate   bool   `json:"is_private"`
 	Links       Links  `json:"links"`
+	Language    string `json:"language"`
+	MainBranch  *Ref   `json:"mainbranch,omitempty"`
+}
+
+type Ref struct {
+	Name string `json:"name"`
 }
 
 type Links struct {
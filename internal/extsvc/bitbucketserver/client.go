@@ -647,6 +647,13 @@ func (c *Client) Repos(ctx context.Context, pageToken *PageToken, searchQueries
 	return repos, next, err
 }
 
+// Labels lists the labels of the Bitbucket Server instance.
+func (c *Client) Labels(ctx context.Context, pageToken *PageToken) ([]*Label, *PageToken, error) {
+	var labels []*Label
+	next, err := c.page(ctx, "rest/api/1.0/labels", nil, pageToken, &labels)
+	return labels, next, err
+}
+
 func (c *Client) LabeledRepos(ctx context.Context, pageToken *PageToken, label string) ([]*Repo, *PageToken, error) {
 	u := fmt.Sprintf("rest/api/1.0/labels/%s/labeled", label)
 	qry := url.Values{
@@ -995,6 +1002,11 @@ func (r *Repo) IsPersonalRepository() bool {
 	return r.Project.Type == "PERSONAL"
 }
 
+// Label is a label which can be added to repositories.
+type Label struct {
+	Name string `json:"name"`
+}
+
 type Project struct {
 	Key    string `json:"key"`
 	ID     int    `json:"id"`
@@ -0,0 +1,195 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+)
+
+var requestCounter = metrics.NewRequestMeter("gerrit_requests_count", "Total number of requests sent to the Gerrit API.")
+
+// PerPage is the number of projects requested per page.
+const PerPage = 100
+
+// Client access a Gerrit instance via its REST API.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of Gerrit.
+	URL *url.URL
+
+	// The username and HTTP password credentials for accessing the server.
+	// If empty, the client only sees projects visible to anonymous users.
+	Username, Password string
+}
+
+// NewClient creates a new Gerrit API client with the given base URL, which
+// must end in a slash. If a nil httpClient is provided, http.DefaultClient
+// will be used.
+func NewClient(baseURL *url.URL, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The first component of the Path after the optional /a/ prefix
+		// of authenticated requests maps to the type of API request.
+		var category string
+		if parts := strings.Split(strings.TrimPrefix(u.Path, "/a/"), "/"); len(parts) > 0 {
+			category = parts[0]
+		}
+		return category
+	})
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+	}
+}
+
+// Projects returns the projects at offset skip of the list of all projects
+// visible to the client, in name order. The returned bool is true if there
+// are more projects.
+func (c *Client) Projects(ctx context.Context, skip int) ([]*Project, bool, error) {
+	qry := url.Values{
+		"d": {""},
+		"n": {strconv.Itoa(PerPage)},
+		"S": {strconv.Itoa(skip)},
+	}
+
+	var byName map[string]*Project
+	if err := c.get(ctx, "projects/", qry, &byName); err != nil {
+		return nil, false, err
+	}
+
+	projects := make([]*Project, 0, len(byName))
+	more := false
+	for name, p := range byName {
+		// Lists are keyed by project name, which the entries omit.
+		p.Name = name
+		more = more || p.MoreProjects
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects, more, nil
+}
+
+// Project returns the project named name.
+func (c *Client) Project(ctx context.Context, name string) (*Project, error) {
+	var p Project
+	if err := c.get(ctx, "projects/"+url.PathEscape(name), nil, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// xssiPrefix is the line Gerrit prepends to all JSON responses to prevent
+// them from being included as scripts.
+var xssiPrefix = []byte(")]}'")
+
+func (c *Client) get(ctx context.Context, path string, qry url.Values, result interface{}) error {
+	// Authenticated requests go to the same endpoints under /a/.
+	if c.Username != "" {
+		path = "a/" + path
+	}
+	// path is escaped already.
+	u, err := url.Parse(path)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = qry.Encode()
+	req, err := http.NewRequest("GET", c.URL.ResolveReference(u).String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	req, ht := nethttp.TraceRequest(opentracing.GlobalTracer(),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gerrit"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	return json.Unmarshal(bytes.TrimPrefix(bs, xssiPrefix), result)
+}
+
+// Project is a Gerrit project, which contains a Git repository.
+type Project struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Parent      string    `json:"parent,omitempty"`
+	Description string    `json:"description,omitempty"`
+	State       string    `json:"state,omitempty"`
+	WebLinks    []WebLink `json:"web_links,omitempty"`
+
+	// MoreProjects is set on the last project of a list if there are
+	// more projects.
+	MoreProjects bool `json:"_more_projects,omitempty"`
+}
+
+// Project states. Projects without a state are active.
+const (
+	ProjectStateActive   = "ACTIVE"
+	ProjectStateReadOnly = "READ_ONLY"
+	ProjectStateHidden   = "HIDDEN"
+)
+
+// WebLink is a link to a project on a code browser such as gitiles.
+type WebLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gerrit API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
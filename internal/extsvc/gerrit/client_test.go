@@ -0,0 +1,73 @@
+package gerrit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_Projects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case "/a/projects/":
+			if r.URL.Query().Get("S") != "0" {
+				_, _ = w.Write([]byte(")]}'\n{}"))
+				return
+			}
+			_, _ = w.Write([]byte(`)]}'
+{
+  "tools/gerrit": {"id": "tools%2Fgerrit", "state": "ACTIVE", "_more_projects": true},
+  "All-Projects": {"id": "All-Projects", "description": "Access inherited by all other projects.", "state": "ACTIVE"}
+}`))
+		case "/a/projects/tools%2Fgerrit":
+			_, _ = w.Write([]byte(")]}'\n" + `{"id": "tools%2Fgerrit", "name": "tools/gerrit", "parent": "All-Projects", "state": "READ_ONLY"}`))
+		default:
+			http.Error(w, r.URL.Path+" not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL + "/")
+	c := NewClient(u, nil)
+	c.Username, c.Password = "alice", "secret"
+
+	projects, more, err := c.Projects(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Project{
+		{ID: "All-Projects", Name: "All-Projects", Description: "Access inherited by all other projects.", State: ProjectStateActive},
+		{ID: "tools%2Fgerrit", Name: "tools/gerrit", State: ProjectStateActive, MoreProjects: true},
+	}
+	if diff := cmp.Diff(want, projects); diff != "" {
+		t.Errorf("projects mismatch (-want +got):\n%s", diff)
+	}
+	if !more {
+		t.Error("got no more projects, want more")
+	}
+
+	if projects, more, err = c.Projects(context.Background(), 2); err != nil || more || len(projects) != 0 {
+		t.Errorf("got %d projects, more %v and error %v for the last page", len(projects), more, err)
+	}
+
+	p, err := c.Project(context.Background(), "tools/gerrit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "tools/gerrit" || p.State != ProjectStateReadOnly {
+		t.Errorf("got project %+v", p)
+	}
+
+	c.Password = "wrong"
+	if _, _, err := c.Projects(context.Background(), 0); err == nil {
+		t.Error("got no error with wrong credentials")
+	}
+}
@@ -0,0 +1,202 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+)
+
+var requestCounter = metrics.NewRequestMeter("gitea_requests_count", "Total number of requests sent to the Gitea API.")
+
+// PerPage is the number of repositories requested per page. Gitea caps it at
+// its configured maximum number of items per response.
+const PerPage = 50
+
+// Client access a Gitea instance via the REST API v1.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of Gitea.
+	URL *url.URL
+
+	// Token is the personal access token for accessing the server. If empty,
+	// the client only sees public repositories.
+	Token string
+}
+
+// NewClient creates a new Gitea API client with the given base URL, which must
+// end in a slash. If a nil httpClient is provided, http.DefaultClient will be
+// used.
+func NewClient(baseURL *url.URL, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The third component of the Path (/api/v1/{category}) mostly maps
+		// to the type of API request we are making.
+		var category string
+		if parts := strings.SplitN(u.Path, "/", 5); len(parts) > 3 {
+			category = parts[3]
+		}
+		return category
+	})
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+	}
+}
+
+// UserRepos returns the given page (starting at 1) of the repositories the
+// authenticated user owns or has access to. The returned bool is true if
+// there may be more pages.
+func (c *Client) UserRepos(ctx context.Context, page int) ([]*Repo, bool, error) {
+	var repos []*Repo
+	err := c.get(ctx, "user/repos", pageValues(page), &repos)
+	return repos, len(repos) > 0, err
+}
+
+// OrgRepos returns the given page (starting at 1) of the repositories of the
+// organization org. The returned bool is true if there may be more pages.
+func (c *Client) OrgRepos(ctx context.Context, org string, page int) ([]*Repo, bool, error) {
+	var repos []*Repo
+	err := c.get(ctx, "orgs/"+url.PathEscape(org)+"/repos", pageValues(page), &repos)
+	return repos, len(repos) > 0, err
+}
+
+// SearchRepos returns the given page (starting at 1) of the repositories
+// matching query. The returned bool is true if there may be more pages.
+func (c *Client) SearchRepos(ctx context.Context, query string, page int) ([]*Repo, bool, error) {
+	qry := pageValues(page)
+	qry.Set("q", query)
+
+	var result struct {
+		OK   bool    `json:"ok"`
+		Data []*Repo `json:"data"`
+	}
+	if err := c.get(ctx, "repos/search", qry, &result); err != nil {
+		return nil, false, err
+	}
+	if !result.OK {
+		return nil, false, errors.Errorf("Gitea repository search for %q failed", query)
+	}
+	return result.Data, len(result.Data) > 0, nil
+}
+
+// Repo returns the repository named owner/name.
+func (c *Client) Repo(ctx context.Context, owner, name string) (*Repo, error) {
+	var repo Repo
+	if err := c.get(ctx, "repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name), nil, &repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// pageValues returns the query parameters requesting the given page. Gitea
+// caps page sizes at its configured maximum, so the only reliable end of a
+// list is an empty page.
+func pageValues(page int) url.Values {
+	return url.Values{
+		"page":  {strconv.Itoa(page)},
+		"limit": {strconv.Itoa(PerPage)},
+	}
+}
+
+func (c *Client) get(ctx context.Context, path string, qry url.Values, result interface{}) error {
+	// path is escaped already.
+	u, err := url.Parse("api/v1/" + path)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = qry.Encode()
+	req, err := http.NewRequest("GET", c.URL.ResolveReference(u).String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+
+	req, ht := nethttp.TraceRequest(opentracing.GlobalTracer(),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gitea"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	return json.Unmarshal(bs, result)
+}
+
+// Repo is a Gitea repository.
+type Repo struct {
+	ID            int64  `json:"id"`
+	Owner         User   `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Description   string `json:"description"`
+	Empty         bool   `json:"empty"`
+	Private       bool   `json:"private"`
+	Fork          bool   `json:"fork"`
+	Mirror        bool   `json:"mirror"`
+	Archived      bool   `json:"archived"`
+	HTMLURL       string `json:"html_url"`
+	SSHURL        string `json:"ssh_url"`
+	CloneURL      string `json:"clone_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// User is a Gitea user or organization.
+type User struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gitea API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
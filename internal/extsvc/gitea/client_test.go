@@ -0,0 +1,75 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_OrgRepos(t *testing.T) {
+	var gotAuth, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gitea/api/v1/orgs/my org/repos" {
+			http.Error(w, r.URL.Path+" not found", http.StatusNotFound)
+			return
+		}
+		gotAuth, gotQuery = r.Header.Get("Authorization"), r.URL.RawQuery
+		if r.URL.Query().Get("page") != "1" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{
+			"id": 1,
+			"owner": {"id": 2, "login": "my org"},
+			"name": "foo",
+			"full_name": "my org/foo",
+			"private": true,
+			"html_url": "https://gitea.example.com/my%20org/foo",
+			"clone_url": "https://gitea.example.com/my%20org/foo.git",
+			"default_branch": "main"
+		}]`))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL + "/gitea/")
+	c := NewClient(u, nil)
+	c.Token = "secret"
+
+	repos, more, err := c.OrgRepos(context.Background(), "my org", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Repo{{
+		ID:            1,
+		Owner:         User{ID: 2, Login: "my org"},
+		Name:          "foo",
+		FullName:      "my org/foo",
+		Private:       true,
+		HTMLURL:       "https://gitea.example.com/my%20org/foo",
+		CloneURL:      "https://gitea.example.com/my%20org/foo.git",
+		DefaultBranch: "main",
+	}}
+	if diff := cmp.Diff(want, repos); diff != "" {
+		t.Errorf("repos mismatch (-want +got):\n%s", diff)
+	}
+	if !more {
+		t.Error("got no more pages after a full page")
+	}
+	if gotAuth != "token secret" || gotQuery != "limit=50&page=1" {
+		t.Errorf("got Authorization %q and query %q", gotAuth, gotQuery)
+	}
+
+	if repos, more, err = c.OrgRepos(context.Background(), "my org", 2); err != nil || more || len(repos) != 0 {
+		t.Errorf("got %d repos, more %v and error %v for the last page", len(repos), more, err)
+	}
+
+	if _, _, err = c.OrgRepos(context.Background(), "other", 1); err == nil {
+		t.Error("got no error for a missing organization")
+	} else if e, ok := err.(interface{ NotFound() bool }); ok && !e.NotFound() {
+		t.Errorf("got error %v, want not found", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -35,6 +36,55 @@ type Repository struct {
 	IsFork           bool   // whether the repository is a fork of another repository
 	IsArchived       bool   // whether the repository is archived on the code host
 	ViewerPermission string // ADMIN, WRITE, READ, or empty if unknown. Only the graphql api populates this. https://developer.github.com/v4/enum/repositorypermission/
+
+	PrimaryLanguage  *Language        // primary language of the repository, if detected
+	DefaultBranchRef *Ref             // default branch of the repository, nil if it is empty
+	Stargazers       Stargazers       // users who starred the repository
+	RepositoryTopics RepositoryTopics // topics of the repository
+}
+
+// Language is a programming language used in a repository.
+type Language struct {
+	Name string
+}
+
+// Ref is a git reference of a repository.
+type Ref struct {
+	Name string
+}
+
+// Stargazers is the connection of users who starred a repository. Only its
+// total count is requested.
+type Stargazers struct {
+	TotalCount int
+}
+
+// RepositoryTopics is the connection of topics of a repository.
+type RepositoryTopics struct {
+	Nodes []RepositoryTopic
+}
+
+// RepositoryTopic is a topic applied to a repository.
+type RepositoryTopic struct {
+	Topic Topic
+}
+
+// Topic is a GitHub topic.
+type Topic struct {
+	Name string
+}
+
+// Topics returns the names of the repository's topics.
+func (r *Repository) Topics() []string {
+	if len(r.RepositoryTopics.Nodes) == 0 {
+		return nil
+	}
+
+	topics := make([]string, 0, len(r.RepositoryTopics.Nodes))
+	for _, n := range r.RepositoryTopics.Nodes {
+		topics = append(topics, n.Topic.Name)
+	}
+	return topics
 }
 
 // repositoryFieldsGraphQLFragment returns a GraphQL fragment that contains the fields needed to populate the
@@ -52,6 +102,10 @@ fragment RepositoryFields on Repository {
 	isFork
 	isArchived
 	viewerPermission
+	primaryLanguage { name }
+	defaultBranchRef { name }
+	stargazers { totalCount }
+	repositoryTopics(first: 100) { nodes { topic { name } } }
 }
 	`
 	}
@@ -68,6 +122,10 @@ fragment RepositoryFields on Repository {
 	isPrivate
 	isFork
 	isArchived
+	primaryLanguage { name }
+	defaultBranchRef { name }
+	stargazers { totalCount }
+	repositoryTopics(first: 100) { nodes { topic { name } } }
 }
 	`
 }
@@ -252,6 +310,12 @@ type restRepository struct {
 	Fork        bool
 	Archived    bool
 	Permissions restRepositoryPermissions `json:"permissions"`
+	PushedAt    time.Time                 `json:"pushed_at"`
+
+	Language        string
+	DefaultBranch   string   `json:"default_branch"`
+	StargazersCount int      `json:"stargazers_count"`
+	Topics          []string `json:"topics"` // only returned with the mercy-preview media type
 }
 
 // getRepositoryFromAPI attempts to fetch a repository from the GitHub API without use of the redis cache.
@@ -273,7 +337,7 @@ func (c *Client) getRepositoryFromAPI(ctx context.Context, owner, name string) (
 // convertRestRepo converts repo information returned by the rest API
 // to a standard format.
 func convertRestRepo(restRepo restRepository) *Repository {
-	return &Repository{
+	repo := &Repository{
 		ID:               restRepo.ID,
 		DatabaseID:       restRepo.DatabaseID,
 		NameWithOwner:    restRepo.FullName,
@@ -284,6 +348,22 @@ func convertRestRepo(restRepo restRepository) *Repository {
 		IsArchived:       restRepo.Archived,
 		ViewerPermission: convertRestRepoPermissions(restRepo.Permissions),
 	}
+
+	if restRepo.Language != "" {
+		repo.PrimaryLanguage = &Language{Name: restRepo.Language}
+	}
+
+	if restRepo.DefaultBranch != "" {
+		repo.DefaultBranchRef = &Ref{Name: restRepo.DefaultBranch}
+	}
+
+	repo.Stargazers.TotalCount = restRepo.StargazersCount
+
+	for _, t := range restRepo.Topics {
+		repo.RepositoryTopics.Nodes = append(repo.RepositoryTopics.Nodes, RepositoryTopic{Topic: Topic{Name: t}})
+	}
+
+	return repo
 }
 
 // convertRestRepoPermissions converts repo information returned by the rest API
@@ -507,6 +587,53 @@ func (c *Client) ListUserRepositories(ctx context.Context, user string, page int
 	return repos, len(repos) > 0, 1, err
 }
 
+// ListAffiliatedRepositoriesPushedSince is like ListAffiliatedRepositories,
+// but only lists the repositories that were pushed to at or after since.
+func (c *Client) ListAffiliatedRepositoriesPushedSince(ctx context.Context, since time.Time, page int) (repos []*Repository, hasNextPage bool, rateLimitCost int, err error) {
+	path := fmt.Sprintf("user/repos?sort=pushed&direction=desc&page=%d&per_page=100", page)
+	repos, hasNextPage, err = c.listRepositoriesPushedSince(ctx, path, since)
+	if err == nil {
+		// 🚨 SECURITY: must forward token here to ensure caching by token
+		c.addRepositoriesToCache("", repos)
+	}
+	return repos, hasNextPage, 1, err
+}
+
+// ListOrgRepositoriesPushedSince is like ListOrgRepositories, but only lists
+// the repositories that were pushed to at or after since.
+func (c *Client) ListOrgRepositoriesPushedSince(ctx context.Context, org string, since time.Time, page int) (repos []*Repository, hasNextPage bool, rateLimitCost int, err error) {
+	path := fmt.Sprintf("orgs/%s/repos?sort=pushed&direction=desc&page=%d&per_page=100", org, page)
+	repos, hasNextPage, err = c.listRepositoriesPushedSince(ctx, path, since)
+	return repos, hasNextPage, 1, err
+}
+
+// ListUserRepositoriesPushedSince is like ListUserRepositories, but only lists
+// the repositories that were pushed to at or after since.
+func (c *Client) ListUserRepositoriesPushedSince(ctx context.Context, user string, since time.Time, page int) (repos []*Repository, hasNextPage bool, rateLimitCost int, err error) {
+	path := fmt.Sprintf("users/%s/repos?sort=pushed&direction=desc&type=owner&page=%d&per_page=100", user, page)
+	repos, hasNextPage, err = c.listRepositoriesPushedSince(ctx, path, since)
+	return repos, hasNextPage, 1, err
+}
+
+// listRepositoriesPushedSince lists the repositories returned by requestURI,
+// which must sort them by push date in descending order, up to the first
+// one that was last pushed to before since. There is no next page once such a
+// repository was found.
+func (c *Client) listRepositoriesPushedSince(ctx context.Context, requestURI string, since time.Time) ([]*Repository, bool, error) {
+	var restRepos []restRepository
+	if err := c.requestGet(ctx, "", requestURI, &restRepos); err != nil {
+		return nil, false, err
+	}
+	repos := make([]*Repository, 0, len(restRepos))
+	for _, restRepo := range restRepos {
+		if restRepo.PushedAt.Before(since) {
+			return repos, false, nil
+		}
+		repos = append(repos, convertRestRepo(restRepo))
+	}
+	return repos, len(restRepos) > 0, nil
+}
+
 type restSearchResponse struct {
 	TotalCount        int              `json:"total_count"`
 	IncompleteResults bool             `json:"incomplete_results"`
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/sergi/go-diff/diffmatchpatch"
@@ -414,6 +415,53 @@ func TestClient_ListOrgRepositories(t *testing.T) {
 	}
 }
 
+func TestClient_ListOrgRepositoriesPushedSince(t *testing.T) {
+	mock := mockHTTPResponseBody{
+		responseBody: `[
+  {
+    "node_id": "i",
+    "full_name": "o/r",
+    "html_url": "https://github.example.com/o/r",
+    "pushed_at": "2020-03-02T10:00:00Z"
+  },
+  {
+    "node_id": "j",
+    "full_name": "o/b",
+    "html_url": "https://github.example.com/o/b",
+    "pushed_at": "2020-02-28T10:00:00Z"
+  }
+]
+`}
+
+	c := newTestClient(t, &mock)
+	since := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	repos, hasNextPage, _, err := c.ListOrgRepositoriesPushedSince(context.Background(), "o", since, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRepos := []*Repository{
+		{
+			ID:            "i",
+			NameWithOwner: "o/r",
+			URL:           "https://github.example.com/o/r",
+		},
+	}
+	if !repoListsAreEqual(repos, wantRepos) {
+		t.Errorf("got repositories:\n%s\nwant:\n%s", stringForRepoList(repos), stringForRepoList(wantRepos))
+	}
+	if hasNextPage {
+		t.Errorf("got hasNextPage: true want: false")
+	}
+
+	if repos, hasNextPage, _, err = c.ListOrgRepositoriesPushedSince(context.Background(), "o", since.AddDate(0, 0, -7), 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 2 || !hasNextPage {
+		t.Errorf("got %d repositories and hasNextPage %v, want 2 and true", len(repos), hasNextPage)
+	}
+}
+
 func stringForRepoList(repos []*Repository) string {
 	repoStrings := []string{}
 	for _, repo := range repos {
@@ -427,7 +475,7 @@ func repoListsAreEqual(a []*Repository, b []*Repository) bool {
 		return false
 	}
 	for i := 0; i < len(a); i++ {
-		if *a[i] != *b[i] {
+		if !reflect.DeepEqual(a[i], b[i]) {
 			return false
 		}
 	}
@@ -27,6 +27,9 @@ type Project struct {
 	Visibility        Visibility     `json:"visibility"`                    // "private", "internal", or "public"
 	ForkedFromProject *ProjectCommon `json:"forked_from_project,omitempty"` // If non-nil, the project from which this project was forked
 	Archived          bool           `json:"archived"`
+	DefaultBranch     string         `json:"default_branch,omitempty"` // empty if the project has no commits
+	StarCount         int            `json:"star_count"`
+	TagList           []string       `json:"tag_list,omitempty"` // the project's topics
 }
 
 type ProjectCommon struct {
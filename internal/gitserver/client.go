@@ -9,10 +9,12 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,6 +30,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/endpoint"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
@@ -60,8 +63,9 @@ func NewClient(cli httpcli.Doer) *Client {
 		Addrs: func(ctx context.Context) []string {
 			return conf.Get().ServiceConnections.GitServers
 		},
-		HTTPClient:  cli,
-		HTTPLimiter: parallel.NewRun(500),
+		ReplicationFactor: conf.GitServerReplicationFactor,
+		HTTPClient:        cli,
+		HTTPLimiter:       parallel.NewRun(500),
 		// Use the binary name for UserAgent. This should effectively identify
 		// which service is making the request (excluding requests proxied via the
 		// frontend internal API)
@@ -82,6 +86,11 @@ type Client struct {
 	// concurrent use. It may return different results at different times.
 	Addrs func(ctx context.Context) []string
 
+	// ReplicationFactor is a function which should return the number of
+	// gitservers each repository is cloned on. If nil, or if it returns less
+	// than 2, every repository lives on exactly one gitserver.
+	ReplicationFactor func() int
+
 	// UserAgent is a string identifing who the client is. It will be logged in
 	// the telemetry in gitserver.
 	UserAgent string
@@ -109,6 +118,74 @@ func addrForKey(addrs []string, key string) string {
 	return addrs[serverIndex]
 }
 
+// AddrsForRepo returns the addresses of the gitservers which hold a replica of
+// the given repo name. The first address is the one returned by AddrForRepo.
+func (c *Client) AddrsForRepo(ctx context.Context, repo api.RepoName) []string {
+	repo = protocol.NormalizeRepo(repo) // in case the caller didn't already normalize it
+	addrs := c.Addrs(ctx)
+	if len(addrs) == 0 {
+		panic("unexpected state: no gitserver addresses")
+	}
+	return addrsForKey(addrs, string(repo), c.replicationFactor())
+}
+
+func (c *Client) replicationFactor() int {
+	if c.ReplicationFactor == nil {
+		return 1
+	}
+	return c.ReplicationFactor()
+}
+
+// addrsForKey returns the n addresses in addrs that key is replicated on.
+// The first address is always addrForKey(addrs, key), so that enabling
+// replication does not move any existing clones. The remaining replicas are
+// chosen by consistent hashing, so that adding or removing a gitserver only
+// moves the replicas which lived on it.
+func addrsForKey(addrs []string, key string, n int) []string {
+	primary := addrForKey(addrs, key)
+	if n > len(addrs) {
+		n = len(addrs)
+	}
+	if n <= 1 {
+		return []string{primary}
+	}
+
+	m := replicaMap(addrs)
+	replicas := make([]string, 1, n)
+	replicas[0] = primary
+	exclude := map[string]bool{primary: true}
+	for len(replicas) < n {
+		// Static maps never return an error.
+		addr, _ := m.Get(key, exclude)
+		if addr == "" {
+			break
+		}
+		replicas = append(replicas, addr)
+		exclude[addr] = true
+	}
+	return replicas
+}
+
+// replicaMaps caches the consistent hash map used by addrsForKey for the most
+// recently seen list of gitserver addresses, which rarely changes.
+var replicaMaps struct {
+	sync.Mutex
+	addrs string
+	m     *endpoint.Map
+}
+
+func replicaMap(addrs []string) *endpoint.Map {
+	key := strings.Join(addrs, " ")
+
+	replicaMaps.Lock()
+	defer replicaMaps.Unlock()
+	if replicaMaps.m == nil || replicaMaps.addrs != key {
+		replicaMaps.addrs = key
+		replicaMaps.m = endpoint.Static(addrs...)
+	}
+	return replicaMaps.m
+}
+
 // ArchiveOptions contains options for the Archive func.
 type ArchiveOptions struct {
 	Treeish string   // the tree or commit to produce an archive for
@@ -142,8 +219,42 @@ func (a *archiveReader) Close() error {
 }
 
 // ArchiveURL returns a URL from which an archive of the given Git repository can
-// be downloaded from.
+// be downloaded from. If the repository is replicated, the URL points at the
+// first gitserver that has cloned it.
 func (c *Client) ArchiveURL(ctx context.Context, repo Repo, opt ArchiveOptions) *url.URL {
+	return &url.URL{
+		Scheme:   "http",
+		Host:     c.clonedAddrForRepo(ctx, repo.Name),
+		Path:     "/archive",
+		RawQuery: archiveQuery(repo, opt).Encode(),
+	}
+}
+
+// clonedAddrForRepo returns the address of the first gitserver holding a
+// replica of repo that has cloned it, or the primary gitserver of repo if
+// none of them has.
+func (c *Client) clonedAddrForRepo(ctx context.Context, repo api.RepoName) string {
+	addrs := c.AddrsForRepo(ctx, repo)
+	if len(addrs) == 1 {
+		return addrs[0]
+	}
+	req := &protocol.IsRepoClonedRequest{
+		Repo: repo,
+	}
+	for _, addr := range addrs {
+		resp, err := c.doAddrs(ctx, []string{addr}, repo, "POST", "is-repo-cloned", req, false)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			return addr
+		}
+	}
+	return addrs[0]
+}
+
+func archiveQuery(repo Repo, opt ArchiveOptions) url.Values {
 	q := url.Values{
 		"repo":    {string(repo.Name)},
 		"treeish": {opt.Treeish},
@@ -153,13 +264,7 @@ func (c *Client) ArchiveURL(ctx context.Context, repo Repo, opt ArchiveOptions)
 	for _, path := range opt.Paths {
 		q.Add("path", path)
 	}
-
-	return &url.URL{
-		Scheme:   "http",
-		Host:     c.AddrForRepo(ctx, repo.Name),
-		Path:     "/archive",
-		RawQuery: q.Encode(),
-	}
+	return q
 }
 
 // Archive produces an archive from a Git repository.
@@ -181,19 +286,29 @@ func (c *Client) Archive(ctx context.Context, repo Repo, opt ArchiveOptions) (_
 		return nil, err
 	}
 
-	u := c.ArchiveURL(ctx, repo, opt)
-	resp, err := c.do(ctx, repo.Name, "GET", u.String(), nil)
+	resp, err := c.doReplicas(ctx, repo.Name, "GET", "archive?"+archiveQuery(repo, opt).Encode(), nil, true)
 	if err != nil {
 		return nil, err
 	}
 
 	switch resp.StatusCode {
 	case http.StatusOK:
+		var base io.ReadCloser = &cmdReader{
+			rc:      resp.Body,
+			trailer: resp.Trailer,
+		}
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			// Cached archives have no exec trailers, but can be resumed.
+			base = &resumingReader{
+				ctx:  ctx,
+				c:    c,
+				url:  resp.Request.URL.String(),
+				etag: etag,
+				body: resp.Body,
+			}
+		}
 		return &archiveReader{
-			base: &cmdReader{
-				rc:      resp.Body,
-				trailer: resp.Trailer,
-			},
+			base: base,
 			repo: repo.Name,
 			spec: opt.Treeish,
 		}, nil
@@ -217,6 +332,76 @@ func (c *Client) Archive(ctx context.Context, repo Repo, opt ArchiveOptions) (_
 	}
 }
 
+// maxArchiveResumes is how many times a resumingReader resumes a download.
+const maxArchiveResumes = 3
+
+// resumingReader reads an archive which gitserver serves from its archive
+// cache. If reading the response body fails part way through, for example
+// because the connection was reset, it requests the rest of the archive with
+// a range request instead of failing.
+type resumingReader struct {
+	ctx  context.Context
+	c    *Client
+	url  string
+	etag string
+
+	body    io.ReadCloser
+	offset  int64 // number of bytes read so far
+	resumes int
+	err     error // set when resuming failed
+}
+
+func (r *resumingReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	for {
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+		if err == nil || err == io.EOF || r.resumes >= maxArchiveResumes || r.ctx.Err() != nil {
+			return n, err
+		}
+		if n > 0 {
+			// Return what we read. The next Read will fail again and resume.
+			return n, nil
+		}
+		r.resumes++
+		if resumeErr := r.resume(); resumeErr != nil {
+			r.err = errors.Wrapf(resumeErr, "failed to resume archive download after %s", err)
+			return 0, r.err
+		}
+	}
+}
+
+// resume replaces the response body with the rest of the archive.
+func (r *resumingReader) resume() error {
+	r.body.Close()
+
+	req, err := http.NewRequest("GET", r.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", r.c.UserAgent)
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", r.offset))
+	// If the archive changed, gitserver responds with all of it instead.
+	req.Header.Set("If-Range", r.etag)
+
+	resp, err := r.c.HTTPClient.Do(req.WithContext(r.ctx))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	r.body = resp.Body
+	return nil
+}
+
+func (r *resumingReader) Close() error {
+	return r.body.Close()
+}
+
 type badRequestError struct{ error }
 
 func (e badRequestError) BadRequest() bool { return true }
@@ -249,7 +434,7 @@ func (c *Cmd) sendExec(ctx context.Context) (_ io.ReadCloser, _ http.Header, err
 		EnsureRevision: c.EnsureRevision,
 		Args:           c.Args[1:],
 	}
-	resp, err := c.client.httpPost(ctx, repoName, "exec", req)
+	resp, err := c.client.doReplicas(ctx, repoName, "POST", "exec", req, true)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -303,6 +488,14 @@ type Repo struct {
 	// this field is optional (it will use the last-used Git remote URL). If the repository is not
 	// cloned on the gitserver, the request will fail.
 	URL string
+
+	// CloneOptions are the options to clone the repository with. If nil, the options of an
+	// existing clone are kept.
+	CloneOptions *protocol.CloneOptions
+
+	// GitOverrides change how the repository is cloned and fetched. If nil, the overrides of an
+	// existing clone are kept.
+	GitOverrides *protocol.GitOverrides
 }
 
 // Command creates a new Cmd. Command name must be 'git',
@@ -485,30 +678,35 @@ func (c *Client) ListCloned(ctx context.Context) ([]string, error) {
 		mu    sync.Mutex
 		err   error
 		repos []string
+		seen  = map[string]struct{}{}
 	)
 	addrs := c.Addrs(ctx)
+	n := c.replicationFactor()
 	for _, addr := range addrs {
 		wg.Add(1)
 		go func(addr string) {
 			defer wg.Done()
 			r, e := c.doListOne(ctx, "?cloned", addr)
 
-			// Only include repos that belong on addr.
-			if len(r) > 0 {
-				filtered := r[:0]
-				for _, repo := range r {
-					if addrForKey(addrs, repo) == addr {
-						filtered = append(filtered, repo)
-					}
-				}
-				r = filtered
-			}
+			// Only include repos that belong on addr. A replicated repo is
+			// only included once, for the first replica listing it.
 			mu.Lock()
+			defer mu.Unlock()
 			if e != nil {
 				err = e
 			}
-			repos = append(repos, r...)
-			mu.Unlock()
+			for _, repo := range r {
+				if _, ok := seen[repo]; ok {
+					continue
+				}
+				for _, a := range addrsForKey(addrs, repo, n) {
+					if a == addr {
+						seen[repo] = struct{}{}
+						repos = append(repos, repo)
+						break
+					}
+				}
+			}
 		}(addr)
 	}
 	wg.Wait()
@@ -555,19 +753,117 @@ func (c *Client) doListOne(ctx context.Context, urlSuffix, addr string) ([]strin
 	return list, err
 }
 
+// Evictions returns the repositories which gitservers recently removed to
+// free up disk space, most recent first.
+func (c *Client) Evictions(ctx context.Context) ([]protocol.Eviction, error) {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		err       error
+		evictions []protocol.Eviction
+	)
+	for _, addr := range c.Addrs(ctx) {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			e, er := c.evictions(ctx, addr)
+			mu.Lock()
+			defer mu.Unlock()
+			if er != nil {
+				err = er
+				return
+			}
+			for i := range e {
+				e[i].Gitserver = addr
+			}
+			evictions = append(evictions, e...)
+		}(addr)
+	}
+	wg.Wait()
+	sort.Slice(evictions, func(i, j int) bool {
+		return evictions[i].EvictedAt.After(evictions[j].EvictedAt)
+	})
+	return evictions, err
+}
+
+func (c *Client) evictions(ctx context.Context, addr string) ([]protocol.Eviction, error) {
+	req, err := http.NewRequest("GET", "http://"+addr+"/evictions", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &url.Error{URL: req.URL.String(), Op: "Evictions", Err: fmt.Errorf("Evictions: http status %d", resp.StatusCode)}
+	}
+
+	var evictions []protocol.Eviction
+	err = json.NewDecoder(resp.Body).Decode(&evictions)
+	return evictions, err
+}
+
 // RequestRepoUpdate is the new protocol endpoint for synchronous requests
 // with more detailed responses. Do not use this if you are not repo-updater.
 //
 // Repo updates are not guaranteed to occur. If a repo has been updated
 // recently (within the Since duration specified in the request), the
 // update won't happen.
+//
+// If the repo is replicated, the update is requested from every gitserver
+// holding a replica. The response of the first gitserver which succeeds is
+// returned, in the order given by AddrsForRepo.
 func (c *Client) RequestRepoUpdate(ctx context.Context, repo Repo, since time.Duration) (*protocol.RepoUpdateResponse, error) {
 	req := &protocol.RepoUpdateRequest{
-		Repo:  repo.Name,
-		URL:   repo.URL,
-		Since: since,
+		Repo:         repo.Name,
+		URL:          repo.URL,
+		Since:        since,
+		CloneOptions: repo.CloneOptions,
+		GitOverrides: repo.GitOverrides,
+	}
+
+	addrs := c.AddrsForRepo(ctx, repo.Name)
+	if len(addrs) == 1 {
+		return c.requestRepoUpdate(ctx, addrs[0], req)
+	}
+
+	type result struct {
+		info *protocol.RepoUpdateResponse
+		err  error
+	}
+	results := make([]result, len(addrs))
+	var wg sync.WaitGroup
+	for i, addr := range addrs {
+		wg.Add(1)
+		go func(i int, addr string) {
+			defer wg.Done()
+			info, err := c.requestRepoUpdate(ctx, addr, req)
+			results[i] = result{info: info, err: err}
+		}(i, addr)
 	}
-	resp, err := c.httpPost(ctx, repo.Name, "repo-update", req)
+	wg.Wait()
+
+	var info *protocol.RepoUpdateResponse
+	for i, r := range results {
+		if r.err != nil {
+			log15.Warn("failed to request repository update from replica", "repo", repo.Name, "addr", addrs[i], "error", r.err)
+			continue
+		}
+		if info == nil {
+			info = r.info
+		}
+	}
+	if info == nil {
+		return nil, results[0].err
+	}
+	return info, nil
+}
+
+func (c *Client) requestRepoUpdate(ctx context.Context, addr string, req *protocol.RepoUpdateRequest) (*protocol.RepoUpdateResponse, error) {
+	resp, err := c.doAddrs(ctx, []string{addr}, req.Repo, "POST", "repo-update", req, false)
 	if err != nil {
 		return nil, err
 	}
@@ -646,7 +942,7 @@ func (c *Client) IsRepoCloned(ctx context.Context, repo api.RepoName) (bool, err
 	req := &protocol.IsRepoClonedRequest{
 		Repo: repo,
 	}
-	resp, err := c.httpPost(ctx, repo, "is-repo-cloned", req)
+	resp, err := c.doReplicas(ctx, repo, "POST", "is-repo-cloned", req, true)
 	if err != nil {
 		return false, err
 	}
@@ -662,7 +958,8 @@ func (c *Client) IsRepoCloned(ctx context.Context, repo api.RepoName) (bool, err
 // RepoInfo retrieves information about one or more repositories on gitserver.
 //
 // The repository not existing is not an error; in that case, RepoInfoResponse.Results[i].Cloned
-// will be false and the error will be nil.
+// will be false and the error will be nil. The information is always
+// retrieved from the gitserver returned by AddrForRepo.
 //
 // If multiple errors occurred, an incomplete result is returned along with a
 // *multierror.Error.
@@ -689,10 +986,10 @@ func (c *Client) RepoInfo(ctx context.Context, repos ...api.RepoName) (*protocol
 	}
 
 	ch := make(chan op, len(shards))
-	for _, req := range shards {
-		go func(o op) {
+	for addr, req := range shards {
+		go func(addr string, o op) {
 			var resp *http.Response
-			resp, o.err = c.httpPost(ctx, o.req.Repos[0], "repos", o.req)
+			resp, o.err = c.doAddrs(ctx, []string{addr}, o.req.Repos[0], "POST", "repos", o.req, false)
 			if o.err != nil {
 				ch <- o
 				return
@@ -712,7 +1009,7 @@ func (c *Client) RepoInfo(ctx context.Context, repos ...api.RepoName) (*protocol
 			o.res = new(protocol.RepoInfoResponse)
 			o.err = json.NewDecoder(resp.Body).Decode(o.res)
 			ch <- o
-		}(op{req: req})
+		}(addr, op{req: req})
 	}
 
 	err := new(multierror.Error)
@@ -736,12 +1033,129 @@ func (c *Client) RepoInfo(ctx context.Context, repos ...api.RepoName) (*protocol
 	return &res, err.ErrorOrNil()
 }
 
-// Remove removes the repository clone from gitserver.
+// RepoStats returns statistics about the clone of repo on gitserver, or nil
+// if it is not cloned. If the repo is replicated, the statistics of the
+// first replica with a clone are returned.
+func (c *Client) RepoStats(ctx context.Context, repo api.RepoName) (*protocol.RepoStats, error) {
+	req := &protocol.RepoStatsRequest{Repo: repo}
+	resp, err := c.doReplicas(ctx, repo, "POST", "repo-stats", req, true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var stats protocol.RepoStats
+		if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+			return nil, err
+		}
+		return &stats, nil
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, &url.Error{
+			URL: resp.Request.URL.String(),
+			Op:  "RepoStats",
+			Err: errors.Errorf("RepoStats: http status %d", resp.StatusCode),
+		}
+	}
+}
+
+// ListRepoStats returns statistics about the repository clones on all
+// gitservers, largest first. If limit is positive, at most limit clones are
+// returned. The statistics of the other gitservers are returned even if some
+// gitservers fail, along with an error.
+func (c *Client) ListRepoStats(ctx context.Context, limit int) ([]*protocol.RepoStats, error) {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		err   error
+		stats []*protocol.RepoStats
+	)
+	for _, addr := range c.Addrs(ctx) {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			s, er := c.listRepoStats(ctx, addr, limit)
+			mu.Lock()
+			defer mu.Unlock()
+			if er != nil {
+				err = er
+				return
+			}
+			for _, st := range s {
+				st.Gitserver = addr
+			}
+			stats = append(stats, s...)
+		}(addr)
+	}
+	wg.Wait()
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].SizeBytes != stats[j].SizeBytes {
+			return stats[i].SizeBytes > stats[j].SizeBytes
+		}
+		return stats[i].Repo < stats[j].Repo
+	})
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats, err
+}
+
+func (c *Client) listRepoStats(ctx context.Context, addr string, limit int) ([]*protocol.RepoStats, error) {
+	u := "http://" + addr + "/list-repo-stats"
+	if limit > 0 {
+		u += "?limit=" + strconv.Itoa(limit)
+	}
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &url.Error{URL: req.URL.String(), Op: "ListRepoStats", Err: fmt.Errorf("ListRepoStats: http status %d", resp.StatusCode)}
+	}
+
+	var stats []*protocol.RepoStats
+	err = json.NewDecoder(resp.Body).Decode(&stats)
+	return stats, err
+}
+
+// Remove removes the repository clone from every gitserver holding a
+// replica of it.
 func (c *Client) Remove(ctx context.Context, repo api.RepoName) error {
+	addrs := c.AddrsForRepo(ctx, repo)
+	errs := make([]error, len(addrs))
+	var wg sync.WaitGroup
+	for i, addr := range addrs {
+		wg.Add(1)
+		go func(i int, addr string) {
+			defer wg.Done()
+			errs[i] = c.remove(ctx, addr, repo)
+		}(i, addr)
+	}
+	wg.Wait()
+
+	var err *multierror.Error
+	for _, e := range errs {
+		if e != nil {
+			err = multierror.Append(err, e)
+		}
+	}
+	return err.ErrorOrNil()
+}
+
+func (c *Client) remove(ctx context.Context, addr string, repo api.RepoName) error {
 	req := &protocol.RepoDeleteRequest{
 		Repo: repo,
 	}
-	resp, err := c.httpPost(ctx, repo, "delete", req)
+	resp, err := c.doAddrs(ctx, []string{addr}, repo, "POST", "delete", req, false)
 	if err != nil {
 		return err
 	}
@@ -758,9 +1172,41 @@ func (c *Client) httpPost(ctx context.Context, repo api.RepoName, op string, pay
 	return c.do(ctx, repo, "POST", op, payload)
 }
 
+var replicaFallbackCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "gitserver",
+	Name:      "client_replica_fallback_total",
+	Help:      "Times that a request to gitserver fell back to another replica of the repository.",
+}, []string{"reason"})
+
+func init() {
+	prometheus.MustRegister(replicaFallbackCounter)
+}
+
 // do performs a request to a gitserver, sharding based on the given
-// repo name (the repo name is otherwise not used).
+// repo name (the repo name is otherwise not used). If the repo is replicated
+// on several gitservers, the request falls back to the next replica when a
+// gitserver can't be reached. Requests that reached a gitserver never fall
+// back, so slow or failing requests are not repeated on every replica.
 func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, payload interface{}) (resp *http.Response, err error) {
+	return c.doReplicas(ctx, repo, method, op, payload, false)
+}
+
+// doReplicas is like do. If fallbackNotFound is true, the request also falls
+// back to the next replica when a gitserver responds with 404 Not Found,
+// which is how gitserver responds to requests for repositories it hasn't
+// cloned (yet).
+func (c *Client) doReplicas(ctx context.Context, repo api.RepoName, method, op string, payload interface{}, fallbackNotFound bool) (resp *http.Response, err error) {
+	if strings.HasPrefix(op, "http") {
+		return c.doAddrs(ctx, nil, repo, method, op, payload, false)
+	}
+	return c.doAddrs(ctx, c.AddrsForRepo(ctx, repo), repo, method, op, payload, fallbackNotFound)
+}
+
+// doAddrs performs a request to the first gitserver in addrs, falling back
+// to the next one as described by do and doReplicas. If addrs is empty, op
+// must be an absolute URL.
+func (c *Client) doAddrs(ctx context.Context, addrs []string, repo api.RepoName, method, op string, payload interface{}, fallbackNotFound bool) (resp *http.Response, err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "Client.do")
 	defer func() {
 		span.LogKV("repo", string(repo), "method", method, "op", op)
@@ -776,11 +1222,40 @@ func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, p
 		return nil, err
 	}
 
-	uri := op
-	if !strings.HasPrefix(op, "http") {
-		uri = "http://" + c.AddrForRepo(ctx, repo) + "/" + op
+	if len(addrs) == 0 {
+		return c.doOnce(ctx, span, method, op, reqBody)
 	}
 
+	for i, addr := range addrs {
+		resp, err = c.doOnce(ctx, span, method, "http://"+addr+"/"+op, reqBody)
+		if i == len(addrs)-1 || ctx.Err() != nil {
+			break
+		}
+
+		var reason string
+		switch {
+		case err != nil && isDialError(err):
+			reason = "unreachable"
+		case err == nil && resp.StatusCode == http.StatusNotFound && fallbackNotFound:
+			reason = "not_found"
+		default:
+			return resp, err
+		}
+		if err == nil {
+			resp.Body.Close()
+			err = errors.Errorf("http status %d", resp.StatusCode)
+		}
+		replicaFallbackCounter.WithLabelValues(reason).Inc()
+		span.LogKV("event", "falling back to replica", "addr", addr, "err", err.Error())
+		if reason != "not_found" {
+			log15.Warn("gitserver request failed, falling back to replica", "repo", repo, "op", op, "addr", addr, "next", addrs[i+1], "error", err)
+		}
+	}
+	return resp, err
+}
+
+// doOnce performs a single request to uri.
+func (c *Client) doOnce(ctx context.Context, span opentracing.Span, method, uri string, reqBody []byte) (*http.Response, error) {
 	req, err := http.NewRequest(method, uri, bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, err
@@ -804,10 +1279,23 @@ func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, p
 	return c.HTTPClient.Do(req)
 }
 
+// isDialError reports whether err means that no connection to the gitserver
+// could be established, so the request was never sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
 // CreateCommitFromPatch will attempt to create a commit from a patch
 // If possible, the error returned will be of type protocol.CreateCommitFromPatchError
 func (c *Client) CreateCommitFromPatch(ctx context.Context, req protocol.CreateCommitFromPatchRequest) (string, error) {
-	resp, err := c.httpPost(ctx, req.Repo, "create-commit-from-patch", req)
+	// The commit is created on the primary replica only, so that it is never
+	// created twice.
+	resp, err := c.doAddrs(ctx, []string{c.AddrForRepo(ctx, req.Repo)}, req.Repo, "POST", "create-commit-from-patch", req, false)
 	if err != nil {
 		return "", err
 	}
@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -14,6 +15,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -57,6 +60,256 @@ func TestClient_ListCloned(t *testing.T) {
 	}
 }
 
+func TestClient_ListRepoStats(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+	cli := &gitserver.Client{
+		Addrs: func(ctx context.Context) []string { return addrs },
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.String() {
+			case "http://gitserver-0/list-repo-stats?limit=2":
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`[{"Repo": "a", "SizeBytes": 30}, {"Repo": "b", "SizeBytes": 10}]`)),
+				}, nil
+			case "http://gitserver-1/list-repo-stats?limit=2":
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`[{"Repo": "c", "SizeBytes": 20}]`)),
+				}, nil
+			default:
+				return nil, fmt.Errorf("unexpected url: %s", r.URL.String())
+			}
+		}),
+	}
+
+	// The largest clones of the gitservers that responded are returned.
+	stats, err := cli.ListRepoStats(context.Background(), 2)
+	if err == nil {
+		t.Error("got nil error, want the error of gitserver-2")
+	}
+	var got []string
+	for _, s := range stats {
+		got = append(got, s.Gitserver+"/"+string(s.Repo))
+	}
+	if want := []string{"gitserver-0/a", "gitserver-1/c"}; !cmp.Equal(want, got) {
+		t.Errorf("mismatch for (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestClient_AddrsForRepo(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2", "gitserver-3"}
+	replicationFactor := 1
+	cli := &gitserver.Client{
+		Addrs:             func(ctx context.Context) []string { return addrs },
+		ReplicationFactor: func() int { return replicationFactor },
+	}
+
+	ctx := context.Background()
+	for _, repo := range []api.RepoName{"github.com/foo/bar", "github.com/foo/baz", "gitlab.com/a/b"} {
+		primary := cli.AddrForRepo(ctx, repo)
+		for replicationFactor = 1; replicationFactor <= len(addrs)+1; replicationFactor++ {
+			got := cli.AddrsForRepo(ctx, repo)
+
+			want := replicationFactor
+			if want > len(addrs) {
+				want = len(addrs)
+			}
+			if len(got) != want {
+				t.Fatalf("%s: replication factor %d: got %d addrs %v, want %d", repo, replicationFactor, len(got), got, want)
+			}
+			if got[0] != primary {
+				t.Errorf("%s: replication factor %d: got primary %q, want %q", repo, replicationFactor, got[0], primary)
+			}
+			seen := map[string]bool{}
+			for _, addr := range got {
+				if seen[addr] {
+					t.Errorf("%s: replication factor %d: duplicate addr %q in %v", repo, replicationFactor, addr, got)
+				}
+				seen[addr] = true
+			}
+			if again := cli.AddrsForRepo(ctx, repo); !cmp.Equal(got, again) {
+				t.Errorf("%s: replication factor %d: got %v, then %v", repo, replicationFactor, got, again)
+			}
+		}
+	}
+}
+
+func TestClient_ReplicaFallback(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1"}
+	repo := api.RepoName("github.com/foo/bar")
+
+	newClient := func(handler func(addr string, r *http.Request) (*http.Response, error)) *gitserver.Client {
+		return &gitserver.Client{
+			Addrs:             func(ctx context.Context) []string { return addrs },
+			ReplicationFactor: func() int { return 2 },
+			HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+				return handler(r.URL.Host, r)
+			}),
+		}
+	}
+	respond := func(status int, body string) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Trailer:    http.Header{"X-Exec-Exit-Status": {"0"}},
+		}, nil
+	}
+	command := func(cli *gitserver.Client) *gitserver.Cmd {
+		cmd := cli.Command("git", "show")
+		cmd.Repo = gitserver.Repo{Name: repo}
+		return cmd
+	}
+
+	dialError := func(addr string) error {
+		return &net.OpError{Op: "dial", Net: "tcp", Addr: &net.TCPAddr{}, Err: errors.New(addr + ": connection refused")}
+	}
+
+	ctx := context.Background()
+	replicas := newClient(nil).AddrsForRepo(ctx, repo)
+	primary, replica := replicas[0], replicas[1]
+
+	t.Run("primary unavailable", func(t *testing.T) {
+		var requested []string
+		cli := newClient(func(addr string, r *http.Request) (*http.Response, error) {
+			requested = append(requested, addr)
+			if addr == primary {
+				return nil, dialError(addr)
+			}
+			return respond(http.StatusOK, "")
+		})
+		cloned, err := cli.IsRepoCloned(ctx, repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cloned {
+			t.Error("got not cloned, want cloned")
+		}
+		if want := []string{primary, replica}; !cmp.Equal(requested, want) {
+			t.Errorf("got requests to %v, want %v", requested, want)
+		}
+	})
+
+	t.Run("requests that reached the primary don't fall back", func(t *testing.T) {
+		for _, primaryErr := range []error{nil, context.DeadlineExceeded} {
+			var requested []string
+			cli := newClient(func(addr string, r *http.Request) (*http.Response, error) {
+				requested = append(requested, addr)
+				if addr == primary {
+					if primaryErr != nil {
+						return nil, primaryErr
+					}
+					return respond(http.StatusInternalServerError, "")
+				}
+				return respond(http.StatusOK, "hello")
+			})
+			if _, err := command(cli).Output(ctx); err == nil {
+				t.Errorf("primary error %v: got no error", primaryErr)
+			}
+			if want := []string{primary}; !cmp.Equal(requested, want) {
+				t.Errorf("primary error %v: got requests to %v, want %v", primaryErr, requested, want)
+			}
+		}
+	})
+
+	t.Run("not cloned on primary", func(t *testing.T) {
+		cli := newClient(func(addr string, r *http.Request) (*http.Response, error) {
+			if addr == primary {
+				return respond(http.StatusNotFound, `{"cloneInProgress": true}`)
+			}
+			return respond(http.StatusOK, "hello")
+		})
+		out, err := command(cli).Output(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != "hello" {
+			t.Errorf("got output %q, want %q", out, "hello")
+		}
+	})
+
+	t.Run("all replicas fail", func(t *testing.T) {
+		cli := newClient(func(addr string, r *http.Request) (*http.Response, error) {
+			return nil, dialError(addr)
+		})
+		_, err := cli.IsRepoCloned(ctx, repo)
+		if err == nil || !strings.Contains(err.Error(), replica+": connection refused") {
+			t.Errorf("got error %v, want error from %s", err, replica)
+		}
+	})
+
+	t.Run("archive URL points at a replica with a clone", func(t *testing.T) {
+		cli := newClient(func(addr string, r *http.Request) (*http.Response, error) {
+			if addr == primary {
+				return respond(http.StatusNotFound, "")
+			}
+			return respond(http.StatusOK, "")
+		})
+		u := cli.ArchiveURL(ctx, gitserver.Repo{Name: repo}, gitserver.ArchiveOptions{Treeish: "HEAD", Format: "tar"})
+		if u.Host != replica {
+			t.Errorf("got archive URL %s, want host %s", u, replica)
+		}
+	})
+
+	t.Run("updates and removals go to all replicas", func(t *testing.T) {
+		var (
+			mu        sync.Mutex
+			requested []string
+		)
+		cli := newClient(func(addr string, r *http.Request) (*http.Response, error) {
+			mu.Lock()
+			requested = append(requested, addr+r.URL.Path)
+			mu.Unlock()
+			if addr == primary {
+				return nil, dialError(addr)
+			}
+			return respond(http.StatusOK, `{"cloned": true}`)
+		})
+
+		resp, err := cli.RequestRepoUpdate(ctx, gitserver.Repo{Name: repo}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.Cloned {
+			t.Errorf("got response %+v, want cloned", resp)
+		}
+		if err := cli.Remove(ctx, repo); err == nil {
+			t.Error("got no error removing repo from unavailable primary")
+		}
+
+		want := []string{primary + "/delete", primary + "/repo-update", replica + "/delete", replica + "/repo-update"}
+		sort.Strings(requested)
+		if !cmp.Equal(requested, want) {
+			t.Errorf("got requests to %v, want %v", requested, want)
+		}
+	})
+}
+
+func TestClient_ListCloned_Replicated(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+	cli := &gitserver.Client{
+		Addrs:             func(ctx context.Context) []string { return addrs },
+		ReplicationFactor: func() int { return 2 },
+	}
+
+	// Every gitserver has every repo cloned, but only the replicas of a repo
+	// should count towards it being cloned.
+	repos := []string{"repo-a", "repo-b", "repo-c", "repo-d"}
+	cli.HTTPClient = httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			Body: ioutil.NopCloser(bytes.NewBufferString(`["repo-a", "repo-b", "repo-c", "repo-d"]`)),
+		}, nil
+	})
+
+	got, err := cli.ListCloned(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	if !cmp.Equal(repos, got) {
+		t.Errorf("mismatch for (-want +got):\n%s", cmp.Diff(repos, got))
+	}
+}
+
 func TestClient_Archive(t *testing.T) {
 	root, err := ioutil.TempDir("", t.Name())
 	if err != nil {
@@ -148,6 +401,80 @@ func TestClient_Archive(t *testing.T) {
 	}
 }
 
+func TestClient_ArchiveResume(t *testing.T) {
+	root, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	h := (&server.Server{
+		ReposDir:              filepath.Join(root, "repos"),
+		ArchiveCacheSizeBytes: 1 << 30,
+	}).Handler()
+
+	// The first archive response is cut off half way through.
+	var (
+		mu     sync.Mutex
+		ranges []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/archive" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		first := len(ranges) == 1
+		mu.Unlock()
+		if !first {
+			h.ServeHTTP(w, r)
+			return
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes()[:rec.Body.Len()/2])
+	}))
+	defer srv.Close()
+
+	cli := gitserver.NewClient(&http.Client{})
+	cli.Addrs = func(context.Context) []string {
+		u, _ := url.Parse(srv.URL)
+		return []string{u.Host}
+	}
+
+	ctx := context.Background()
+	repo := gitserver.Repo{Name: "simple", URL: createSimpleGitRepo(t, root)}
+	if _, err := cli.RequestRepoUpdate(ctx, repo, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := cli.Archive(ctx, repo, gitserver.ArchiveOptions{Treeish: "HEAD", Format: "zip"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) != 3 {
+		t.Errorf("got %d files in resumed archive, want 3", len(zr.File))
+	}
+
+	if want := []string{"", fmt.Sprintf("bytes=%d-", len(data)/2)}; !cmp.Equal(ranges, want) {
+		t.Errorf("mismatch in requested ranges (-want +got):\n%s", cmp.Diff(want, ranges))
+	}
+}
+
 func createRepoWithDotGitDir(t *testing.T, root string) string {
 	t.Helper()
 	b64 := func(s string) string {
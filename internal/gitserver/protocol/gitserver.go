@@ -48,6 +48,48 @@ type RepoUpdateRequest struct {
 	Repo  api.RepoName  `json:"repo"`  // identifying URL for repo
 	URL   string        `json:"url"`   // repo's remote URL
 	Since time.Duration `json:"since"` // debounce interval for queries, used only with request-repo-update
+
+	// CloneOptions are the options to clone the repo with. If they differ
+	// from the options an existing clone was made with, the repo is
+	// recloned. If nil, the repo is cloned with the options of an existing
+	// clone, or in full.
+	CloneOptions *CloneOptions `json:"cloneOptions,omitempty"`
+
+	// GitOverrides change how the repo is cloned and fetched. If nil, the
+	// overrides of an existing clone are kept.
+	GitOverrides *GitOverrides `json:"gitOverrides,omitempty"`
+}
+
+// CloneOptions are options for cloning a repo which reduce how much of it is
+// cloned.
+type CloneOptions struct {
+	// BlobSizeLimit, if non-empty, makes a partial clone which omits blobs
+	// larger than this size (e.g. "1m"). Omitted blobs are fetched from the
+	// remote when they are needed.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+
+	// Depth, if positive, makes a shallow clone with this many commits of
+	// history.
+	Depth int `json:"depth,omitempty"`
+}
+
+// GitOverrides change how gitserver clones and fetches a repo, for repos
+// which can't be mirrored the usual way.
+type GitOverrides struct {
+	// Config is git config which is set in the clone, and passed to git
+	// clone so that it also applies while cloning.
+	Config map[string]string `json:"config,omitempty"`
+
+	// FetchCommand, if non-empty, is run in the clone instead of git fetch
+	// to update it. It is split into arguments at spaces.
+	FetchCommand string `json:"fetchCommand,omitempty"`
+
+	// FetchLFS, if true, fetches the Git LFS objects of all refs after each
+	// clone and fetch. By default they are not fetched.
+	FetchLFS bool `json:"fetchLFS,omitempty"`
+
+	// Refspecs are fetched in addition to the default refspecs.
+	Refspecs []string `json:"refspecs,omitempty"`
 }
 
 // RepoUpdateResponse returns meta information of the repo enqueued for
@@ -131,6 +173,39 @@ type RepoInfoResponse struct {
 	Results map[api.RepoName]*RepoInfo
 }
 
+// RepoStatsRequest is a request for statistics about a repository clone.
+type RepoStatsRequest struct {
+	// Repo is the repository to get statistics about.
+	Repo api.RepoName
+}
+
+// RepoStats are statistics about a repository clone on a gitserver, which
+// help to find out why operations on it are slow.
+type RepoStats struct {
+	Repo api.RepoName
+
+	// Gitserver is the address of the gitserver with the clone. It is set by
+	// the client when listing the statistics of several gitservers.
+	Gitserver string `json:",omitempty"`
+
+	SizeBytes      int64 // disk space used by the clone
+	PackfileCount  int   // number of packfiles
+	PackedObjects  int64 // number of objects in packfiles
+	LooseObjects   int64 // number of loose objects
+	LooseSizeBytes int64 // disk space used by loose objects
+
+	LastFetched       *time.Time    // when the last `git fetch` occurred
+	LastFetchDuration time.Duration // how long the last fetch took
+	LastFetchError    string        // why the last fetch failed, if it did
+
+	// LastGC is when git gc last repacked the objects of the clone, or nil
+	// if it never did.
+	LastGC *time.Time
+
+	// CloneTime is when the repository was (re)cloned.
+	CloneTime *time.Time
+}
+
 // CreateCommitFromPatchRequest is the request information needed for creating
 // the simulated staging area git object for a repo.
 type CreateCommitFromPatchRequest struct {
@@ -203,3 +278,28 @@ type CreateCommitFromPatchError struct {
 func (e *CreateCommitFromPatchError) Error() string {
 	return e.InternalError
 }
+
+// Eviction describes a repository which gitserver removed to free up disk
+// space.
+type Eviction struct {
+	Repo api.RepoName `json:"repo"`
+
+	// Gitserver is the address of the gitserver which removed the
+	// repository. It is set by the client.
+	Gitserver string `json:"gitserver,omitempty"`
+
+	EvictedAt time.Time `json:"evictedAt"`
+
+	// Reason explains why the repository was chosen.
+	Reason string `json:"reason"`
+
+	// SizeBytes is the disk space freed.
+	SizeBytes int64 `json:"sizeBytes"`
+
+	// LastUsed is when the repository was last fetched or accessed.
+	LastUsed time.Time `json:"lastUsed"`
+
+	// AccessScore is the decayed number of recent accesses of the
+	// repository.
+	AccessScore float64 `json:"accessScore"`
+}
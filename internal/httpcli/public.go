@@ -0,0 +1,84 @@
+package httpcli
+
+import (
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrPrivateAddress is returned when a client configured with
+// PublicAddressesOnlyOpt connects to an address that isn't public.
+var ErrPrivateAddress = errors.New("connecting to non-public addresses is not allowed")
+
+// privateNetworks are the networks that aren't publicly routable, apart from
+// the loopback, link-local and multicast networks, which are checked with the
+// methods of net.IP.
+var privateNetworks = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",      // "this" network
+		"10.0.0.0/8",     // RFC 1918
+		"100.64.0.0/10",  // carrier-grade NAT
+		"172.16.0.0/12",  // RFC 1918
+		"192.168.0.0/16", // RFC 1918
+		"fc00::/7",       // unique local addresses
+	} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// IsPublicIP reports whether ip is a public unicast address, as opposed to a
+// loopback, private, link-local, multicast or unspecified address.
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// PublicAddressesOnlyOpt is an Opt that makes an http.Client only connect to
+// public addresses (see IsPublicIP), so that it can't be used to reach
+// services on the internal network. The address is checked when connecting,
+// after host names were resolved, so host names that resolve to internal
+// addresses are rejected too. Proxies are disabled, since the addresses of
+// requests sent through a proxy can't be checked.
+func PublicAddressesOnlyOpt(cli *http.Client) error {
+	tr, err := getTransportForMutation(cli)
+	if err != nil {
+		return errors.Wrap(err, "httpcli.PublicAddressesOnlyOpt")
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicAddressesOnlyControl,
+	}
+	tr.DialContext = dialer.DialContext
+	tr.Proxy = nil
+
+	return nil
+}
+
+func publicAddressesOnlyControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+		return errors.Wrap(ErrPrivateAddress, address)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package httpcli
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	for ip, want := range map[string]bool{
+		"8.8.8.8":            true,
+		"2001:4860::8888":    true,
+		"127.0.0.1":          false,
+		"::1":                false,
+		"10.1.2.3":           false,
+		"172.20.0.1":         false,
+		"192.168.1.1":        false,
+		"100.64.0.1":         false,
+		"169.254.169.254":    false, // cloud metadata services
+		"fe80::1":            false,
+		"fd00::1":            false,
+		"0.0.0.0":            false,
+		"::":                 false,
+		"224.0.0.1":          false,
+		"::ffff:127.0.0.1":   false,
+		"::ffff:192.168.0.1": false,
+	} {
+		if got := IsPublicIP(net.ParseIP(ip)); got != want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", ip, got, want)
+		}
+	}
+}
+
+func TestPublicAddressesOnlyOpt(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request to a loopback address was sent")
+	}))
+	defer srv.Close()
+
+	cli := &http.Client{}
+	if err := PublicAddressesOnlyOpt(cli); err != nil {
+		t.Fatal(err)
+	}
+
+	// Host names are checked once they are resolved.
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	for _, u := range []string{srv.URL, "http://localhost:" + port} {
+		resp, err := cli.Get(u)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("got error %v for %s, want ErrPrivateAddress", err, u)
+		}
+	}
+}
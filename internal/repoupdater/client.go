@@ -151,11 +151,26 @@ func (c *Client) EnqueueRepoUpdate(ctx context.Context, repo gitserver.Repo) (*p
 		return MockEnqueueRepoUpdate(ctx, repo)
 	}
 
-	req := &protocol.RepoUpdateRequest{
+	return c.enqueueRepoUpdate(ctx, &protocol.RepoUpdateRequest{
 		Repo: repo.Name,
 		URL:  repo.URL,
+	})
+}
+
+// MockEnqueueExternalRepoUpdate mocks (*Client).EnqueueExternalRepoUpdate for tests.
+var MockEnqueueExternalRepoUpdate func(ctx context.Context, spec api.ExternalRepoSpec) (*protocol.RepoUpdateResponse, error)
+
+// EnqueueExternalRepoUpdate requests that the repository with the given
+// external repo spec be updated as soon as possible, such as after a push to
+// it. It does not wait for the update.
+func (c *Client) EnqueueExternalRepoUpdate(ctx context.Context, spec api.ExternalRepoSpec) (*protocol.RepoUpdateResponse, error) {
+	if MockEnqueueExternalRepoUpdate != nil {
+		return MockEnqueueExternalRepoUpdate(ctx, spec)
 	}
+	return c.enqueueRepoUpdate(ctx, &protocol.RepoUpdateRequest{ExternalRepo: &spec})
+}
 
+func (c *Client) enqueueRepoUpdate(ctx context.Context, req *protocol.RepoUpdateRequest) (*protocol.RepoUpdateResponse, error) {
 	resp, err := c.httpPost(ctx, "enqueue-repo-update", req)
 	if err != nil {
 		return nil, err
@@ -168,7 +183,9 @@ func (c *Client) EnqueueRepoUpdate(ctx context.Context, repo gitserver.Repo) (*p
 	}
 
 	var res protocol.RepoUpdateResponse
-	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &repoNotFoundError{msg: string(bs)}
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 400 {
 		return nil, errors.New(string(bs))
 	} else if err = json.Unmarshal(bs, &res); err != nil {
 		return nil, err
@@ -177,6 +194,13 @@ func (c *Client) EnqueueRepoUpdate(ctx context.Context, repo gitserver.Repo) (*p
 	return &res, nil
 }
 
+// repoNotFoundError is returned when repo-updater doesn't know the repository
+// to update.
+type repoNotFoundError struct{ msg string }
+
+func (e *repoNotFoundError) Error() string  { return e.msg }
+func (e *repoNotFoundError) NotFound() bool { return true }
+
 // MockEnqueueChangesetSync mocks (*Client).EnqueueChangesetSync for tests.
 var MockEnqueueChangesetSync func(ctx context.Context, ids []int64) error
 
@@ -243,6 +267,44 @@ func (c *Client) SyncExternalService(ctx context.Context, svc api.ExternalServic
 	return &result, nil
 }
 
+// MockExternalServiceDryRun mocks (*Client).ExternalServiceDryRun for tests.
+var MockExternalServiceDryRun func(ctx context.Context, svc api.ExternalService) (*protocol.ExternalServiceDryRunResult, error)
+
+// ExternalServiceDryRun returns the changes syncing the given external service
+// with its (proposed) configuration would make to the synced repositories,
+// without saving or syncing anything.
+func (c *Client) ExternalServiceDryRun(ctx context.Context, svc api.ExternalService) (*protocol.ExternalServiceDryRunResult, error) {
+	if MockExternalServiceDryRun != nil {
+		return MockExternalServiceDryRun(ctx, svc)
+	}
+
+	req := &protocol.ExternalServiceDryRunRequest{ExternalService: svc}
+	resp, err := c.httpPost(ctx, "external-service-dry-run", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.New(string(bs))
+	}
+
+	var result protocol.ExternalServiceDryRunResult
+	if err = json.Unmarshal(bs, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Error != "" {
+		return nil, errors.New(result.Error)
+	}
+	return &result, nil
+}
+
 // RepoExternalServices requests the external services associated with a
 // repository with the given id.
 func (c *Client) RepoExternalServices(ctx context.Context, id api.RepoID) ([]api.ExternalService, error) {
@@ -297,6 +359,38 @@ func (c *Client) ExcludeRepo(ctx context.Context, id api.RepoID) (*protocol.Excl
 	return &res, nil
 }
 
+// MockDeletePendingRepos mocks (*Client).DeletePendingRepos for tests.
+var MockDeletePendingRepos func(ctx context.Context) (*protocol.DeletePendingReposResponse, error)
+
+// DeletePendingRepos deletes the repositories that are pending deletion,
+// confirming the deletions quarantined by the repoDeletionSafeguard site
+// configuration.
+func (c *Client) DeletePendingRepos(ctx context.Context) (*protocol.DeletePendingReposResponse, error) {
+	if MockDeletePendingRepos != nil {
+		return MockDeletePendingRepos(ctx)
+	}
+
+	resp, err := c.httpPost(ctx, "delete-pending-repos", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	var res protocol.DeletePendingReposResponse
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.New(string(bs))
+	} else if err = json.Unmarshal(bs, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
 // MockStatusMessages mocks (*Client).StatusMessages for tests.
 var MockStatusMessages func(context.Context) (*protocol.StatusMessagesResponse, error)
 
@@ -144,9 +144,16 @@ type RepoUpdateRequest struct {
 
 	// URL is the repository's Git remote URL (from which to clone or update).
 	URL string `json:"url"`
+
+	// ExternalRepo, if set, identifies the repository on its code host
+	// instead of Repo, such as when a code host notifies us of a push.
+	ExternalRepo *api.ExternalRepoSpec `json:"externalRepo,omitempty"`
 }
 
 func (a *RepoUpdateRequest) String() string {
+	if a.ExternalRepo != nil {
+		return fmt.Sprintf("RepoUpdateRequest{%s, %s}", a.ExternalRepo, a.URL)
+	}
 	return fmt.Sprintf("RepoUpdateRequest{%s, %s}", a.Repo, a.URL)
 }
 
@@ -185,6 +192,51 @@ type ExternalServiceSyncResult struct {
 	Error           string
 }
 
+// ExternalServiceDryRunRequest is a request to preview the changes syncing an
+// external service with a proposed configuration would make to the synced
+// repositories, without saving the configuration or syncing anything.
+//
+// The FrontendAPI issues this request so that admins can review the effects
+// of a configuration change, like repositories being deleted because of a
+// wrong repositoryQuery or exclude, before saving it.
+type ExternalServiceDryRunRequest struct {
+	ExternalService api.ExternalService
+}
+
+// ExternalServiceDryRunResult is the result type of an external service's dry
+// run request.
+type ExternalServiceDryRunResult struct {
+	// Added are the repositories that would be added.
+	Added []api.RepoName
+	// Deleted are the repositories that would be deleted.
+	Deleted []api.RepoName
+	// Quarantined are the repositories that would be marked as pending
+	// deletion by the repoDeletionSafeguard site configuration instead of
+	// being deleted.
+	Quarantined []api.RepoName
+	// Renamed are the repositories that would be renamed.
+	Renamed []RepoRename
+	// Modified are the other repositories whose metadata would change.
+	Modified []api.RepoName
+	// Error is the error listing the repositories of the proposed
+	// configuration, if any.
+	Error string
+}
+
+// RepoRename is a repository that would be renamed by a sync.
+type RepoRename struct {
+	From api.RepoName
+	To   api.RepoName
+}
+
+// DeletePendingReposResponse is returned in response to a request to delete
+// the repositories that are pending deletion, which confirms the deletions
+// quarantined by the repoDeletionSafeguard site configuration.
+type DeletePendingReposResponse struct {
+	// Deleted are the names of the deleted repositories.
+	Deleted []api.RepoName
+}
+
 type CloningProgress struct {
 	Message string
 }
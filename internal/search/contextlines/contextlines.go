@@ -0,0 +1,90 @@
+// Package contextlines computes the lines of a file shown around search
+// matches. It is shared by searcher and the frontend's Zoekt adapter, so that
+// both backends merge the context of nearby matches in the same way.
+package contextlines
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Range is a run of consecutive lines of a file that contains one or more
+// matching lines and the context lines around them.
+type Range struct {
+	// StartLine is the 0-based line number of the first line of the range.
+	StartLine int
+
+	// Preview is the content of the lines in the range, without the
+	// trailing newline.
+	Preview string
+}
+
+// Ranges returns the ranges of content that contain the given 0-based
+// matching lines together with up to before lines before and after lines
+// after each of them. Ranges that overlap or touch are merged, so each line
+// of content appears in at most one range. It returns nil if no context lines
+// are requested.
+func Ranges(content []byte, lines []int, before, after int) []Range {
+	if (before <= 0 && after <= 0) || len(lines) == 0 {
+		return nil
+	}
+	if before < 0 {
+		before = 0
+	}
+	if after < 0 {
+		after = 0
+	}
+
+	// starts[i] is the offset of line i in content.
+	starts := []int{0}
+	for i, c := range content {
+		if c == '\n' && i+1 < len(content) {
+			starts = append(starts, i+1)
+		}
+	}
+	lineEnd := func(line int) int {
+		if line+1 < len(starts) {
+			return starts[line+1] - 1 // exclude the newline
+		}
+		return len(bytes.TrimSuffix(content, []byte{'\n'}))
+	}
+
+	sorted := append([]int(nil), lines...)
+	sort.Ints(sorted)
+
+	var (
+		ranges     []Range
+		start, end = -1, -1 // the line range being built
+	)
+	flush := func() {
+		if start < 0 {
+			return
+		}
+		ranges = append(ranges, Range{
+			StartLine: start,
+			Preview:   string(content[starts[start]:lineEnd(end)]),
+		})
+	}
+	for _, line := range sorted {
+		if line < 0 || line >= len(starts) {
+			continue
+		}
+		lo, hi := line-before, line+after
+		if lo < 0 {
+			lo = 0
+		}
+		if hi >= len(starts) {
+			hi = len(starts) - 1
+		}
+		if start >= 0 && lo <= end+1 {
+			if hi > end {
+				end = hi
+			}
+			continue
+		}
+		flush()
+		start, end = lo, hi
+	}
+	flush()
+	return ranges
+}
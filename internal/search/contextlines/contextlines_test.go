@@ -0,0 +1,54 @@
+package contextlines
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRanges(t *testing.T) {
+	content := []byte("a\nb\nc\nd\ne\nf\ng\nh\n")
+	tests := map[string]struct {
+		lines         []int
+		before, after int
+		want          []Range
+	}{
+		"no context": {
+			lines: []int{1},
+		},
+		"single match": {
+			lines: []int{3}, before: 1, after: 1,
+			want: []Range{{StartLine: 2, Preview: "c\nd\ne"}},
+		},
+		"clamped to file": {
+			lines: []int{0, 7}, before: 2, after: 2,
+			want: []Range{{StartLine: 0, Preview: "a\nb\nc"}, {StartLine: 5, Preview: "f\ng\nh"}},
+		},
+		"overlapping merged": {
+			lines: []int{4, 1}, before: 1, after: 1,
+			want: []Range{{StartLine: 0, Preview: "a\nb\nc\nd\ne\nf"}},
+		},
+		"before only": {
+			lines: []int{2, 6}, before: 1,
+			want: []Range{{StartLine: 1, Preview: "b\nc"}, {StartLine: 5, Preview: "f\ng"}},
+		},
+		"out of range line": {
+			lines: []int{100}, after: 1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Ranges(content, tt.lines, tt.before, tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no trailing newline", func(t *testing.T) {
+		got := Ranges([]byte("a\nb"), []int{0}, 0, 5)
+		want := []Range{{StartLine: 0, Preview: "a\nb"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+}
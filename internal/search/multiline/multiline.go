@@ -0,0 +1,36 @@
+// Package multiline detects search patterns that match text spanning several
+// lines. It is shared by the frontend, which sends such patterns to searcher
+// instead of Zoekt, and searcher, which reports their matches as ranges.
+package multiline
+
+import "regexp/syntax"
+
+// Regexp reports whether re explicitly matches a newline, either with a
+// literal newline (such as \n or [\n ]) or with a "." that matches newlines
+// (using the s flag). Character classes that merely include a newline, such as
+// \s or [^a], are not considered multi-line, so that common patterns keep
+// being matched line by line.
+func Regexp(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if r == '\n' {
+				return true
+			}
+		}
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			if re.Rune[i] == '\n' && re.Rune[i+1] == '\n' {
+				return true
+			}
+		}
+	case syntax.OpAnyChar:
+		return true
+	}
+	for _, sub := range re.Sub {
+		if Regexp(sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,618 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/search/query/syntax"
+)
+
+// This file implements the boolean query language, which extends the flat
+// query syntax handled by package syntax with the operators "and", "or",
+// "not" and parenthesized grouping. Queries are parsed into a tree of Nodes.
+// Subtrees that do not contain any of these operators can be converted back
+// into the flat syntax, so that they can be evaluated by the existing search
+// backends.
+
+// Node is a node in the boolean query tree produced by ParseAndOr. It is one
+// of *Pattern, *Parameter or *Operator.
+type Node interface {
+	String() string
+	node()
+}
+
+// Pattern is a term without a field, i.e., a search pattern.
+type Pattern struct {
+	Pos     int    // the starting character position of the pattern
+	Value   string // the raw value, including any quotes or slash delimiters
+	Negated bool   // the pattern is negated (e.g., -term or not term)
+}
+
+// Parameter is a field:value term.
+type Parameter struct {
+	Pos     int    // the starting character position of the parameter
+	Field   string // the field name, lowercased
+	Value   string // the raw value, including any quotes
+	Negated bool   // the parameter is negated (e.g., -field:value or not field:value)
+}
+
+// OperatorKind is the kind of an Operator.
+type OperatorKind int
+
+const (
+	// Or matches results that match any of its operands.
+	Or OperatorKind = iota
+	// And matches results that match all of its operands.
+	And
+	// Concat is the implicit operator between space-separated terms. Its
+	// operands are evaluated like in the flat query syntax: patterns are
+	// joined in order and parameters apply to all patterns.
+	Concat
+	// Not matches results that do not match its single operand.
+	Not
+)
+
+// Operator is a boolean operator applied to one or more operands.
+type Operator struct {
+	Kind     OperatorKind
+	Operands []Node
+}
+
+func (*Pattern) node()   {}
+func (*Parameter) node() {}
+func (*Operator) node()  {}
+
+func (p *Pattern) String() string {
+	if p.Negated {
+		return "-" + p.Value
+	}
+	return p.Value
+}
+
+func (p *Parameter) String() string {
+	s := p.Field + ":" + p.Value
+	if p.Negated {
+		return "-" + s
+	}
+	return s
+}
+
+func (o *Operator) String() string {
+	operands := make([]string, len(o.Operands))
+	for i, operand := range o.Operands {
+		operands[i] = operand.String()
+	}
+	switch o.Kind {
+	case Or:
+		return "(" + strings.Join(operands, " or ") + ")"
+	case And:
+		return "(" + strings.Join(operands, " and ") + ")"
+	case Not:
+		return "(not " + strings.Join(operands, " ") + ")"
+	default:
+		return strings.Join(operands, " ")
+	}
+}
+
+// ContainsAndOrOperator reports whether the tree rooted at n uses any of the
+// boolean operators, i.e., whether it needs to be evaluated by something other
+// than the flat query evaluation.
+func ContainsAndOrOperator(n Node) bool {
+	switch n := n.(type) {
+	case *Pattern:
+		return n.Negated
+	case *Operator:
+		if n.Kind != Concat {
+			return true
+		}
+		for _, operand := range n.Operands {
+			if ContainsAndOrOperator(operand) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ContainsPattern reports whether the tree rooted at n contains a pattern.
+func ContainsPattern(n Node) bool {
+	switch n := n.(type) {
+	case *Pattern:
+		return true
+	case *Operator:
+		for _, operand := range n.Operands {
+			if ContainsPattern(operand) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FlatQueryString returns the flat query syntax for the parameters in scope
+// followed by n. It panics if n uses a boolean operator.
+func FlatQueryString(scope []*Parameter, n Node) string {
+	if n != nil && ContainsAndOrOperator(n) {
+		panic("(bug) FlatQueryString called with a boolean operator: " + n.String())
+	}
+	var pieces []string
+	seen := make(map[string]struct{}, len(scope))
+	for _, p := range scope {
+		s := p.String()
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		pieces = append(pieces, s)
+	}
+	if n != nil {
+		if s := n.String(); s != "" {
+			pieces = append(pieces, s)
+		}
+	}
+	return strings.Join(pieces, " ")
+}
+
+// andOrKeywordRx matches queries which would have a different meaning in the
+// boolean query language than in the flat query syntax.
+var andOrKeywordRx = lazyregexp.New(`(?i)(^|\s)(and|or|not)(\s|$)|(^|\s)-?\(`)
+
+// ContainsAndOrKeyword reports whether input uses one of the boolean
+// operators or a parenthesized group.
+func ContainsAndOrKeyword(input string) bool {
+	return andOrKeywordRx.MatchString(input)
+}
+
+// ParseAndOr parses input using the boolean query grammar and returns the
+// root of its tree, or nil if input is empty. Returned errors are of type
+// *syntax.ParseError.
+//
+// BNF-ish query syntax:
+//
+//   query   := orExpr
+//   orExpr  := andExpr ("or" andExpr)*
+//   andExpr := concat ("and" concat)*
+//   concat  := unary (sep unary)*
+//   unary   := "not" unary | "(" orExpr ")" | term
+//   term    := {"-"} (parameter | pattern)
+//
+// Keywords are case insensitive. Parentheses that are balanced within a term
+// (as in `foo()`) are part of the term.
+func ParseAndOr(input string) (Node, error) {
+	tokens, err := scanAndOr(input)
+	if err != nil {
+		return nil, err
+	}
+	p := andOrParser{tokens: tokens}
+	if p.peek().typ == andOrTokenEOF {
+		return nil, nil
+	}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.typ != andOrTokenEOF {
+		return nil, &syntax.ParseError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %s", tok)}
+	}
+	return n, nil
+}
+
+// CheckAndOr typechecks the parameters in the tree rooted at n against the
+// default query configuration, and checks that negated terms are used in a
+// way that can be evaluated.
+func CheckAndOr(n Node) error {
+	if n == nil {
+		return nil
+	}
+	if isNegation(n) {
+		return &ValidationError{Msg: fmt.Sprintf("the negated term %s must be combined with a positive term using \"and\"", n)}
+	}
+	if err := checkAndOr(n); err != nil {
+		return err
+	}
+	return checkSelect(n)
+}
+
+// checkSelect checks that "select:" parameters apply to the whole query, i.e.
+// are only combined with the rest of the query using "and", since the results
+// are projected after the query has been evaluated.
+func checkSelect(n Node) error {
+	var check func(n Node, whole bool) error
+	check = func(n Node, whole bool) error {
+		switch n := n.(type) {
+		case *Parameter:
+			if n.Field != FieldSelect {
+				return nil
+			}
+			if !whole {
+				return &ValidationError{Msg: `the parameter "select:" must apply to the whole query, not be used inside "or" or "not"`}
+			}
+			value := n.Value
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			}
+			return validateSelect(value)
+		case *Operator:
+			whole = whole && (n.Kind == And || n.Kind == Concat)
+			for _, operand := range n.Operands {
+				if err := check(operand, whole); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return check(n, true)
+}
+
+func checkAndOr(n Node) error {
+	switch n := n.(type) {
+	case *Parameter:
+		expr := &syntax.Expr{Pos: n.Pos, Not: n.Negated, Field: n.Field, Value: n.Value, ValueType: syntax.TokenLiteral}
+		if strings.HasPrefix(n.Value, `"`) || strings.HasPrefix(n.Value, `'`) {
+			expr.ValueType = syntax.TokenQuoted
+		}
+		if _, err := conf.Check(syntax.ParseTree{expr}); err != nil {
+			return err
+		}
+		if n.Field == FieldReplace {
+			return &ValidationError{Msg: `the parameter "replace:" may not be used with "and", "or" or "not"`}
+		}
+		if n.Field == FieldContext || n.Field == FieldContextBefore || n.Field == FieldContextAfter {
+			value := n.Value
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			}
+			if err := validateContextLines(n.Field, value); err != nil {
+				return err
+			}
+		}
+	case *Operator:
+		if n.Kind == And || n.Kind == Concat {
+			positive := false
+			for _, operand := range n.Operands {
+				if ContainsPattern(operand) && !isNegation(operand) {
+					positive = true
+				}
+			}
+			for _, operand := range n.Operands {
+				if isNegation(operand) && ContainsPattern(operand) && !positive {
+					return &ValidationError{Msg: fmt.Sprintf("the negated term %s must be combined with a positive pattern using \"and\"", operand)}
+				}
+			}
+		} else {
+			for _, operand := range n.Operands {
+				if isNegation(operand) && ContainsPattern(operand) {
+					return &ValidationError{Msg: fmt.Sprintf("the negated term %s must be combined with a positive pattern using \"and\"", operand)}
+				}
+			}
+		}
+		for _, operand := range n.Operands {
+			if err := checkAndOr(operand); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isNegation reports whether n is a negated pattern or a "not" operator.
+func isNegation(n Node) bool {
+	switch n := n.(type) {
+	case *Pattern:
+		return n.Negated
+	case *Operator:
+		return n.Kind == Not
+	}
+	return false
+}
+
+// ProcessAndOr parses and checks a query in the boolean query language.
+func ProcessAndOr(input string) (Node, error) {
+	n, err := ParseAndOr(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckAndOr(n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+type andOrTokenType int
+
+const (
+	andOrTokenEOF andOrTokenType = iota
+	andOrTokenLParen
+	andOrTokenRParen
+	andOrTokenAnd
+	andOrTokenOr
+	andOrTokenNot
+	andOrTokenTerm
+)
+
+type andOrToken struct {
+	typ   andOrTokenType
+	value string
+	pos   int
+}
+
+func (t andOrToken) String() string {
+	switch t.typ {
+	case andOrTokenEOF:
+		return "end of query"
+	case andOrTokenRParen:
+		return `")"`
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+var andOrKeywords = map[string]andOrTokenType{
+	"and": andOrTokenAnd,
+	"or":  andOrTokenOr,
+	"not": andOrTokenNot,
+}
+
+// scanAndOr splits input into parentheses, keywords and terms.
+func scanAndOr(input string) ([]andOrToken, error) {
+	var tokens []andOrToken
+	i := 0
+	for i < len(input) {
+		r, w := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += w
+		case r == '(':
+			tokens = append(tokens, andOrToken{typ: andOrTokenLParen, value: "(", pos: i})
+			i += w
+		case r == ')':
+			tokens = append(tokens, andOrToken{typ: andOrTokenRParen, value: ")", pos: i})
+			i += w
+		default:
+			end, err := scanAndOrTerm(input, i)
+			if err != nil {
+				return nil, err
+			}
+			value := input[i:end]
+			typ, ok := andOrKeywords[strings.ToLower(value)]
+			if !ok {
+				typ = andOrTokenTerm
+			}
+			tokens = append(tokens, andOrToken{typ: typ, value: value, pos: i})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// scanAndOrTerm returns the end position of the term starting at start. A
+// term ends at whitespace outside of quotes and slash delimiters, or at a
+// closing parenthesis that is not balanced within the term.
+func scanAndOrTerm(input string, start int) (int, error) {
+	depth := 0
+	i := start
+	valueStart := start
+	if strings.HasPrefix(input[i:], "-") {
+		valueStart++
+	}
+	valueStart += len(fieldPrefixRx.FindString(input[valueStart:]))
+	for i < len(input) {
+		r, w := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case r == '\\':
+			i += w
+			if i >= len(input) {
+				return i, nil
+			}
+			_, w = utf8.DecodeRuneInString(input[i:])
+		case r == '"' || r == '\'' || (r == '/' && i == valueStart):
+			end, err := scanDelimited(input, i, r)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+			continue
+		case unicode.IsSpace(r):
+			return i, nil
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth == 0 {
+				return i, nil
+			}
+			depth--
+		}
+		i += w
+	}
+	return i, nil
+}
+
+// scanDelimited returns the position after the closing delimiter of the
+// quoted string or slash-delimited pattern starting at start.
+func scanDelimited(input string, start int, delim rune) (int, error) {
+	i := start + 1
+	for i < len(input) {
+		r, w := utf8.DecodeRuneInString(input[i:])
+		switch r {
+		case '\\':
+			i += w
+			if i >= len(input) {
+				return 0, &syntax.ParseError{Pos: start, Msg: "unterminated escape sequence"}
+			}
+			_, w = utf8.DecodeRuneInString(input[i:])
+		case delim:
+			return i + w, nil
+		}
+		i += w
+	}
+	if delim == '/' {
+		// Like the flat syntax, an unclosed pattern extends to the end of the
+		// input.
+		return i, nil
+	}
+	return 0, &syntax.ParseError{Pos: start, Msg: "unclosed quoted string"}
+}
+
+var fieldPrefixRx = lazyregexp.New(`^[a-zA-Z]+:`)
+
+type andOrParser struct {
+	tokens []andOrToken
+	pos    int
+}
+
+func (p *andOrParser) peek() andOrToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	end := 0
+	if len(p.tokens) > 0 {
+		last := p.tokens[len(p.tokens)-1]
+		end = last.pos + len(last.value)
+	}
+	return andOrToken{typ: andOrTokenEOF, pos: end}
+}
+
+func (p *andOrParser) next() andOrToken {
+	tok := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return tok
+}
+
+// orExpr := andExpr ("or" andExpr)*
+func (p *andOrParser) parseOr() (Node, error) {
+	return p.parseBinary(Or, andOrTokenOr, p.parseAnd)
+}
+
+// andExpr := concat ("and" concat)*
+func (p *andOrParser) parseAnd() (Node, error) {
+	return p.parseBinary(And, andOrTokenAnd, p.parseConcat)
+}
+
+func (p *andOrParser) parseBinary(kind OperatorKind, keyword andOrTokenType, parseOperand func() (Node, error)) (Node, error) {
+	var operands []Node
+	for {
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		if p.peek().typ != keyword {
+			break
+		}
+		p.next()
+	}
+	return newOperator(kind, operands), nil
+}
+
+// concat := unary (sep unary)*
+func (p *andOrParser) parseConcat() (Node, error) {
+	var operands []Node
+loop:
+	for {
+		switch p.peek().typ {
+		case andOrTokenEOF, andOrTokenRParen, andOrTokenAnd, andOrTokenOr:
+			break loop
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 0 {
+		tok := p.peek()
+		return nil, &syntax.ParseError{Pos: tok.pos, Msg: fmt.Sprintf("got %s, want expression", tok)}
+	}
+	return newOperator(Concat, operands), nil
+}
+
+// unary := "not" unary | "(" orExpr ")" | term
+func (p *andOrParser) parseUnary() (Node, error) {
+	tok := p.next()
+	switch tok.typ {
+	case andOrTokenNot:
+		switch p.peek().typ {
+		case andOrTokenEOF, andOrTokenRParen, andOrTokenAnd, andOrTokenOr:
+			tok := p.peek()
+			return nil, &syntax.ParseError{Pos: tok.pos, Msg: fmt.Sprintf("got %s, want expression after \"not\"", tok)}
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negate(operand), nil
+	case andOrTokenLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.typ != andOrTokenRParen {
+			return nil, &syntax.ParseError{Pos: tok.pos, Msg: "unbalanced parenthesis"}
+		}
+		return n, nil
+	case andOrTokenTerm:
+		return parseAndOrTerm(tok), nil
+	}
+	return nil, &syntax.ParseError{Pos: tok.pos, Msg: fmt.Sprintf("got %s, want expression", tok)}
+}
+
+func parseAndOrTerm(tok andOrToken) Node {
+	value := tok.value
+	negated := false
+	if len(value) > 1 && value[0] == '-' {
+		negated = true
+		value = value[1:]
+	}
+	if m := fieldPrefixRx.FindString(value); m != "" {
+		return &Parameter{
+			Pos:     tok.pos,
+			Field:   strings.ToLower(strings.TrimSuffix(m, ":")),
+			Value:   value[len(m):],
+			Negated: negated,
+		}
+	}
+	return &Pattern{Pos: tok.pos, Value: value, Negated: negated}
+}
+
+// negate returns the negation of n. Negated terms are represented with their
+// Negated field rather than with a Not operator.
+func negate(n Node) Node {
+	switch n := n.(type) {
+	case *Pattern:
+		cpy := *n
+		cpy.Negated = !cpy.Negated
+		return &cpy
+	case *Parameter:
+		cpy := *n
+		cpy.Negated = !cpy.Negated
+		return &cpy
+	case *Operator:
+		if n.Kind == Not {
+			return n.Operands[0]
+		}
+	}
+	return &Operator{Kind: Not, Operands: []Node{n}}
+}
+
+// newOperator returns an operator of the given kind, flattening nested
+// operators of the same kind. A single operand is returned as is.
+func newOperator(kind OperatorKind, operands []Node) Node {
+	if len(operands) == 1 {
+		return operands[0]
+	}
+	var flattened []Node
+	for _, operand := range operands {
+		if o, ok := operand.(*Operator); ok && o.Kind == kind {
+			flattened = append(flattened, o.Operands...)
+			continue
+		}
+		flattened = append(flattened, operand)
+	}
+	return &Operator{Kind: kind, Operands: flattened}
+}
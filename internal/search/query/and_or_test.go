@@ -0,0 +1,152 @@
+package query
+
+import (
+	"testing"
+)
+
+func TestParseAndOr(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		{input: "", want: "<nil>"},
+		{input: "a", want: "a"},
+		{input: "a b", want: "a b"},
+		{input: "a and b", want: "(a and b)"},
+		{input: "a AND b", want: "(a and b)"},
+		{input: "a or b", want: "(a or b)"},
+		{input: "a or b and c", want: "(a or (b and c))"},
+		{input: "(a or b) and c", want: "((a or b) and c)"},
+		{input: "a and b and c", want: "(a and b and c)"},
+		{input: "a or (b or c)", want: "(a or b or c)"},
+		{input: "a b and c d", want: "(a b and c d)"},
+		{input: "repo:a (lang:go or lang:rust)", want: "repo:a (lang:go or lang:rust)"},
+		{input: "(foo or bar) and not baz", want: "((foo or bar) and -baz)"},
+		{input: "foo and not (bar or baz)", want: "(foo and (not (bar or baz)))"},
+		{input: "not not foo", want: "foo"},
+		{input: "not repo:a foo", want: "-repo:a foo"},
+		{input: "-file:a foo", want: "-file:a foo"},
+		{input: "Repo:a", want: "repo:a"},
+		{input: "foo() or bar", want: "(foo() or bar)"},
+		{input: "(foo())", want: "foo()"},
+		{input: `"a or b" or c`, want: `("a or b" or c)`},
+		{input: `file:"a b" or c`, want: `(file:"a b" or c)`},
+		{input: `/a b/ or c`, want: `(/a b/ or c)`},
+		{input: `a\) or b`, want: `(a\) or b)`},
+		{input: "android or orange", want: "(android or orange)"},
+
+		{input: "(a", wantErr: "parse error at character 0: unbalanced parenthesis"},
+		{input: "a)", wantErr: `parse error at character 1: unexpected ")"`},
+		{input: "()", wantErr: `parse error at character 1: got ")", want expression`},
+		{input: "a and", wantErr: "parse error at character 5: got end of query, want expression"},
+		{input: "or a", wantErr: `parse error at character 0: got "or", want expression`},
+		{input: "a not", wantErr: `parse error at character 5: got end of query, want expression after "not"`},
+		{input: `"a or b`, wantErr: "parse error at character 0: unclosed quoted string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			n, err := ParseAndOr(tt.input)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("got nil error, want %q", tt.wantErr)
+				}
+				if err.Error() != tt.wantErr {
+					t.Fatalf("got error %q, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := "<nil>"
+			if n != nil {
+				got = n.String()
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAndOr(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{input: "repo:a (lang:go or lang:rust)"},
+		{input: "foo and not bar"},
+		{input: "foo and not (repo:a or repo:b)"},
+		{input: "not repo:a foo"},
+		{input: "not foo", wantErr: `the negated term -foo must be combined with a positive term using "and"`},
+		{input: "foo or not bar", wantErr: `the negated term -bar must be combined with a positive pattern using "and"`},
+		{input: "repo:a and not bar", wantErr: `the negated term -bar must be combined with a positive pattern using "and"`},
+		{input: "foo or bar replace:baz", wantErr: `the parameter "replace:" may not be used with "and", "or" or "not"`},
+		{input: "foo or nosuchfield:bar", wantErr: `type error at character 7: unrecognized field "nosuchfield"`},
+		{input: "foo or case:maybe", wantErr: `type error at character 7: invalid boolean "maybe"`},
+		{input: "foo or -lang:go", wantErr: ""},
+		{input: "foo or -case:yes", wantErr: `type error at character 7: field "case" does not support negation`},
+		{input: "(foo or bar) select:repo"},
+		{input: "foo and bar select:repo"},
+		{input: "select:file"},
+		{input: "(foo or bar) select:commit", wantErr: `invalid select: value "commit" (valid values are: repo, file, symbol, author)`},
+		{input: "foo or (bar select:repo)", wantErr: `the parameter "select:" must apply to the whole query, not be used inside "or" or "not"`},
+		{input: "foo or -select:repo", wantErr: `type error at character 7: field "select" does not support negation`},
+		{input: "(foo or bar) context:2"},
+		{input: "(foo or bar) context:x", wantErr: `invalid context: value "x" (must be a number of lines between 0 and 20)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ProcessAndOr(tt.input)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContainsAndOrOperator(t *testing.T) {
+	tests := map[string]bool{
+		"a b":                false,
+		"a and b":            true,
+		"a or b":             true,
+		"(a)":                false,
+		"repo:a a":           false,
+		"repo:a (a)":         false,
+		"a -b":               true,
+		"-repo:a a":          false,
+		"a (b or c)":         true,
+		"not (repo:a) foo()": false,
+	}
+	for input, want := range tests {
+		n, err := ParseAndOr(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ContainsAndOrOperator(n); got != want {
+			t.Errorf("%q: got %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestFlatQueryString(t *testing.T) {
+	scope := []*Parameter{{Field: "repo", Value: "a"}, {Field: "lang", Value: "go", Negated: true}, {Field: "repo", Value: "a"}}
+	n, err := ParseAndOr(`foo "bar baz" file:x`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `repo:a -lang:go foo "bar baz" file:x`
+	if got := FlatQueryString(scope, n); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got, want := FlatQueryString(scope, nil), "repo:a -lang:go"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -4,6 +4,8 @@ package query
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/search/query/syntax"
@@ -23,8 +25,18 @@ const (
 	FieldType               = "type"
 	FieldRepoHasFile        = "repohasfile"
 	FieldRepoHasCommitAfter = "repohascommitafter"
+	FieldRepoTopic          = "repotopic"
+	FieldRepoLang           = "repolang"
+	FieldVisibility         = "visibility"
+	FieldStars              = "stars"
 	FieldPatternType        = "patterntype"
 	FieldContent            = "content"
+	FieldSelect             = "select"
+
+	// For text search only:
+	FieldContext       = "context"       // number of lines shown before and after each match
+	FieldContextBefore = "contextbefore" // number of lines shown before each match, overrides context:
+	FieldContextAfter  = "contextafter"  // number of lines shown after each match, overrides context:
 
 	// For diff and commit search only:
 	FieldBefore    = "before"
@@ -59,9 +71,18 @@ var (
 			FieldType:        stringFieldType,
 			FieldPatternType: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 			FieldContent:     {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+			FieldSelect:      {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+
+			FieldContext:       {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+			FieldContextBefore: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+			FieldContextAfter:  {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 
 			FieldRepoHasFile:        regexpNegatableFieldType,
 			FieldRepoHasCommitAfter: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+			FieldRepoTopic:          {Literal: types.StringType, Quoted: types.StringType, Negatable: true},
+			FieldRepoLang:           {Literal: types.StringType, Quoted: types.StringType, Negatable: true},
+			FieldVisibility:         {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+			FieldStars:              {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 
 			FieldBefore:    stringFieldType,
 			FieldAfter:     stringFieldType,
@@ -145,6 +166,149 @@ func processSearchPattern(q *Query) string {
 	return strings.Join(pieces, " ")
 }
 
+// Values of the "select:" field, which projects the results of a search.
+const (
+	SelectRepo   = "repo"   // the distinct repositories of the results
+	SelectFile   = "file"   // the distinct files of the results
+	SelectSymbol = "symbol" // the symbols enclosing the line matches
+	SelectAuthor = "author" // the distinct authors of commit results
+)
+
+func validateSelect(value string) error {
+	switch value {
+	case SelectRepo, SelectFile, SelectSymbol, SelectAuthor:
+		return nil
+	}
+	return &ValidationError{Msg: fmt.Sprintf("invalid select: value %q (valid values are: repo, file, symbol, author)", value)}
+}
+
+// MaxContextLines is the maximum number of lines that may be requested before
+// or after each match with the "context:" fields.
+const MaxContextLines = 20
+
+func validateContextLines(field, value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > MaxContextLines {
+		return &ValidationError{Msg: fmt.Sprintf("invalid %s: value %q (must be a number of lines between 0 and %d)", field, value, MaxContextLines)}
+	}
+	return nil
+}
+
+// ContextLines returns the number of lines to show before and after each
+// match, as requested by the "context:", "contextbefore:" and "contextafter:"
+// fields. Invalid values are treated as 0.
+func (q *Query) ContextLines() (before, after int) {
+	if value, _ := q.StringValue(FieldContext); value != "" {
+		before, _ = strconv.Atoi(value)
+		after = before
+	}
+	if value, _ := q.StringValue(FieldContextBefore); value != "" {
+		before, _ = strconv.Atoi(value)
+	}
+	if value, _ := q.StringValue(FieldContextAfter); value != "" {
+		after, _ = strconv.Atoi(value)
+	}
+	return before, after
+}
+
+// Values of the "visibility:" field, which filters repositories by whether
+// they are private on their code host.
+const (
+	VisibilityAny     = "any"
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+func validateVisibility(value string) error {
+	switch strings.ToLower(value) {
+	case VisibilityAny, VisibilityPublic, VisibilityPrivate:
+		return nil
+	}
+	return &ValidationError{Msg: fmt.Sprintf("invalid visibility: value %q (valid values are: any, public, private)", value)}
+}
+
+// Visibility returns the lower-cased value of the "visibility:" field, or
+// VisibilityAny if it isn't set.
+func (q *Query) Visibility() string {
+	if value, _ := q.StringValue(FieldVisibility); value != "" {
+		return strings.ToLower(value)
+	}
+	return VisibilityAny
+}
+
+// parseStars parses a "stars:" value into inclusive bounds, nil meaning
+// unbounded. It accepts the GitHub search syntax: N, >N, >=N, <N, <=N and
+// N..M.
+func parseStars(value string) (min, max *int, err error) {
+	atoi := func(s string) (*int, error) {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return nil, errors.New("not a number of stars")
+		}
+		return &n, nil
+	}
+
+	offset := func(n *int, by int) *int {
+		m := *n + by
+		return &m
+	}
+
+	switch {
+	case strings.Contains(value, ".."):
+		i := strings.Index(value, "..")
+		if min, err = atoi(value[:i]); err != nil {
+			return nil, nil, err
+		}
+		if max, err = atoi(value[i+2:]); err != nil {
+			return nil, nil, err
+		}
+		if *min > *max {
+			return nil, nil, errors.New("empty range")
+		}
+	case strings.HasPrefix(value, ">="):
+		min, err = atoi(value[2:])
+	case strings.HasPrefix(value, ">"):
+		if min, err = atoi(value[1:]); err == nil {
+			min = offset(min, 1)
+		}
+	case strings.HasPrefix(value, "<="):
+		max, err = atoi(value[2:])
+	case strings.HasPrefix(value, "<"):
+		if max, err = atoi(value[1:]); err == nil {
+			if *max == 0 {
+				return nil, nil, errors.New("empty range")
+			}
+			max = offset(max, -1)
+		}
+	default:
+		if min, err = atoi(value); err == nil {
+			max = min
+		}
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+	return min, max, nil
+}
+
+func validateStars(value string) error {
+	if _, _, err := parseStars(value); err != nil {
+		return &ValidationError{Msg: fmt.Sprintf("invalid stars: value %q (valid values look like 100, >100, >=100, <100, <=100 or 10..100)", value)}
+	}
+	return nil
+}
+
+// Stars returns the inclusive bounds on the number of stars requested by the
+// "stars:" field. A nil bound is unbounded. Invalid values are treated as
+// unbounded.
+func (q *Query) Stars() (min, max *int) {
+	if value, _ := q.StringValue(FieldStars); value != "" {
+		min, max, _ = parseStars(value)
+	}
+	return min, max
+}
+
 type ValidationError struct {
 	Msg string
 }
@@ -156,6 +320,28 @@ func (e *ValidationError) Error() string {
 // Validate validates legal combinations of fields and search patterns of a
 // successfully parsed query.
 func Validate(q *Query, searchType SearchType) error {
+	if value, _ := q.StringValue(FieldSelect); value != "" {
+		if err := validateSelect(value); err != nil {
+			return err
+		}
+	}
+	if value, _ := q.StringValue(FieldVisibility); value != "" {
+		if err := validateVisibility(value); err != nil {
+			return err
+		}
+	}
+	if value, _ := q.StringValue(FieldStars); value != "" {
+		if err := validateStars(value); err != nil {
+			return err
+		}
+	}
+	for _, field := range []string{FieldContext, FieldContextBefore, FieldContextAfter} {
+		if value, _ := q.StringValue(field); value != "" {
+			if err := validateContextLines(field, value); err != nil {
+				return err
+			}
+		}
+	}
 	if searchType == SearchTypeStructural {
 		if q.Fields[FieldCase] != nil {
 			return errors.New(`the parameter "case:" is not valid for structural search, matching is always case-sensitive`)
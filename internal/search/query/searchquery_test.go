@@ -163,6 +163,42 @@ func TestQuery_Validate(t *testing.T) {
 			SearchType: SearchTypeStructural,
 			Want:       "",
 		},
+		{
+			Name:       `Valid "select:" value`,
+			Query:      `select:repo foo`,
+			SearchType: SearchTypeRegex,
+			Want:       "",
+		},
+		{
+			Name:       `Invalid "select:" value`,
+			Query:      `select:commit foo`,
+			SearchType: SearchTypeRegex,
+			Want:       `invalid select: value "commit" (valid values are: repo, file, symbol, author)`,
+		},
+		{
+			Name:       `Invalid "context:" value`,
+			Query:      `context:many foo`,
+			SearchType: SearchTypeRegex,
+			Want:       `invalid context: value "many" (must be a number of lines between 0 and 20)`,
+		},
+		{
+			Name:       `Too large "contextafter:" value`,
+			Query:      `contextafter:21 foo`,
+			SearchType: SearchTypeRegex,
+			Want:       `invalid contextafter: value "21" (must be a number of lines between 0 and 20)`,
+		},
+		{
+			Name:       `Invalid "visibility:" value`,
+			Query:      `visibility:internal foo`,
+			SearchType: SearchTypeRegex,
+			Want:       `invalid visibility: value "internal" (valid values are: any, public, private)`,
+		},
+		{
+			Name:       `Invalid "stars:" value`,
+			Query:      `stars:lots foo`,
+			SearchType: SearchTypeRegex,
+			Want:       `invalid stars: value "lots" (valid values look like 100, >100, >=100, <100, <=100 or 10..100)`,
+		},
 	}
 	for _, tt := range cases {
 		t.Run(tt.Name, func(t *testing.T) {
@@ -176,6 +212,61 @@ func TestQuery_Validate(t *testing.T) {
 	}
 }
 
+func TestQuery_ContextLines(t *testing.T) {
+	cases := []struct {
+		query                 string
+		wantBefore, wantAfter int
+	}{
+		{query: "foo"},
+		{query: "foo context:3", wantBefore: 3, wantAfter: 3},
+		{query: "foo context:3 contextbefore:1", wantBefore: 1, wantAfter: 3},
+		{query: "foo contextafter:2", wantAfter: 2},
+	}
+	for _, tt := range cases {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseAndCheck(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before, after := q.ContextLines()
+			if before != tt.wantBefore || after != tt.wantAfter {
+				t.Errorf("got (%d, %d), want (%d, %d)", before, after, tt.wantBefore, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestQuery_Stars(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+
+	cases := []struct {
+		query    string
+		min, max *int
+	}{
+		{query: "foo"},
+		{query: "foo stars:100", min: intPtr(100), max: intPtr(100)},
+		{query: "foo stars:>100", min: intPtr(101)},
+		{query: "foo stars:>=100", min: intPtr(100)},
+		{query: "foo stars:<100", max: intPtr(99)},
+		{query: "foo stars:<=100", max: intPtr(100)},
+		{query: "foo stars:10..100", min: intPtr(10), max: intPtr(100)},
+		{query: "foo stars:<0"},
+		{query: "foo stars:100..10"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseAndCheck(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			min, max := q.Stars()
+			if !cmp.Equal(min, tt.min) || !cmp.Equal(max, tt.max) {
+				t.Errorf("got (%v, %v), want (%v, %v)", min, max, tt.min, tt.max)
+			}
+		})
+	}
+}
+
 func TestQuery_CaseInsensitiveFields(t *testing.T) {
 	query, err := ParseAndCheck("repoHasFile:foo")
 	if err != nil {
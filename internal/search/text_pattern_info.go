@@ -3,12 +3,33 @@ package search
 
 import (
 	"regexp/syntax"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/multiline"
 )
 
 func (p *TextPatternInfo) IsEmpty() bool {
 	return p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0
 }
 
+// IsMultiline reports whether the pattern can match text spanning several
+// lines (see multiline.Regexp). Matches of such patterns can't be found line
+// by line.
+func (p *TextPatternInfo) IsMultiline() bool {
+	if p.IsStructuralPat {
+		// Structural search has its own notion of matching across lines.
+		return false
+	}
+	if !p.IsRegExp {
+		return strings.Contains(p.Pattern, "\n")
+	}
+	re, err := syntax.Parse(p.Pattern, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	return multiline.Regexp(re)
+}
+
 func (p *TextPatternInfo) Validate() error {
 	if p.IsRegExp {
 		if _, err := syntax.Parse(p.Pattern, syntax.Perl); err != nil {
@@ -0,0 +1,28 @@
+package search
+
+import "testing"
+
+func TestTextPatternInfo_IsMultiline(t *testing.T) {
+	tests := map[string]struct {
+		p    TextPatternInfo
+		want bool
+	}{
+		"literal":                   {p: TextPatternInfo{Pattern: `foo\nbar`}},
+		"literal with newline":      {p: TextPatternInfo{Pattern: "foo\nbar"}, want: true},
+		"regexp":                    {p: TextPatternInfo{Pattern: `foo.*bar`, IsRegExp: true}},
+		"regexp with newline":       {p: TextPatternInfo{Pattern: `foo\nbar`, IsRegExp: true}, want: true},
+		"regexp with newline class": {p: TextPatternInfo{Pattern: `foo[ \n]+bar`, IsRegExp: true}, want: true},
+		"regexp with s flag":        {p: TextPatternInfo{Pattern: `(?s)foo.*bar`, IsRegExp: true}, want: true},
+		"regexp with whitespace":    {p: TextPatternInfo{Pattern: `foo\s+bar`, IsRegExp: true}},
+		"regexp with negated class": {p: TextPatternInfo{Pattern: `"[^"]*"`, IsRegExp: true}},
+		"invalid regexp":            {p: TextPatternInfo{Pattern: `(\n`, IsRegExp: true}},
+		"structural":                {p: TextPatternInfo{Pattern: "foo(:[_])\n", IsStructuralPat: true}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.p.IsMultiline(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
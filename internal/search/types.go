@@ -111,8 +111,6 @@ type TextPatternInfo struct {
 	IsCaseSensitive bool
 	FileMatchLimit  int32
 
-	// We do not support IsMultiline
-	// IsMultiline     bool
 	IncludePatterns []string
 	ExcludePattern  string
 
@@ -126,6 +124,15 @@ type TextPatternInfo struct {
 	PatternMatchesPath    bool
 
 	Languages []string
+
+	// ContextBefore and ContextAfter are the number of lines shown before
+	// and after each matching line.
+	ContextBefore int
+	ContextAfter  int
+
+	// FilePaths are the paths of the candidate files for structural search,
+	// as found by Zoekt. If set, only these files are searched.
+	FilePaths []string
 }
 
 // CommitPatternInfo is the data type that describes the properties of
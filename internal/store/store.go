@@ -241,7 +241,7 @@ func (s *Store) fetch(ctx context.Context, repo gitserver.Repo, commit api.Commi
 		}
 		done(err)
 		// CloseWithError is guaranteed to return a nil error
-		_ = pw.CloseWithError(errors.Wrapf(err, "failed to fetch %s@%s", repo, commit))
+		_ = pw.CloseWithError(errors.Wrapf(err, "failed to fetch %s@%s", repo.Name, commit))
 	}()
 
 	return pr, nil
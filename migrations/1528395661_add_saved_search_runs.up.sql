@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS saved_search_runs (
+    id bigserial PRIMARY KEY,
+    saved_search_id integer NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE DEFERRABLE,
+    query text NOT NULL,
+    executed_at timestamp with time zone NOT NULL DEFAULT now(),
+    exec_duration_ns bigint NOT NULL,
+    result_count integer NOT NULL,
+    limit_hit boolean NOT NULL DEFAULT false,
+    fingerprints jsonb NOT NULL DEFAULT '[]'::jsonb,
+    added jsonb NOT NULL DEFAULT '[]'::jsonb,
+    removed jsonb NOT NULL DEFAULT '[]'::jsonb
+);
+
+CREATE INDEX IF NOT EXISTS saved_search_runs_saved_search_id_executed_at ON saved_search_runs(saved_search_id, executed_at DESC);
+
+COMMIT;
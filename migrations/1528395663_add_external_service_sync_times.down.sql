@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE external_services DROP COLUMN IF EXISTS last_sync_at;
+ALTER TABLE external_services DROP COLUMN IF EXISTS last_full_sync_at;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE external_services ADD COLUMN IF NOT EXISTS last_sync_at timestamp with time zone;
+ALTER TABLE external_services ADD COLUMN IF NOT EXISTS last_full_sync_at timestamp with time zone;
+
+COMMIT;
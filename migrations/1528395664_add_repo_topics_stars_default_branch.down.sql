@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS repo_stars_idx;
+DROP INDEX IF EXISTS repo_topics_gin_idx;
+
+ALTER TABLE repo DROP COLUMN IF EXISTS default_branch;
+ALTER TABLE repo DROP COLUMN IF EXISTS stars;
+ALTER TABLE repo DROP COLUMN IF EXISTS topics;
+
+COMMIT;
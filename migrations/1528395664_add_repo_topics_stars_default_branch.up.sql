@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE repo ADD COLUMN IF NOT EXISTS topics text[] NOT NULL DEFAULT '{}';
+ALTER TABLE repo ADD COLUMN IF NOT EXISTS stars integer NOT NULL DEFAULT 0;
+ALTER TABLE repo ADD COLUMN IF NOT EXISTS default_branch text;
+
+CREATE INDEX IF NOT EXISTS repo_topics_gin_idx ON repo USING gin (topics);
+CREATE INDEX IF NOT EXISTS repo_stars_idx ON repo (stars);
+
+COMMIT;
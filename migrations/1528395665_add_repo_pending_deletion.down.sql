@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS repo_pending_deletion_idx;
+
+ALTER TABLE repo DROP COLUMN IF EXISTS missing_syncs;
+ALTER TABLE repo DROP COLUMN IF EXISTS pending_deletion_at;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE repo ADD COLUMN IF NOT EXISTS pending_deletion_at timestamp with time zone;
+ALTER TABLE repo ADD COLUMN IF NOT EXISTS missing_syncs integer NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS repo_pending_deletion_idx ON repo (pending_deletion_at) WHERE pending_deletion_at IS NOT NULL;
+
+COMMIT;
@@ -88,6 +88,16 @@
 // 1528395659_user_pending_perms_table_add_service_type_and_id.up.sql (1.289kB)
 // 1528395660_add_state_columns_to_changesets.down.sql (215B)
 // 1528395660_add_state_columns_to_changesets.up.sql (544B)
+// 1528395661_add_saved_search_runs.down.sql (57B)
+// 1528395661_add_saved_search_runs.up.sql (680B)
+// 1528395662_add_saved_search_webhooks.down.sql (209B)
+// 1528395662_add_saved_search_webhooks.up.sql (259B)
+// 1528395663_add_external_service_sync_times.down.sql (154B)
+// 1528395663_add_external_service_sync_times.up.sql (210B)
+// 1528395664_add_repo_topics_stars_default_branch.down.sql (245B)
+// 1528395664_add_repo_topics_stars_default_branch.up.sql (370B)
+// 1528395665_add_repo_pending_deletion.down.sql (180B)
+// 1528395665_add_repo_pending_deletion.up.sql (312B)
 
 package migrations
 
@@ -1916,6 +1926,206 @@ func _1528395660_add_state_columns_to_changesetsUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1528395661_add_saved_search_runsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x39\x00\xc6\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x61\x76\x65\x64\x5f\x73\x65\x61\x72\x63\x68\x5f\x72\x75\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x63\xd8\x64\xdb\x39\x00\x00\x00")
+
+func _1528395661_add_saved_search_runsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395661_add_saved_search_runsDownSql,
+		"1528395661_add_saved_search_runs.down.sql",
+	)
+}
+
+func _1528395661_add_saved_search_runsDownSql() (*asset, error) {
+	bytes, err := _1528395661_add_saved_search_runsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395661_add_saved_search_runs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xcd, 0x7b, 0x4, 0x1c, 0xbe, 0x4f, 0x37, 0xae, 0x3b, 0xb9, 0x97, 0x34, 0xfd, 0xf5, 0x85, 0x9d, 0xb5, 0xc7, 0xbf, 0x61, 0xb6, 0xb1, 0x6a, 0xb7, 0xcf, 0xcc, 0x37, 0x98, 0xb4, 0xc, 0x34, 0x8b}}
+	return a, nil
+}
+
+var __1528395661_add_saved_search_runsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\xd1\xae\x9a\x40\x10\x86\xef\x79\x8a\xb9\x3b\x98\x9c\x27\x38\x5e\x21\x8c\x0d\x29\x42\x03\x98\x68\x9a\x66\xb3\xba\xa3\x4e\x03\xbb\x76\x77\x51\xdb\xa7\x6f\x84\xa4\x16\xb9\x38\x5e\xee\xce\xf7\xcf\xff\x4f\x66\x16\xf8\x25\xcd\xe7\x41\x10\x97\x18\xd5\x08\x75\xb4\xc8\x10\xd2\x25\xe4\x45\x0d\xb8\x49\xab\xba\x02\x27\x2f\xa4\x84\x23\x69\xf7\x27\x61\x3b\xed\x20\x0c\x00\x00\x58\xc1\x8e\x8f\x8e\x2c\xcb\x06\xbe\x95\xe9\x2a\x2a\xb7\xf0\x15\xb7\xef\x7d\x75\xa4\x62\x05\xac\x3d\x1d\xc9\xf6\x8d\xf3\x75\x96\x41\x89\x4b\x2c\x31\x8f\x71\xec\x40\x2e\x64\x35\x83\x22\x87\x04\x33\xac\x11\xe2\xa8\x8a\xa3\x04\x21\xb9\xf3\xe5\x3d\xdf\x60\xf0\xab\x23\xfb\x1b\x3c\xdd\xfc\xbf\x9e\x43\x81\x6e\xb4\xef\x3c\x29\x21\x3d\x78\x6e\xc9\x79\xd9\x9e\xe1\xca\xfe\xd4\x3f\xe1\x8f\xd1\xf4\x88\x91\xe0\x32\x5a\x67\x35\x68\x73\x0d\x67\x8f\x06\x42\x75\x56\x7a\x36\x5a\x68\x77\x1f\x93\xf5\xb3\x8d\x25\xd7\x35\x5e\xec\x4d\xa7\xfd\x64\xba\x01\x69\xb8\x65\x2f\x4e\xec\x61\x67\x4c\x43\x52\x4f\x6d\x0f\xb2\x71\x34\xd0\x07\xd6\x47\xb2\x67\xcb\xda\x3b\xf8\xe9\x8c\xde\x4d\xf1\xb7\xef\x3f\xde\x3e\x3e\xfa\xe2\x20\x92\x4a\x91\x7a\x99\xb6\xd4\x9a\xcb\x4b\x7c\x30\x7b\x1c\x45\x9a\x27\xb8\xf9\xec\x28\xc4\xe8\x87\x95\xf8\x7f\x0d\x45\x3e\x15\x84\x4f\x82\xf7\xd1\xe2\x12\xac\xe2\x3e\x42\xb1\x5a\xa5\xf5\x3c\xf8\x3b\x00\x55\xbf\xac\x6a\xa8\x02\x00\x00")
+
+func _1528395661_add_saved_search_runsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395661_add_saved_search_runsUpSql,
+		"1528395661_add_saved_search_runs.up.sql",
+	)
+}
+
+func _1528395661_add_saved_search_runsUpSql() (*asset, error) {
+	bytes, err := _1528395661_add_saved_search_runsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395661_add_saved_search_runs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7e, 0xf1, 0x46, 0xf7, 0x65, 0x5d, 0x96, 0x10, 0x83, 0xbb, 0xf2, 0x41, 0x7f, 0xdb, 0x9, 0x91, 0x1d, 0x18, 0x82, 0xce, 0x93, 0x75, 0x3d, 0xd7, 0xad, 0x92, 0x11, 0x29, 0xed, 0x89, 0x85, 0x2e}}
+	return a, nil
+}
+
+var __1528395662_add_saved_search_webhooksDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4e\x2c\x4b\x4d\x89\x2f\x4e\x4d\x2c\x4a\xce\x48\x2d\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\xc8\xcb\x2f\xc9\x4c\xab\x8c\x2f\x4f\x4d\xca\xc8\xcf\xcf\xb6\x26\xdd\x00\xa8\xce\xf8\xd2\xa2\x1c\x0a\x74\x17\xa7\x26\x17\xa5\x96\x58\x73\x71\x39\xfb\xfb\xfa\x7a\x86\x58\x73\x01\x06\x00\x27\x78\xc6\x79\xd1\x00\x00\x00")
+
+func _1528395662_add_saved_search_webhooksDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395662_add_saved_search_webhooksDownSql,
+		"1528395662_add_saved_search_webhooks.down.sql",
+	)
+}
+
+func _1528395662_add_saved_search_webhooksDownSql() (*asset, error) {
+	bytes, err := _1528395662_add_saved_search_webhooksDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395662_add_saved_search_webhooks.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x84, 0xfd, 0xf8, 0xb0, 0x73, 0x7e, 0x20, 0xb2, 0xc3, 0xbf, 0xe2, 0x53, 0x3b, 0x33, 0x2d, 0xb4, 0x77, 0x7, 0x16, 0xb2, 0x56, 0x28, 0xda, 0xb, 0xa3, 0xef, 0xc6, 0x8e, 0x68, 0x29, 0x44, 0x5a}}
+	return a, nil
+}
+
+var __1528395662_add_saved_search_webhooksUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\xcd\x4d\xaa\x83\x30\x14\x47\xf1\x79\x56\xf1\xdf\x87\xa3\xa8\xf1\x11\xb8\x46\x78\x5e\xa1\x33\x89\xf6\x8a\xa5\x62\x20\x49\xbf\x76\x5f\x28\xae\xa0\x9d\x1f\x7e\xa7\x34\x7f\xd6\x15\x4a\x69\x62\xf3\x0f\xd6\x25\x19\x24\x7f\x97\xf3\x98\xc4\xc7\x79\x95\x04\x5d\xd7\xa8\x3a\x1a\x5a\x07\xdb\xc0\x75\x0c\x73\xb2\x3d\xf7\xd8\x43\xbe\x2c\xaf\xf1\x21\xd3\x1a\xc2\x15\x53\x08\x9b\xf8\xfd\x53\xb8\x81\x08\xb5\x69\xf4\x40\x8c\xc5\x6f\x49\x8a\xaf\x16\x87\x3d\xde\xe2\x86\x2c\xcf\xfc\x9b\x92\x64\x8e\x92\x0f\x48\x55\x5d\xdb\x5a\x2e\xd4\x7b\x00\xb2\xf6\x72\x49\x03\x01\x00\x00")
+
+func _1528395662_add_saved_search_webhooksUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395662_add_saved_search_webhooksUpSql,
+		"1528395662_add_saved_search_webhooks.up.sql",
+	)
+}
+
+func _1528395662_add_saved_search_webhooksUpSql() (*asset, error) {
+	bytes, err := _1528395662_add_saved_search_webhooksUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395662_add_saved_search_webhooks.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x13, 0xb7, 0xbf, 0x37, 0x3e, 0xfb, 0xf1, 0x9d, 0x94, 0xd, 0x3e, 0xb2, 0xcb, 0xf7, 0x91, 0xaf, 0x74, 0x9e, 0x4f, 0x51, 0xd5, 0x1f, 0xa3, 0xb2, 0x7b, 0xd9, 0x9d, 0xc2, 0x37, 0x71, 0xa8, 0xd1}}
+	return a, nil
+}
+
+var __1528395663_add_external_service_sync_timesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xad\x28\x49\x2d\xca\x4b\xcc\x89\x2f\x4e\x2d\x2a\xcb\x4c\x4e\x2d\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\xc8\x49\x2c\x2e\x89\x2f\xae\xcc\x4b\x8e\x4f\x2c\xb1\x26\xdf\x84\xb4\xd2\x9c\x1c\x84\x31\x5c\xce\xfe\xbe\xbe\x9e\x21\xd6\x5c\x80\x01\x00\x96\xae\x8b\xb2\x9a\x00\x00\x00")
+
+func _1528395663_add_external_service_sync_timesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395663_add_external_service_sync_timesDownSql,
+		"1528395663_add_external_service_sync_times.down.sql",
+	)
+}
+
+func _1528395663_add_external_service_sync_timesDownSql() (*asset, error) {
+	bytes, err := _1528395663_add_external_service_sync_timesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395663_add_external_service_sync_times.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xfc, 0xc9, 0xd4, 0xa3, 0x2, 0xba, 0x5b, 0xd9, 0x2e, 0xe0, 0x3f, 0x14, 0x39, 0xc5, 0x8b, 0x4, 0xb7, 0xb0, 0x5, 0x38, 0x5c, 0xa9, 0x64, 0xf4, 0xc1, 0x37, 0xce, 0xaf, 0xa5, 0xe1, 0xb3, 0xde}}
+	return a, nil
+}
+
+var __1528395663_add_external_service_sync_timesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xad\x28\x49\x2d\xca\x4b\xcc\x89\x2f\x4e\x2d\x2a\xcb\x4c\x4e\x2d\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\xf0\xf3\x0f\x51\x70\x8d\xf0\x0c\x0e\x09\x56\xc8\x49\x2c\x2e\x89\x2f\xae\xcc\x4b\x8e\x4f\x2c\x51\x28\xc9\xcc\x4d\x2d\x2e\x49\xcc\x2d\x50\x28\xcf\x2c\xc9\x00\x73\x15\xaa\xf2\xf3\x52\xad\x29\x32\x3d\xad\x34\x27\x87\x08\x2b\xb8\x9c\xfd\x7d\x7d\x3d\x43\xac\xb9\x00\x03\x00\x7a\xf7\xec\xad\xd2\x00\x00\x00")
+
+func _1528395663_add_external_service_sync_timesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395663_add_external_service_sync_timesUpSql,
+		"1528395663_add_external_service_sync_times.up.sql",
+	)
+}
+
+func _1528395663_add_external_service_sync_timesUpSql() (*asset, error) {
+	bytes, err := _1528395663_add_external_service_sync_timesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395663_add_external_service_sync_times.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd2, 0x58, 0xf5, 0x8f, 0x7a, 0x23, 0xd9, 0x58, 0xf3, 0x41, 0x3b, 0xde, 0xb3, 0x7b, 0xdf, 0x1c, 0x42, 0xb5, 0x15, 0x3a, 0x8d, 0x86, 0x99, 0x3b, 0x58, 0x5e, 0x8c, 0xf3, 0x7f, 0x95, 0x1b, 0xc4}}
+	return a, nil
+}
+
+var __1528395664_add_repo_topics_stars_default_branchDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4a\x2d\xc8\x8f\x2f\x2e\x49\x2c\x2a\x8e\xcf\x4c\xa9\xb0\xc6\xa3\xa8\x24\xbf\x20\x33\xb9\x38\x3e\x3d\x33\x0f\xa2\x92\xcb\xd1\x27\xc4\x35\x48\x21\xc4\xd1\xc9\xc7\x15\x6c\x8c\x02\x58\xaf\xb3\xbf\x4f\xa8\xaf\x1f\x92\xe6\x94\xd4\xb4\xc4\xd2\x9c\x92\xf8\xa4\xa2\xc4\xbc\xe4\x0c\x6b\x62\xb5\x81\xdd\x44\xb4\x6a\x88\xe3\xac\xb9\xb8\x9c\xfd\x7d\x7d\x3d\x43\xac\xb9\x00\x03\x00\x9f\x4e\x31\x08\xf5\x00\x00\x00")
+
+func _1528395664_add_repo_topics_stars_default_branchDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395664_add_repo_topics_stars_default_branchDownSql,
+		"1528395664_add_repo_topics_stars_default_branch.down.sql",
+	)
+}
+
+func _1528395664_add_repo_topics_stars_default_branchDownSql() (*asset, error) {
+	bytes, err := _1528395664_add_repo_topics_stars_default_branchDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395664_add_repo_topics_stars_default_branch.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xff, 0x13, 0xdc, 0x4a, 0x43, 0xe0, 0xaf, 0x5f, 0x5c, 0x58, 0xb0, 0x3f, 0xff, 0xc, 0xde, 0x41, 0x7b, 0x59, 0x69, 0x1c, 0x2a, 0x31, 0xc, 0xa0, 0x2d, 0x95, 0x43, 0xd7, 0x94, 0x8b, 0x40, 0x30}}
+	return a, nil
+}
+
+var __1528395664_add_repo_topics_stars_default_branchUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xcf\xcd\x6a\x03\x21\x14\x05\xe0\xbd\x4f\x71\x76\x49\x76\xdd\xbb\x32\xe3\x4d\x10\x1c\x85\x8c\x42\xa0\x14\x99\x26\x76\x2a\x14\x13\x1c\x0b\x81\xd2\x77\x2f\x75\x56\x6d\x17\x25\xdb\xfb\xf3\x71\xce\x96\xf6\xca\x70\xc6\x84\x76\x74\x80\x13\x5b\x4d\x28\xf1\x7a\x81\x90\x12\x9d\xd5\xbe\x37\x50\x3b\x18\xeb\x40\x47\x35\xb8\x01\xf5\x72\x4d\xa7\x19\x35\xde\xea\xe3\x53\x5b\x18\xaf\x35\x24\xed\x84\xd7\x0e\xab\x8f\xcf\x15\xbf\x83\x9b\xeb\x58\x66\xa4\x5c\xe3\x14\xcb\x5f\xee\xe1\x1e\xeb\x1c\x5f\xc6\xf7\xb7\x1a\x9e\xcb\x98\x4f\xaf\x2d\x22\x67\xac\x3b\x90\x70\x04\x65\x24\x1d\x7f\x3d\x7c\x73\x61\x29\x14\xa6\x94\x43\x3a\xdf\x60\xcd\x92\xd8\x0f\xca\xec\x31\xa5\x8c\xf5\x72\xb1\xe1\xff\x52\xad\xcc\x0f\x65\xdd\x46\x1b\xce\x58\x67\xfb\x5e\x39\xce\xbe\x06\x00\x61\x16\xac\x3c\x72\x01\x00\x00")
+
+func _1528395664_add_repo_topics_stars_default_branchUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395664_add_repo_topics_stars_default_branchUpSql,
+		"1528395664_add_repo_topics_stars_default_branch.up.sql",
+	)
+}
+
+func _1528395664_add_repo_topics_stars_default_branchUpSql() (*asset, error) {
+	bytes, err := _1528395664_add_repo_topics_stars_default_branchUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395664_add_repo_topics_stars_default_branch.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5, 0x3b, 0xca, 0x5d, 0xed, 0x94, 0xba, 0x11, 0x73, 0x82, 0x59, 0x2d, 0x6b, 0xc5, 0x12, 0x27, 0x7, 0xd9, 0xab, 0x6e, 0x7, 0xbb, 0xb7, 0x90, 0xc3, 0xef, 0x55, 0x1f, 0xd7, 0x98, 0xb6, 0x8f}}
+	return a, nil
+}
+
+var __1528395665_add_repo_pending_deletionDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4a\x2d\xc8\x8f\x2f\x48\xcd\x4b\xc9\xcc\x4b\x8f\x4f\x49\xcd\x49\x2d\xc9\xcc\xcf\x8b\xcf\x4c\xa9\xb0\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x05\xab\x53\x00\x9b\xe0\xec\xef\x13\xea\xeb\x87\x64\x44\x6e\x66\x71\x31\x48\x77\x71\x65\x5e\x72\xb1\x35\xb1\xba\x30\xec\x4c\x2c\xb1\xe6\xe2\x72\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x0c\x00\xc1\xeb\x8b\xb4\xb4\x00\x00\x00")
+
+func _1528395665_add_repo_pending_deletionDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395665_add_repo_pending_deletionDownSql,
+		"1528395665_add_repo_pending_deletion.down.sql",
+	)
+}
+
+func _1528395665_add_repo_pending_deletionDownSql() (*asset, error) {
+	bytes, err := _1528395665_add_repo_pending_deletionDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395665_add_repo_pending_deletion.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2e, 0x2f, 0x90, 0xc4, 0xa0, 0x9a, 0x12, 0x82, 0x50, 0x0, 0x35, 0x31, 0xdf, 0x65, 0x3c, 0xa4, 0x2e, 0xf0, 0xe5, 0x33, 0x6c, 0x5f, 0x82, 0x5c, 0xd3, 0x3, 0x9b, 0xaf, 0x9e, 0x81, 0x1a, 0xd1}}
+	return a, nil
+}
+
+var __1528395665_add_repo_pending_deletionUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xcf\xbb\x6a\xc4\x30\x10\x85\xe1\x5e\x4f\x71\xca\xa4\x4b\xaf\x4a\x6b\xcd\x26\x02\x59\x02\x5b\x26\xdb\x99\x25\x1e\x1c\x41\x2c\x1b\x4b\x90\xcb\xd3\x07\x3b\x90\x22\x71\xb3\xe5\x34\xff\x7c\xe7\x44\x8f\xc6\x49\x21\x94\x0d\xd4\x20\xa8\x93\x25\xac\xbc\xcc\x50\x5a\xa3\xf2\xb6\xab\x1d\xcc\x19\xce\x07\xd0\xc5\xb4\xa1\xc5\xc2\x69\x88\x69\xec\x07\x7e\xe3\x12\xe7\xd4\x5f\x0b\x4a\x9c\x38\x97\xeb\xb4\xe0\x3d\x96\xd7\xfd\xc4\xd7\x9c\x58\xde\xd0\x9d\x62\xce\x5b\x37\x7f\xa6\x97\x8c\x98\x0a\x8f\xbc\xee\x8f\x5d\x67\x2d\x34\x9d\x55\x67\x03\x1e\xa4\x10\x55\x43\x2a\x10\x8c\xd3\x74\xf9\x53\xd9\xec\xfd\x3f\x62\x1c\x3e\xe0\xdd\x0f\xe0\xee\x60\xc0\x3d\x9e\x9f\xa8\xa1\xc3\x6d\xa6\xfd\x45\x48\x21\x2a\x5f\xd7\x26\x48\xf1\x3d\x00\x5e\xb1\xde\x3b\x38\x01\x00\x00")
+
+func _1528395665_add_repo_pending_deletionUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395665_add_repo_pending_deletionUpSql,
+		"1528395665_add_repo_pending_deletion.up.sql",
+	)
+}
+
+func _1528395665_add_repo_pending_deletionUpSql() (*asset, error) {
+	bytes, err := _1528395665_add_repo_pending_deletionUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395665_add_repo_pending_deletion.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3b, 0x56, 0xc0, 0x5c, 0x6, 0xf1, 0xe1, 0x95, 0x8f, 0x1c, 0x53, 0x4a, 0xe5, 0xe2, 0x43, 0xf8, 0x61, 0xbe, 0xf, 0x5b, 0x5d, 0x8b, 0xff, 0x9d, 0x3, 0xd6, 0x89, 0x19, 0x93, 0x8d, 0x42, 0x81}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -2095,6 +2305,16 @@ var _bindata = map[string]func() (*asset, error){
 	"1528395659_user_pending_perms_table_add_service_type_and_id.up.sql":      _1528395659_user_pending_perms_table_add_service_type_and_idUpSql,
 	"1528395660_add_state_columns_to_changesets.down.sql":                     _1528395660_add_state_columns_to_changesetsDownSql,
 	"1528395660_add_state_columns_to_changesets.up.sql":                       _1528395660_add_state_columns_to_changesetsUpSql,
+	"1528395661_add_saved_search_runs.down.sql":                               _1528395661_add_saved_search_runsDownSql,
+	"1528395661_add_saved_search_runs.up.sql":                                 _1528395661_add_saved_search_runsUpSql,
+	"1528395662_add_saved_search_webhooks.down.sql":                           _1528395662_add_saved_search_webhooksDownSql,
+	"1528395662_add_saved_search_webhooks.up.sql":                             _1528395662_add_saved_search_webhooksUpSql,
+	"1528395663_add_external_service_sync_times.down.sql":                     _1528395663_add_external_service_sync_timesDownSql,
+	"1528395663_add_external_service_sync_times.up.sql":                       _1528395663_add_external_service_sync_timesUpSql,
+	"1528395664_add_repo_topics_stars_default_branch.down.sql":                _1528395664_add_repo_topics_stars_default_branchDownSql,
+	"1528395664_add_repo_topics_stars_default_branch.up.sql":                  _1528395664_add_repo_topics_stars_default_branchUpSql,
+	"1528395665_add_repo_pending_deletion.down.sql":                           _1528395665_add_repo_pending_deletionDownSql,
+	"1528395665_add_repo_pending_deletion.up.sql":                             _1528395665_add_repo_pending_deletionUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -2225,6 +2445,16 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"1528395659_user_pending_perms_table_add_service_type_and_id.down.sql":    {_1528395659_user_pending_perms_table_add_service_type_and_idDownSql, map[string]*bintree{}},
 	"1528395659_user_pending_perms_table_add_service_type_and_id.up.sql":      {_1528395659_user_pending_perms_table_add_service_type_and_idUpSql, map[string]*bintree{}},
 	"1528395660_add_state_columns_to_changesets.down.sql":                     {_1528395660_add_state_columns_to_changesetsDownSql, map[string]*bintree{}},
+	"1528395661_add_saved_search_runs.down.sql":                               {_1528395661_add_saved_search_runsDownSql, map[string]*bintree{}},
+	"1528395661_add_saved_search_runs.up.sql":                                 {_1528395661_add_saved_search_runsUpSql, map[string]*bintree{}},
+	"1528395662_add_saved_search_webhooks.down.sql":                           {_1528395662_add_saved_search_webhooksDownSql, map[string]*bintree{}},
+	"1528395662_add_saved_search_webhooks.up.sql":                             {_1528395662_add_saved_search_webhooksUpSql, map[string]*bintree{}},
+	"1528395663_add_external_service_sync_times.down.sql":                     {_1528395663_add_external_service_sync_timesDownSql, map[string]*bintree{}},
+	"1528395663_add_external_service_sync_times.up.sql":                       {_1528395663_add_external_service_sync_timesUpSql, map[string]*bintree{}},
+	"1528395664_add_repo_topics_stars_default_branch.down.sql":                &bintree{_1528395664_add_repo_topics_stars_default_branchDownSql, map[string]*bintree{}},
+	"1528395664_add_repo_topics_stars_default_branch.up.sql":                  &bintree{_1528395664_add_repo_topics_stars_default_branchUpSql, map[string]*bintree{}},
+	"1528395665_add_repo_pending_deletion.down.sql":                           &bintree{_1528395665_add_repo_pending_deletionDownSql, map[string]*bintree{}},
+	"1528395665_add_repo_pending_deletion.up.sql":                             &bintree{_1528395665_add_repo_pending_deletionUpSql, map[string]*bintree{}},
 	"1528395660_add_state_columns_to_changesets.up.sql":                       {_1528395660_add_state_columns_to_changesetsUpSql, map[string]*bintree{}},
 }}
 
@@ -221,6 +221,69 @@ const BitbucketServerSchemaJSON = `{
           "default": "72h"
         }
       }
+    },
+    "cloneOptions": {
+      "title": "BitbucketServerCloneOptions",
+      "description": "Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "blobSizeLimit": {
+          "description": "Makes partial clones which omit files (blobs) larger than this size, such as \"1m\" or \"500k\". Omitted files are fetched from the code host when they are needed, for example to search or show them. Requires a code host which supports partial clones.",
+          "type": "string",
+          "pattern": "^[0-9]+[kmg]?$",
+          "examples": ["1m", "500k"]
+        },
+        "depth": {
+          "description": "Makes shallow clones which only contain this many commits of the history of each branch and tag. Older commits can't be searched or browsed.",
+          "type": "integer",
+          "minimum": 1,
+          "examples": [1, 100]
+        }
+      }
+    },
+    "gitOverrides": {
+      "description": "Changes how gitserver clones and fetches repositories from this code host, for repositories which can't be mirrored the usual way. For each repository, the first entry whose repos include it applies.",
+      "type": "array",
+      "items": {
+        "title": "BitbucketServerGitOverride",
+        "type": "object",
+        "additionalProperties": false,
+        "properties": {
+          "repos": {
+            "description": "The names of the repositories on Sourcegraph this entry applies to, such as \"github.com/foo/bar\". If empty, it applies to all repositories from this code host.",
+            "type": "array",
+            "items": { "type": "string" }
+          },
+          "refspecs": {
+            "description": "Refspecs which are fetched in addition to branches, tags and pull requests.",
+            "type": "array",
+            "items": { "type": "string", "pattern": "^[^-]" },
+            "examples": [["+refs/changes/*:refs/changes/*"]]
+          },
+          "config": {
+            "description": "Git config which is set in the clones of the repositories and used while cloning them, for example to configure HTTP headers or LFS. Only git config which tunes transfers is allowed, such as http.extraHeader, http.postBuffer, protocol.version, fetch.recurseSubmodules and lfs.fetchInclude.",
+            "type": "object",
+            "additionalProperties": { "type": "string" },
+            "examples": [{ "http.extraHeader": "X-Custom: value" }]
+          },
+          "fetchLFS": {
+            "description": "Fetch the Git LFS objects of all branches and tags after each clone and fetch. By default they are not fetched.",
+            "type": "boolean",
+            "default": false
+          },
+          "fetchCommand": {
+            "description": "A command which is run in the clone instead of git fetch to update it. It is split into arguments at spaces. Its executable must be listed in the SRC_GIT_OVERRIDES_FETCH_COMMANDS environment variable of gitserver.",
+            "type": "string",
+            "minLength": 1
+          }
+        }
+      }
+    },
+    "pushWebhookSecret": {
+      "description": "The secret of the webhooks which notify Sourcegraph of pushes to repositories on this code host, so that they are updated immediately instead of on their next scheduled update. Add a webhook with the URL https://sourcegraph.example.com/.api/bitbucket-server-push-webhooks and this secret in the webhooks settings of a repository or project, for the \"Repository: Push\" event.",
+      "type": "string",
+      "minLength": 1
     }
   },
   "definitions": {
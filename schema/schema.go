@@ -8,10 +8,20 @@ import (
 	"fmt"
 )
 
+// AWSCodeCommitCloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+type AWSCodeCommitCloneOptions struct {
+	// BlobSizeLimit description: Makes partial clones which omit files (blobs) larger than this size, such as "1m" or "500k". Omitted files are fetched from the code host when they are needed, for example to search or show them. Requires a code host which supports partial clones.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// Depth description: Makes shallow clones which only contain this many commits of the history of each branch and tag. Older commits can't be searched or browsed.
+	Depth int `json:"depth,omitempty"`
+}
+
 // AWSCodeCommitConnection description: Configuration for a connection to AWS CodeCommit.
 type AWSCodeCommitConnection struct {
 	// AccessKeyID description: The AWS access key ID to use when listing and updating repositories from AWS CodeCommit. Must have the AWSCodeCommitReadOnly IAM policy.
 	AccessKeyID string `json:"accessKeyID"`
+	// CloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+	CloneOptions *AWSCodeCommitCloneOptions `json:"cloneOptions,omitempty"`
 	// Exclude description: A list of repositories to never mirror from AWS CodeCommit.
 	//
 	// Supports excluding by name ({"name": "git-codecommit.us-west-1.amazonaws.com/repo-name"}) or by ARN ({"id": "arn:aws:codecommit:us-west-1:999999999999:name"}).
@@ -21,6 +31,8 @@ type AWSCodeCommitConnection struct {
 	// See the AWS CodeCommit documentation on Git credentials for CodeCommit: https://docs.aws.amazon.com/IAM/latest/UserGuide/id_credentials_ssh-keys.html#git-credentials-code-commit.
 	// For detailed instructions on how to create the credentials in IAM, see this page: https://docs.aws.amazon.com/codecommit/latest/userguide/setting-up-gc.html
 	GitCredentials AWSCodeCommitGitCredentials `json:"gitCredentials"`
+	// GitOverrides description: Changes how gitserver clones and fetches repositories from this code host, for repositories which can't be mirrored the usual way. For each repository, the first entry whose repos include it applies.
+	GitOverrides []*AWSCodeCommitGitOverride `json:"gitOverrides,omitempty"`
 	// InitialRepositoryEnablement description: Deprecated and ignored field which will be removed entirely in the next release. AWS CodeCommit repositories can no longer be enabled or disabled explicitly. Configure which repositories should not be mirrored via "exclude" instead.
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
 	// Region description: The AWS region in which to access AWS CodeCommit. See the list of supported regions at https://docs.aws.amazon.com/codecommit/latest/userguide/regions.html#regions-git.
@@ -45,6 +57,18 @@ type AWSCodeCommitGitCredentials struct {
 	// Username description: The Git username
 	Username string `json:"username"`
 }
+type AWSCodeCommitGitOverride struct {
+	// Config description: Git config which is set in the clones of the repositories and used while cloning them, for example to configure HTTP headers or LFS. Only git config which tunes transfers is allowed, such as http.extraHeader, http.postBuffer, protocol.version, fetch.recurseSubmodules and lfs.fetchInclude.
+	Config map[string]string `json:"config,omitempty"`
+	// FetchCommand description: A command which is run in the clone instead of git fetch to update it. It is split into arguments at spaces. Its executable must be listed in the SRC_GIT_OVERRIDES_FETCH_COMMANDS environment variable of gitserver.
+	FetchCommand string `json:"fetchCommand,omitempty"`
+	// FetchLFS description: Fetch the Git LFS objects of all branches and tags after each clone and fetch. By default they are not fetched.
+	FetchLFS bool `json:"fetchLFS,omitempty"`
+	// Refspecs description: Refspecs which are fetched in addition to branches, tags and pull requests.
+	Refspecs []string `json:"refspecs,omitempty"`
+	// Repos description: The names of the repositories on Sourcegraph this entry applies to, such as "github.com/foo/bar". If empty, it applies to all repositories from this code host.
+	Repos []string `json:"repos,omitempty"`
+}
 
 // AuthAccessTokens description: Settings for access tokens, which enable external tools to access the Sourcegraph API with the privileges of the user.
 type AuthAccessTokens struct {
@@ -111,16 +135,28 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab"})
 }
 
+// BitbucketCloudCloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+type BitbucketCloudCloneOptions struct {
+	// BlobSizeLimit description: Makes partial clones which omit files (blobs) larger than this size, such as "1m" or "500k". Omitted files are fetched from the code host when they are needed, for example to search or show them. Requires a code host which supports partial clones.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// Depth description: Makes shallow clones which only contain this many commits of the history of each branch and tag. Older commits can't be searched or browsed.
+	Depth int `json:"depth,omitempty"`
+}
+
 // BitbucketCloudConnection description: Configuration for a connection to Bitbucket Cloud.
 type BitbucketCloudConnection struct {
 	// ApiURL description: The API URL of Bitbucket Cloud, such as https://api.bitbucket.org. Generally, admin should not modify the value of this option because Bitbucket Cloud is a public hosting platform.
 	ApiURL string `json:"apiURL,omitempty"`
 	// AppPassword description: The app password to use when authenticating to the Bitbucket Cloud. Also set the corresponding "username" field.
 	AppPassword string `json:"appPassword"`
+	// CloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+	CloneOptions *BitbucketCloudCloneOptions `json:"cloneOptions,omitempty"`
 	// Exclude description: A list of repositories to never mirror from Bitbucket Cloud. Takes precedence over "teams" configuration.
 	//
 	// Supports excluding by name ({"name": "myorg/myrepo"}) or by UUID ({"uuid": "{fceb73c7-cef6-4abe-956d-e471281126bd}"}).
 	Exclude []*ExcludedBitbucketCloudRepo `json:"exclude,omitempty"`
+	// GitOverrides description: Changes how gitserver clones and fetches repositories from this code host, for repositories which can't be mirrored the usual way. For each repository, the first entry whose repos include it applies.
+	GitOverrides []*BitbucketCloudGitOverride `json:"gitOverrides,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Bitbucket Cloud.
 	//
 	// If "http", Sourcegraph will access Bitbucket Cloud repositories using Git URLs of the form https://bitbucket.org/myteam/myproject.git.
@@ -142,6 +178,18 @@ type BitbucketCloudConnection struct {
 	// Username description: The username to use when authenticating to the Bitbucket Cloud. Also set the corresponding "appPassword" field.
 	Username string `json:"username"`
 }
+type BitbucketCloudGitOverride struct {
+	// Config description: Git config which is set in the clones of the repositories and used while cloning them, for example to configure HTTP headers or LFS. Only git config which tunes transfers is allowed, such as http.extraHeader, http.postBuffer, protocol.version, fetch.recurseSubmodules and lfs.fetchInclude.
+	Config map[string]string `json:"config,omitempty"`
+	// FetchCommand description: A command which is run in the clone instead of git fetch to update it. It is split into arguments at spaces. Its executable must be listed in the SRC_GIT_OVERRIDES_FETCH_COMMANDS environment variable of gitserver.
+	FetchCommand string `json:"fetchCommand,omitempty"`
+	// FetchLFS description: Fetch the Git LFS objects of all branches and tags after each clone and fetch. By default they are not fetched.
+	FetchLFS bool `json:"fetchLFS,omitempty"`
+	// Refspecs description: Refspecs which are fetched in addition to branches, tags and pull requests.
+	Refspecs []string `json:"refspecs,omitempty"`
+	// Repos description: The names of the repositories on Sourcegraph this entry applies to, such as "github.com/foo/bar". If empty, it applies to all repositories from this code host.
+	Repos []string `json:"repos,omitempty"`
+}
 
 // BitbucketServerAuthorization description: If non-null, enforces Bitbucket Server repository permissions.
 type BitbucketServerAuthorization struct {
@@ -159,18 +207,30 @@ type BitbucketServerAuthorization struct {
 	Ttl string `json:"ttl,omitempty"`
 }
 
+// BitbucketServerCloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+type BitbucketServerCloneOptions struct {
+	// BlobSizeLimit description: Makes partial clones which omit files (blobs) larger than this size, such as "1m" or "500k". Omitted files are fetched from the code host when they are needed, for example to search or show them. Requires a code host which supports partial clones.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// Depth description: Makes shallow clones which only contain this many commits of the history of each branch and tag. Older commits can't be searched or browsed.
+	Depth int `json:"depth,omitempty"`
+}
+
 // BitbucketServerConnection description: Configuration for a connection to Bitbucket Server.
 type BitbucketServerConnection struct {
 	// Authorization description: If non-null, enforces Bitbucket Server repository permissions.
 	Authorization *BitbucketServerAuthorization `json:"authorization,omitempty"`
 	// Certificate description: TLS certificate of the Bitbucket Server instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
 	Certificate string `json:"certificate,omitempty"`
+	// CloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+	CloneOptions *BitbucketServerCloneOptions `json:"cloneOptions,omitempty"`
 	// Exclude description: A list of repositories to never mirror from this Bitbucket Server instance. Takes precedence over "repos" and "repositoryQuery".
 	//
 	// Supports excluding by name ({"name": "projectKey/repositorySlug"}) or by ID ({"id": 42}).
 	Exclude []*ExcludedBitbucketServerRepo `json:"exclude,omitempty"`
 	// ExcludePersonalRepositories description: Whether or not personal repositories should be excluded or not. When true, Sourcegraph will ignore personal repositories it may have access to. See https://docs.sourcegraph.com/integration/bitbucket_server#excluding-personal-repositories for more information.
 	ExcludePersonalRepositories bool `json:"excludePersonalRepositories,omitempty"`
+	// GitOverrides description: Changes how gitserver clones and fetches repositories from this code host, for repositories which can't be mirrored the usual way. For each repository, the first entry whose repos include it applies.
+	GitOverrides []*BitbucketServerGitOverride `json:"gitOverrides,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Bitbucket Server instance.
 	//
 	// If "http", Sourcegraph will access Bitbucket Server repositories using Git URLs of the form http(s)://bitbucket.example.com/scm/myproject/myrepo.git (using https: if the Bitbucket Server instance uses HTTPS).
@@ -185,6 +245,8 @@ type BitbucketServerConnection struct {
 	Password string `json:"password,omitempty"`
 	// Plugin description: Configuration for Bitbucket Server Sourcegraph plugin
 	Plugin *BitbucketServerPlugin `json:"plugin,omitempty"`
+	// PushWebhookSecret description: The secret of the webhooks which notify Sourcegraph of pushes to repositories on this code host, so that they are updated immediately instead of on their next scheduled update. Add a webhook with the URL https://sourcegraph.example.com/.api/bitbucket-server-push-webhooks and this secret in the webhooks settings of a repository or project, for the "Repository: Push" event.
+	PushWebhookSecret string `json:"pushWebhookSecret,omitempty"`
 	// Repos description: An array of repository "projectKey/repositorySlug" strings specifying repositories to mirror on Sourcegraph.
 	Repos []string `json:"repos,omitempty"`
 	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Bitbucket Server repository.
@@ -212,6 +274,18 @@ type BitbucketServerConnection struct {
 	// Webhooks description: DEPRECATED: Switch to "plugin.webhooks"
 	Webhooks *Webhooks `json:"webhooks,omitempty"`
 }
+type BitbucketServerGitOverride struct {
+	// Config description: Git config which is set in the clones of the repositories and used while cloning them, for example to configure HTTP headers or LFS. Only git config which tunes transfers is allowed, such as http.extraHeader, http.postBuffer, protocol.version, fetch.recurseSubmodules and lfs.fetchInclude.
+	Config map[string]string `json:"config,omitempty"`
+	// FetchCommand description: A command which is run in the clone instead of git fetch to update it. It is split into arguments at spaces. Its executable must be listed in the SRC_GIT_OVERRIDES_FETCH_COMMANDS environment variable of gitserver.
+	FetchCommand string `json:"fetchCommand,omitempty"`
+	// FetchLFS description: Fetch the Git LFS objects of all branches and tags after each clone and fetch. By default they are not fetched.
+	FetchLFS bool `json:"fetchLFS,omitempty"`
+	// Refspecs description: Refspecs which are fetched in addition to branches, tags and pull requests.
+	Refspecs []string `json:"refspecs,omitempty"`
+	// Repos description: The names of the repositories on Sourcegraph this entry applies to, such as "github.com/foo/bar". If empty, it applies to all repositories from this code host.
+	Repos []string `json:"repos,omitempty"`
+}
 
 // BitbucketServerIdentityProvider description: The source of identity to use when computing permissions. This defines how to compute the Bitbucket Server identity to use for a given Sourcegraph user. When 'username' is used, Sourcegraph assumes usernames are identical in Sourcegraph and Bitbucket Server accounts and `auth.enableUsernameChanges` must be set to false for security reasons.
 type BitbucketServerIdentityProvider struct {
@@ -340,6 +414,12 @@ type ExcludedBitbucketServerRepo struct {
 	// Pattern description: Regular expression which matches against the name of a Bitbucket Server repo.
 	Pattern string `json:"pattern,omitempty"`
 }
+type ExcludedGerritProject struct {
+	// Name description: The name of a Gerrit project to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a Gerrit project.
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitHubRepo struct {
 	// Id description: The node ID of a GitHub repository (as returned by the GitHub instance's API) to exclude from mirroring. Use this to exclude the repository, even if renamed. Note: This is the GraphQL ID, not the GitHub database ID. eg: "curl https://api.github.com/repos/vuejs/vue | jq .node_id"
 	Id string `json:"id,omitempty"`
@@ -354,6 +434,14 @@ type ExcludedGitLabProject struct {
 	// Name description: The name of a GitLab project ("group/name") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
 }
+type ExcludedGiteaRepo struct {
+	// Id description: The ID of a Gitea repository (as returned by the Gitea API) to exclude from mirroring. Use this to exclude the repository, even if renamed.
+	Id int `json:"id,omitempty"`
+	// Name description: The name of a Gitea repository ("owner/name") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a Gitea repository ("owner/name").
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitoliteRepo struct {
 	// Name description: The name of a Gitolite repo ("my-repo") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
@@ -361,6 +449,8 @@ type ExcludedGitoliteRepo struct {
 
 // ExperimentalFeatures description: Experimental features to enable or disable. Features that are now enabled by default are marked as deprecated.
 type ExperimentalFeatures struct {
+	// AndOrQuery description: Enables the boolean query language for search, which supports the operators `and`, `or` and `not` and grouping terms with parentheses.
+	AndOrQuery string `json:"andOrQuery,omitempty"`
 	// Automation description: Enables the experimental code change management campaigns feature. NOTE: The automation feature was renamed to campaigns, but this experimental feature flag name was not changed (because the feature flag will go away soon anyway).
 	Automation string `json:"automation,omitempty"`
 	// BitbucketServerFastPerm description: DEPRECATED: Configure in Bitbucket Server config.
@@ -402,6 +492,54 @@ type ExternalIdentity struct {
 	Type           string `json:"type"`
 }
 
+// GerritCloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+type GerritCloneOptions struct {
+	// BlobSizeLimit description: Makes partial clones which omit files (blobs) larger than this size, such as "1m" or "500k". Omitted files are fetched from the code host when they are needed, for example to search or show them. Requires a code host which supports partial clones.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// Depth description: Makes shallow clones which only contain this many commits of the history of each branch and tag. Older commits can't be searched or browsed.
+	Depth int `json:"depth,omitempty"`
+}
+
+// GerritConnection description: Configuration for a connection to Gerrit.
+type GerritConnection struct {
+	// Certificate description: TLS certificate of the Gerrit instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
+	Certificate string `json:"certificate,omitempty"`
+	// CloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+	CloneOptions *GerritCloneOptions `json:"cloneOptions,omitempty"`
+	// Exclude description: A list of projects to never mirror from this Gerrit instance. Takes precedence over "projects" configuration.
+	//
+	// Supports excluding by name ({"name": "tools/gerrit"}) or by a regular expression matching the name ({"pattern": "^sandbox/.*"}).
+	Exclude []*ExcludedGerritProject `json:"exclude,omitempty"`
+	// GitOverrides description: Changes how gitserver clones and fetches repositories from this code host, for repositories which can't be mirrored the usual way. For each repository, the first entry whose repos include it applies.
+	GitOverrides []*GerritGitOverride `json:"gitOverrides,omitempty"`
+	// Password description: The HTTP password to use when authenticating to Gerrit, which is generated in the user settings under "HTTP Credentials". Also set the corresponding "username" field.
+	Password string `json:"password,omitempty"`
+	// Projects description: An array of names of Gerrit projects to mirror on Sourcegraph. If omitted, all projects visible to the configured user are mirrored, except for Gerrit's own All-Projects and All-Users and hidden projects.
+	Projects []string `json:"projects,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gerrit project. In the pattern, the variable "{host}" is replaced with the Gerrit URL's host (such as gerrit.example.com), and "{name}" is replaced with the Gerrit project's name (such as "tools/gerrit").
+	//
+	// For example, if your Gerrit is https://gerrit.example.com and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of "{host}/{name}" would mean that the Gerrit project tools/gerrit is available on Sourcegraph at https://src.example.com/gerrit.example.com/tools/gerrit.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Url description: URL of a Gerrit instance, such as https://gerrit.example.com.
+	Url string `json:"url"`
+	// Username description: The username to use when authenticating to Gerrit. Also set the corresponding "password" field. If omitted, only projects visible to anonymous users are mirrored.
+	Username string `json:"username,omitempty"`
+}
+type GerritGitOverride struct {
+	// Config description: Git config which is set in the clones of the repositories and used while cloning them, for example to configure HTTP headers or LFS. Only git config which tunes transfers is allowed, such as http.extraHeader, http.postBuffer, protocol.version, fetch.recurseSubmodules and lfs.fetchInclude.
+	Config map[string]string `json:"config,omitempty"`
+	// FetchCommand description: A command which is run in the clone instead of git fetch to update it. It is split into arguments at spaces. Its executable must be listed in the SRC_GIT_OVERRIDES_FETCH_COMMANDS environment variable of gitserver.
+	FetchCommand string `json:"fetchCommand,omitempty"`
+	// FetchLFS description: Fetch the Git LFS objects of all branches and tags after each clone and fetch. By default they are not fetched.
+	FetchLFS bool `json:"fetchLFS,omitempty"`
+	// Refspecs description: Refspecs which are fetched in addition to branches, tags and pull requests.
+	Refspecs []string `json:"refspecs,omitempty"`
+	// Repos description: The names of the repositories on Sourcegraph this entry applies to, such as "gerrit.example.com/tools/gerrit". If empty, it applies to all repositories from this code host.
+	Repos []string `json:"repos,omitempty"`
+}
+
 // GitHubAuthProvider description: Configures the GitHub (or GitHub Enterprise) OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create a OAuth App on your GitHub instance: https://developer.github.com/apps/building-oauth-apps/creating-an-oauth-app/. When a user signs into Sourcegraph or links their GitHub account to their existing Sourcegraph account, GitHub will prompt the user for the repo scope.
 type GitHubAuthProvider struct {
 	// AllowOrgs description: Restricts new logins to members of these GitHub organizations. Existing sessions won't be invalidated. Leave empty or unset for no org restrictions.
@@ -430,18 +568,30 @@ type GitHubAuthorization struct {
 	Ttl string `json:"ttl,omitempty"`
 }
 
+// GitHubCloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+type GitHubCloneOptions struct {
+	// BlobSizeLimit description: Makes partial clones which omit files (blobs) larger than this size, such as "1m" or "500k". Omitted files are fetched from the code host when they are needed, for example to search or show them. Requires a code host which supports partial clones.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// Depth description: Makes shallow clones which only contain this many commits of the history of each branch and tag. Older commits can't be searched or browsed.
+	Depth int `json:"depth,omitempty"`
+}
+
 // GitHubConnection description: Configuration for a connection to GitHub or GitHub Enterprise.
 type GitHubConnection struct {
 	// Authorization description: If non-null, enforces GitHub repository permissions. This requires that there is an item in the `auth.providers` field of type "github" with the same `url` field as specified in this `GitHubConnection`.
 	Authorization *GitHubAuthorization `json:"authorization,omitempty"`
 	// Certificate description: TLS certificate of the GitHub Enterprise instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
 	Certificate string `json:"certificate,omitempty"`
+	// CloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+	CloneOptions *GitHubCloneOptions `json:"cloneOptions,omitempty"`
 	// Exclude description: A list of repositories to never mirror from this GitHub instance. Takes precedence over "orgs", "repos", and "repositoryQuery" configuration.
 	//
 	// Supports excluding by name ({"name": "owner/name"}) or by ID ({"id": "MDEwOlJlcG9zaXRvcnkxMTczMDM0Mg=="}).
 	//
 	// Note: ID is the GitHub GraphQL ID, not the GitHub database ID. eg: "curl https://api.github.com/repos/vuejs/vue | jq .node_id"
 	Exclude []*ExcludedGitHubRepo `json:"exclude,omitempty"`
+	// GitOverrides description: Changes how gitserver clones and fetches repositories from this code host, for repositories which can't be mirrored the usual way. For each repository, the first entry whose repos include it applies.
+	GitOverrides []*GitHubGitOverride `json:"gitOverrides,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this GitHub instance.
 	//
 	// If "http", Sourcegraph will access GitHub repositories using Git URLs of the form http(s)://github.com/myteam/myproject.git (using https: if the GitHub instance uses HTTPS).
@@ -452,6 +602,8 @@ type GitHubConnection struct {
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
 	// Orgs description: An array of organization names identifying GitHub organizations whose repositories should be mirrored on Sourcegraph.
 	Orgs []string `json:"orgs,omitempty"`
+	// PushWebhookSecret description: The secret of the webhooks which notify Sourcegraph of pushes to repositories on this code host, so that they are updated immediately instead of on their next scheduled update. Add a webhook with the URL https://sourcegraph.example.com/.api/github-push-webhooks and this secret in Settings > Webhooks of a repository or organization, for push events with the content type application/json.
+	PushWebhookSecret string `json:"pushWebhookSecret,omitempty"`
 	// Repos description: An array of repository "owner/name" strings specifying which GitHub or GitHub Enterprise repositories to mirror on Sourcegraph.
 	Repos []string `json:"repos,omitempty"`
 	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a GitHub or GitHub Enterprise repository. In the pattern, the variable "{host}" is replaced with the GitHub host (such as github.example.com), and "{nameWithOwner}" is replaced with the GitHub repository's "owner/path" (such as "myorg/myrepo").
@@ -484,6 +636,18 @@ type GitHubConnection struct {
 	// Webhooks description: An array of configurations defining existing GitHub webhooks that send updates back to Sourcegraph.
 	Webhooks []*GitHubWebhook `json:"webhooks,omitempty"`
 }
+type GitHubGitOverride struct {
+	// Config description: Git config which is set in the clones of the repositories and used while cloning them, for example to configure HTTP headers or LFS. Only git config which tunes transfers is allowed, such as http.extraHeader, http.postBuffer, protocol.version, fetch.recurseSubmodules and lfs.fetchInclude.
+	Config map[string]string `json:"config,omitempty"`
+	// FetchCommand description: A command which is run in the clone instead of git fetch to update it. It is split into arguments at spaces. Its executable must be listed in the SRC_GIT_OVERRIDES_FETCH_COMMANDS environment variable of gitserver.
+	FetchCommand string `json:"fetchCommand,omitempty"`
+	// FetchLFS description: Fetch the Git LFS objects of all branches and tags after each clone and fetch. By default they are not fetched.
+	FetchLFS bool `json:"fetchLFS,omitempty"`
+	// Refspecs description: Refspecs which are fetched in addition to branches, tags and pull requests.
+	Refspecs []string `json:"refspecs,omitempty"`
+	// Repos description: The names of the repositories on Sourcegraph this entry applies to, such as "github.com/foo/bar". If empty, it applies to all repositories from this code host.
+	Repos []string `json:"repos,omitempty"`
+}
 type GitHubWebhook struct {
 	// Org description: The name of the GitHub organization to which the webhook belongs
 	Org string `json:"org"`
@@ -517,14 +681,26 @@ type GitLabAuthorization struct {
 	Ttl string `json:"ttl,omitempty"`
 }
 
+// GitLabCloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+type GitLabCloneOptions struct {
+	// BlobSizeLimit description: Makes partial clones which omit files (blobs) larger than this size, such as "1m" or "500k". Omitted files are fetched from the code host when they are needed, for example to search or show them. Requires a code host which supports partial clones.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// Depth description: Makes shallow clones which only contain this many commits of the history of each branch and tag. Older commits can't be searched or browsed.
+	Depth int `json:"depth,omitempty"`
+}
+
 // GitLabConnection description: Configuration for a connection to GitLab (GitLab.com or GitLab self-managed).
 type GitLabConnection struct {
 	// Authorization description: If non-null, enforces GitLab repository permissions. This requires that there be an item in the `auth.providers` field of type "gitlab" with the same `url` field as specified in this `GitLabConnection`.
 	Authorization *GitLabAuthorization `json:"authorization,omitempty"`
 	// Certificate description: TLS certificate of the GitLab instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
 	Certificate string `json:"certificate,omitempty"`
+	// CloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+	CloneOptions *GitLabCloneOptions `json:"cloneOptions,omitempty"`
 	// Exclude description: A list of projects to never mirror from this GitLab instance. Takes precedence over "projects" and "projectQuery" configuration. Supports excluding by name ({"name": "group/name"}) or by ID ({"id": 42}).
 	Exclude []*ExcludedGitLabProject `json:"exclude,omitempty"`
+	// GitOverrides description: Changes how gitserver clones and fetches repositories from this code host, for repositories which can't be mirrored the usual way. For each repository, the first entry whose repos include it applies.
+	GitOverrides []*GitLabGitOverride `json:"gitOverrides,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this GitLab instance.
 	//
 	// If "http", Sourcegraph will access GitLab repositories using Git URLs of the form http(s)://gitlab.example.com/myteam/myproject.git (using https: if the GitLab instance uses HTTPS).
@@ -541,6 +717,8 @@ type GitLabConnection struct {
 	ProjectQuery []string `json:"projectQuery"`
 	// Projects description: A list of projects to mirror from this GitLab instance. Supports including by name ({"name": "group/name"}) or by ID ({"id": 42}).
 	Projects []*GitLabProject `json:"projects,omitempty"`
+	// PushWebhookSecret description: The secret of the webhooks which notify Sourcegraph of pushes to repositories on this code host, so that they are updated immediately instead of on their next scheduled update. Add a webhook with the URL https://sourcegraph.example.com/.api/gitlab-push-webhooks and this secret in Settings > Webhooks of a project or group, for push and tag push events, as the secret token.
+	PushWebhookSecret string `json:"pushWebhookSecret,omitempty"`
 	// RepositoryPathPattern description: The pattern used to generate a the corresponding Sourcegraph repository name for a GitLab project. In the pattern, the variable "{host}" is replaced with the GitLab URL's host (such as gitlab.example.com), and "{pathWithNamespace}" is replaced with the GitLab project's "namespace/path" (such as "myteam/myproject").
 	//
 	// For example, if your GitLab is https://gitlab.example.com and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of "{host}/{pathWithNamespace}" would mean that a GitLab project at https://gitlab.example.com/myteam/myproject is available on Sourcegraph at https://src.example.com/gitlab.example.com/myteam/myproject.
@@ -552,6 +730,18 @@ type GitLabConnection struct {
 	// Url description: URL of a GitLab instance, such as https://gitlab.example.com or (for GitLab.com) https://gitlab.com.
 	Url string `json:"url"`
 }
+type GitLabGitOverride struct {
+	// Config description: Git config which is set in the clones of the repositories and used while cloning them, for example to configure HTTP headers or LFS. Only git config which tunes transfers is allowed, such as http.extraHeader, http.postBuffer, protocol.version, fetch.recurseSubmodules and lfs.fetchInclude.
+	Config map[string]string `json:"config,omitempty"`
+	// FetchCommand description: A command which is run in the clone instead of git fetch to update it. It is split into arguments at spaces. Its executable must be listed in the SRC_GIT_OVERRIDES_FETCH_COMMANDS environment variable of gitserver.
+	FetchCommand string `json:"fetchCommand,omitempty"`
+	// FetchLFS description: Fetch the Git LFS objects of all branches and tags after each clone and fetch. By default they are not fetched.
+	FetchLFS bool `json:"fetchLFS,omitempty"`
+	// Refspecs description: Refspecs which are fetched in addition to branches, tags and pull requests.
+	Refspecs []string `json:"refspecs,omitempty"`
+	// Repos description: The names of the repositories on Sourcegraph this entry applies to, such as "github.com/foo/bar". If empty, it applies to all repositories from this code host.
+	Repos []string `json:"repos,omitempty"`
+}
 type GitLabNameTransformation struct {
 	// Regex description: The regex to match for the occurrences of its replacement.
 	Regex string `json:"regex,omitempty"`
@@ -565,12 +755,90 @@ type GitLabProject struct {
 	Name string `json:"name,omitempty"`
 }
 
+// GiteaCloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+type GiteaCloneOptions struct {
+	// BlobSizeLimit description: Makes partial clones which omit files (blobs) larger than this size, such as "1m" or "500k". Omitted files are fetched from the code host when they are needed, for example to search or show them. Requires a code host which supports partial clones.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// Depth description: Makes shallow clones which only contain this many commits of the history of each branch and tag. Older commits can't be searched or browsed.
+	Depth int `json:"depth,omitempty"`
+}
+
+// GiteaConnection description: Configuration for a connection to Gitea.
+type GiteaConnection struct {
+	// Certificate description: TLS certificate of the Gitea instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
+	Certificate string `json:"certificate,omitempty"`
+	// CloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+	CloneOptions *GiteaCloneOptions `json:"cloneOptions,omitempty"`
+	// Exclude description: A list of repositories to never mirror from this Gitea instance. Takes precedence over "orgs", "repos", and "repositoryQuery" configuration.
+	//
+	// Supports excluding by name ({"name": "owner/name"}), by ID ({"id": 42}) or by a regular expression matching the name ({"pattern": "^myorg/archive-.*"}).
+	Exclude []*ExcludedGiteaRepo `json:"exclude,omitempty"`
+	// GitOverrides description: Changes how gitserver clones and fetches repositories from this code host, for repositories which can't be mirrored the usual way. For each repository, the first entry whose repos include it applies.
+	GitOverrides []*GiteaGitOverride `json:"gitOverrides,omitempty"`
+	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Gitea instance.
+	//
+	// If "http", Sourcegraph will access Gitea repositories using Git URLs of the form https://gitea.example.com/myorg/myrepo.git, with the token (if any) as credentials.
+	//
+	// If "ssh", Sourcegraph will access Gitea repositories using Git URLs of the form git@gitea.example.com:myorg/myrepo.git. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
+	GitURLType string `json:"gitURLType,omitempty"`
+	// Orgs description: An array of organization names identifying Gitea organizations whose repositories should be mirrored on Sourcegraph.
+	Orgs []string `json:"orgs,omitempty"`
+	// Repos description: An array of repository "owner/name" strings specifying which Gitea repositories to mirror on Sourcegraph.
+	Repos []string `json:"repos,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gitea repository. In the pattern, the variable "{host}" is replaced with the Gitea URL's host (such as gitea.example.com), and "{nameWithOwner}" is replaced with the Gitea repository's "owner/name" (such as "myorg/myrepo").
+	//
+	// For example, if your Gitea is https://gitea.example.com and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of "{host}/{nameWithOwner}" would mean that a Gitea repository at https://gitea.example.com/myorg/myrepo is available on Sourcegraph at https://src.example.com/gitea.example.com/myorg/myrepo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// RepositoryQuery description: An array of strings specifying which Gitea repositories to mirror on Sourcegraph. The valid values are:
+	//
+	// - `affiliated` mirrors all repositories the configured token's user owns or has access to
+	//
+	// - `all` mirrors all repositories visible to the configured token's user, or all public repositories without a token
+	//
+	// - `none` mirrors no repositories (except those specified in the `orgs` and `repos` configuration properties)
+	//
+	// - All other values are executed as a Gitea repository search for repositories whose name or description contain the value.
+	//
+	// If multiple values are provided, their results are unioned.
+	RepositoryQuery []string `json:"repositoryQuery,omitempty"`
+	// Token description: A Gitea access token, created in the user settings under "Applications". It is required to mirror private repositories and to use the "affiliated" repository query. If using only public repositories, it can be omitted.
+	Token string `json:"token,omitempty"`
+	// Url description: URL of a Gitea instance, such as https://gitea.example.com.
+	Url string `json:"url"`
+}
+type GiteaGitOverride struct {
+	// Config description: Git config which is set in the clones of the repositories and used while cloning them, for example to configure HTTP headers or LFS. Only git config which tunes transfers is allowed, such as http.extraHeader, http.postBuffer, protocol.version, fetch.recurseSubmodules and lfs.fetchInclude.
+	Config map[string]string `json:"config,omitempty"`
+	// FetchCommand description: A command which is run in the clone instead of git fetch to update it. It is split into arguments at spaces. Its executable must be listed in the SRC_GIT_OVERRIDES_FETCH_COMMANDS environment variable of gitserver.
+	FetchCommand string `json:"fetchCommand,omitempty"`
+	// FetchLFS description: Fetch the Git LFS objects of all branches and tags after each clone and fetch. By default they are not fetched.
+	FetchLFS bool `json:"fetchLFS,omitempty"`
+	// Refspecs description: Refspecs which are fetched in addition to branches, tags and pull requests.
+	Refspecs []string `json:"refspecs,omitempty"`
+	// Repos description: The names of the repositories on Sourcegraph this entry applies to, such as "gitea.example.com/owner/name". If empty, it applies to all repositories from this code host.
+	Repos []string `json:"repos,omitempty"`
+}
+
+// GitoliteCloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+type GitoliteCloneOptions struct {
+	// BlobSizeLimit description: Makes partial clones which omit files (blobs) larger than this size, such as "1m" or "500k". Omitted files are fetched from the code host when they are needed, for example to search or show them. Requires a code host which supports partial clones.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// Depth description: Makes shallow clones which only contain this many commits of the history of each branch and tag. Older commits can't be searched or browsed.
+	Depth int `json:"depth,omitempty"`
+}
+
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
 	// Blacklist description: Regular expression to filter repositories from auto-discovery, so they will not get cloned automatically.
 	Blacklist string `json:"blacklist,omitempty"`
+	// CloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+	CloneOptions *GitoliteCloneOptions `json:"cloneOptions,omitempty"`
 	// Exclude description: A list of repositories to never mirror from this Gitolite instance. Supports excluding by exact name ({"name": "foo"}).
 	Exclude []*ExcludedGitoliteRepo `json:"exclude,omitempty"`
+	// GitOverrides description: Changes how gitserver clones and fetches repositories from this code host, for repositories which can't be mirrored the usual way. For each repository, the first entry whose repos include it applies.
+	GitOverrides []*GitoliteGitOverride `json:"gitOverrides,omitempty"`
 	// Host description: Gitolite host that stores the repositories (e.g., git@gitolite.example.com, ssh://git@gitolite.example.com:2222/).
 	Host string `json:"host"`
 	// Phabricator description: Phabricator instance that integrates with this Gitolite instance
@@ -582,6 +850,18 @@ type GitoliteConnection struct {
 	// It is important that the Sourcegraph repository name generated with this prefix be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
 	Prefix string `json:"prefix"`
 }
+type GitoliteGitOverride struct {
+	// Config description: Git config which is set in the clones of the repositories and used while cloning them, for example to configure HTTP headers or LFS. Only git config which tunes transfers is allowed, such as http.extraHeader, http.postBuffer, protocol.version, fetch.recurseSubmodules and lfs.fetchInclude.
+	Config map[string]string `json:"config,omitempty"`
+	// FetchCommand description: A command which is run in the clone instead of git fetch to update it. It is split into arguments at spaces. Its executable must be listed in the SRC_GIT_OVERRIDES_FETCH_COMMANDS environment variable of gitserver.
+	FetchCommand string `json:"fetchCommand,omitempty"`
+	// FetchLFS description: Fetch the Git LFS objects of all branches and tags after each clone and fetch. By default they are not fetched.
+	FetchLFS bool `json:"fetchLFS,omitempty"`
+	// Refspecs description: Refspecs which are fetched in addition to branches, tags and pull requests.
+	Refspecs []string `json:"refspecs,omitempty"`
+	// Repos description: The names of the repositories on Sourcegraph this entry applies to, such as "github.com/foo/bar". If empty, it applies to all repositories from this code host.
+	Repos []string `json:"repos,omitempty"`
+}
 
 // HTTPHeaderAuthProvider description: Configures the HTTP header authentication provider (which authenticates users by consulting an HTTP request header set by an authentication proxy such as https://github.com/bitly/oauth2_proxy).
 type HTTPHeaderAuthProvider struct {
@@ -684,9 +964,21 @@ type OpenIDConnectAuthProvider struct {
 	Type               string `json:"type"`
 }
 
+// OtherExternalServiceCloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+type OtherExternalServiceCloneOptions struct {
+	// BlobSizeLimit description: Makes partial clones which omit files (blobs) larger than this size, such as "1m" or "500k". Omitted files are fetched from the code host when they are needed, for example to search or show them. Requires a code host which supports partial clones.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// Depth description: Makes shallow clones which only contain this many commits of the history of each branch and tag. Older commits can't be searched or browsed.
+	Depth int `json:"depth,omitempty"`
+}
+
 // OtherExternalServiceConnection description: Configuration for a Connection to Git repositories for which an external service integration isn't yet available.
 type OtherExternalServiceConnection struct {
-	Repos []string `json:"repos"`
+	// CloneOptions description: Options for cloning repositories from this code host, which can reduce the disk space used by very large repositories on gitserver. Changing them causes repositories to be recloned on their next update.
+	CloneOptions *OtherExternalServiceCloneOptions `json:"cloneOptions,omitempty"`
+	// GitOverrides description: Changes how gitserver clones and fetches repositories from this code host, for repositories which can't be mirrored the usual way. For each repository, the first entry whose repos include it applies.
+	GitOverrides []*OtherExternalServiceGitOverride `json:"gitOverrides,omitempty"`
+	Repos        []string                           `json:"repos"`
 	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the Git clone base URL host and path, and "{repo}" is replaced with the repository path taken from the `repos` field.
 	//
 	// For example, if your Git clone base URL is https://git.example.com/repos and `repos` contains the value "my/repo", then a repositoryPathPattern of "{base}/{repo}" would mean that a repository at https://git.example.com/repos/my/repo is available on Sourcegraph at https://sourcegraph.example.com/git.example.com/repos/my/repo.
@@ -695,6 +987,18 @@ type OtherExternalServiceConnection struct {
 	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
 	Url                   string `json:"url,omitempty"`
 }
+type OtherExternalServiceGitOverride struct {
+	// Config description: Git config which is set in the clones of the repositories and used while cloning them, for example to configure HTTP headers or LFS. Only git config which tunes transfers is allowed, such as http.extraHeader, http.postBuffer, protocol.version, fetch.recurseSubmodules and lfs.fetchInclude.
+	Config map[string]string `json:"config,omitempty"`
+	// FetchCommand description: A command which is run in the clone instead of git fetch to update it. It is split into arguments at spaces. Its executable must be listed in the SRC_GIT_OVERRIDES_FETCH_COMMANDS environment variable of gitserver.
+	FetchCommand string `json:"fetchCommand,omitempty"`
+	// FetchLFS description: Fetch the Git LFS objects of all branches and tags after each clone and fetch. By default they are not fetched.
+	FetchLFS bool `json:"fetchLFS,omitempty"`
+	// Refspecs description: Refspecs which are fetched in addition to branches, tags and pull requests.
+	Refspecs []string `json:"refspecs,omitempty"`
+	// Repos description: The names of the repositories on Sourcegraph this entry applies to, such as "github.com/foo/bar". If empty, it applies to all repositories from this code host.
+	Repos []string `json:"repos,omitempty"`
+}
 
 // ParentSourcegraph description: URL to fetch unreachable repository details from. Defaults to "https://sourcegraph.com"
 type ParentSourcegraph struct {
@@ -740,6 +1044,16 @@ type QuickLink struct {
 	// Url description: The URL of this quick link (absolute or relative)
 	Url string `json:"url"`
 }
+
+// RepoDeletionSafeguard description: Protects against deleting many repositories at once, e.g. when a code host returns an incomplete list of repositories during an outage. When a check of the code hosts would delete more repositories than these thresholds allow, the repositories are marked as pending deletion instead. They stay searchable, and site admins are alerted. They're deleted once a site admin confirms the deletion, or once enough consecutive checks still don't find them.
+type RepoDeletionSafeguard struct {
+	// ConsistentSyncs description: The number of consecutive full checks of the code hosts that must not find a repository pending deletion before it's deleted without confirmation. If unset or 0, repositories pending deletion are only deleted once a site admin confirms it.
+	ConsistentSyncs int `json:"consistentSyncs,omitempty"`
+	// MaxDeletionPercentage description: The percentage of the repositories of an external service a check of the code hosts may delete at once. Each external service's deletions are compared to its own number of repositories. If unset or 0, there's no limit on the percentage of deleted repositories.
+	MaxDeletionPercentage float64 `json:"maxDeletionPercentage,omitempty"`
+	// MaxDeletions description: The number of repositories a check of the code hosts may delete at once. If unset or 0, there's no limit on the number of deleted repositories.
+	MaxDeletions int `json:"maxDeletions,omitempty"`
+}
 type Repos struct {
 	// Callsign description: The unique Phabricator identifier for the repository, like 'MUX'.
 	Callsign string `json:"callsign"`
@@ -939,6 +1253,12 @@ type SiteConfiguration struct {
 	GitCloneURLToRepositoryName []*CloneURLToRepositoryName `json:"git.cloneURLToRepositoryName,omitempty"`
 	// GitMaxConcurrentClones description: Maximum number of git clone processes that will be run concurrently to update repositories.
 	GitMaxConcurrentClones int `json:"gitMaxConcurrentClones,omitempty"`
+	// GitServerPinnedRepos description: Names of repositories which gitservers never remove to free up disk space, such as large repositories which take a long time to clone again.
+	GitServerPinnedRepos []string `json:"gitServerPinnedRepos,omitempty"`
+	// GitServerRebalancing description: When enabled, a gitserver which needs to clone a repository first copies it from another gitserver which has a clone, such as its previous owner after gitservers were added or removed. It only clones from the code host if no other gitserver has the repository. Gitservers also remove their clones of repositories which now belong to other gitservers, once those have cloned them.
+	GitServerRebalancing bool `json:"gitServerRebalancing,omitempty"`
+	// GitServerReplicationFactor description: Number of gitservers each repository is cloned on. If a gitserver can't be reached, requests for its repositories fall back to another gitserver holding a replica. Values larger than the number of gitservers are treated as the number of gitservers.
+	GitServerReplicationFactor int `json:"gitServerReplicationFactor,omitempty"`
 	// GithubClientID description: Client ID for GitHub. (DEPRECATED)
 	GithubClientID string `json:"githubClientID,omitempty"`
 	// GithubClientSecret description: Client secret for GitHub. (DEPRECATED)
@@ -969,6 +1289,10 @@ type SiteConfiguration struct {
 	PermissionsBackgroundSync *PermissionsBackgroundSync `json:"permissions.backgroundSync,omitempty"`
 	// PermissionsUserMapping description: Settings for Sourcegraph permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. This setting cannot be enabled if repository permissions for any specific external service are enabled (i.e., when the external service's `authorization` field is set).
 	PermissionsUserMapping *PermissionsUserMapping `json:"permissions.userMapping,omitempty"`
+	// RepoDeletionSafeguard description: Protects against deleting many repositories at once, e.g. when a code host returns an incomplete list of repositories during an outage. When a check of the code hosts would delete more repositories than these thresholds allow, the repositories are marked as pending deletion instead. They stay searchable, and site admins are alerted. They're deleted once a site admin confirms the deletion, or once enough consecutive checks still don't find them.
+	RepoDeletionSafeguard *RepoDeletionSafeguard `json:"repoDeletionSafeguard,omitempty"`
+	// RepoListFullSyncInterval description: Interval (in minutes) for listing all repositories of code hosts that support listing only the repositories changed since the previous check (GitHub and GitLab). Deleted repositories are only noticed by these full listings. If unset or 0, all repositories are listed on every check.
+	RepoListFullSyncInterval int `json:"repoListFullSyncInterval,omitempty"`
 	// RepoListUpdateInterval description: Interval (in minutes) for checking code hosts (such as GitHub, Gitolite, etc.) for new repositories.
 	RepoListUpdateInterval int `json:"repoListUpdateInterval,omitempty"`
 	// SearchIndexEnabled description: Whether indexed search is enabled. If unset Sourcegraph detects the environment to decide if indexed search is enabled. Indexed search is RAM heavy, and is disabled by default in the single docker image. All other environments will have it enabled by default. The size of all your repository working copies is the amount of additional RAM required.
@@ -90,6 +90,12 @@ const SiteSchemaJSON = `{
           "enum": ["enabled", "disabled"],
           "default": "enabled"
         },
+        "andOrQuery": {
+          "description": "Enables the boolean query language for search, which supports the operators ` + "`" + `and` + "`" + `, ` + "`" + `or` + "`" + ` and ` + "`" + `not` + "`" + ` and grouping terms with parentheses.",
+          "type": "string",
+          "enum": ["enabled", "disabled"],
+          "default": "disabled"
+        },
         "bitbucketServerFastPerm": {
           "description": "DEPRECATED: Configure in Bitbucket Server config.",
           "type": "string",
@@ -243,12 +249,65 @@ const SiteSchemaJSON = `{
       "default": 5,
       "group": "External services"
     },
+    "gitServerRebalancing": {
+      "description": "When enabled, a gitserver which needs to clone a repository first copies it from another gitserver which has a clone, such as its previous owner after gitservers were added or removed. It only clones from the code host if no other gitserver has the repository. Gitservers also remove their clones of repositories which now belong to other gitservers, once those have cloned them.",
+      "type": "boolean",
+      "default": false,
+      "group": "External services"
+    },
+    "gitServerPinnedRepos": {
+      "description": "Names of repositories which gitservers never remove to free up disk space, such as large repositories which take a long time to clone again.",
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "examples": [["github.com/example/monorepo"]],
+      "group": "External services"
+    },
+    "gitServerReplicationFactor": {
+      "description": "Number of gitservers each repository is cloned on. If a gitserver can't be reached, requests for its repositories fall back to another gitserver holding a replica. Values larger than the number of gitservers are treated as the number of gitservers.",
+      "type": "integer",
+      "minimum": 1,
+      "default": 1,
+      "group": "External services"
+    },
     "repoListUpdateInterval": {
       "description": "Interval (in minutes) for checking code hosts (such as GitHub, Gitolite, etc.) for new repositories.",
       "type": "integer",
       "default": 1,
       "group": "External services"
     },
+    "repoListFullSyncInterval": {
+      "description": "Interval (in minutes) for listing all repositories of code hosts that support listing only the repositories changed since the previous check (GitHub and GitLab). Deleted repositories are only noticed by these full listings. If unset or 0, all repositories are listed on every check.",
+      "type": "integer",
+      "minimum": 0,
+      "group": "External services"
+    },
+    "repoDeletionSafeguard": {
+      "description": "Protects against deleting many repositories at once, e.g. when a code host returns an incomplete list of repositories during an outage. When a check of the code hosts would delete more repositories than these thresholds allow, the repositories are marked as pending deletion instead. They stay searchable, and site admins are alerted. They're deleted once a site admin confirms the deletion, or once enough consecutive checks still don't find them.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "maxDeletions": {
+          "description": "The number of repositories a check of the code hosts may delete at once. If unset or 0, there's no limit on the number of deleted repositories.",
+          "type": "integer",
+          "minimum": 0
+        },
+        "maxDeletionPercentage": {
+          "description": "The percentage of the repositories of an external service a check of the code hosts may delete at once. Each external service's deletions are compared to its own number of repositories. If unset or 0, there's no limit on the percentage of deleted repositories.",
+          "type": "number",
+          "minimum": 0,
+          "maximum": 100
+        },
+        "consistentSyncs": {
+          "description": "The number of consecutive full checks of the code hosts that must not find a repository pending deletion before it's deleted without confirmation. If unset or 0, repositories pending deletion are only deleted once a site admin confirms it.",
+          "type": "integer",
+          "minimum": 0
+        }
+      },
+      "examples": [{ "maxDeletions": 100, "maxDeletionPercentage": 10, "consistentSyncs": 5 }],
+      "group": "External services"
+    },
     "maxReposToSearch": {
       "description": "The maximum number of repositories to search across. The user is prompted to narrow their query if exceeded. Any value less than or equal to zero means unlimited.",
       "type": "integer",
@@ -68,18 +68,28 @@ export class SiteAdminExternalServicePage extends React.Component<Props, State>
                     switchMap(input =>
                         concat(
                             [{ updatedOrError: LOADING, warning: null }],
-                            updateExternalService(input).pipe(
-                                mergeMap(({ warning }) =>
-                                    warning
-                                        ? of({ warning, updatedOrError: null })
-                                        : concat(
-                                              // Flash "updated" text
-                                              of({ updatedOrError: true }),
-                                              // Hide "updated" text again after 1s
-                                              of({ updatedOrError: null }).pipe(delay(1000))
+                            // Preview the repositories the new config would delete or rename, so that a wrong
+                            // repositoryQuery or exclude doesn't go unnoticed. When the preview fails, the admin
+                            // is shown the error and asked whether to save anyway.
+                            dryRunExternalService({ id: input.id, config: input.config }).pipe(
+                                catchError(err => [asError(err)]),
+                                mergeMap(dryRun =>
+                                    confirmDryRun(dryRun)
+                                        ? updateExternalService(input).pipe(
+                                              mergeMap(({ warning }) =>
+                                                  warning
+                                                      ? of({ warning, updatedOrError: null })
+                                                      : concat(
+                                                            // Flash "updated" text
+                                                            of({ updatedOrError: true }),
+                                                            // Hide "updated" text again after 1s
+                                                            of({ updatedOrError: null }).pipe(delay(1000))
+                                                        )
+                                              ),
+                                              catchError((error: Error) => [{ updatedOrError: asError(error) }])
                                           )
-                                ),
-                                catchError((error: Error) => [{ updatedOrError: asError(error) }])
+                                        : [{ updatedOrError: null }]
+                                )
                             )
                         )
                     )
@@ -205,6 +215,67 @@ function updateExternalService(
     )
 }
 
+function dryRunExternalService(input: GQL.IDryRunExternalServiceInput): Observable<GQL.IExternalServiceDryRun> {
+    return mutateGraphQL(
+        gql`
+            mutation DryRunExternalService($input: DryRunExternalServiceInput!) {
+                dryRunExternalService(input: $input) {
+                    added
+                    deleted
+                    quarantined
+                    renamed {
+                        from
+                        to
+                    }
+                    modified
+                }
+            }
+        `,
+        { input }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.dryRunExternalService)
+    )
+}
+
+/**
+ * Asks the user to confirm the repositories that saving the config would delete, mark as pending deletion or
+ * rename, if any.
+ */
+function confirmDryRun(dryRun: GQL.IExternalServiceDryRun | ErrorLike): boolean {
+    if (isErrorLike(dryRun)) {
+        return window.confirm(
+            `Could not preview the repositories this configuration would delete or rename:\n  ${dryRun.message}\n\nSave anyway?`
+        )
+    }
+    if (dryRun.deleted.length === 0 && dryRun.quarantined.length === 0 && dryRun.renamed.length === 0) {
+        return true
+    }
+
+    const limit = 10
+    const list = (names: string[]): string =>
+        names
+            .slice(0, limit)
+            .map(name => `  ${name}\n`)
+            .join('') + (names.length > limit ? `  ...and ${names.length - limit} more\n` : '')
+
+    let message = ''
+    if (dryRun.deleted.length > 0) {
+        message += `Saving this configuration will delete ${dryRun.deleted.length} repositories:\n${list(dryRun.deleted)}\n`
+    }
+    if (dryRun.quarantined.length > 0) {
+        message += `Saving this configuration will mark ${
+            dryRun.quarantined.length
+        } repositories as pending deletion:\n${list(dryRun.quarantined)}\n`
+    }
+    if (dryRun.renamed.length > 0) {
+        message += `Saving this configuration will rename ${dryRun.renamed.length} repositories:\n${list(
+            dryRun.renamed.map(({ from, to }) => `${from} → ${to}`)
+        )}\n`
+    }
+    return window.confirm(`${message}Continue?`)
+}
+
 function fetchExternalService(id: GQL.ID): Observable<GQL.IExternalService> {
     return queryGraphQL(
         gql`
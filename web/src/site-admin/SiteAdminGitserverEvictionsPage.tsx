@@ -0,0 +1,99 @@
+import { LoadingSpinner } from '@sourcegraph/react-loading-spinner'
+import prettyBytes from 'pretty-bytes'
+import * as React from 'react'
+import { RouteComponentProps } from 'react-router'
+import { Link } from 'react-router-dom'
+import { Subscription } from 'rxjs'
+import * as GQL from '../../../shared/src/graphql/schema'
+import { ErrorAlert } from '../components/alerts'
+import { PageTitle } from '../components/PageTitle'
+import { Timestamp } from '../components/time/Timestamp'
+import { eventLogger } from '../tracking/eventLogger'
+import { fetchGitserverEvictions } from './backend'
+
+interface Props extends RouteComponentProps<{}> {}
+
+interface State {
+    evictions?: GQL.IGitserverEviction[]
+    error?: string
+}
+
+/**
+ * A page displaying the repositories which gitservers recently removed to free up disk space.
+ */
+export class SiteAdminGitserverEvictionsPage extends React.Component<Props, State> {
+    public state: State = {}
+
+    private subscriptions = new Subscription()
+
+    public componentDidMount(): void {
+        eventLogger.logViewEvent('SiteAdminGitserverEvictions')
+
+        this.subscriptions.add(
+            fetchGitserverEvictions().subscribe(
+                evictions => this.setState({ evictions, error: undefined }),
+                error => this.setState({ error: error.message })
+            )
+        )
+    }
+
+    public componentWillUnmount(): void {
+        this.subscriptions.unsubscribe()
+    }
+
+    public render(): JSX.Element | null {
+        return (
+            <div className="site-admin-gitserver-evictions-page">
+                <PageTitle title="Removed repositories - Admin" />
+                <h2>Removed repositories</h2>
+                <p>
+                    When disk space is low, gitservers remove the least valuable repository clones first, based on how
+                    frequently and recently they were used and their size. Removed repositories are cloned again when
+                    they are next used. Repositories listed in the <code>gitServerPinnedRepos</code>{' '}
+                    <Link to="/site-admin/configuration">site configuration</Link> option are never removed.
+                </p>
+                {this.state.error && <ErrorAlert className="mb-3" error={this.state.error} />}
+                {!this.state.evictions && !this.state.error && <LoadingSpinner className="icon-inline" />}
+                {this.state.evictions &&
+                    (this.state.evictions.length === 0 ? (
+                        <p>No repositories were removed recently.</p>
+                    ) : (
+                        <table className="table">
+                            <thead>
+                                <tr>
+                                    <th>Repository</th>
+                                    <th>Gitserver</th>
+                                    <th>Removed</th>
+                                    <th>Size</th>
+                                    <th>Last used</th>
+                                    <th>Reason</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {this.state.evictions.map((eviction, i) => (
+                                    <tr key={i}>
+                                        <td>
+                                            {eviction.repository ? (
+                                                <Link to={eviction.repository.url}>{eviction.repositoryName}</Link>
+                                            ) : (
+                                                eviction.repositoryName
+                                            )}
+                                        </td>
+                                        <td>{eviction.gitserver}</td>
+                                        <td>
+                                            <Timestamp date={eviction.evictedAt} />
+                                        </td>
+                                        <td>{prettyBytes(eviction.sizeBytes)}</td>
+                                        <td>
+                                            <Timestamp date={eviction.lastUsedAt} />
+                                        </td>
+                                        <td>{eviction.reason}</td>
+                                    </tr>
+                                ))}
+                            </tbody>
+                        </table>
+                    ))}
+            </div>
+        )
+    }
+}
@@ -0,0 +1,110 @@
+import { LoadingSpinner } from '@sourcegraph/react-loading-spinner'
+import prettyBytes from 'pretty-bytes'
+import * as React from 'react'
+import { RouteComponentProps } from 'react-router'
+import { Link } from 'react-router-dom'
+import { Subscription } from 'rxjs'
+import * as GQL from '../../../shared/src/graphql/schema'
+import { ErrorAlert } from '../components/alerts'
+import { PageTitle } from '../components/PageTitle'
+import { Timestamp } from '../components/time/Timestamp'
+import { eventLogger } from '../tracking/eventLogger'
+import { fetchGitserverRepositoryStats } from './backend'
+
+/** The number of repositories shown. */
+const REPOSITORY_COUNT = 50
+
+interface Props extends RouteComponentProps<{}> {}
+
+interface State {
+    stats?: GQL.IGitserverRepositoryStats[]
+    error?: string
+}
+
+/**
+ * A page displaying the largest repository clones on gitservers and statistics about them.
+ */
+export class SiteAdminGitserverRepositoryStatsPage extends React.Component<Props, State> {
+    public state: State = {}
+
+    private subscriptions = new Subscription()
+
+    public componentDidMount(): void {
+        eventLogger.logViewEvent('SiteAdminGitserverRepositoryStats')
+
+        this.subscriptions.add(
+            fetchGitserverRepositoryStats(REPOSITORY_COUNT).subscribe(
+                stats => this.setState({ stats, error: undefined }),
+                error => this.setState({ error: error.message })
+            )
+        )
+    }
+
+    public componentWillUnmount(): void {
+        this.subscriptions.unsubscribe()
+    }
+
+    public render(): JSX.Element | null {
+        return (
+            <div className="site-admin-gitserver-repository-stats-page">
+                <PageTitle title="Repository disk usage - Admin" />
+                <h2>Repository disk usage</h2>
+                <p>
+                    The {REPOSITORY_COUNT} largest repository clones on gitservers, largest first. Many loose objects or
+                    packfiles slow down git operations until the next <code>git gc</code> repacks them.
+                </p>
+                {this.state.error && <ErrorAlert className="mb-3" error={this.state.error} />}
+                {!this.state.stats && !this.state.error && <LoadingSpinner className="icon-inline" />}
+                {this.state.stats &&
+                    (this.state.stats.length === 0 ? (
+                        <p>No repositories are cloned.</p>
+                    ) : (
+                        <table className="table">
+                            <thead>
+                                <tr>
+                                    <th>Repository</th>
+                                    <th>Gitserver</th>
+                                    <th>Size</th>
+                                    <th>Packfiles</th>
+                                    <th>Loose objects</th>
+                                    <th>Last fetched</th>
+                                    <th>Last fetch duration</th>
+                                    <th>Last git gc</th>
+                                    <th>Cloned</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {this.state.stats.map((stats, i) => (
+                                    <tr key={i}>
+                                        <td>
+                                            {stats.repository ? (
+                                                <Link to={stats.repository.url}>{stats.repositoryName}</Link>
+                                            ) : (
+                                                stats.repositoryName
+                                            )}
+                                            {stats.lastFetchError && (
+                                                <div className="text-danger">
+                                                    <small>{stats.lastFetchError}</small>
+                                                </div>
+                                            )}
+                                        </td>
+                                        <td>{stats.gitserver}</td>
+                                        <td>{prettyBytes(stats.sizeBytes)}</td>
+                                        <td>{stats.packfileCount}</td>
+                                        <td>{stats.looseObjects}</td>
+                                        <td>{stats.lastFetchedAt && <Timestamp date={stats.lastFetchedAt} />}</td>
+                                        <td>
+                                            {stats.lastFetchDurationSeconds !== null &&
+                                                `${stats.lastFetchDurationSeconds.toFixed(1)}s`}
+                                        </td>
+                                        <td>{stats.lastGCAt && <Timestamp date={stats.lastGCAt} />}</td>
+                                        <td>{stats.clonedAt && <Timestamp date={stats.clonedAt} />}</td>
+                                    </tr>
+                                ))}
+                            </tbody>
+                        </table>
+                    ))}
+            </div>
+        )
+    }
+}
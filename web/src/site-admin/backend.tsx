@@ -577,3 +577,65 @@ export function fetchSiteUpdateCheck(): Observable<{
         map(data => data.site)
     )
 }
+
+/**
+ * Fetches the repositories which gitservers recently removed to free up disk space.
+ */
+export function fetchGitserverEvictions(): Observable<GQL.IGitserverEviction[]> {
+    return queryGraphQL(
+        gql`
+            query GitserverEvictions {
+                site {
+                    gitserverEvictions {
+                        repositoryName
+                        repository {
+                            url
+                        }
+                        gitserver
+                        evictedAt
+                        reason
+                        sizeBytes
+                        lastUsedAt
+                        accessScore
+                    }
+                }
+            }
+        `
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.site.gitserverEvictions)
+    )
+}
+
+/**
+ * Fetches statistics about the largest repository clones on gitservers.
+ */
+export function fetchGitserverRepositoryStats(first: number): Observable<GQL.IGitserverRepositoryStats[]> {
+    return queryGraphQL(
+        gql`
+            query GitserverRepositoryStats($first: Int) {
+                site {
+                    gitserverRepositoryStats(first: $first) {
+                        repositoryName
+                        repository {
+                            url
+                        }
+                        gitserver
+                        sizeBytes
+                        packfileCount
+                        looseObjects
+                        lastFetchedAt
+                        lastFetchDurationSeconds
+                        lastFetchError
+                        lastGCAt
+                        clonedAt
+                    }
+                }
+            }
+        `,
+        { first }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.site.gitserverRepositoryStats)
+    )
+}
@@ -9,6 +9,8 @@ import React from 'react'
 import awsCodeCommitSchemaJSON from '../../../schema/aws_codecommit.schema.json'
 import bitbucketCloudSchemaJSON from '../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../schema/bitbucket_server.schema.json'
+import gerritSchemaJSON from '../../../schema/gerrit.schema.json'
+import giteaSchemaJSON from '../../../schema/gitea.schema.json'
 import githubSchemaJSON from '../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../schema/gitolite.schema.json'
@@ -941,6 +943,164 @@ const GITOLITE: AddExternalServiceOptions = {
         },
     ],
 }
+const GITEA: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.GITEA,
+    title: 'Gitea',
+    icon: GitIcon,
+    jsonSchema: giteaSchemaJSON,
+    defaultDisplayName: 'Gitea',
+    defaultConfig: `{
+  "url": "https://gitea.example.com",
+  "token": "<access token>",
+  "repositoryQuery": [
+    "affiliated"
+  ]
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL of your Gitea instance.
+                </li>
+                <li>
+                    Create a Gitea access token in your user's <b>Settings &gt; Applications</b> and set it to be the
+                    value of the <Field>token</Field> field.
+                </li>
+                <li>
+                    Use the <Field>orgs</Field>, <Field>repos</Field> and <Field>repositoryQuery</Field> fields to
+                    select the repositories Sourcegraph should index.
+                </li>
+            </ol>
+            <p>
+                See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/gitea#configuration"
+                >
+                    the docs for more options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'setAccessToken',
+            label: 'Set access token',
+            run: config => {
+                const value = '<access token>'
+                const edits = setProperty(config, ['token'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addOrgRepositories',
+            label: 'Add repositories belonging to organization',
+            run: config => {
+                const value = '<organization>'
+                const edits = setProperty(config, ['orgs', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addRepository',
+            label: 'Add a single repository',
+            run: config => {
+                const value = '<owner>/<repository>'
+                const edits = setProperty(config, ['repos', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'excludeRepo',
+            label: 'Exclude a repository',
+            run: config => {
+                const value = { name: '<owner>/<repository>' }
+                const edits = setProperty(config, ['exclude', -1], value, defaultFormattingOptions)
+                return { edits, selectText: '<owner>/<repository>' }
+            },
+        },
+    ],
+}
+const GERRIT: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.GERRIT,
+    title: 'Gerrit',
+    icon: GitIcon,
+    jsonSchema: gerritSchemaJSON,
+    defaultDisplayName: 'Gerrit',
+    defaultConfig: `{
+  "url": "https://gerrit.example.com",
+  "username": "<username>",
+  "password": "<HTTP password>"
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL of your Gerrit server.
+                </li>
+                <li>
+                    Set the <Field>username</Field> and <Field>password</Field> fields to a Gerrit user and the HTTP
+                    password generated in its <b>Settings &gt; HTTP Credentials</b>.
+                </li>
+                <li>
+                    All projects visible to the user are indexed. Set the <Field>projects</Field> field to only index
+                    the listed projects.
+                </li>
+            </ol>
+            <p>
+                See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/gerrit#configuration"
+                >
+                    the docs for more options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'setUsername',
+            label: 'Set username',
+            run: config => {
+                const value = '<username>'
+                const edits = setProperty(config, ['username'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'setPassword',
+            label: 'Set HTTP password',
+            run: config => {
+                const value = '<HTTP password>'
+                const edits = setProperty(config, ['password'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addProject',
+            label: 'Add a project',
+            run: config => {
+                const value = '<project>'
+                const edits = setProperty(config, ['projects', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'excludeProject',
+            label: 'Exclude a project',
+            run: config => {
+                const value = { name: '<project>' }
+                const edits = setProperty(config, ['exclude', -1], value, defaultFormattingOptions)
+                return { edits, selectText: '<project>' }
+            },
+        },
+    ],
+}
 const PHABRICATOR_SERVICE: AddExternalServiceOptions = {
     kind: GQL.ExternalServiceKind.PHABRICATOR,
     title: 'Phabricator connection',
@@ -1056,6 +1216,8 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     bitbucketserver: BITBUCKET_SERVER,
     aws_codecommit: AWS_CODE_COMMIT,
     gitolite: GITOLITE,
+    gitea: GITEA,
+    gerrit: GERRIT,
     git: GENERIC_GIT,
 }
 
@@ -1072,6 +1234,8 @@ export const defaultExternalServices: Record<GQL.ExternalServiceKind, AddExterna
     [GQL.ExternalServiceKind.GITHUB]: GITHUB_DOTCOM,
     [GQL.ExternalServiceKind.BITBUCKETCLOUD]: BITBUCKET_CLOUD,
     [GQL.ExternalServiceKind.BITBUCKETSERVER]: BITBUCKET_SERVER,
+    [GQL.ExternalServiceKind.GERRIT]: GERRIT,
+    [GQL.ExternalServiceKind.GITEA]: GITEA,
     [GQL.ExternalServiceKind.GITLAB]: GITLAB_DOTCOM,
     [GQL.ExternalServiceKind.GITOLITE]: GITOLITE,
     [GQL.ExternalServiceKind.PHABRICATOR]: PHABRICATOR_SERVICE,
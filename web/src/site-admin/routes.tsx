@@ -51,6 +51,19 @@ export const siteAdminAreaRoutes: readonly SiteAdminAreaRoute[] = [
         render: lazyComponent(() => import('./SiteAdminRepositoriesPage'), 'SiteAdminRepositoriesPage'),
         exact: true,
     },
+    {
+        path: '/gitserver-evictions',
+        render: lazyComponent(() => import('./SiteAdminGitserverEvictionsPage'), 'SiteAdminGitserverEvictionsPage'),
+        exact: true,
+    },
+    {
+        path: '/gitserver-repository-stats',
+        render: lazyComponent(
+            () => import('./SiteAdminGitserverRepositoryStatsPage'),
+            'SiteAdminGitserverRepositoryStatsPage'
+        ),
+        exact: true,
+    },
     {
         path: '/organizations',
         render: lazyComponent(() => import('./SiteAdminOrgsPage'), 'SiteAdminOrgsPage'),
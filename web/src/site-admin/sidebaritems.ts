@@ -57,6 +57,14 @@ const repositoriesGroup: SiteAdminSideBarGroup = {
             label: 'Repository status',
             to: '/site-admin/repositories',
         },
+        {
+            label: 'Removed repositories',
+            to: '/site-admin/gitserver-evictions',
+        },
+        {
+            label: 'Repository disk usage',
+            to: '/site-admin/gitserver-repository-stats',
+        },
     ],
 }
 